@@ -71,6 +71,7 @@ export default defineConfig({
         items: [
           { text: 'ConnectRPC', link: '/guide/connect-rpc' },
           { text: 'gRPC-web', link: '/guide/grpc-web' },
+          { text: 'HTTP/JSON Transcoding', link: '/guide/http-transcoding' },
         ],
         collapsed: false,
       },
@@ -80,6 +81,7 @@ export default defineConfig({
           { text: 'Overview', link: '/guide/sources/' },
           { text: 'BSR', link: '/guide/sources/bsr' },
           { text: 'gRPC Reflection', link: '/guide/sources/grpc-reflection' },
+          { text: 'Hot Reload', link: '/guide/sources/hot-reload' },
         ],
         collapsed: false,
       },
@@ -90,6 +92,9 @@ export default defineConfig({
           { text: 'Proxy', link: '/guide/modes/proxy' },
           { text: 'Replay', link: '/guide/modes/replay' },
           { text: 'Capture', link: '/guide/modes/capture' },
+          { text: 'Shadow', link: '/guide/modes/shadow' },
+          { text: 'Upstream Patching', link: '/guide/modes/upstream-patching' },
+          { text: 'Routing Rules', link: '/guide/modes/routing-rules' },
         ],
         collapsed: false,
       },
@@ -107,7 +112,11 @@ export default defineConfig({
           { text: 'Streaming', link: '/guide/stubs/streaming' },
           { text: 'Health Service', link: '/guide/stubs/health' },
           { text: 'Dynamic Templates', link: '/guide/stubs/dynamic-templates' },
+          { text: 'Output Scripts', link: '/guide/stubs/scripts' },
+          { text: 'Fault Injection', link: '/guide/stubs/faults' },
+          { text: 'Auto-Mock', link: '/guide/stubs/auto-mock' },
           { text: 'Effects', link: '/guide/stubs/effects' },
+          { text: 'Scenarios', link: '/guide/stubs/scenarios' },
           { text: 'Faker Reference', link: '/guide/stubs/faker' }
         ],
         collapsed: false,
@@ -119,6 +128,7 @@ export default defineConfig({
           { text: 'Matching Logic', link: '/guide/matcher/logic' },
           { text: 'Input', link: '/guide/matcher/input' },
           { text: 'Headers', link: '/guide/matcher/headers' },
+          { text: 'Request Validation', link: '/guide/matcher/request-validation' },
         ],
         collapsed: false,
       },
@@ -147,6 +157,8 @@ export default defineConfig({
         text: 'Plugins',
         items: [
           { text: 'Overview', link: '/guide/plugins/' },
+          { text: 'Process Plugins', link: '/guide/plugins/process' },
+          { text: 'Lifecycle Hooks', link: '/guide/plugins/hooks' },
           { text: 'Builder Image', link: '/guide/plugins/builder-image' },
           { text: 'Advanced', link: '/guide/plugins/advanced' },
           { text: 'Testing', link: '/guide/plugins/testing' }
@@ -160,6 +172,9 @@ export default defineConfig({
           { text: 'Descriptors API', link: '/guide/api/descriptors' },
           { text: 'History API', link: '/guide/api/history' },
           { text: 'Verify API', link: '/guide/api/verify' },
+          { text: 'Faults API', link: '/guide/api/faults' },
+          { text: 'Seed API', link: '/guide/api/seed' },
+          { text: 'Snapshots API', link: '/guide/api/snapshots' },
           {
             text: 'Stubs',
             items: [
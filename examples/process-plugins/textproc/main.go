@@ -0,0 +1,35 @@
+// Command textproc is a process plugin: build it with any Go toolchain and
+// point --plugins at the binary.
+package main
+
+import (
+	"log"
+	"strings"
+
+	"github.com/bavix/gripmock/v3/pkg/plugins"
+	"github.com/bavix/gripmock/v3/pkg/plugins/pluginrpc"
+)
+
+func main() {
+	err := pluginrpc.Serve(plugins.PluginInfo{
+		Name:        "textproc",
+		Version:     "v1.0.0",
+		Description: "text helpers served from a subprocess",
+	},
+		plugins.FuncSpec{
+			Name:        "shout",
+			Fn:          func(s string) string { return strings.ToUpper(s) + "!" },
+			Description: "upper-case and exclaim",
+			Group:       "text",
+		},
+		plugins.FuncSpec{
+			Name:        "repeat",
+			Fn:          func(s string, n float64) string { return strings.Repeat(s, int(n)) },
+			Description: "repeat a string n times",
+			Group:       "text",
+		},
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+}
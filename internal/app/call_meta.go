@@ -0,0 +1,102 @@
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+)
+
+// callOrigin is what an HTTP gateway knows about a call that the mocker cannot
+// recover from the context on its own: there is no grpc peer behind it.
+type callOrigin struct {
+	transport string
+	peer      string
+}
+
+type callOriginKey struct{}
+
+// withCallOrigin tags ctx with the protocol that received r and its client address.
+func withCallOrigin(ctx context.Context, transport string, r *http.Request) context.Context {
+	return context.WithValue(ctx, callOriginKey{}, callOrigin{transport: transport, peer: r.RemoteAddr})
+}
+
+// gatewayCallContext is the context a gateway hands to the mocker for r.
+func gatewayCallContext(r *http.Request, transport string) context.Context {
+	return withCallOrigin(httpHeadersToGRPCContext(r.Context(), r.Header), transport, r)
+}
+
+// callMeta is what history records about the caller, next to the messages.
+type callMeta struct {
+	session   string
+	headers   map[string]string
+	peer      string
+	transport string
+	deadline  *time.Time
+}
+
+func callMetaFromContext(ctx context.Context) callMeta {
+	meta := callMeta{transport: history.TransportGRPC}
+
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		meta.session = sessionFromMetadata(md)
+		meta.headers = recordedRequestHeaders(md)
+	}
+
+	if origin, ok := ctx.Value(callOriginKey{}).(callOrigin); ok {
+		meta.transport = origin.transport
+		meta.peer = origin.peer
+	} else if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+		meta.peer = p.Addr.String()
+	}
+
+	if deadline, ok := ctx.Deadline(); ok {
+		meta.deadline = &deadline
+	}
+
+	return meta
+}
+
+// apply copies the caller details into rec.
+func (m callMeta) apply(rec *history.CallRecord) {
+	rec.Session = m.session
+	rec.RequestHeaders = m.headers
+	rec.Peer = m.peer
+	rec.Transport = m.transport
+	rec.Deadline = m.deadline
+}
+
+// recordedRequestHeaders flattens incoming metadata for history. Pseudo-headers
+// are dropped and binary values are base64-encoded so they survive JSON.
+func recordedRequestHeaders(md metadata.MD) map[string]string {
+	if len(md) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(md))
+
+	for k, values := range md {
+		if strings.HasPrefix(k, ":") || len(values) == 0 {
+			continue
+		}
+
+		if strings.HasSuffix(k, "-bin") {
+			encoded := make([]string, len(values))
+			for i, v := range values {
+				encoded[i] = base64.StdEncoding.EncodeToString([]byte(v))
+			}
+
+			values = encoded
+		}
+
+		out[k] = strings.Join(values, ", ")
+	}
+
+	return out
+}
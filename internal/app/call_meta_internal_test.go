@@ -0,0 +1,143 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func TestHistoryRecordsCallMetadata(t *testing.T) {
+	t.Parallel()
+
+	mocker := createTestMockerWithRecorder(t)
+	mocker.recorder = history.NewMemoryStore(0, history.WithRedactKeys([]string{"authorization"}))
+	mocker.fullServiceName = testServiceName
+	mocker.serviceName = testServiceName
+	mocker.methodName = testMethodName
+	mocker.budgerigar.PutMany(&stuber.Stub{
+		ID:      uuid.New(),
+		Service: testServiceName,
+		Method:  testMethodName,
+		Input:   stuber.InputData{Contains: map[string]any{}},
+		Output:  stuber.Output{Data: map[string]any{"result": 1}},
+	})
+
+	deadline := time.Now().Add(time.Minute)
+
+	ctx, cancel := context.WithDeadline(t.Context(), deadline)
+	defer cancel()
+
+	ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(
+		"authorization", "Bearer secret",
+		"x-trace-id", "a",
+		"x-trace-id", "b",
+		"trace-bin", "\x01\x02",
+		":authority", "localhost",
+	))
+	ctx = peer.NewContext(ctx, &peer.Peer{Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 5100}})
+
+	_, err := mocker.handleUnary(ctx, nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.NoError(t, err)
+
+	recorder, ok := mocker.recorder.(*history.MemoryStore)
+	require.True(t, ok, testRecorderShouldBeMemoryStore)
+
+	call := recorder.All()[0]
+	require.Equal(t, map[string]string{
+		"authorization": "[REDACTED]",
+		"x-trace-id":    "a, b",
+		"trace-bin":     "AQI=",
+	}, call.RequestHeaders)
+	require.Equal(t, "10.0.0.7:5100", call.Peer)
+	require.Equal(t, history.TransportGRPC, call.Transport)
+	require.NotNil(t, call.Deadline)
+	require.WithinDuration(t, deadline, *call.Deadline, 0)
+
+	require.Len(t, recorder.Filter(history.FilterOpts{Headers: map[string]string{"Authorization": ""}}), 1)
+	require.Empty(t, recorder.Filter(history.FilterOpts{Headers: map[string]string{"authorization": "Bearer secret"}}))
+	require.Len(t, recorder.Filter(history.FilterOpts{Peer: "10.0.0.7", DeadlineOnly: true}), 1)
+}
+
+func TestGatewayCallContextCarriesOrigin(t *testing.T) {
+	t.Parallel()
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/svc/Method", nil)
+	r.RemoteAddr = "192.0.2.1:4242"
+	r.Header.Set("X-Request-Id", "42")
+	r.Header.Set("X-Gripmock-Session", "s1")
+
+	meta := callMetaFromContext(gatewayCallContext(r, history.TransportConnect))
+	require.Equal(t, history.TransportConnect, meta.transport)
+	require.Equal(t, "192.0.2.1:4242", meta.peer)
+	require.Equal(t, "s1", meta.session)
+	require.Equal(t, "42", meta.headers["x-request-id"])
+	require.Nil(t, meta.deadline)
+}
+
+func TestListHistoryFiltersByCallMetadata(t *testing.T) {
+	t.Parallel()
+
+	deadline := time.Now().Add(time.Minute)
+	store := history.NewMemoryStore(0)
+	store.Record(history.CallRecord{
+		Service: "svc", Method: "M", Transport: history.TransportGRPC, Peer: "10.0.0.1:1",
+		RequestHeaders: map[string]string{"authorization": "Bearer a", "x-trace-id": "t1"},
+		Deadline:       &deadline,
+	})
+	store.Record(history.CallRecord{
+		Service: "svc", Method: "M", Transport: history.TransportConnect, Peer: "10.0.0.2:2",
+		RequestHeaders: map[string]string{"x-trace-id": "t2"},
+	})
+
+	srv, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), &mockExtender{}, store, nil, nil, nil)
+	require.NoError(t, err)
+
+	get := func(params rest.ListHistoryParams) rest.HistoryList {
+		rec := httptest.NewRecorder()
+		srv.ListHistory(rec, httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/api/history", nil), params)
+		require.Equal(t, http.StatusOK, rec.Code)
+
+		var out rest.HistoryList
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+
+		return out
+	}
+
+	out := get(rest.ListHistoryParams{Header: &[]string{"Authorization"}})
+	require.Len(t, out, 1)
+	require.Equal(t, "Bearer a", out[0].RequestHeaders["authorization"])
+	require.Equal(t, history.TransportGRPC, *out[0].Transport)
+	require.Equal(t, "10.0.0.1:1", *out[0].Peer)
+	require.NotNil(t, out[0].Deadline)
+
+	require.Len(t, get(rest.ListHistoryParams{Header: &[]string{"x-trace-id=t2"}}), 1)
+	require.Empty(t, get(rest.ListHistoryParams{Header: &[]string{"x-trace-id=t2", "authorization"}}))
+	require.Len(t, get(rest.ListHistoryParams{Transport: new(history.TransportConnect)}), 1)
+	require.Len(t, get(rest.ListHistoryParams{Peer: new("10.0.0.2")}), 1)
+	require.Len(t, get(rest.ListHistoryParams{Deadline: new(true)}), 1)
+
+	result, err := mcpHistoryList(t.Context(), srv, map[string]any{
+		"headers":   map[string]any{"X-Trace-Id": "t1"},
+		"transport": history.TransportGRPC,
+		"deadline":  true,
+	})
+	require.NoError(t, err)
+	require.Equal(t, 1, result["total"])
+
+	_, err = mcpHistoryList(t.Context(), srv, map[string]any{"headers": map[string]any{"x-trace-id": 1}})
+	require.Error(t, err)
+}
@@ -10,6 +10,8 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
+
+	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
 )
 
 func TestSerializeErrorStatusUsesProtocolDetailShape(t *testing.T) {
@@ -19,7 +21,7 @@ func TestSerializeErrorStatusUsesProtocolDetailShape(t *testing.T) {
 		WithDetails(&errdetails.ErrorInfo{Reason: "QUOTA", Domain: "e2e"})
 	require.NoError(t, err)
 
-	body := serializeErrorStatus(st)
+	body := serializeErrorStatus(st, protosetinfra.GlobalTypeResolver())
 
 	require.Equal(t, "resource_exhausted", body.Code)
 	require.Equal(t, "quota exhausted", body.Message)
@@ -45,7 +47,7 @@ func TestSerializeErrorStatusUsesProtocolDetailShape(t *testing.T) {
 func TestSerializeErrorStatusOmitsEmptyDetails(t *testing.T) {
 	t.Parallel()
 
-	encoded, err := json.Marshal(serializeErrorStatus(status.New(codes.NotFound, "missing")))
+	encoded, err := json.Marshal(serializeErrorStatus(status.New(codes.NotFound, "missing"), protosetinfra.GlobalTypeResolver()))
 	require.NoError(t, err)
 
 	require.JSONEq(t, `{"code":"not_found","message":"missing"}`, string(encoded))
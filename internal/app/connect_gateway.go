@@ -218,7 +218,7 @@ func (g *ConnectRPCGateway) serveMethod(
 
 	adapter := &httpStreamAdapter{
 		baseStreamAdapter: baseStreamAdapter{
-			ctx:           httpHeadersToGRPCContext(r.Context(), r.Header),
+			ctx:           gatewayCallContext(r, history.TransportConnect),
 			req:           r,
 			w:             w,
 			typeResolver:  mocker.typeResolver,
@@ -381,6 +381,8 @@ func (connectResponse) WriteError(w http.ResponseWriter, r *http.Request, code c
 	_, _ = w.Write(body)
 }
 
+func (connectResponse) Transport() string { return history.TransportConnect }
+
 func (connectResponse) WriteSuccess(w http.ResponseWriter, r *http.Request) {
 	ct := r.Header.Get(headerContentType)
 	if isJSONContentType(ct) {
@@ -598,7 +600,7 @@ func (a *httpStreamAdapter) writeError(code codes.Code, msg string) {
 }
 
 func (a *httpStreamAdapter) writeErrorStatus(st *status.Status) {
-	connErr := serializeErrorStatus(st)
+	connErr := serializeErrorStatus(st, a.typeResolver)
 
 	if !a.streaming {
 		body, _ := json.Marshal(connErr)
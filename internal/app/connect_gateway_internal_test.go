@@ -769,7 +769,7 @@ func registerMultiverseDescriptors(t *testing.T, ctx context.Context, registry *
 		merged.File = append(merged.File, set.GetFile()...)
 	}
 
-	files, err := decodeDescriptorFiles(&merged)
+	files, err := decodeDescriptorFiles(&merged, registry.Startup())
 	require.NoError(t, err)
 
 	for _, fd := range files {
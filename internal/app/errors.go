@@ -25,11 +25,15 @@ var (
 	ErrServiceIsMissing             = stderrors.New("service name is missing")
 	ErrMethodIsMissing              = stderrors.New("method name is missing")
 	ErrServiceNotRemovable          = stderrors.New("service not found or not removable")
+	ErrScenarioNotFound             = stderrors.New("scenario not found")
 	ErrEmptyBody                    = stderrors.New("empty body")
 	ErrFileDescriptorSetNoFiles     = stderrors.New("FileDescriptorSet does not contain files")
 	ErrResolveDescriptorDeps        = stderrors.New("failed to resolve FileDescriptorSet dependencies")
 	ErrInvalidFileDescriptorSet     = stderrors.New("invalid FileDescriptorSet")
 	ErrRegisterDescriptorFile       = stderrors.New("failed to register descriptor file")
+	ErrSnapshotNotFound             = stderrors.New("snapshot not found")
+	ErrInvalidSnapshot              = stderrors.New("invalid snapshot")
+	ErrUpstreamWithData             = stderrors.New("output.upstream cannot be combined with output.data or output.stream")
 
 	ErrMCPInvalidArgument = stderrors.New("mcp invalid argument")
 	ErrMCPToolNotFound    = stderrors.New("mcp tool not found")
@@ -178,3 +182,21 @@ func serviceNotRemovable(serviceID string) error {
 
 	return kindError{kind: ErrServiceNotRemovable, message: message}
 }
+
+func scenarioNotFound(name string) error {
+	return kindError{kind: ErrScenarioNotFound, message: fmt.Sprintf("scenario %s not found", name)}
+}
+
+func snapshotNotFound(id string) error {
+	return kindError{kind: ErrSnapshotNotFound, message: fmt.Sprintf("snapshot %s not found", id)}
+}
+
+func invalidSnapshotError(err error) error {
+	return kindError{kind: ErrInvalidSnapshot, cause: err, message: ErrInvalidSnapshot.Error() + ": " + err.Error()}
+}
+
+func unsupportedSnapshotVersion(version int) error {
+	message := fmt.Sprintf("%s: unsupported version %d", ErrInvalidSnapshot, version)
+
+	return kindError{kind: ErrInvalidSnapshot, message: message}
+}
@@ -6,7 +6,6 @@ import (
 	reflectionv1 "google.golang.org/grpc/reflection/grpc_reflection_v1"
 	reflectionv1alpha "google.golang.org/grpc/reflection/grpc_reflection_v1alpha"
 	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/reflect/protoregistry"
 
 	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
 	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
@@ -37,7 +36,7 @@ type gatewayReflection struct {
 }
 
 func newGatewayReflection(registry *descriptors.Registry) *gatewayReflection {
-	resolver := &dynamicDescriptorResolver{static: protoregistry.GlobalFiles, dynamic: registry}
+	resolver := &dynamicDescriptorResolver{dynamic: registry}
 	opts := reflection.ServerOptions{
 		Services:           &gatewayServiceInfoProvider{registry: registry},
 		DescriptorResolver: resolver,
@@ -53,8 +52,8 @@ func newGatewayReflection(registry *descriptors.Registry) *gatewayReflection {
 // gatewayServiceInfoProvider lists what the gateway can dispatch. The gRPC
 // server answers ListServices from grpc.Server.GetServiceInfo, but the gateways
 // route straight off the descriptors, so the service list has to be read from
-// there instead: runtime-compiled protos land in GlobalFiles, proxy-fetched
-// ones in the dynamic registry.
+// there instead: protos compiled at startup land in the startup files,
+// uploaded and proxy-fetched ones in the dynamic registry.
 type gatewayServiceInfoProvider struct {
 	registry *descriptors.Registry
 }
@@ -71,7 +70,7 @@ func (p *gatewayServiceInfoProvider) GetServiceInfo() map[string]grpc.ServiceInf
 		return true
 	}
 
-	protoregistry.GlobalFiles.RangeFiles(collect)
+	p.registry.Startup().RangeFiles(collect)
 
 	if p.registry != nil {
 		p.registry.RangeFiles(collect)
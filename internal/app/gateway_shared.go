@@ -21,28 +21,34 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/dynamicpb"
 
 	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
 	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/automock"
+	"github.com/bavix/gripmock/v3/internal/infra/drift"
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
+	"github.com/bavix/gripmock/v3/internal/infra/plugins"
 	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
 	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+	"github.com/bavix/gripmock/v3/internal/infra/requestcheck"
+	"github.com/bavix/gripmock/v3/internal/infra/script"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
 )
 
+// findMethodDescriptor looks in files first, as GRPCServer does, so the
+// gateways serve the same definition of a reloaded or uploaded method.
+//
 //nolint:ireturn
 func findMethodDescriptor(files *descriptors.Registry, serviceName, methodName string) (protoreflect.MethodDescriptor, error) {
-	if method := findMethodInGlobalFiles(serviceName, methodName); method != nil {
-		return method, nil
-	}
-
-	if files == nil {
-		return nil, &connectMethodNotFoundError{service: serviceName, method: methodName}
+	if files != nil {
+		if method := findMethodInFiles(files, serviceName, methodName); method != nil {
+			return method, nil
+		}
 	}
 
-	if method := findMethodInFiles(files, serviceName, methodName); method != nil {
+	if method := findMethodInFiles(files.Startup(), serviceName, methodName); method != nil {
 		return method, nil
 	}
 
@@ -51,7 +57,8 @@ func findMethodDescriptor(files *descriptors.Registry, serviceName, methodName s
 
 func recordCall(
 	recorder history.Recorder,
-	service, method, session string,
+	service, method string,
+	meta callMeta,
 	stubID uuid.UUID,
 	code uint32,
 	ts time.Time,
@@ -67,7 +74,6 @@ func recordCall(
 		StubID:          stubID,
 		Service:         service,
 		Method:          method,
-		Session:         session,
 		Code:            code,
 		Error:           errMsg,
 		ElapsedMS:       time.Since(ts).Milliseconds(),
@@ -76,6 +82,7 @@ func recordCall(
 		Responses:       responses,
 		ResponseHeaders: respHeaders,
 	}
+	meta.apply(&rec)
 
 	recordOwned(recorder, rec)
 }
@@ -145,6 +152,13 @@ type gatewayHandler struct {
 	templateEngine *template.Engine
 
 	typeResolver *protosetinfra.TypeResolver
+
+	hooks    *plugins.Hooks
+	scripts  *script.Runner
+	faults   *faults.Injector
+	requests *requestcheck.Validator
+	autoMock *automock.Synthesizer
+	drift    *drift.Store
 }
 
 func newGatewayHandler(
@@ -171,13 +185,28 @@ func newGatewayHandler(
 		errorFormatter: e,
 		reflection:     newGatewayReflection(descriptorRegistry),
 		templateEngine: engineOr(context.WithoutCancel(ctx), engines),
-		typeResolver: protosetinfra.NewTypeResolver(&dynamicDescriptorResolver{
-			static:  protoregistry.GlobalFiles,
-			dynamic: descriptorRegistry,
-		}),
+		typeResolver:   protosetinfra.NewTypeResolver(&dynamicDescriptorResolver{dynamic: descriptorRegistry}),
 	}
 }
 
+// SetHooks installs the plugin lifecycle hooks run for every mocked call.
+func (h *gatewayHandler) SetHooks(hooks *plugins.Hooks) { h.hooks = hooks }
+
+// SetScripts installs the runner for output scripts.
+func (h *gatewayHandler) SetScripts(scripts *script.Runner) { h.scripts = scripts }
+
+// SetFaults installs the fault injector shared with the gRPC server.
+func (h *gatewayHandler) SetFaults(injector *faults.Injector) { h.faults = injector }
+
+// SetRequestValidator installs the protovalidate check run before matching.
+func (h *gatewayHandler) SetRequestValidator(v *requestcheck.Validator) { h.requests = v }
+
+// SetAutoMock installs the synthesizer answering calls no stub matches.
+func (h *gatewayHandler) SetAutoMock(synth *automock.Synthesizer) { h.autoMock = synth }
+
+// SetDrift installs the store of shadow-mode comparisons.
+func (h *gatewayHandler) SetDrift(store *drift.Store) { h.drift = store }
+
 func (h *gatewayHandler) buildMocker(_ *http.Request, service, method, fullMethod string,
 	methodDesc protoreflect.MethodDescriptor,
 ) *grpcMocker {
@@ -194,6 +223,12 @@ func (h *gatewayHandler) buildMocker(_ *http.Request, service, method, fullMetho
 		typeResolver:       h.typeResolver,
 		proxies:            proxies,
 		validator:          h.validator,
+		hooks:              h.hooks,
+		scripts:            h.scripts,
+		faults:             h.faults,
+		requests:           h.requests,
+		autoMock:           h.autoMock,
+		drift:              h.drift,
 		fullServiceName:    service,
 		serviceName:        service,
 		methodName:         method,
@@ -202,13 +237,14 @@ func (h *gatewayHandler) buildMocker(_ *http.Request, service, method, fullMetho
 		outputDesc:         methodDesc.Output(),
 		serverStream:       methodDesc.IsStreamingServer(),
 		clientStream:       methodDesc.IsStreamingClient(),
-		strictServiceMatch: proxies != nil && proxies.RouteByMethod(fullMethod) != nil,
+		strictServiceMatch: proxies != nil && proxies.RouteByMethod(fullMethod, nil) != nil,
 	}
 }
 
 type withoutDescriptorResponse interface {
 	WriteError(w http.ResponseWriter, r *http.Request, code codes.Code, msg string)
 	WriteSuccess(w http.ResponseWriter, r *http.Request)
+	Transport() string
 }
 
 //nolint:funlen
@@ -221,6 +257,7 @@ func (h *gatewayHandler) handleWithoutDescriptor(
 
 	requestTime := time.Now()
 	emptyInput := map[string]any{}
+	meta := callMetaFromContext(gatewayCallContext(r, resp.Transport()))
 
 	query := stuber.Query{
 		Service: serviceName,
@@ -237,7 +274,7 @@ func (h *gatewayHandler) handleWithoutDescriptor(
 		}
 
 		notFoundMsg := h.errorFormatter.FormatStubNotFoundError(query, result).Error()
-		recordCall(h.recorder, serviceName, methodName, query.Session, uuid.Nil, uint32(codes.NotFound),
+		recordCall(h.recorder, serviceName, methodName, meta, uuid.Nil, uint32(codes.NotFound),
 			requestTime, []map[string]any{emptyInput}, nil, nil, notFoundMsg)
 		resp.WriteError(w, r, codes.NotFound, notFoundMsg)
 
@@ -251,7 +288,7 @@ func (h *gatewayHandler) handleWithoutDescriptor(
 
 	if err := delayTemplated(r.Context(), h.templateEngine, found.Output.Delay, td); err != nil {
 		st, _ := status.FromError(err)
-		recordCall(h.recorder, serviceName, methodName, query.Session, found.ID, uint32(st.Code()),
+		recordCall(h.recorder, serviceName, methodName, meta, found.ID, uint32(st.Code()),
 			requestTime, []map[string]any{emptyInput}, nil, nil, st.Message())
 		resp.WriteError(w, r, st.Code(), st.Message())
 
@@ -261,7 +298,7 @@ func (h *gatewayHandler) handleWithoutDescriptor(
 	outputToUse := found.Output
 
 	if st := outputStatusBase(outputToUse); st != nil {
-		recordCall(h.recorder, serviceName, methodName, query.Session, found.ID, uint32(st.Code()),
+		recordCall(h.recorder, serviceName, methodName, meta, found.ID, uint32(st.Code()),
 			requestTime, []map[string]any{emptyInput}, nil, nil, st.Message())
 		resp.WriteError(w, r, st.Code(), st.Message())
 
@@ -269,7 +306,7 @@ func (h *gatewayHandler) handleWithoutDescriptor(
 	}
 
 	if outputToUse.Data != nil {
-		recordCall(h.recorder, serviceName, methodName, query.Session, found.ID, uint32(codes.Unimplemented),
+		recordCall(h.recorder, serviceName, methodName, meta, found.ID, uint32(codes.Unimplemented),
 			requestTime, []map[string]any{emptyInput}, nil, nil,
 			"proto descriptor required to encode non-empty output for "+serviceName+"/"+methodName)
 		resp.WriteError(w, r, codes.Unimplemented,
@@ -284,7 +321,7 @@ func (h *gatewayHandler) handleWithoutDescriptor(
 
 	resp.WriteSuccess(w, r)
 
-	recordCall(h.recorder, serviceName, methodName, query.Session, found.ID, uint32(codes.OK),
+	recordCall(h.recorder, serviceName, methodName, meta, found.ID, uint32(codes.OK),
 		requestTime, []map[string]any{emptyInput}, []map[string]any{{}}, outputToUse.Headers, "")
 }
 
@@ -378,11 +415,11 @@ func normalizeFieldMaskJSON(data []byte, msg proto.Message) []byte {
 
 const anyTypeURLPrefix = "type.googleapis.com/"
 
-func serializeErrorStatus(st *status.Status) connectError {
+func serializeErrorStatus(st *status.Status, resolver *protosetinfra.TypeResolver) connectError {
 	sp := st.Proto()
 
 	details := make([]connectErrorDetail, 0, len(sp.GetDetails()))
-	debug := debugRenderedDetails(sp)
+	debug := debugRenderedDetails(sp, resolver)
 
 	for i, detail := range sp.GetDetails() {
 		entry := connectErrorDetail{
@@ -404,12 +441,12 @@ func serializeErrorStatus(st *status.Status) connectError {
 	}
 }
 
-func debugRenderedDetails(sp *spb.Status) []json.RawMessage {
+func debugRenderedDetails(sp *spb.Status, resolver *protosetinfra.TypeResolver) []json.RawMessage {
 	if len(sp.GetDetails()) == 0 {
 		return nil
 	}
 
-	statusData, err := protosetinfra.GlobalTypeResolver().Marshal(sp)
+	statusData, err := resolver.Marshal(sp)
 	if err != nil {
 		return nil
 	}
@@ -0,0 +1,90 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+)
+
+// synthesizes reports whether a call that no stub matched, failing with
+// notFound, is answered by auto-mock. A proxy that serves the miss wins.
+func (m *grpcMocker) synthesizes(ctx context.Context, notFound error) bool {
+	return m.autoMock.Covers(m.fullServiceName, m.methodName) && !m.proxyFallbackWillServe(ctx, notFound)
+}
+
+// synthesizeUnary makes up the response of a unary or client streaming call
+// and records it.
+func (m *grpcMocker) synthesizeUnary(ctx context.Context, requestTime time.Time, requests []map[string]any) *dynamicpb.Message {
+	msg := m.autoMock.Message(m.outputDesc)
+	m.recordSynthesized(ctx, requestTime, requests, msg)
+
+	return msg
+}
+
+// synthesizeStream sends the made-up responses of a server streaming call and
+// records them.
+func (m *grpcMocker) synthesizeStream(stream grpc.ServerStream, requestTime time.Time, requests []map[string]any) error {
+	sent := make([]*dynamicpb.Message, 0, m.autoMock.StreamCount())
+
+	for range m.autoMock.StreamCount() {
+		msg := m.autoMock.Message(m.outputDesc)
+		if err := sendStreamMessage(stream, msg); err != nil {
+			return err
+		}
+
+		sent = append(sent, msg)
+	}
+
+	m.recordSynthesized(stream.Context(), requestTime, requests, sent...)
+
+	return nil
+}
+
+// synthesizeBidi answers one message of a bidirectional stream. The stream is
+// recorded as a whole when it ends.
+func (m *grpcMocker) synthesizeBidi(stream grpc.ServerStream) error {
+	if rec, ok := stream.(*bidiRecordingStream); ok {
+		rec.synthesized = true
+	}
+
+	for range m.autoMock.StreamCount() {
+		if err := sendStreamMessage(stream, m.autoMock.Message(m.outputDesc)); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (m *grpcMocker) recordSynthesized(
+	ctx context.Context,
+	requestTime time.Time,
+	requests []map[string]any,
+	responses ...*dynamicpb.Message,
+) {
+	if m.recorder == nil {
+		return
+	}
+
+	rec := history.CallRecord{
+		Service:     m.fullServiceName,
+		Method:      m.methodName,
+		Requests:    requests,
+		Responses:   make([]map[string]any, len(responses)),
+		ElapsedMS:   time.Since(requestTime).Milliseconds(),
+		Timestamp:   requestTime,
+		Synthesized: true,
+	}
+
+	for i, msg := range responses {
+		rec.Responses[i] = m.convertToMap(msg)
+	}
+
+	callMetaFromContext(ctx).apply(&rec)
+
+	recordOwned(m.recorder, rec)
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/infra/automock"
+)
+
+func TestAutoMockAnswersUnaryMiss(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, nil)
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.Equal(t, codes.NotFound, status.Code(err))
+
+	mocker.autoMock = automock.New([]string{testServiceName + "/" + testMethodName}, 0)
+
+	resp, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.NoError(t, err)
+	require.NotZero(t, resp.Get(mocker.outputDesc.Fields().ByName("fields")).Map().Len())
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 2)
+	require.False(t, calls[0].Synthesized)
+	require.True(t, calls[1].Synthesized)
+	require.Equal(t, uint32(codes.OK), calls[1].Code)
+	require.Len(t, calls[1].Responses, 1)
+}
+
+func TestAutoMockAnswersServerStreamMiss(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, nil)
+	mocker.autoMock = automock.New([]string{testServiceName}, 4)
+
+	stream := &mockArrayStreamServerStream{ctx: t.Context()}
+	require.NoError(t, mocker.handleServerStream(stream))
+	require.Len(t, stream.sentMessages, 4)
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 1)
+	require.True(t, calls[0].Synthesized)
+	require.Len(t, calls[0].Responses, 4)
+}
+
+func TestAutoMockSkipsStubbedCalls(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, nil)
+	mocker.autoMock = automock.New([]string{automock.All}, 0)
+	stub := putHookStub(mocker)
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.NoError(t, err)
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 1)
+	require.False(t, calls[0].Synthesized)
+	require.Equal(t, stub.ID, calls[0].StubID)
+}
@@ -0,0 +1,138 @@
+package app
+
+import (
+	"context"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
+	protoloc "github.com/bavix/gripmock/v3/internal/domain/proto"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// serveItems builds a server from descFile answering every Get with output,
+// and returns a client connection to it along with its descriptor registry.
+func serveItems(
+	t *testing.T,
+	ctx context.Context,
+	descFile string,
+	output map[string]any,
+) (*grpc.ClientConn, *descriptors.Registry) {
+	t.Helper()
+
+	budgerigar := stuber.NewBudgerigar()
+	budgerigar.PutMany(&stuber.Stub{
+		Service: "reloadtest.Items",
+		Method:  "Get",
+		Input:   stuber.InputData{Equals: map[string]any{"id": "1"}},
+		Output:  stuber.Output{Data: output},
+	})
+
+	registry := descriptors.NewRegistry()
+	grpcServer := NewGRPCServer("tcp", "127.0.0.1:0", protoloc.New([]string{descFile}, nil, nil), budgerigar, nil, nil,
+		registry, nil, nil, false, 256, nil, nil, DefaultServerLimits())
+
+	server, err := grpcServer.Build(ctx)
+	require.NoError(t, err)
+
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() { _ = server.Serve(listener) }()
+
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return conn, registry
+}
+
+func getItem(t *testing.T, ctx context.Context, conn *grpc.ClientConn, method protoreflect.MethodDescriptor) *dynamicpb.Message {
+	t.Helper()
+
+	req := dynamicpb.NewMessage(method.Input())
+	req.Set(method.Input().Fields().ByName("id"), protoreflect.ValueOfString("1"))
+
+	resp := dynamicpb.NewMessage(method.Output())
+	require.NoError(t, conn.Invoke(ctx, "/reloadtest.Items/Get", req, resp))
+
+	return resp
+}
+
+func TestServersKeepTheirOwnVersionOfAFile(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	dir := t.TempDir()
+	v1 := filepath.Join(dir, "v1.pb")
+	v2 := filepath.Join(dir, "v2.pb")
+
+	writeReloadSet(t, v1, "id")
+	writeReloadSet(t, v2, "id", "name")
+
+	_, oldRegistry := serveItems(t, ctx, v1, map[string]any{"id": "1"})
+	conn, registry := serveItems(t, ctx, v2, map[string]any{"id": "1", "name": "widget"})
+
+	oldMethod, err := findMethodDescriptor(oldRegistry, "reloadtest.Items", "Get")
+	require.NoError(t, err)
+	require.Nil(t, oldMethod.Output().Fields().ByName("name"))
+
+	method, err := findMethodDescriptor(registry, "reloadtest.Items", "Get")
+	require.NoError(t, err)
+	require.NotNil(t, method.Output().Fields().ByName("name"),
+		"the second server is not shadowed by the file the first one registered")
+
+	resp := getItem(t, ctx, conn, method)
+	require.Equal(t, "widget", resp.Get(method.Output().Fields().ByName("name")).String())
+}
+
+func TestUploadedDescriptorReplacesStartupVersion(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	dir := t.TempDir()
+	descFile := filepath.Join(dir, "items.pb")
+	writeReloadSet(t, descFile, "id")
+
+	conn, registry := serveItems(t, ctx, descFile, map[string]any{"id": "1", "name": "widget"})
+
+	uploaded := filepath.Join(dir, "uploaded.pb")
+	writeReloadSet(t, uploaded, "id", "name")
+
+	data, err := os.ReadFile(uploaded)
+	require.NoError(t, err)
+
+	var fds descriptorpb.FileDescriptorSet
+	require.NoError(t, proto.Unmarshal(data, &fds))
+
+	file, err := protodesc.NewFile(fds.GetFile()[0], registry.Startup())
+	require.NoError(t, err)
+
+	registry.Register(file)
+
+	method, err := findMethodDescriptor(registry, "reloadtest.Items", "Get")
+	require.NoError(t, err)
+
+	resp := getItem(t, ctx, conn, method)
+	require.Equal(t, "widget", resp.Get(method.Output().Fields().ByName("name")).String(),
+		"the method registered at startup answers with the uploaded definition")
+}
@@ -0,0 +1,51 @@
+package app
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	"github.com/bavix/gripmock/v3/internal/infra/types"
+)
+
+// faultPlan rolls the faults of a call matched to found that sends messages
+// responses; nil when nothing is injected.
+func (m *grpcMocker) faultPlan(found *stuber.Stub, messages int) *faults.Plan {
+	return m.faults.Plan(found.Faults, messages)
+}
+
+// injectFaults waits out the injected latency and returns the reset or error
+// rolled for the first response. Record the call, then pass the error through
+// resetOn.
+func (m *grpcMocker) injectFaults(ctx context.Context, plan *faults.Plan) error {
+	if plan == nil {
+		return nil
+	}
+
+	if err := delayResponse(ctx, types.Duration(plan.Delay)); err != nil {
+		return err
+	}
+
+	if plan.ResetBefore(0) {
+		return faults.ErrReset
+	}
+
+	return plan.Err
+}
+
+// resetOn cuts the call short when err is an injected reset, and returns err.
+// HTTP gateways abort the handler, which resets the stream with RST_STREAM on
+// HTTP/2 and closes the connection on HTTP/1. grpc-go cannot reset a single
+// stream from a handler, so native gRPC ends it with ErrReset instead; other
+// calls multiplexed on the connection are never touched.
+func (m *grpcMocker) resetOn(ctx context.Context, err error) error {
+	if errors.Is(err, faults.ErrReset) && callTransport(ctx) != history.TransportGRPC {
+		panic(http.ErrAbortHandler)
+	}
+
+	return err
+}
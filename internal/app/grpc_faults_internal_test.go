@@ -0,0 +1,195 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	"github.com/bavix/gripmock/v3/internal/infra/types"
+)
+
+func TestFaultsFailUnaryCall(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, nil)
+	mocker.faults = faults.New(1)
+
+	stub := putHookStub(mocker)
+	stub.Faults = &stuber.Faults{
+		Error:   &stuber.FaultError{Code: codes.Unavailable, Message: "flaky"},
+		Latency: &stuber.FaultLatency{Min: types.Duration(10 * time.Millisecond)},
+	}
+
+	start := time.Now()
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.GreaterOrEqual(t, time.Since(start), 10*time.Millisecond)
+	require.Equal(t, codes.Unavailable, status.Code(err))
+	require.Equal(t, "flaky", status.Convert(err).Message())
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 1)
+	require.Equal(t, uint32(codes.Unavailable), calls[0].Code)
+	require.Equal(t, stub.ID, calls[0].StubID)
+}
+
+func TestFaultsGlobalResetUnaryCall(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, nil)
+	mocker.faults = faults.New(1)
+	mocker.faults.SetGlobal(&stuber.Faults{Reset: 1})
+
+	putHookStub(mocker)
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.ErrorIs(t, err, faults.ErrReset)
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 1)
+	require.Equal(t, uint32(codes.Unavailable), calls[0].Code)
+}
+
+func TestFaultsArrayStream(t *testing.T) {
+	t.Parallel()
+
+	mocker := createTestMocker(t)
+	stub := &stuber.Stub{
+		ID:      uuid.New(),
+		Service: "TestService",
+		Method:  "TestMethod",
+		Output: stuber.Output{Stream: []any{
+			map[string]any{"value": "message1"},
+			map[string]any{"value": "message2"},
+			map[string]any{"value": "message3"},
+		}},
+	}
+	input := dynamicpb.NewMessage(mocker.inputDesc)
+
+	stream := &mockArrayStreamServerStream{ctx: t.Context()}
+	sent, err := mocker.handleArrayStreamData(stream, stub, input, time.Now(), 1, &faults.Plan{ResetAt: 2})
+	require.ErrorIs(t, err, faults.ErrReset)
+	require.Len(t, sent, 2)
+	require.Len(t, stream.sentMessages, 2)
+
+	stream = &mockArrayStreamServerStream{ctx: t.Context()}
+	sent, err = mocker.handleArrayStreamData(stream, stub, input, time.Now(), 1, faults.New(1).Plan(&stuber.Faults{Drop: 1}, 3))
+	require.NoError(t, err)
+	require.Empty(t, sent)
+	require.Empty(t, stream.sentMessages)
+}
+
+func newFaultBidiMocker(t *testing.T, output stuber.Output, profile *stuber.Faults) (*grpcMocker, *mockFullServerStream) {
+	t.Helper()
+
+	mocker := newHookedMocker(t, nil)
+	mocker.clientStream = true
+	mocker.serverStream = true
+	mocker.faults = faults.New(1)
+	mocker.faults.SetGlobal(profile)
+
+	mocker.budgerigar.PutMany(&stuber.Stub{
+		ID:      uuid.New(),
+		Service: testServiceName,
+		Method:  testMethodName,
+		Input:   stuber.InputData{Contains: map[string]any{}},
+		Output:  output,
+	})
+
+	return mocker, createTestStream(t, mocker)
+}
+
+func TestFaultsBidiStream(t *testing.T) {
+	t.Parallel()
+
+	output := stuber.Output{Stream: []any{
+		map[string]any{"value": "message1"},
+		map[string]any{"value": "message2"},
+	}}
+
+	mocker, stream := newFaultBidiMocker(t, output, &stuber.Faults{Reset: 1})
+	require.ErrorIs(t, mocker.handleBidiStream(stream), faults.ErrReset)
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 1)
+	require.Equal(t, uint32(codes.Unavailable), calls[0].Code)
+
+	mocker, stream = newFaultBidiMocker(t, output, &stuber.Faults{
+		Error: &stuber.FaultError{Code: codes.ResourceExhausted},
+	})
+	err := mocker.handleBidiStream(stream)
+	require.Equal(t, codes.ResourceExhausted, status.Code(err))
+	require.Equal(t, faults.DefaultErrorMessage, status.Convert(err).Message())
+	require.Empty(t, stream.sentMessages)
+
+	mocker, stream = newFaultBidiMocker(t, output, &stuber.Faults{Drop: 1})
+	require.NoError(t, mocker.handleBidiStream(stream))
+	require.Empty(t, stream.sentMessages)
+}
+
+func TestRestFaultProfile(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	server.SetFaultProfile(w, scenarioRequest(t, http.MethodPut, "/api/faults", "",
+		`{"seed":42,"error":{"probability":0.5,"code":14},"latency":{"distribution":"percentiles","percentiles":{"p50":"20ms"}}}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var profile rest.FaultProfile
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &profile))
+	require.Equal(t, uint64(42), *profile.Seed)
+	require.Equal(t, codes.Unavailable, profile.Error.Code)
+	require.Equal(t, rest.Percentiles, *profile.Latency.Distribution)
+	require.Equal(t, types.Duration(20*time.Millisecond), profile.Latency.Percentiles["p50"])
+	require.Equal(t, uint64(42), server.faults.Seed())
+	require.NotNil(t, server.faults.Global())
+
+	w = httptest.NewRecorder()
+	server.SetFaultProfile(w, scenarioRequest(t, http.MethodPut, "/api/faults", "", `{"reset":2}`))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.NotNil(t, server.faults.Global().Error)
+
+	w = httptest.NewRecorder()
+	server.ClearFaultProfile(w, scenarioRequest(t, http.MethodDelete, "/api/faults", "", ""))
+	require.Equal(t, http.StatusNoContent, w.Code)
+
+	w = httptest.NewRecorder()
+	server.GetFaultProfile(w, scenarioRequest(t, http.MethodGet, "/api/faults", "", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"seed":42}`, w.Body.String())
+}
+
+func TestValidateStubRejectsBadFaults(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	stub := &stuber.Stub{
+		Service: testServiceName,
+		Method:  testMethodName,
+		Input:   stuber.InputData{Contains: map[string]any{}},
+		Output:  stuber.Output{Data: map[string]any{}},
+		Faults:  &stuber.Faults{Drop: 1.5},
+	}
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, server.validateStub(stub), &validationErr)
+	require.Equal(t, "faults", validationErr.Field)
+
+	stub.Faults.Drop = 0.5
+	require.NoError(t, server.validateStub(stub))
+}
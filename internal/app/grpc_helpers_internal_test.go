@@ -13,6 +13,7 @@ import (
 	"google.golang.org/protobuf/types/known/structpb"
 	"google.golang.org/protobuf/types/known/wrapperspb"
 
+	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
 	"github.com/bavix/gripmock/v3/pkg/plugintest"
@@ -155,20 +156,20 @@ func TestProtoToJSON(t *testing.T) {
 	t.Run("nil message", func(t *testing.T) {
 		t.Parallel()
 
-		require.Nil(t, protoToJSON(nil))
+		require.Nil(t, protoToJSON(nil, protosetinfra.GlobalTypeResolver()))
 	})
 
 	t.Run("non proto message", func(t *testing.T) {
 		t.Parallel()
 
-		require.Nil(t, protoToJSON("not a proto"))
+		require.Nil(t, protoToJSON("not a proto", protosetinfra.GlobalTypeResolver()))
 	})
 
 	t.Run("valid proto message", func(t *testing.T) {
 		t.Parallel()
 
 		msg := wrapperspb.String("hello")
-		got := protoToJSON(msg)
+		got := protoToJSON(msg, protosetinfra.GlobalTypeResolver())
 		require.NotNil(t, got)
 		require.Contains(t, string(got), "hello")
 	})
@@ -225,21 +226,21 @@ func TestToLogArray(t *testing.T) {
 	t.Run("empty", func(t *testing.T) {
 		t.Parallel()
 
-		arr := toLogArray()
+		arr := toLogArray(protosetinfra.GlobalTypeResolver())
 		require.NotNil(t, arr)
 	})
 
 	t.Run("with values", func(t *testing.T) {
 		t.Parallel()
 
-		arr := toLogArray("a", 1, true)
+		arr := toLogArray(protosetinfra.GlobalTypeResolver(), "a", 1, true)
 		require.NotNil(t, arr)
 	})
 
 	t.Run("skips nil", func(t *testing.T) {
 		t.Parallel()
 
-		arr := toLogArray("a", nil, 1)
+		arr := toLogArray(protosetinfra.GlobalTypeResolver(), "a", nil, 1)
 		require.NotNil(t, arr)
 	})
 }
@@ -0,0 +1,197 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+)
+
+// hook runs the plugin hooks of group for a call matched with query. It
+// returns a nil event when no hook is registered for group, so callers skip
+// all hook work.
+func (m *grpcMocker) hook(
+	ctx context.Context,
+	group string,
+	query stuber.Query,
+	stub *stuber.Stub,
+) (*pkgplugins.HookEvent, error) {
+	if !m.hooks.Has(group) {
+		return nil, nil //nolint:nilnil
+	}
+
+	return m.runHook(ctx, m.hookEvent(ctx, group, query, stub))
+}
+
+func (m *grpcMocker) hookEvent(
+	ctx context.Context,
+	group string,
+	query stuber.Query,
+	stub *stuber.Stub,
+) *pkgplugins.HookEvent {
+	event := &pkgplugins.HookEvent{
+		Hook:      group,
+		Transport: callTransport(ctx),
+		Service:   m.fullServiceName,
+		Method:    m.methodName,
+		Session:   query.Session,
+		Headers:   query.Headers,
+		Requests:  query.Input,
+	}
+
+	if stub != nil {
+		event.Stub = hookStub(stub)
+	}
+
+	return event
+}
+
+func (m *grpcMocker) runHook(ctx context.Context, event *pkgplugins.HookEvent) (*pkgplugins.HookEvent, error) {
+	event, err := m.hooks.Run(ctx, event)
+	if err != nil {
+		return nil, handlerStatusError(err)
+	}
+
+	return event, nil
+}
+
+// beforeMatch lets before-match hooks rewrite query. It returns the response a
+// hook answered with, if any.
+func (m *grpcMocker) beforeMatch(ctx context.Context, query *stuber.Query) (*pkgplugins.HookResponse, error) {
+	event, err := m.hook(ctx, pkgplugins.HookBeforeMatch, *query, nil)
+	if err != nil || event == nil {
+		return nil, err
+	}
+
+	if len(event.Requests) > 0 {
+		query.Input = event.Requests
+	}
+
+	query.Headers = event.Headers
+
+	return event.Response, nil
+}
+
+// hookAnswer runs group and returns the response a hook answered with, if any.
+func (m *grpcMocker) hookAnswer(
+	ctx context.Context,
+	group string,
+	query stuber.Query,
+	stub *stuber.Stub,
+) (*pkgplugins.HookResponse, error) {
+	event, err := m.hook(ctx, group, query, stub)
+	if err != nil || event == nil {
+		return nil, err
+	}
+
+	return event.Response, nil
+}
+
+// beforeResponse lets before-response hooks change the rendered output.
+func (m *grpcMocker) beforeResponse(
+	ctx context.Context,
+	query stuber.Query,
+	stub *stuber.Stub,
+	output *stuber.Output,
+	data *any,
+) error {
+	if !m.hooks.Has(pkgplugins.HookBeforeResponse) {
+		return nil
+	}
+
+	event := m.hookEvent(ctx, pkgplugins.HookBeforeResponse, query, stub)
+	event.Response = hookResponse(*output, *data)
+
+	event, err := m.runHook(ctx, event)
+	if err != nil {
+		return err
+	}
+
+	applyHookResponse(event.Response, output, data)
+
+	return nil
+}
+
+// rejectCall records a call refused by a hook or an injected fault and
+// returns the refusal.
+func (m *grpcMocker) rejectCall(
+	ctx context.Context,
+	stubID uuid.UUID,
+	requestTime time.Time,
+	requests []map[string]any,
+	err error,
+) error {
+	m.recordCall(ctx, stubID, uint32(status.Code(err)), requestTime, requests, nil, nil, err.Error())
+
+	return err
+}
+
+// answerFromHook sends the response a hook answered a unary call with.
+func (m *grpcMocker) answerFromHook(
+	ctx context.Context,
+	stream grpc.ServerStream,
+	resp *pkgplugins.HookResponse,
+	requests []map[string]any,
+	requestTime time.Time,
+) (*dynamicpb.Message, error) {
+	var (
+		output stuber.Output
+		data   any
+	)
+
+	applyHookResponse(resp, &output, &data)
+
+	if err := m.setResponseHeadersAny(ctx, stream, output.Headers); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	m.setResponseTrailersAny(ctx, stream, output.Trailers)
+
+	if err := m.handleOutputError(ctx, stream, output); err != nil {
+		m.recordCall(ctx, uuid.Nil, uint32(status.Code(err)), requestTime, requests, nil, recordedMetadata(output), err.Error())
+
+		return nil, err
+	}
+
+	msg, err := m.newOutputMessage(data)
+	if err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
+	m.recordCall(ctx, uuid.Nil, uint32(codes.OK), requestTime, requests, []any{data}, recordedMetadata(output), "")
+
+	return msg, nil
+}
+
+// sendHookAnswer answers a streaming call with the single message a hook
+// responded with.
+func (m *grpcMocker) sendHookAnswer(
+	stream grpc.ServerStream,
+	resp *pkgplugins.HookResponse,
+	requests []map[string]any,
+	requestTime time.Time,
+) error {
+	msg, err := m.answerFromHook(stream.Context(), stream, resp, requests, requestTime)
+	if err != nil {
+		return err
+	}
+
+	return stream.SendMsg(msg) //nolint:wrapcheck
+}
+
+// callTransport is the protocol a call arrived over.
+func callTransport(ctx context.Context) string {
+	if origin, ok := ctx.Value(callOriginKey{}).(callOrigin); ok {
+		return origin.transport
+	}
+
+	return history.TransportGRPC
+}
@@ -0,0 +1,267 @@
+package app
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/plugins"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+)
+
+func newTestHooks(t *testing.T, specs ...pkgplugins.FuncSpec) *plugins.Hooks {
+	t.Helper()
+
+	reg := plugins.NewRegistry()
+	reg.AddPlugin(pkgplugins.PluginInfo{Name: "hooks"}, []pkgplugins.SpecProvider{pkgplugins.Specs(specs...)})
+
+	hooks := plugins.NewHooks(reg)
+	require.NotNil(t, hooks)
+
+	return hooks
+}
+
+func newHookedMocker(t *testing.T, hooks *plugins.Hooks) *grpcMocker {
+	t.Helper()
+
+	mocker := createTestMockerWithRecorder(t)
+	mocker.fullMethod = testServiceName + "/" + testMethodName
+	mocker.fullServiceName = testServiceName
+	mocker.serviceName = testServiceName
+	mocker.methodName = testMethodName
+	mocker.hooks = hooks
+
+	return mocker
+}
+
+func putHookStub(mocker *grpcMocker) *stuber.Stub {
+	stub := &stuber.Stub{
+		ID:      uuid.New(),
+		Service: testServiceName,
+		Method:  testMethodName,
+		Input:   stuber.InputData{Contains: map[string]any{}},
+		Output:  stuber.Output{Data: map[string]any{"result": "stub"}},
+	}
+	mocker.budgerigar.PutMany(stub)
+
+	return stub
+}
+
+func hookCalls(t *testing.T, mocker *grpcMocker) []history.CallRecord {
+	t.Helper()
+
+	recorder, ok := mocker.recorder.(*history.MemoryStore)
+	require.True(t, ok, testRecorderShouldBeMemoryStore)
+
+	return recorder.Filter(history.FilterOpts{})
+}
+
+func TestHooksBeforeMatchRejects(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, newTestHooks(t, pkgplugins.FuncSpec{
+		Name:  "auth",
+		Group: pkgplugins.HookBeforeMatch,
+		Fn: pkgplugins.HookFunc(func(_ context.Context, event *pkgplugins.HookEvent) error {
+			if event.Headers["authorization"] == nil {
+				return status.Error(codes.Unauthenticated, "missing token")
+			}
+
+			return nil
+		}),
+	}))
+	putHookStub(mocker)
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	ctx := metadata.NewIncomingContext(t.Context(), metadata.Pairs("authorization", "Bearer x"))
+	_, err = mocker.handleUnary(ctx, nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.NoError(t, err)
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 2)
+	require.Equal(t, uint32(codes.Unauthenticated), calls[0].Code)
+	require.Equal(t, uuid.Nil, calls[0].StubID)
+	require.Equal(t, uint32(codes.OK), calls[1].Code)
+}
+
+func TestHooksBeforeResponsePatchesOutput(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, newTestHooks(t, pkgplugins.FuncSpec{
+		Name:  "patch",
+		Group: pkgplugins.HookBeforeResponse,
+		Fn: pkgplugins.HookFunc(func(_ context.Context, event *pkgplugins.HookEvent) error {
+			data, _ := event.Response.Data.(map[string]any)
+			data["result"] = "patched"
+
+			return nil
+		}),
+	}))
+	stub := putHookStub(mocker)
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.NoError(t, err)
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 1)
+	require.Equal(t, "patched", calls[0].Responses[0]["result"])
+	require.Equal(t, "stub", stub.Output.Data.(map[string]any)["result"], "the stored stub must not change")
+}
+
+func TestHooksAfterMatchSeesStub(t *testing.T) {
+	t.Parallel()
+
+	var seen string
+
+	mocker := newHookedMocker(t, newTestHooks(t, pkgplugins.FuncSpec{
+		Name:  "deny",
+		Group: pkgplugins.HookAfterMatch,
+		Fn: pkgplugins.HookFunc(func(_ context.Context, event *pkgplugins.HookEvent) error {
+			seen, _ = event.Stub["id"].(string)
+
+			return status.Error(codes.PermissionDenied, "denied")
+		}),
+	}))
+	stub := putHookStub(mocker)
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+	require.Equal(t, stub.ID.String(), seen)
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 1)
+	require.Equal(t, stub.ID, calls[0].StubID)
+}
+
+func TestHooksOnMissAnswers(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, newTestHooks(t, pkgplugins.FuncSpec{
+		Name:  "fallback",
+		Group: pkgplugins.HookOnMiss,
+		Fn: pkgplugins.HookFunc(func(_ context.Context, event *pkgplugins.HookEvent) error {
+			event.Response = &pkgplugins.HookResponse{Data: map[string]any{"result": "fallback"}}
+
+			return nil
+		}),
+	}))
+
+	resp, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.NoError(t, err)
+	require.NotNil(t, resp)
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 1)
+	require.Equal(t, "fallback", calls[0].Responses[0]["result"])
+}
+
+func TestHooksOnStubUpsert(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	server.SetHooks(newTestHooks(t, pkgplugins.FuncSpec{
+		Name:  "tag",
+		Group: pkgplugins.HookOnStubUpsert,
+		Fn: pkgplugins.HookFunc(func(_ context.Context, event *pkgplugins.HookEvent) error {
+			if event.Service == "blocked.Service" {
+				return status.Error(codes.PermissionDenied, "blocked")
+			}
+
+			event.Stub["priority"] = 7
+
+			return nil
+		}),
+	}))
+
+	stub := &stuber.Stub{
+		Service: testServiceName,
+		Method:  testMethodName,
+		Session: "s1",
+		Source:  stuber.SourceRest,
+		Input:   stuber.InputData{Equals: map[string]any{"id": 1}},
+		Output:  stuber.Output{Data: map[string]any{"ok": true}},
+	}
+	require.NoError(t, server.applyUpsertHooks(t.Context(), history.TransportHTTP, []*stuber.Stub{stub}))
+	require.Equal(t, 7, stub.Priority)
+	require.Equal(t, "s1", stub.Session)
+	require.Equal(t, stuber.SourceRest, stub.Source)
+	require.Contains(t, stub.Input.Equals, "id")
+
+	blocked := &stuber.Stub{Service: "blocked.Service", Method: testMethodName}
+	err = server.applyUpsertHooks(t.Context(), history.TransportHTTP, []*stuber.Stub{blocked})
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestHooksOnStubUpsertSeesMCPRequestContext(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	server.SetHooks(newTestHooks(t, pkgplugins.FuncSpec{
+		Name:  "cancelled",
+		Group: pkgplugins.HookOnStubUpsert,
+		Fn: pkgplugins.HookFunc(func(ctx context.Context, _ *pkgplugins.HookEvent) error {
+			return ctx.Err()
+		}),
+	}))
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err = mcpStubsUpsert(ctx, server, map[string]any{
+		"stubs": []any{map[string]any{
+			"service": testServiceName,
+			"method":  testMethodName,
+			"input":   map[string]any{"equals": map[string]any{"id": 1}},
+			"output":  map[string]any{"data": map[string]any{"ok": true}},
+		}},
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	require.Empty(t, server.budgerigar.All())
+}
+
+func TestHooksBeforeMatchHeadersReachClientStreamResponse(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, newTestHooks(t, pkgplugins.FuncSpec{
+		Name:  "tenant",
+		Group: pkgplugins.HookBeforeMatch,
+		Fn: pkgplugins.HookFunc(func(_ context.Context, event *pkgplugins.HookEvent) error {
+			event.Headers["x-tenant"] = "acme"
+
+			return nil
+		}),
+	}))
+	mocker.budgerigar.PutMany(&stuber.Stub{
+		ID:      uuid.New(),
+		Service: testServiceName,
+		Method:  testMethodName,
+		Inputs:  []stuber.InputData{{Contains: map[string]any{}}},
+		Output: stuber.Output{
+			Data:    map[string]any{"result": "stub"},
+			Headers: map[string]string{"x-tenant": `{{index .Headers "x-tenant"}}`},
+		},
+	})
+
+	stream := &mockFullServerStream{
+		ctx:              metadata.NewIncomingContext(t.Context(), metadata.Pairs("x-request", "1")),
+		receivedMessages: []*dynamicpb.Message{dynamicpb.NewMessage(mocker.inputDesc)},
+		recvMsgLimit:     1,
+	}
+	require.NoError(t, mocker.handleClientStream(stream))
+	require.Equal(t, []string{"acme"}, stream.headers.Get("x-tenant"),
+		"the response is rendered from the headers the hook rewrote")
+}
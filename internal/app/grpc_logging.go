@@ -18,74 +18,80 @@ import (
 	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
 )
 
-// LogUnaryInterceptor logs unary gRPC calls.
-func LogUnaryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
-	start := time.Now()
-	resp, err := handler(ctx, req)
+// LogUnaryInterceptor logs unary gRPC calls, encoding their messages through
+// resolver.
+func LogUnaryInterceptor(resolver *protosetinfra.TypeResolver) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		grpcPeer, _ := peer.FromContext(ctx)
+		service, method := splitMethodName(info.FullMethod)
+
+		level := zerolog.InfoLevel
+		if service == serviceReflection {
+			level = zerolog.DebugLevel
+		}
 
-	grpcPeer, _ := peer.FromContext(ctx)
-	service, method := splitMethodName(info.FullMethod)
+		event := zerolog.Ctx(ctx).WithLevel(level).
+			Str("grpc.component", "server").
+			Str("grpc.method", method).
+			Str("grpc.method_type", "unary").
+			Str("grpc.service", service).
+			Str("grpc.code", status.Code(err).String()).
+			Dur("grpc.time_ms", time.Since(start)).
+			Str("peer.address", getPeerAddress(grpcPeer)).
+			Str("protocol", "grpc")
+
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			event.Interface("grpc.metadata", md)
+		}
 
-	level := zerolog.InfoLevel
-	if service == serviceReflection {
-		level = zerolog.DebugLevel
-	}
+		if content := protoToJSON(req, resolver); content != nil {
+			event.RawJSON("grpc.request.content", content)
+		}
 
-	event := zerolog.Ctx(ctx).WithLevel(level).
-		Str("grpc.component", "server").
-		Str("grpc.method", method).
-		Str("grpc.method_type", "unary").
-		Str("grpc.service", service).
-		Str("grpc.code", status.Code(err).String()).
-		Dur("grpc.time_ms", time.Since(start)).
-		Str("peer.address", getPeerAddress(grpcPeer)).
-		Str("protocol", "grpc")
+		if content := protoToJSON(resp, resolver); content != nil {
+			event.RawJSON("grpc.response.content", content)
+		}
 
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		event.Interface("grpc.metadata", md)
-	}
+		event.Msg("gRPC call completed")
 
-	if content := protoToJSON(req); content != nil {
-		event.RawJSON("grpc.request.content", content)
+		return resp, err
 	}
+}
 
-	if content := protoToJSON(resp); content != nil {
-		event.RawJSON("grpc.response.content", content)
-	}
+// LogStreamInterceptor logs streaming gRPC calls, encoding their messages
+// through resolver.
+func LogStreamInterceptor(resolver *protosetinfra.TypeResolver) grpc.StreamServerInterceptor {
+	return func(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		start := time.Now()
+		grpcPeer, _ := peer.FromContext(stream.Context())
+		service, method := splitMethodName(info.FullMethod)
 
-	event.Msg("gRPC call completed")
+		wrapped := &loggingStream{stream, []any{}, []any{}}
+		err := handler(srv, wrapped)
 
-	return resp, err
-}
+		level := zerolog.InfoLevel
+		if service == serviceReflection {
+			level = zerolog.DebugLevel
+		}
 
-// LogStreamInterceptor logs streaming gRPC calls.
-func LogStreamInterceptor(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-	start := time.Now()
-	grpcPeer, _ := peer.FromContext(stream.Context())
-	service, method := splitMethodName(info.FullMethod)
-
-	wrapped := &loggingStream{stream, []any{}, []any{}}
-	err := handler(srv, wrapped)
-
-	level := zerolog.InfoLevel
-	if service == serviceReflection {
-		level = zerolog.DebugLevel
-	}
-
-	zerolog.Ctx(stream.Context()).WithLevel(level).
-		Str("grpc.component", "server").
-		Str("grpc.method", method).
-		Str("grpc.method_type", "stream").
-		Str("grpc.service", service).
-		Str("grpc.code", status.Code(err).String()).
-		Dur("grpc.time_ms", time.Since(start)).
-		Str("peer.address", getPeerAddress(grpcPeer)).
-		Array("grpc.request.content", toLogArray(wrapped.requests...)).
-		Array("grpc.response.content", toLogArray(wrapped.responses...)).
-		Str("protocol", "grpc").
-		Msg("gRPC call completed")
-
-	return err
+		zerolog.Ctx(stream.Context()).WithLevel(level).
+			Str("grpc.component", "server").
+			Str("grpc.method", method).
+			Str("grpc.method_type", "stream").
+			Str("grpc.service", service).
+			Str("grpc.code", status.Code(err).String()).
+			Dur("grpc.time_ms", time.Since(start)).
+			Str("peer.address", getPeerAddress(grpcPeer)).
+			Array("grpc.request.content", toLogArray(resolver, wrapped.requests...)).
+			Array("grpc.response.content", toLogArray(resolver, wrapped.responses...)).
+			Str("protocol", "grpc").
+			Msg("gRPC call completed")
+
+		return err
+	}
 }
 
 func splitMethodName(fullMethod string) (string, string) {
@@ -109,7 +115,7 @@ func getPeerAddress(p *peer.Peer) string {
 	return unknownValue
 }
 
-func protoToJSON(msg any) []byte {
+func protoToJSON(msg any, resolver *protosetinfra.TypeResolver) []byte {
 	if msg == nil || isNilInterface(msg) {
 		return nil
 	}
@@ -119,7 +125,7 @@ func protoToJSON(msg any) []byte {
 		return nil
 	}
 
-	data, err := protosetinfra.GlobalTypeResolver().MarshalProtoNames(message)
+	data, err := resolver.MarshalProtoNames(message)
 	if err != nil {
 		return nil
 	}
@@ -127,8 +133,8 @@ func protoToJSON(msg any) []byte {
 	return data
 }
 
-func protoToMap(msg any) map[string]any {
-	data := protoToJSON(msg)
+func protoToMap(msg any, resolver *protosetinfra.TypeResolver) map[string]any {
+	data := protoToJSON(msg, resolver)
 	if data == nil {
 		return nil
 	}
@@ -156,7 +162,7 @@ func isNilInterface(v any) bool {
 	}
 }
 
-func toLogArray(items ...any) *zerolog.Array {
+func toLogArray(resolver *protosetinfra.TypeResolver, items ...any) *zerolog.Array {
 	arr := zerolog.Arr()
 
 	for _, item := range items {
@@ -164,7 +170,7 @@ func toLogArray(items ...any) *zerolog.Array {
 			continue
 		}
 
-		if value := protoToJSON(item); value != nil {
+		if value := protoToJSON(item, resolver); value != nil {
 			arr = arr.RawJSON(value)
 		} else {
 			arr = arr.Str(fmt.Sprintf("%v", item))
@@ -14,6 +14,7 @@ import (
 	"google.golang.org/protobuf/types/dynamicpb"
 
 	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
 )
@@ -34,8 +35,10 @@ func (m *grpcMocker) handleBidiStream(stream grpc.ServerStream) error {
 		}
 	}
 
+	// With no stubs for the method at all, auto-mock answers every message and
+	// bidiResult stays nil.
 	bidiResult, err := m.budgerigar.FindByQueryBidi(queryBidi)
-	if err != nil {
+	if err != nil && !m.synthesizes(stream.Context(), status.Error(codes.NotFound, err.Error())) {
 		query := stuber.Query{
 			Service: m.fullServiceName,
 			Method:  m.methodName,
@@ -53,6 +56,7 @@ func (m *grpcMocker) handleBidiStream(stream grpc.ServerStream) error {
 
 	recordingStream := &bidiRecordingStream{
 		ServerStream:  stream,
+		resolver:      m.typeResolver,
 		requests:      make([]map[string]any, 0, bidiRecordingStreamInitCap),
 		responses:     make([]map[string]any, 0, bidiRecordingStreamResponsesCap),
 		maxItems:      maxHistoryStreamMsgs,
@@ -88,7 +92,7 @@ func (m *grpcMocker) handleBidiStream(stream grpc.ServerStream) error {
 		if err := m.processBidiStreamMessage(recordingStream, bidiResult, inputMsg); err != nil {
 			m.recordBidiStreamUnlessProxied(recordingStream, bidiResult, requestTime, err)
 
-			return err
+			return m.resetOn(stream.Context(), err)
 		}
 	}
 }
@@ -98,6 +102,14 @@ func (m *grpcMocker) processBidiStreamMessage(
 	bidiResult *stuber.BidiResult,
 	inputMsg *dynamicpb.Message,
 ) error {
+	if err := m.validateRequests(inputMsg); err != nil {
+		return err
+	}
+
+	if bidiResult == nil {
+		return m.synthesizeBidi(stream)
+	}
+
 	requestTime := time.Now()
 	inputMap := m.convertToMap(inputMsg)
 
@@ -105,6 +117,10 @@ func (m *grpcMocker) processBidiStreamMessage(
 	if err != nil {
 		wrappedErr := errors.Wrap(err, "failed to process bidirectional message")
 		if errors.Is(err, stuber.ErrStubNotFound) {
+			if m.synthesizes(stream.Context(), status.Error(codes.NotFound, wrappedErr.Error())) {
+				return m.synthesizeBidi(stream)
+			}
+
 			if rec, ok := stream.(*bidiRecordingStream); ok && len(rec.getResponses()) > 0 {
 				return status.Error(codes.NotFound, wrappedErr.Error())
 			}
@@ -136,6 +152,13 @@ func (m *grpcMocker) sendBidiResponse(
 	td := newTemplateData(requestData, headers, bidiResult.GetMessageIndex(), requestTime,
 		[]any{requestData}, stub, bidiResult.MatchNumber())
 
+	// Every client message rolls its own faults, so a reset or error can land
+	// anywhere in a long-lived stream.
+	plan := m.faultPlan(stub, bidiResponseCount(stub, bidiResult.GetMessageIndex()))
+	if err := m.injectFaults(stream.Context(), plan); err != nil {
+		return err
+	}
+
 	if len(stub.Output.Stream) == 0 {
 		if err := delayTemplated(stream.Context(), m.templateEngine, stub.Output.Delay, td); err != nil {
 			return err
@@ -167,7 +190,7 @@ func (m *grpcMocker) sendBidiResponse(
 		recStream.setStubID(stub.ID)
 	}
 
-	return m.sendBidiResponses(stream, outputToUse, stub, bidiResult.GetMessageIndex(), td)
+	return m.sendBidiResponses(stream, outputToUse, stub, bidiResult.GetMessageIndex(), td, plan)
 }
 
 func (m *grpcMocker) recordBidiStreamUnlessProxied(
@@ -176,7 +199,7 @@ func (m *grpcMocker) recordBidiStreamUnlessProxied(
 	requestTime time.Time,
 	callErr error,
 ) {
-	if m.proxyFallbackWillServe(callErr) {
+	if m.proxyFallbackWillServe(stream.Context(), callErr) {
 		return
 	}
 
@@ -208,7 +231,6 @@ func (m *grpcMocker) recordBidiStream(
 	rec := history.CallRecord{
 		Service:         m.fullServiceName,
 		Method:          m.methodName,
-		Session:         sessionFromContext(stream.Context()),
 		Requests:        requests,
 		Responses:       responses,
 		ResponseHeaders: stream.getResponseHeaders(),
@@ -217,7 +239,9 @@ func (m *grpcMocker) recordBidiStream(
 		StubID:          stream.getStubID(),
 		ElapsedMS:       time.Since(requestTime).Milliseconds(),
 		Timestamp:       requestTime,
+		Synthesized:     stream.synthesized,
 	}
+	callMetaFromContext(stream.Context()).apply(&rec)
 
 	recordOwned(m.recorder, rec)
 }
@@ -290,9 +314,14 @@ func (m *grpcMocker) sendBidiResponses(
 	stub *stuber.Stub,
 	messageIndex int,
 	td template.Data,
+	plan *faults.Plan,
 ) error {
 	if len(output.Stream) > 0 {
-		return m.sendStreamResponses(stream, output, stub, messageIndex, td)
+		return m.sendStreamResponses(stream, output, stub, messageIndex, td, plan)
+	}
+
+	if plan.Drop() {
+		return nil
 	}
 
 	outputMsg, err := m.newOutputMessage(output.Data)
@@ -309,12 +338,13 @@ func (m *grpcMocker) sendStreamResponses(
 	stub *stuber.Stub,
 	messageIndex int,
 	td template.Data,
+	plan *faults.Plan,
 ) error {
 	if stub.IsClientStream() {
-		return m.sendClientStreamResponses(stream, output, stub, messageIndex, td)
+		return m.sendClientStreamResponses(stream, output, stub, messageIndex, td, plan)
 	}
 
-	return m.sendServerStreamResponses(stream, output, td)
+	return m.sendServerStreamResponses(stream, output, td, plan)
 }
 
 //nolint:cyclop
@@ -324,6 +354,7 @@ func (m *grpcMocker) sendClientStreamResponses(
 	stub *stuber.Stub,
 	messageIndex int,
 	td template.Data,
+	plan *faults.Plan,
 ) error {
 	streamLen := len(output.Stream)
 	if streamLen == 0 {
@@ -350,11 +381,19 @@ func (m *grpcMocker) sendClientStreamResponses(
 		end = streamLen
 	}
 
-	for _, streamElement := range output.Stream[start:end] {
+	for i, streamElement := range output.Stream[start:end] {
 		if _, ok := streamElement.(map[string]any); !ok {
 			continue
 		}
 
+		if i > 0 && plan.ResetBefore(i) {
+			return faults.ErrReset
+		}
+
+		if plan.Drop() {
+			continue
+		}
+
 		payload, err := m.prepareStreamElement(stream.Context(), streamElement, output, td)
 		if err != nil {
 			return err
@@ -373,6 +412,21 @@ func (m *grpcMocker) sendClientStreamResponses(
 	return nil
 }
 
+// bidiResponseCount is how many responses the client message at messageIndex
+// is answered with, which bounds where a rolled reset lands.
+func bidiResponseCount(stub *stuber.Stub, messageIndex int) int {
+	streamLen := len(stub.Output.Stream)
+	if !stub.IsClientStream() || streamLen == 0 {
+		return max(streamLen, 1)
+	}
+
+	if messageIndex == len(stub.Inputs)-1 {
+		return max(streamLen-messageIndex, 1)
+	}
+
+	return 1
+}
+
 func exhaustedBidiScriptError(stub *stuber.Stub, messageIndex, inputLen int) error {
 	return status.Errorf(codes.NotFound,
 		"stub %s scripts %d message(s) for %s/%s, but the client sent message #%d; "+
@@ -416,8 +470,17 @@ func (m *grpcMocker) sendServerStreamResponses(
 	stream grpc.ServerStream,
 	output stuber.Output,
 	td template.Data,
+	plan *faults.Plan,
 ) error {
-	for _, streamElement := range output.Stream {
+	for i, streamElement := range output.Stream {
+		if i > 0 && plan.ResetBefore(i) {
+			return faults.ErrReset
+		}
+
+		if plan.Drop() {
+			continue
+		}
+
 		payload, err := m.prepareStreamElement(stream.Context(), streamElement, output, td)
 		if err != nil {
 			return err
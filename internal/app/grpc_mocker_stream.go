@@ -11,6 +11,7 @@ import (
 
 	"github.com/cockroachdb/errors"
 	"github.com/goccy/go-json"
+	"github.com/google/uuid"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -18,8 +19,10 @@ import (
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/types/dynamicpb"
 
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
 )
 
 func (m *grpcMocker) convertToMap(msg proto.Message) map[string]any {
@@ -28,7 +31,7 @@ func (m *grpcMocker) convertToMap(msg proto.Message) map[string]any {
 
 //nolint:cyclop
 func (m *grpcMocker) streamHandler(srv any, stream grpc.ServerStream) error {
-	route := m.proxyRoute()
+	route := m.proxyRoute(stream.Context())
 
 	if route == nil && m.proxies != nil {
 		if m.fullMethod == "/grpc.health.v1.Health/Watch" {
@@ -47,6 +50,8 @@ func (m *grpcMocker) streamHandler(srv any, stream grpc.ServerStream) error {
 	var err error
 
 	switch {
+	case m.serverStream && !m.clientStream && behavior != nil && behavior.shadows() && m.drift != nil:
+		err = m.shadowServerStream(stream, route)
 	case m.serverStream && !m.clientStream:
 		err = m.handleServerStream(stream)
 	case !m.serverStream && m.clientStream:
@@ -142,12 +147,38 @@ func (m *grpcMocker) handleServerStream(stream grpc.ServerStream) error {
 
 	query := m.newQuery(stream.Context(), inputMsg)
 
+	if err := m.validateRequests(inputMsg); err != nil {
+		return m.rejectCall(stream.Context(), uuid.Nil, requestTime, query.Input, err)
+	}
+
+	answer, err := m.beforeMatch(stream.Context(), &query)
+	if err != nil {
+		return m.rejectCall(stream.Context(), uuid.Nil, requestTime, query.Input, err)
+	}
+
+	if answer != nil {
+		return m.sendHookAnswer(stream, answer, query.Input, requestTime)
+	}
+
 	result, err := m.budgerigar.FindByQuery(query)
 
 	result, err = m.ensureServerStreamResult(query, result, err)
 	if err != nil {
 		if status.Code(err) == codes.NotFound {
-			m.recordUnmatched(stream.Context(), requestTime, []map[string]any{m.convertToMap(inputMsg)}, err)
+			answer, hookErr := m.hookAnswer(stream.Context(), pkgplugins.HookOnMiss, query, nil)
+			if hookErr != nil {
+				return m.rejectCall(stream.Context(), uuid.Nil, requestTime, query.Input, hookErr)
+			}
+
+			if answer != nil {
+				return m.sendHookAnswer(stream, answer, query.Input, requestTime)
+			}
+
+			if m.synthesizes(stream.Context(), err) {
+				return m.synthesizeStream(stream, requestTime, query.Input)
+			}
+
+			m.recordUnmatched(stream.Context(), requestTime, query.Input, err)
 
 			return newServerStreamFallbackError(err, inputMsg)
 		}
@@ -157,16 +188,25 @@ func (m *grpcMocker) handleServerStream(stream grpc.ServerStream) error {
 
 	found := result.Found()
 
-	outputToUse := found.Output
-	requestData := m.convertToMap(inputMsg)
+	answer, err = m.hookAnswer(stream.Context(), pkgplugins.HookAfterMatch, query, found)
+	if err != nil {
+		return m.rejectCall(stream.Context(), found.ID, requestTime, query.Input, err)
+	}
 
-	headers := make(map[string]any)
-	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
-		headers = processHeaders(md)
+	if answer != nil {
+		return m.sendHookAnswer(stream, answer, query.Input, requestTime)
 	}
 
+	plan := m.faultPlan(found, max(len(found.Output.Stream), 1))
+	if err := m.injectFaults(stream.Context(), plan); err != nil {
+		return m.resetOn(stream.Context(), m.rejectCall(stream.Context(), found.ID, requestTime, query.Input, err))
+	}
+
+	outputToUse := found.Output
+	requestData := query.Input[0]
+
 	matchNumber := result.MatchNumber()
-	templateData := newTemplateData(requestData, headers, 0, requestTime,
+	templateData := newTemplateData(requestData, query.Headers, 0, requestTime,
 		[]any{requestData}, found, matchNumber)
 
 	if !streamDelaysPerMessage(found) {
@@ -215,7 +255,7 @@ func (m *grpcMocker) handleServerStream(stream grpc.ServerStream) error {
 	}
 
 	if found.Output.Stream == nil {
-		return m.handleServerStreamOutput(stream, found, requestData, outputToUse, requestTime, matchNumber)
+		return m.handleServerStreamOutput(stream, found, requestData, outputToUse, requestTime, matchNumber, plan)
 	}
 
 	if len(found.Output.Stream) == 0 {
@@ -227,15 +267,15 @@ func (m *grpcMocker) handleServerStream(stream grpc.ServerStream) error {
 		return callErr //nolint:wrapcheck
 	}
 
-	sent, callErr := m.handleArrayStreamData(stream, found, inputMsg, requestTime, matchNumber)
+	sent, callErr := m.handleArrayStreamData(stream, found, inputMsg, requestTime, matchNumber, plan)
 	if callErr == nil {
 		callErr = m.handleOutputError(stream.Context(), stream, outputToUse)
 	}
 
 	m.recordServerStreamUnlessProxied(stream.Context(), found, requestTime,
-		requestData, cleanStreamResponses(found.Output.Stream[:sent]), recordedMetadata(outputToUse), callErr)
+		requestData, cleanStreamResponses(sent), recordedMetadata(outputToUse), callErr)
 
-	return callErr //nolint:wrapcheck
+	return m.resetOn(stream.Context(), callErr) //nolint:wrapcheck
 }
 
 func streamDelaysPerMessage(found *stuber.Stub) bool {
@@ -301,7 +341,7 @@ func (m *grpcMocker) recordServerStreamUnlessProxied(
 	headers map[string]string,
 	callErr error,
 ) {
-	if callErr != nil && m.proxyFallbackWillServe(callErr) {
+	if callErr != nil && m.proxyFallbackWillServe(ctx, callErr) {
 		return
 	}
 
@@ -315,6 +355,7 @@ func (m *grpcMocker) recordServerStreamUnlessProxied(
 
 	m.recordCall(ctx, found.ID, code, requestTime,
 		[]map[string]any{requestData}, responses, headers, errMsg)
+	markShadowed(ctx, found.ID)
 }
 
 func (m *grpcMocker) handleServerStreamOutput(
@@ -324,8 +365,9 @@ func (m *grpcMocker) handleServerStreamOutput(
 	outputToUse stuber.Output,
 	requestTime time.Time,
 	matchNumber int,
+	plan *faults.Plan,
 ) error {
-	callErr := m.handleNonArrayStreamData(stream, found, outputToUse, requestData, requestTime, matchNumber)
+	callErr := m.handleNonArrayStreamData(stream, found, outputToUse, requestData, requestTime, matchNumber, plan)
 
 	m.recordServerStreamUnlessProxied(stream.Context(), found, requestTime,
 		requestData, []any{outputToUse.Data}, recordedMetadata(outputToUse), callErr)
@@ -355,22 +397,34 @@ func (m *grpcMocker) handleArrayStreamData(
 	inputMsg *dynamicpb.Message,
 	requestTime time.Time,
 	matchNumber int,
-) (int, error) {
+	plan *faults.Plan,
+) ([]any, error) {
 	done := stream.Context().Done()
+	sent := make([]any, 0, len(found.Output.Stream))
 
 	for i, streamData := range found.Output.Stream {
 		select {
 		case <-done:
-			return i, stream.Context().Err()
+			return sent, stream.Context().Err()
 		default:
 		}
 
+		if i > 0 && plan.ResetBefore(i) {
+			return sent, faults.ErrReset
+		}
+
+		if plan.Drop() {
+			continue
+		}
+
 		if err := m.handleStreamElement(stream, found, streamData, i, inputMsg, requestTime, matchNumber); err != nil {
-			return i, err
+			return sent, err
 		}
+
+		sent = append(sent, streamData)
 	}
 
-	return len(found.Output.Stream), nil
+	return sent, nil
 }
 
 func (m *grpcMocker) handleStreamElement(
@@ -432,6 +486,7 @@ func (m *grpcMocker) handleNonArrayStreamData(
 	requestData map[string]any,
 	requestTime time.Time,
 	matchNumber int,
+	plan *faults.Plan,
 ) error {
 	if err := m.handleOutputError(stream.Context(), stream, outputToUse); err != nil {
 		return err
@@ -481,8 +536,10 @@ func (m *grpcMocker) handleNonArrayStreamData(
 			return errors.Wrap(err, "failed to convert response to dynamic message")
 		}
 
-		if err := sendStreamMessage(stream, outputMsg); err != nil {
-			return err //nolint:wrapcheck
+		if !plan.Drop() {
+			if err := sendStreamMessage(stream, outputMsg); err != nil {
+				return err //nolint:wrapcheck
+			}
 		}
 
 		if err := stream.RecvMsg(nil); err != nil {
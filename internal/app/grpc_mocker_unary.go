@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
@@ -20,6 +21,7 @@ import (
 
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
 )
 
 func (m *grpcMocker) unaryHandler() grpc.MethodHandler {
@@ -52,7 +54,7 @@ func (m *grpcMocker) handleUnaryWithProxy(
 	stream grpc.ServerStream,
 	req *dynamicpb.Message,
 ) (*dynamicpb.Message, error) {
-	route := m.proxyRoute()
+	route := m.proxyRoute(ctx)
 
 	if route == nil && m.proxies != nil {
 		if m.fullMethod == "/grpc.health.v1.Health/Check" {
@@ -76,6 +78,10 @@ func (m *grpcMocker) handleUnaryWithProxy(
 		return m.proxyUnary(ctx, stream, req, route, true)
 	}
 
+	if behavior.shadows() && m.drift != nil {
+		return m.shadowUnary(ctx, stream, req, route)
+	}
+
 	resp, err := m.handleUnary(ctx, stream, req)
 
 	var fallbackErr *fallbackError
@@ -112,6 +118,19 @@ func (m *grpcMocker) handleUnary(ctx context.Context, stream grpc.ServerStream,
 
 	query := m.newQuery(ctx, req)
 
+	if err := m.validateRequests(req); err != nil {
+		return nil, m.rejectCall(ctx, uuid.Nil, requestTime, query.Input, err)
+	}
+
+	answer, err := m.beforeMatch(ctx, &query)
+	if err != nil {
+		return nil, m.rejectCall(ctx, uuid.Nil, requestTime, query.Input, err)
+	}
+
+	if answer != nil {
+		return m.answerFromHook(ctx, stream, answer, query.Input, requestTime)
+	}
+
 	result, err := m.budgerigar.FindByQuery(query)
 
 	if err != nil || (result != nil && result.Found() == nil) {
@@ -119,16 +138,42 @@ func (m *grpcMocker) handleUnary(ctx context.Context, stream grpc.ServerStream,
 			result = &stuber.Result{}
 		}
 
+		answer, hookErr := m.hookAnswer(ctx, pkgplugins.HookOnMiss, query, nil)
+		if hookErr != nil {
+			return nil, m.rejectCall(ctx, uuid.Nil, requestTime, query.Input, hookErr)
+		}
+
+		if answer != nil {
+			return m.answerFromHook(ctx, stream, answer, query.Input, requestTime)
+		}
+
 		notFound := status.Error(codes.NotFound, m.errorFormatter.FormatStubNotFoundError(query, result).Error())
-		m.recordUnmatched(ctx, requestTime, []map[string]any{m.convertToMap(req)}, notFound)
+		if m.synthesizes(ctx, notFound) {
+			return m.synthesizeUnary(ctx, requestTime, query.Input), nil
+		}
+
+		m.recordUnmatched(ctx, requestTime, query.Input, notFound)
 
 		return nil, newUnaryFallbackError(notFound)
 	}
 
 	found := result.Found()
 
+	answer, err = m.hookAnswer(ctx, pkgplugins.HookAfterMatch, query, found)
+	if err != nil {
+		return nil, m.rejectCall(ctx, found.ID, requestTime, query.Input, err)
+	}
+
+	if answer != nil {
+		return m.answerFromHook(ctx, stream, answer, query.Input, requestTime)
+	}
+
+	if err := m.injectFaults(ctx, m.faultPlan(found, 1)); err != nil {
+		return nil, m.resetOn(ctx, m.rejectCall(ctx, found.ID, requestTime, query.Input, err))
+	}
+
 	outputToUse := found.Output
-	requestData := m.convertToMap(req)
+	requestData := query.Input[0]
 
 	if found.UnaryHandler != nil {
 		data, hErr := found.UnaryHandler(ctx, requestData)
@@ -139,12 +184,7 @@ func (m *grpcMocker) handleUnary(ctx context.Context, stream grpc.ServerStream,
 		outputToUse.Data = data
 	}
 
-	headers := make(map[string]any)
-	if md, ok := metadata.FromIncomingContext(ctx); ok {
-		headers = processHeaders(md)
-	}
-
-	templateData := newTemplateData(requestData, headers, 0, requestTime,
+	templateData := newTemplateData(requestData, query.Headers, 0, requestTime,
 		[]any{requestData}, found, result.MatchNumber())
 
 	if err := delayTemplated(ctx, m.templateEngine, found.Output.Delay, templateData); err != nil {
@@ -181,14 +221,28 @@ func (m *grpcMocker) handleUnary(ctx context.Context, stream grpc.ServerStream,
 		outputToUse.Error = errorStr
 	}
 
-	if err := m.setResponseHeadersAny(ctx, stream, outputToUse.Headers); err != nil {
-		return nil, err //nolint:wrapcheck
-	}
-
 	if err := m.renderTrailers(&outputToUse, templateData); err != nil {
 		return nil, status.Error(codes.Internal, fmt.Sprintf("failed to process trailer templates: %v", err))
 	}
 
+	if outputToUse.Upstream != nil {
+		if err := m.forwardUpstream(ctx, m.proxyRoute(ctx), req, &outputToUse, &outputDataCopy, templateData); err != nil {
+			return nil, m.rejectCall(ctx, found.ID, requestTime, query.Input, err)
+		}
+	}
+
+	if err := runOutputScript(ctx, m.scripts, &outputToUse, &outputDataCopy, templateData); err != nil {
+		return nil, err
+	}
+
+	if err := m.beforeResponse(ctx, query, found, &outputToUse, &outputDataCopy); err != nil {
+		return nil, m.rejectCall(ctx, found.ID, requestTime, query.Input, err)
+	}
+
+	if err := m.setResponseHeadersAny(ctx, stream, outputToUse.Headers); err != nil {
+		return nil, err //nolint:wrapcheck
+	}
+
 	m.setResponseTrailersAny(ctx, stream, outputToUse.Trailers)
 
 	m.applyEffects(ctx, found, templateData)
@@ -196,6 +250,7 @@ func (m *grpcMocker) handleUnary(ctx context.Context, stream grpc.ServerStream,
 	if err := m.handleOutputError(ctx, stream, outputToUse); err != nil {
 		code := status.Code(err)
 		m.recordCall(ctx, found.ID, uint32(code), requestTime, []map[string]any{requestData}, nil, recordedMetadata(outputToUse), err.Error())
+		markShadowed(ctx, found.ID)
 		outputToUse.Error = err.Error()
 
 		return nil, err //nolint:wrapcheck
@@ -208,6 +263,7 @@ func (m *grpcMocker) handleUnary(ctx context.Context, stream grpc.ServerStream,
 
 	m.recordCall(ctx, found.ID, uint32(codes.OK), requestTime,
 		[]map[string]any{requestData}, []any{outputDataCopy}, recordedMetadata(outputToUse), "")
+	markShadowed(ctx, found.ID)
 
 	return outputMsg, nil
 }
@@ -305,22 +361,6 @@ func (m *grpcMocker) handleOutputError(_ context.Context, _ grpc.ServerStream, o
 	return nil
 }
 
-func (m *grpcMocker) tryV2API(messages []map[string]any, md metadata.MD) (*stuber.Result, error) {
-	query := stuber.Query{
-		Service:       m.fullServiceName,
-		Method:        m.methodName,
-		StrictService: m.strictServiceMatch,
-		Input:         messages,
-	}
-
-	if len(md) > 0 {
-		query.Headers = processHeaders(md)
-		query.Session = sessionFromMetadata(md)
-	}
-
-	return m.budgerigar.FindByQuery(query)
-}
-
 func handlerStatusError(err error) error {
 	if _, ok := status.FromError(err); ok {
 		return err
@@ -339,18 +379,56 @@ func (m *grpcMocker) handleClientStream(stream grpc.ServerStream) error {
 
 	zerolog.Ctx(stream.Context()).Debug().Int("msg_count", len(messages)).Msg("client_stream: collected messages")
 
-	found, matchNumber, err := m.tryFindStub(stream, messages)
+	ctx := stream.Context()
+	query := m.clientStreamQuery(ctx, messages)
+
+	if err := m.validateRequests(originalMessages...); err != nil {
+		return m.rejectCall(ctx, uuid.Nil, requestTime, query.Input, err)
+	}
+
+	answer, err := m.beforeMatch(ctx, &query)
 	if err != nil {
-		if status.Code(err) == codes.NotFound {
-			m.recordUnmatched(stream.Context(), requestTime, messages, err)
+		return m.rejectCall(ctx, uuid.Nil, requestTime, query.Input, err)
+	}
+
+	if answer != nil {
+		return m.sendHookAnswer(stream, answer, query.Input, requestTime)
+	}
 
-			return newClientStreamFallbackError(err, originalMessages)
+	found, matchNumber, err := m.tryFindStub(query)
+	if err != nil {
+		answer, hookErr := m.hookAnswer(ctx, pkgplugins.HookOnMiss, query, nil)
+		if hookErr != nil {
+			return m.rejectCall(ctx, uuid.Nil, requestTime, query.Input, hookErr)
 		}
 
-		return err
+		if answer != nil {
+			return m.sendHookAnswer(stream, answer, query.Input, requestTime)
+		}
+
+		if m.synthesizes(ctx, err) {
+			return sendStreamMessage(stream, m.synthesizeUnary(ctx, requestTime, query.Input))
+		}
+
+		m.recordUnmatched(ctx, requestTime, query.Input, err)
+
+		return newClientStreamFallbackError(err, originalMessages)
+	}
+
+	answer, err = m.hookAnswer(ctx, pkgplugins.HookAfterMatch, query, found)
+	if err != nil {
+		return m.rejectCall(ctx, found.ID, requestTime, query.Input, err)
 	}
 
-	return m.sendClientStreamResponse(stream, found, messages, requestTime, matchNumber)
+	if answer != nil {
+		return m.sendHookAnswer(stream, answer, query.Input, requestTime)
+	}
+
+	if err := m.injectFaults(ctx, m.faultPlan(found, 1)); err != nil {
+		return m.resetOn(ctx, m.rejectCall(ctx, found.ID, requestTime, query.Input, err))
+	}
+
+	return m.sendClientStreamResponse(stream, found, query, requestTime, matchNumber)
 }
 
 func (m *grpcMocker) collectClientMessages(stream grpc.ServerStream) ([]map[string]any, []*dynamicpb.Message, error) {
@@ -377,23 +455,25 @@ func (m *grpcMocker) collectClientMessages(stream grpc.ServerStream) ([]map[stri
 	return messages, originalMessages, nil
 }
 
-func (m *grpcMocker) tryFindStub(stream grpc.ServerStream, messages []map[string]any) (*stuber.Stub, int, error) {
-	md, _ := metadata.FromIncomingContext(stream.Context())
+func (m *grpcMocker) clientStreamQuery(ctx context.Context, messages []map[string]any) stuber.Query {
+	query := stuber.Query{
+		Service:       m.fullServiceName,
+		Method:        m.methodName,
+		StrictService: m.strictServiceMatch,
+		Input:         messages,
+	}
 
-	result, foundErr := m.tryV2API(messages, md)
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md) > 0 {
+		query.Headers = processHeaders(md)
+		query.Session = sessionFromMetadata(md)
+	}
 
-	if foundErr != nil || result == nil || result.Found() == nil {
-		query := stuber.Query{
-			Service:       m.fullServiceName,
-			Method:        m.methodName,
-			StrictService: m.strictServiceMatch,
-			Input:         messages,
-		}
-		if len(md) > 0 {
-			query.Headers = processHeaders(md)
-			query.Session = sessionFromMetadata(md)
-		}
+	return query
+}
 
+func (m *grpcMocker) tryFindStub(query stuber.Query) (*stuber.Stub, int, error) {
+	result, err := m.budgerigar.FindByQuery(query)
+	if err != nil || result == nil || result.Found() == nil {
 		if result == nil {
 			result = &stuber.Result{}
 		}
@@ -403,22 +483,18 @@ func (m *grpcMocker) tryFindStub(stream grpc.ServerStream, messages []map[string
 		return nil, 0, status.Error(codes.NotFound, errMsg)
 	}
 
-	found := result.Found()
-	if found == nil {
-		return nil, 0, status.Errorf(codes.NotFound, "No response found for client stream: %v", result.Similar())
-	}
-
-	return found, result.MatchNumber(), nil
+	return result.Found(), result.MatchNumber(), nil
 }
 
 //nolint:cyclop,funlen
 func (m *grpcMocker) sendClientStreamResponse(
 	stream grpc.ServerStream,
 	found *stuber.Stub,
-	messages []map[string]any,
+	query stuber.Query,
 	requestTime time.Time,
 	matchNumber int,
 ) error {
+	messages := query.Input
 	outputToUse := found.Output
 
 	if found.ClientStreamHandler != nil {
@@ -435,17 +511,12 @@ func (m *grpcMocker) sendClientStreamResponse(
 		outputToUse.Data = data
 	}
 
-	headers := make(map[string]any)
-	if md, ok := metadata.FromIncomingContext(stream.Context()); ok {
-		headers = processHeaders(md)
-	}
-
 	requestsAny := make([]any, len(messages))
 	for i, msg := range messages {
 		requestsAny[i] = msg
 	}
 
-	templateData := newTemplateData(nil, headers, 0, requestTime,
+	templateData := newTemplateData(nil, query.Headers, 0, requestTime,
 		requestsAny, found, matchNumber)
 
 	if err := delayTemplated(stream.Context(), m.templateEngine, found.Output.Delay, templateData); err != nil {
@@ -470,16 +541,10 @@ func (m *grpcMocker) sendClientStreamResponse(
 		outputToUse.Error = errorStr
 	}
 
-	if err := m.setResponseHeadersAny(stream.Context(), stream, outputToUse.Headers); err != nil {
-		return errors.Wrap(err, "failed to set headers")
-	}
-
 	if err := m.renderTrailers(&outputToUse, templateData); err != nil {
 		return errors.Wrap(err, "failed to process trailer templates")
 	}
 
-	m.setResponseTrailersAny(stream.Context(), stream, outputToUse.Trailers)
-
 	outputDataCopy := copyForTemplates(outputToUse.Data)
 	if dataMap, ok := outputDataCopy.(map[string]any); ok {
 		if err := m.templateEngine.ProcessMap(dataMap, templateData); err != nil {
@@ -489,6 +554,20 @@ func (m *grpcMocker) sendClientStreamResponse(
 		outputDataCopy = dataMap
 	}
 
+	if err := runOutputScript(stream.Context(), m.scripts, &outputToUse, &outputDataCopy, templateData); err != nil {
+		return err
+	}
+
+	if err := m.beforeResponse(stream.Context(), query, found, &outputToUse, &outputDataCopy); err != nil {
+		return m.rejectCall(stream.Context(), found.ID, requestTime, messages, err)
+	}
+
+	if err := m.setResponseHeadersAny(stream.Context(), stream, outputToUse.Headers); err != nil {
+		return errors.Wrap(err, "failed to set headers")
+	}
+
+	m.setResponseTrailersAny(stream.Context(), stream, outputToUse.Trailers)
+
 	m.applyEffects(stream.Context(), found, templateData)
 
 	if err := m.handleOutputError(stream.Context(), stream, outputToUse); err != nil { //nolint:wrapcheck
@@ -9,6 +9,7 @@ import (
 	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/descriptorpb"
 
 	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
@@ -18,7 +19,7 @@ import (
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 )
 
-func (s *GRPCServer) buildProxiesWithBindings(ctx context.Context, imports []string) (
+func (s *GRPCServer) buildProxiesWithBindings(ctx context.Context, files *protoregistry.Files, imports []string) (
 	[]*descriptorpb.FileDescriptorSet,
 	*proxyroutes.Registry,
 	error,
@@ -38,7 +39,7 @@ func (s *GRPCServer) buildProxiesWithBindings(ctx context.Context, imports []str
 		var bindingDescriptors []*descriptorpb.FileDescriptorSet
 
 		if len(binding.Sources) > 0 {
-			bindingDescriptors, err = protosetdom.Build(ctx, imports, binding.Sources, s.remoteClient)
+			bindingDescriptors, err = protosetdom.BuildInto(ctx, files, imports, binding.Sources, s.remoteClient)
 			if err != nil {
 				return nil, nil, errors.Wrapf(err, "failed to build descriptors for proxy %s", binding.ProxyURL)
 			}
@@ -74,7 +75,13 @@ func (s *GRPCServer) buildProxiesWithBindings(ctx context.Context, imports []str
 	return nil, proxies, nil
 }
 
-func (s *GRPCServer) buildProxiesFromSources(ctx context.Context, imports []string, protoPaths []string, sources []string) (
+func (s *GRPCServer) buildProxiesFromSources(
+	ctx context.Context,
+	files *protoregistry.Files,
+	imports []string,
+	protoPaths []string,
+	sources []string,
+) (
 	[]*descriptorpb.FileDescriptorSet,
 	*proxyroutes.Registry,
 	error,
@@ -88,7 +95,7 @@ func (s *GRPCServer) buildProxiesFromSources(ctx context.Context, imports []stri
 	if len(allPaths) > 0 {
 		var err error
 
-		descriptors, err = protosetdom.Build(ctx, imports, allPaths, s.remoteClient)
+		descriptors, err = protosetdom.BuildInto(ctx, files, imports, allPaths, s.remoteClient)
 		if err != nil {
 			return nil, nil, errors.Wrap(err, "failed to build descriptors")
 		}
@@ -109,7 +116,7 @@ func (s *GRPCServer) startProxyCleanup(ctx context.Context) {
 	}()
 }
 
-func (s *GRPCServer) registerProxyDescriptors(ctx context.Context) {
+func (s *GRPCServer) registerProxyDescriptors(ctx context.Context, files *protoregistry.Files) {
 	proxyFiles := s.proxies.Files()
 	if len(proxyFiles) == 0 {
 		return
@@ -117,12 +124,16 @@ func (s *GRPCServer) registerProxyDescriptors(ctx context.Context) {
 
 	for i, fds := range proxyFiles {
 		source := fmt.Sprintf("proxy-descriptor-set-%d", i)
-		if err := protosetdom.RegisterDescriptorSetFiles(ctx, source, fds); err != nil {
+		if err := protosetdom.RegisterDescriptorSetFiles(ctx, files, source, fds); err != nil {
 			zerolog.Ctx(ctx).Err(err).Int("index", i).Msg("failed to register proxy descriptor set")
 		}
 	}
 }
 
+// BuildFromDescriptorSet creates a gRPC server from a pre-built FileDescriptorSet.
+// Used by the SDK for embedded mode: the server resolves from fds alone, so
+// embedded servers in one process may carry different versions of a file.
+// If recorder is non-nil, all gRPC calls are recorded for History/Verify.
 func BuildFromDescriptorSet(
 	ctx context.Context,
 	fds *descriptorpb.FileDescriptorSet,
@@ -150,9 +161,11 @@ func BuildFromDescriptorSet(
 		errorFormatter: NewErrorFormatter(),
 		limits:         DefaultServerLimits(),
 	}
+	s.descriptors.SetStartup(reg)
+
 	server := s.createServer(ctx)
-	s.setupHealthCheck(ctx, server, reg)
-	s.registerServices(ctx, server, []*descriptorpb.FileDescriptorSet{fds}, reg)
+	s.setupHealthCheck(ctx, server)
+	s.registerServices(ctx, server, []*descriptorpb.FileDescriptorSet{fds})
 
 	// Mark server as ready synchronously after all descriptors and stubs are loaded.
 	s.markServerReady(ctx)
@@ -92,12 +92,16 @@ type captureRequestContext struct {
 	sessionID string
 }
 
-func (m *grpcMocker) proxyRoute() *proxyroutes.Route {
+// proxyRoute returns the route of the call, evaluating the routing rules
+// against its incoming metadata.
+func (m *grpcMocker) proxyRoute(ctx context.Context) *proxyroutes.Route {
 	if m.proxies == nil {
 		return nil
 	}
 
-	return m.proxies.RouteByMethod(m.fullMethod)
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	return m.proxies.RouteByMethod(m.fullMethod, md)
 }
 
 func (m *grpcMocker) newCaptureRequestContext(ctx context.Context) captureRequestContext {
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/rs/zerolog"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
@@ -13,6 +14,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
+	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/types"
 )
@@ -66,8 +68,8 @@ func captureMetadata(head metadata.MD, tail metadata.MD) proxycapture.ResponseMe
 	return proxycapture.CaptureMetadata(head, tail)
 }
 
-func messageToAny(message proto.Message) any {
-	return proxycapture.MessageToAny(message)
+func (m *grpcMocker) messageToAny(message proto.Message) any {
+	return proxycapture.MessageToAny(message, m.typeResolver)
 }
 
 func selectCaptureError(firstErr, secondErr error) error {
@@ -117,7 +119,7 @@ func (m *grpcMocker) recordProxyCall(
 		errMsg = callErr.Error()
 	}
 
-	recordCall(m.recorder, m.fullServiceName, m.methodName, sessionFromContext(ctx),
+	recordCall(m.recorder, m.fullServiceName, m.methodName, callMetaFromContext(ctx),
 		uuid.Nil, code, startTime, requests, responses, respHeaders, errMsg)
 }
 
@@ -134,6 +136,8 @@ func capturableResult(ctx context.Context, requests, responses int, callErr erro
 }
 
 func (m *grpcMocker) recordCapturedStub(
+	ctx context.Context,
+	route *proxyroutes.Route,
 	build func() *stuber.Stub,
 	recordDelay bool,
 	elapsed time.Duration,
@@ -147,11 +151,36 @@ func (m *grpcMocker) recordCapturedStub(
 		stub.Output.Delay = types.NewDelay(elapsed)
 	}
 
+	finishCapturedStub(ctx, route, stub)
 	m.budgerigar.PutMany(stub)
 }
 
+// finishCapturedStub applies the route's capture rules and adds the stub to
+// the route's capture directory, if it has one. The stub then carries its
+// stable ID, so a repeated exchange replaces the in-memory copy instead of
+// piling up next to it.
+func finishCapturedStub(ctx context.Context, route *proxyroutes.Route, stub *stuber.Stub) {
+	if route == nil {
+		return
+	}
+
+	route.CaptureRules.Apply(stub)
+
+	if route.Capture == nil {
+		return
+	}
+
+	if _, err := route.Capture.Write(stub); err != nil {
+		zerolog.Ctx(ctx).Err(err).
+			Str("service", stub.Service).
+			Str("method", stub.Method).
+			Msg("failed to write captured stub")
+	}
+}
+
 func (m *grpcMocker) captureBidiResult(
 	downstreamCtx context.Context,
+	route *proxyroutes.Route,
 	clientStream grpc.ClientStream,
 	captureCtx captureRequestContext,
 	requests []map[string]any,
@@ -168,7 +197,7 @@ func (m *grpcMocker) captureBidiResult(
 		return
 	}
 
-	m.recordCapturedStub(
+	m.recordCapturedStub(downstreamCtx, route,
 		func() *stuber.Stub {
 			return proxycapture.BuildBidiStub(
 				m.fullServiceName, m.methodName, captureCtx.sessionID,
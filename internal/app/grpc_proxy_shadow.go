@@ -0,0 +1,221 @@
+package app
+
+import (
+	"context"
+	stderrors "errors"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/infra/drift"
+	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
+	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
+	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+)
+
+// shadowTimeoutFallback bounds a shadow call whose source sets no timeout:
+// nothing waits on it, so it must not be left open for good.
+const shadowTimeoutFallback = 30 * time.Second
+
+// shadowTrace notes, through the context of a shadow-mode call, the stub that
+// answered it. Calls answered by hooks, auto-mock or a rejection leave it
+// unset and are not compared.
+type shadowTrace struct {
+	stubID uuid.UUID
+}
+
+type shadowTraceKey struct{}
+
+func withShadowTrace(ctx context.Context) (context.Context, *shadowTrace) {
+	trace := &shadowTrace{}
+
+	return context.WithValue(ctx, shadowTraceKey{}, trace), trace
+}
+
+// markShadowed notes that the stub answered the call, if it is shadowed.
+func markShadowed(ctx context.Context, stubID uuid.UUID) {
+	if trace, ok := ctx.Value(shadowTraceKey{}).(*shadowTrace); ok {
+		trace.stubID = stubID
+	}
+}
+
+// shadowStream keeps the request and the responses of a server stream served
+// by a stub.
+type shadowStream struct {
+	grpc.ServerStream
+
+	ctx       context.Context //nolint:containedctx
+	resolver  *protosetinfra.TypeResolver
+	request   proto.Message
+	responses []any
+}
+
+func (s *shadowStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *shadowStream) RecvMsg(msg any) error {
+	err := s.ServerStream.RecvMsg(msg)
+	if message, ok := msg.(proto.Message); ok && err == nil && s.request == nil {
+		s.request = proto.Clone(message)
+	}
+
+	return err //nolint:wrapcheck
+}
+
+func (s *shadowStream) SendMsg(msg any) error {
+	err := s.ServerStream.SendMsg(msg)
+	if message, ok := msg.(proto.Message); ok && err == nil {
+		s.responses = append(s.responses, proxycapture.MessageToMap(message, s.resolver))
+	}
+
+	return err //nolint:wrapcheck
+}
+
+// shadowUnary serves the call like replay and, when a stub answered it, sends
+// it to the upstream as well to compare the responses.
+func (m *grpcMocker) shadowUnary(
+	ctx context.Context,
+	stream grpc.ServerStream,
+	req *dynamicpb.Message,
+	route *proxyroutes.Route,
+) (*dynamicpb.Message, error) {
+	tracedCtx, trace := withShadowTrace(ctx)
+
+	resp, err := m.handleUnary(tracedCtx, stream, req)
+
+	var fallbackErr *fallbackError
+	if stderrors.As(err, &fallbackErr) && fallbackErr.streamType == StreamTypeUnary {
+		return m.proxyUnary(ctx, stream, req, route, false)
+	}
+
+	if trace.stubID == uuid.Nil {
+		return resp, err
+	}
+
+	stub := drift.Response{Code: status.Code(err)}
+	if err == nil {
+		stub.Messages = []any{proxycapture.MessageToMap(resp, m.typeResolver)}
+	}
+
+	request := proto.Clone(req)
+
+	m.shadow(ctx, route, trace.stubID, stub, func(ctx context.Context) drift.Response {
+		upstream := dynamicpb.NewMessage(m.outputDesc)
+		if err := route.Conn.Invoke(ctx, m.fullMethod, request, upstream); err != nil {
+			return drift.Response{Code: status.Code(err)}
+		}
+
+		return drift.Response{Code: codes.OK, Messages: []any{proxycapture.MessageToMap(upstream, m.typeResolver)}}
+	})
+
+	return resp, err
+}
+
+// shadowServerStream is shadowUnary for server streams.
+func (m *grpcMocker) shadowServerStream(stream grpc.ServerStream, route *proxyroutes.Route) error {
+	tracedCtx, trace := withShadowTrace(stream.Context())
+	shadowed := &shadowStream{ServerStream: stream, ctx: tracedCtx, resolver: m.typeResolver}
+
+	err := m.handleServerStream(shadowed)
+	if trace.stubID == uuid.Nil || shadowed.request == nil {
+		return err
+	}
+
+	stub := drift.Response{Code: status.Code(err)}
+	if err == nil {
+		stub.Messages = shadowed.responses
+	}
+
+	m.shadow(stream.Context(), route, trace.stubID, stub, func(ctx context.Context) drift.Response {
+		return m.shadowServerStreamCall(ctx, route, shadowed.request)
+	})
+
+	return err
+}
+
+func (m *grpcMocker) shadowServerStreamCall(ctx context.Context, route *proxyroutes.Route, req proto.Message) drift.Response {
+	clientStream, err := route.Conn.NewStream(ctx, &grpc.StreamDesc{ServerStreams: true}, m.fullMethod)
+	if err != nil {
+		return drift.Response{Code: status.Code(err)}
+	}
+
+	// A failed send surfaces its status on RecvMsg.
+	if err := clientStream.SendMsg(req); err == nil {
+		_ = clientStream.CloseSend()
+	}
+
+	var messages []any
+
+	for {
+		upstream := dynamicpb.NewMessage(m.outputDesc)
+
+		err := clientStream.RecvMsg(upstream)
+		if stderrors.Is(err, io.EOF) {
+			return drift.Response{Code: codes.OK, Messages: messages}
+		}
+
+		if err != nil {
+			return drift.Response{Code: status.Code(err)}
+		}
+
+		messages = append(messages, proxycapture.MessageToMap(upstream, m.typeResolver))
+	}
+}
+
+// shadow runs call against the upstream in the background, detached from the
+// client call, and records how its response compares with the stub's.
+func (m *grpcMocker) shadow(
+	ctx context.Context,
+	route *proxyroutes.Route,
+	stubID uuid.UUID,
+	stub drift.Response,
+	call func(ctx context.Context) drift.Response,
+) {
+	if strings.HasPrefix(m.fullMethod, healthServicePrefix) {
+		return
+	}
+
+	var ignore []string
+	if route.Source != nil {
+		ignore = route.Source.ShadowIgnore
+	}
+
+	logger := zerolog.Ctx(ctx)
+	shadowCtx := proxyroutes.ForwardIncomingMetadata(context.WithoutCancel(ctx))
+
+	go func() {
+		callCtx, cancel := shadowContext(shadowCtx, route)
+		defer cancel()
+
+		result := drift.Compare(stub, call(callCtx), ignore)
+		if result != nil {
+			logger.Debug().
+				Str("stub", stubID.String()).
+				Str("method", m.fullMethod).
+				Int("fields", len(result.Fields)).
+				Msg("shadow response drifted from the stub")
+		}
+
+		m.drift.Record(stubID, m.fullServiceName, m.methodName, result)
+	}()
+}
+
+func shadowContext(ctx context.Context, route *proxyroutes.Route) (context.Context, context.CancelFunc) {
+	routeCtx, cancel := route.WithTimeout(ctx)
+	if _, ok := routeCtx.Deadline(); ok {
+		return routeCtx, cancel
+	}
+
+	cancel()
+
+	return context.WithTimeout(ctx, shadowTimeoutFallback)
+}
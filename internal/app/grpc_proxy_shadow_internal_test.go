@@ -0,0 +1,120 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	protosetdom "github.com/bavix/gripmock/v3/internal/domain/protoset"
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/drift"
+	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
+	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
+	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func newShadowMocker(t *testing.T) *grpcMocker {
+	t.Helper()
+
+	mocker := newHookedMocker(t, nil)
+	mocker.fullMethod = "/" + testServiceName + "/" + testMethodName
+	mocker.drift = drift.NewStore()
+
+	return mocker
+}
+
+func awaitDriftReport(t *testing.T, store *drift.Store) drift.Report {
+	t.Helper()
+
+	require.Eventually(t, func() bool { return len(store.Reports()) == 1 }, 5*time.Second, 10*time.Millisecond)
+
+	return store.Reports()[0]
+}
+
+func TestShadowUnaryRecordsDrift(t *testing.T) {
+	t.Parallel()
+
+	mocker := newShadowMocker(t)
+	stub := putHookStub(mocker)
+
+	route := &proxyroutes.Route{
+		Conn:   startStructUpstream(t, "upstream"),
+		Source: &protosetdom.Source{ShadowIgnore: []string{"message"}},
+	}
+
+	resp, err := mocker.shadowUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc), route)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"result": "stub"}, proxycapture.MessageToMap(resp, protosetinfra.GlobalTypeResolver()), "the client gets the stub response")
+
+	report := awaitDriftReport(t, mocker.drift)
+	require.Equal(t, stub.ID, report.StubID)
+	require.Equal(t, 1, report.Checks)
+	require.Equal(t, 1, report.Drifts)
+	require.Equal(t, []drift.FieldDiff{{Path: "result", Stub: "stub"}}, report.Last.Fields)
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+	server.SetDrift(mocker.drift)
+
+	w := httptest.NewRecorder()
+	server.ListShadowDrift(w, scenarioRequest(t, http.MethodGet, "/api/shadow/drift", "", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var reports rest.DriftReportList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &reports))
+	require.Len(t, reports, 1)
+	require.Equal(t, testMethodName, reports[0].Method)
+	require.Equal(t, "result", reports[0].Last.Fields[0].Path)
+
+	w = httptest.NewRecorder()
+	server.ClearShadowDrift(w, scenarioRequest(t, http.MethodDelete, "/api/shadow/drift", "", ""))
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Empty(t, mocker.drift.Reports())
+}
+
+func TestShadowServerStreamRecordsMatch(t *testing.T) {
+	t.Parallel()
+
+	mocker := newShadowMocker(t)
+	mocker.serverStream = true
+	mocker.budgerigar.PutMany(&stuber.Stub{
+		Service: testServiceName,
+		Method:  testMethodName,
+		Input:   stuber.InputData{Contains: map[string]any{}},
+		Output: stuber.Output{Stream: []any{
+			map[string]any{"message": "one"},
+			map[string]any{"message": "two"},
+		}},
+	})
+
+	route := &proxyroutes.Route{Conn: startStructUpstream(t, "one", "two"), Source: &protosetdom.Source{}}
+
+	stream := &mockArrayStreamServerStream{ctx: t.Context()}
+	require.NoError(t, mocker.shadowServerStream(stream, route))
+	require.Len(t, stream.sentMessages, 2)
+
+	report := awaitDriftReport(t, mocker.drift)
+	require.Equal(t, 1, report.Checks)
+	require.Zero(t, report.Drifts)
+	require.Nil(t, report.Last)
+}
+
+func TestShadowSkipsCallsNoStubAnswered(t *testing.T) {
+	t.Parallel()
+
+	mocker := newShadowMocker(t)
+
+	route := &proxyroutes.Route{Conn: startStructUpstream(t, "upstream"), Source: &protosetdom.Source{}}
+
+	resp, err := mocker.shadowUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc), route)
+	require.NoError(t, err, "a miss is proxied like replay")
+	require.Equal(t, map[string]any{"message": "upstream"}, proxycapture.MessageToMap(resp, protosetinfra.GlobalTypeResolver()))
+
+	require.Never(t, func() bool { return len(mocker.drift.Reports()) > 0 }, 100*time.Millisecond, 10*time.Millisecond)
+}
@@ -99,7 +99,7 @@ func (m *grpcMocker) proxyServerStreamWithRequest(
 
 		if err != nil {
 			if capture && capturableResult(stream.Context(), 1, len(responses), err) {
-				m.recordCapturedStub(
+				m.recordCapturedStub(stream.Context(), route,
 					func() *stuber.Stub {
 						return proxycapture.BuildServerStreamStub(
 							m.fullServiceName, m.methodName, captureCtx.sessionID,
@@ -123,7 +123,7 @@ func (m *grpcMocker) proxyServerStreamWithRequest(
 			var marked bool
 
 			responses, historyResponses, marked = bufferProxyStreamMessage(
-				responses, historyResponses, messageToAny(resp),
+				responses, historyResponses, m.messageToAny(resp),
 				capture, historyEnabled, recordDelay, now.Sub(lastMsgTime),
 			)
 			recorded = recorded || marked
@@ -139,7 +139,7 @@ func (m *grpcMocker) proxyServerStreamWithRequest(
 	forwardUpstreamTrailer(stream, clientStream)
 
 	if capture {
-		m.recordCapturedStub(
+		m.recordCapturedStub(stream.Context(), route,
 			func() *stuber.Stub {
 				return proxycapture.BuildServerStreamStub(
 					m.fullServiceName, m.methodName, captureCtx.sessionID,
@@ -239,7 +239,7 @@ func (m *grpcMocker) proxyClientStreamWithRequests(
 	resp := dynamicpb.NewMessage(m.outputDesc)
 	if err = clientStream.RecvMsg(resp); err != nil {
 		if capture && capturableResult(stream.Context(), len(requests), 0, err) {
-			m.recordCapturedStub(
+			m.recordCapturedStub(stream.Context(), route,
 				func() *stuber.Stub {
 					return proxycapture.BuildClientStreamStub(
 						m.fullServiceName, m.methodName, captureCtx.sessionID,
@@ -258,7 +258,7 @@ func (m *grpcMocker) proxyClientStreamWithRequests(
 
 	var respEntry any
 	if bookkeeping {
-		respEntry = messageToAny(resp)
+		respEntry = m.messageToAny(resp)
 
 		if respMap, ok := respEntry.(map[string]any); ok && historyEnabled {
 			historyResponses = append(historyResponses, respMap)
@@ -270,7 +270,7 @@ func (m *grpcMocker) proxyClientStreamWithRequests(
 	}
 
 	if capture {
-		m.recordCapturedStub(
+		m.recordCapturedStub(stream.Context(), route,
 			func() *stuber.Stub {
 				return proxycapture.BuildClientStreamStub(
 					m.fullServiceName, m.methodName, captureCtx.sessionID,
@@ -343,7 +343,7 @@ func (m *grpcMocker) proxyBidiStreamWithRequests(
 	if capture {
 		requests, responses := state.Snapshot()
 		needGlobalDelay := route.Source.RecordDelay && !state.HasTimedResponses()
-		m.captureBidiResult(stream.Context(), clientStream, captureCtx,
+		m.captureBidiResult(stream.Context(), route, clientStream, captureCtx,
 			requests, responses, firstErr, secondErr, needGlobalDelay, time.Since(startTime))
 	}
 
@@ -383,12 +383,12 @@ func (m *grpcMocker) captureRequest(state *StreamCaptureState, req *dynamicpb.Me
 	state.AppendRequest(m.convertToMap(req))
 }
 
-func captureResponse(state *StreamCaptureState, resp *dynamicpb.Message) {
+func (m *grpcMocker) captureResponse(state *StreamCaptureState, resp *dynamicpb.Message) {
 	if state == nil {
 		return
 	}
 
-	state.AppendResponseWithTiming(messageToAny(resp), time.Now())
+	state.AppendResponseWithTiming(m.messageToAny(resp), time.Now())
 }
 
 func trySendErr(ch chan<- error, err error) {
@@ -498,7 +498,7 @@ func (m *grpcMocker) forwardBidiResponses(
 			return
 		}
 
-		captureResponse(state, resp)
+		m.captureResponse(state, resp)
 
 		if err = stream.SendMsg(resp); err != nil {
 			trySendErr(errCh, err)
@@ -165,10 +165,10 @@ func (m *grpcMocker) recordUnaryStub(
 
 	var responseData any
 	if resp != nil {
-		responseData = messageToAny(resp)
+		responseData = m.messageToAny(resp)
 	}
 
-	m.recordCapturedStub(
+	m.recordCapturedStub(ctx, route,
 		func() *stuber.Stub {
 			return proxycapture.BuildUnaryStub(
 				m.fullServiceName, m.methodName, captureCtx.sessionID,
@@ -0,0 +1,71 @@
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
+	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	"github.com/bavix/gripmock/v3/internal/infra/template"
+)
+
+// forwardUpstream serves a stub with output.upstream: it sends the call to the
+// route's upstream and replaces the output and its data with the
+// upstream response, patched by the stub. A failed upstream call is not an
+// error here; its status becomes the output's unless the stub rewrites it.
+func (m *grpcMocker) forwardUpstream(
+	ctx context.Context,
+	route *proxyroutes.Route,
+	req *dynamicpb.Message,
+	output *stuber.Output,
+	data *any,
+	templateData template.Data,
+) error {
+	if route == nil {
+		return status.Errorf(codes.FailedPrecondition,
+			"stub forwards to the upstream but no proxy route serves %s", m.fullMethod)
+	}
+
+	patch := *output.Upstream
+	if merge, ok := copyForTemplates(patch.Merge).(map[string]any); ok && merge != nil {
+		if err := m.templateEngine.ProcessMap(merge, templateData); err != nil {
+			return status.Errorf(codes.Internal, "failed to process upstream merge templates: %v", err)
+		}
+
+		patch.Merge = merge
+	}
+
+	proxyCtx, cancel := route.WithTimeout(proxyroutes.ForwardIncomingMetadata(ctx))
+	defer cancel()
+
+	var header, trailer metadata.MD
+
+	resp := dynamicpb.NewMessage(m.outputDesc)
+
+	callErr := route.Conn.Invoke(proxyCtx, m.fullMethod, req, resp, grpc.Header(&header), grpc.Trailer(&trailer))
+
+	var response any
+	if callErr == nil {
+		response = m.messageToAny(resp)
+	}
+
+	stub := *output
+	stub.Upstream = &patch
+
+	patched, err := proxycapture.PatchUpstream(stub, response, proxycapture.CaptureMetadata(header, trailer), callErr)
+	if err != nil {
+		return status.Error(codes.Internal, fmt.Sprintf("failed to patch upstream response: %v", err))
+	}
+
+	*output = patched
+	*data = patched.Data
+
+	return nil
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	protosetdom "github.com/bavix/gripmock/v3/internal/domain/protoset"
+	"github.com/bavix/gripmock/v3/internal/infra/jsonpatch"
+	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func TestForwardUpstreamPatchesResponse(t *testing.T) {
+	t.Parallel()
+
+	mocker := newShadowMocker(t)
+	route := &proxyroutes.Route{Conn: startStructUpstream(t, "upstream"), Source: &protosetdom.Source{}}
+
+	merge := map[string]any{"message": "{{ .Request.name }}"}
+	output := stuber.Output{
+		Headers: map[string]string{"x-mock": "yes"},
+		Upstream: &stuber.UpstreamPatch{
+			Patch: []jsonpatch.Operation{{Op: "add", Path: "/source", Value: "patched"}},
+			Merge: merge,
+		},
+	}
+
+	var data any
+
+	templateData := newTemplateData(map[string]any{"name": "alice"}, nil, 0, time.Now(), nil, nil, 1)
+	require.NoError(t, mocker.forwardUpstream(t.Context(), route, dynamicpb.NewMessage(mocker.inputDesc),
+		&output, &data, templateData))
+
+	require.Equal(t, map[string]any{"message": "alice", "source": "patched"}, data)
+	require.Equal(t, "yes", output.Headers["x-mock"])
+	require.Nil(t, output.Upstream)
+	require.Equal(t, "{{ .Request.name }}", merge["message"], "the stub is not rendered in place")
+}
+
+func TestUpstreamStubWithoutRouteFails(t *testing.T) {
+	t.Parallel()
+
+	mocker := newShadowMocker(t)
+	mocker.budgerigar.PutMany(&stuber.Stub{
+		Service: testServiceName,
+		Method:  testMethodName,
+		Input:   stuber.InputData{Contains: map[string]any{}},
+		Output:  stuber.Output{Upstream: &stuber.UpstreamPatch{}},
+	})
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.Equal(t, codes.FailedPrecondition, status.Code(err))
+	require.Len(t, hookCalls(t, mocker), 1, "the rejected call is recorded")
+}
+
+func TestValidateStubChecksUpstream(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	stub := &stuber.Stub{
+		Service: testServiceName,
+		Method:  testMethodName,
+		Input:   stuber.InputData{Contains: map[string]any{}},
+		Output: stuber.Output{
+			Data:     map[string]any{"message": "stub"},
+			Upstream: &stuber.UpstreamPatch{},
+		},
+	}
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, server.validateStub(stub), &validationErr)
+	require.Equal(t, "upstream", validationErr.Field)
+	require.ErrorContains(t, validationErr, ErrUpstreamWithData.Error())
+
+	stub.Output.Data = nil
+	stub.Output.Upstream.Patch = []jsonpatch.Operation{{Op: "rename", Path: "/message"}}
+	require.ErrorAs(t, server.validateStub(stub), &validationErr)
+	require.ErrorContains(t, validationErr, "unknown op")
+
+	stub.Output.Upstream.Patch[0].Op = "remove"
+	require.NoError(t, server.validateStub(stub))
+}
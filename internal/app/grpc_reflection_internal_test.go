@@ -85,7 +85,7 @@ func registerGreeterDescriptors(t *testing.T, ctx context.Context, registry *des
 		merged.File = append(merged.File, set.GetFile()...)
 	}
 
-	files, err := decodeDescriptorFiles(&merged)
+	files, err := decodeDescriptorFiles(&merged, registry.Startup())
 	require.NoError(t, err)
 
 	for _, fd := range files {
@@ -35,6 +35,7 @@ import (
 func (s *GRPCServer) createServer(ctx context.Context) *grpc.Server {
 	logger := zerolog.Ctx(ctx)
 	limits := s.limits.withDefaults()
+	typeResolver := protosetinfra.NewTypeResolver(s.descriptorResolver())
 
 	opts := []grpc.ServerOption{
 		grpc.NumStreamWorkers(uint32(runtimeNumStreamWorkers)), //nolint:gosec
@@ -54,12 +55,12 @@ func (s *GRPCServer) createServer(ctx context.Context) *grpc.Server {
 		grpc.ChainUnaryInterceptor(
 			grpccontext.PanicRecoveryUnaryInterceptor,
 			grpccontext.UnaryInterceptor(logger),
-			LogUnaryInterceptor,
+			LogUnaryInterceptor(typeResolver),
 		),
 		grpc.ChainStreamInterceptor(
 			grpccontext.PanicRecoveryStreamInterceptor,
 			grpccontext.StreamInterceptor(logger),
-			LogStreamInterceptor,
+			LogStreamInterceptor(typeResolver),
 		),
 		grpc.UnknownServiceHandler(s.handleUnknownService),
 	}
@@ -102,9 +103,15 @@ func (s *GRPCServer) handleUnknownService(_ any, stream grpc.ServerStream) error
 		templateEngine:     s.templates(stream.Context()),
 		errorFormatter:     s.errorFormatter,
 		recorder:           s.recorder,
-		typeResolver:       protosetinfra.NewTypeResolver(s.globalResolver()),
+		typeResolver:       protosetinfra.NewTypeResolver(s.descriptorResolver()),
 		proxies:            s.proxies,
 		validator:          s.validator,
+		hooks:              s.hooks,
+		scripts:            s.scripts,
+		faults:             s.faults,
+		requests:           s.requests,
+		autoMock:           s.autoMock,
+		drift:              s.drift,
 		maxNestingDepth:    s.maxNestingDepth,
 		inputDesc:          methodDesc.Input(),
 		outputDesc:         methodDesc.Output(),
@@ -114,7 +121,7 @@ func (s *GRPCServer) handleUnknownService(_ any, stream grpc.ServerStream) error
 		fullMethod:         fullMethod,
 		serverStream:       methodDesc.IsStreamingServer(),
 		clientStream:       methodDesc.IsStreamingClient(),
-		strictServiceMatch: s.proxies != nil && s.proxies.RouteByMethod(fullMethod) != nil,
+		strictServiceMatch: s.proxies != nil && s.proxies.RouteByMethod(fullMethod, nil) != nil,
 	}
 
 	if methodDesc.IsStreamingServer() || methodDesc.IsStreamingClient() {
@@ -134,22 +141,20 @@ func (s *GRPCServer) handleUnknownService(_ any, stream grpc.ServerStream) error
 	return stream.SendMsg(resp)
 }
 
+// findMethodDescriptor looks in the runtime registry first: a descriptor
+// uploaded or reloaded after startup supersedes the startup copy.
 func (s *GRPCServer) findMethodDescriptor(serviceName, methodName string) (protoreflect.MethodDescriptor, error) { //nolint:ireturn
-	if method := findMethodInGlobalFiles(serviceName, methodName); method != nil {
+	if method := findMethodInFiles(s.descriptors, serviceName, methodName); method != nil {
 		return method, nil
 	}
 
-	if method := findMethodInFiles(s.descriptors, serviceName, methodName); method != nil {
+	if method := findMethodInFiles(s.descriptors.Startup(), serviceName, methodName); method != nil {
 		return method, nil
 	}
 
 	return nil, errors.Errorf("unknown service/method: %s/%s", serviceName, methodName)
 }
 
-func findMethodInGlobalFiles(serviceName, methodName string) protoreflect.MethodDescriptor { //nolint:ireturn
-	return findMethodInFiles(protoregistry.GlobalFiles, serviceName, methodName)
-}
-
 type methodFilesLister interface {
 	RangeFiles(f func(protoreflect.FileDescriptor) bool)
 }
@@ -184,21 +189,15 @@ func findMethodInFiles(files methodFilesLister, serviceName, methodName string)
 	return found
 }
 
-func (s *GRPCServer) setupHealthCheck(ctx context.Context, server *grpc.Server, descResolver *protoregistry.Files) {
+func (s *GRPCServer) setupHealthCheck(ctx context.Context, server *grpc.Server) {
+	resolver := s.descriptorResolver()
+
 	healthServer := health.NewServer()
 	healthgrpc.RegisterHealthServer(server,
-		newMockableHealthServer(healthServer, s.budgerigar, descResolver, s.proxies, s.templates(ctx)))
+		newMockableHealthServer(healthServer, s.budgerigar, resolver, s.proxies, s.templates(ctx)))
 
 	provider := &dynamicServiceInfoProvider{base: server, registry: s.descriptors}
 
-	resolver := s.globalResolver()
-	if descResolver != nil {
-		resolver = &dynamicDescriptorResolver{
-			static:  descResolver,
-			dynamic: s.descriptors,
-		}
-	}
-
 	reflectionSvr := reflection.NewServerV1(reflection.ServerOptions{
 		Services:           provider,
 		DescriptorResolver: resolver,
@@ -240,19 +239,21 @@ func (p *dynamicServiceInfoProvider) GetServiceInfo() map[string]grpc.ServiceInf
 	return result
 }
 
-// globalResolver returns the shared GlobalFiles-backed resolver so its
-// dynamic-registry cache is reused across requests instead of being rebuilt
-// per allocation.
-func (s *GRPCServer) globalResolver() *dynamicDescriptorResolver {
+// descriptorResolver returns the shared resolver over the runtime registry
+// and the startup files, so its dynamic-registry cache is reused across
+// requests instead of being rebuilt per allocation.
+func (s *GRPCServer) descriptorResolver() *dynamicDescriptorResolver {
 	s.resolverOnce.Do(func() {
-		s.dynResolver = &dynamicDescriptorResolver{static: protoregistry.GlobalFiles, dynamic: s.descriptors}
+		s.dynResolver = &dynamicDescriptorResolver{dynamic: s.descriptors}
 	})
 
 	return s.dynResolver
 }
 
+// dynamicDescriptorResolver resolves from the files registered in dynamic at
+// runtime, then from its startup files. The startup files are read on every
+// call: a gateway may resolve through it before the gRPC server is built.
 type dynamicDescriptorResolver struct {
-	static  protodesc.Resolver
 	dynamic *descriptors.Registry
 
 	mu        sync.Mutex
@@ -261,11 +262,11 @@ type dynamicDescriptorResolver struct {
 }
 
 func (r *dynamicDescriptorResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) { //nolint:ireturn
-	return (&protosetinfra.Fallback{Primary: r.dynamicFiles(), Fallback: r.static}).FindFileByPath(path)
+	return (&protosetinfra.Fallback{Primary: r.dynamicFiles(), Fallback: r.dynamic.Startup()}).FindFileByPath(path)
 }
 
 func (r *dynamicDescriptorResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) { //nolint:ireturn
-	return (&protosetinfra.Fallback{Primary: r.dynamicFiles(), Fallback: r.static}).FindDescriptorByName(name)
+	return (&protosetinfra.Fallback{Primary: r.dynamicFiles(), Fallback: r.dynamic.Startup()}).FindDescriptorByName(name)
 }
 
 func (r *dynamicDescriptorResolver) dynamicFiles() *protoregistry.Files {
@@ -302,7 +303,6 @@ func (s *GRPCServer) registerServices(
 	ctx context.Context,
 	server *grpc.Server,
 	descriptors []*descriptorpb.FileDescriptorSet,
-	reg *protoregistry.Files,
 ) {
 	logger := zerolog.Ctx(ctx)
 	registered := make(map[string]struct{})
@@ -322,7 +322,7 @@ func (s *GRPCServer) registerServices(
 					continue
 				}
 
-				if err := s.registerServiceMethods(ctx, &serviceDesc, svc, reg); err != nil {
+				if err := s.registerServiceMethods(ctx, &serviceDesc, svc); err != nil {
 					logger.Warn().Err(err).Str("service", serviceDesc.ServiceName).Msg("Skipping service due to descriptor error")
 
 					continue
@@ -347,27 +347,26 @@ func (s *GRPCServer) registerServiceMethods(
 	ctx context.Context,
 	serviceDesc *grpc.ServiceDesc,
 	svc *descriptorpb.ServiceDescriptorProto,
-	reg *protoregistry.Files,
 ) error {
 	for _, method := range svc.GetMethod() {
-		inputDesc, outputDesc, err := s.resolveMethodMessageDescriptors(serviceDesc.ServiceName, method, reg)
+		inputDesc, outputDesc, err := s.resolveMethodMessageDescriptors(serviceDesc.ServiceName, method)
 		if err != nil {
 			return err
 		}
 
-		m := s.createGrpcMocker(ctx, serviceDesc, svc, method, inputDesc, outputDesc, reg)
+		m := s.createGrpcMocker(ctx, serviceDesc, svc, method, inputDesc, outputDesc)
 
 		if method.GetServerStreaming() || method.GetClientStreaming() {
 			serviceDesc.Streams = append(serviceDesc.Streams, grpc.StreamDesc{
 				StreamName:    method.GetName(),
-				Handler:       m.streamHandler,
+				Handler:       s.reloadableStream(m),
 				ServerStreams: m.serverStream,
 				ClientStreams: m.clientStream,
 			})
 		} else {
 			serviceDesc.Methods = append(serviceDesc.Methods, grpc.MethodDesc{
 				MethodName: method.GetName(),
-				Handler:    m.unaryHandler(),
+				Handler:    s.reloadableUnary(m),
 			})
 		}
 	}
@@ -379,15 +378,14 @@ func (s *GRPCServer) registerServiceMethods(
 func (s *GRPCServer) resolveMethodMessageDescriptors(
 	serviceName string,
 	method *descriptorpb.MethodDescriptorProto,
-	reg *protoregistry.Files,
 ) (protoreflect.MessageDescriptor, protoreflect.MessageDescriptor, error) {
-	if reg != nil {
-		inputDesc, err := getMessageDescriptor(reg, method.GetInputType())
-		if err == nil {
-			outputDesc, outErr := getMessageDescriptor(reg, method.GetOutputType())
-			if outErr == nil {
-				return inputDesc, outputDesc, nil
-			}
+	startup := s.descriptors.Startup()
+
+	inputDesc, err := getMessageDescriptor(startup, method.GetInputType())
+	if err == nil {
+		outputDesc, outErr := getMessageDescriptor(startup, method.GetOutputType())
+		if outErr == nil {
+			return inputDesc, outputDesc, nil
 		}
 	}
 
@@ -405,13 +403,7 @@ func (s *GRPCServer) createGrpcMocker(
 	svc *descriptorpb.ServiceDescriptorProto,
 	method *descriptorpb.MethodDescriptorProto,
 	inputDesc, outputDesc protoreflect.MessageDescriptor,
-	reg *protoregistry.Files,
 ) *grpcMocker {
-	var resolver protodesc.Resolver = protoregistry.GlobalFiles
-	if reg != nil {
-		resolver = reg
-	}
-
 	fullMethod := fmt.Sprintf("/%s/%s", serviceDesc.ServiceName, method.GetName())
 
 	return &grpcMocker{
@@ -419,9 +411,15 @@ func (s *GRPCServer) createGrpcMocker(
 		templateEngine:  s.templates(ctx),
 		errorFormatter:  s.errorFormatter,
 		recorder:        s.recorder,
-		typeResolver:    protosetinfra.NewTypeResolver(resolver),
+		typeResolver:    protosetinfra.NewTypeResolver(s.descriptorResolver()),
 		proxies:         s.proxies,
 		validator:       s.validator,
+		hooks:           s.hooks,
+		scripts:         s.scripts,
+		faults:          s.faults,
+		requests:        s.requests,
+		autoMock:        s.autoMock,
+		drift:           s.drift,
 		maxNestingDepth: s.maxNestingDepth,
 
 		inputDesc:  inputDesc,
@@ -435,7 +433,7 @@ func (s *GRPCServer) createGrpcMocker(
 		serverStream: method.GetServerStreaming(),
 		clientStream: method.GetClientStreaming(),
 
-		strictServiceMatch: s.proxies != nil && s.proxies.RouteByMethod(fullMethod) != nil,
+		strictServiceMatch: s.proxies != nil && s.proxies.RouteByMethod(fullMethod, nil) != nil,
 	}
 }
 
@@ -457,11 +455,7 @@ func getServiceName(file *descriptorpb.FileDescriptorProto, svc *descriptorpb.Se
 	return svc.GetName()
 }
 
-func getMessageDescriptor(reg *protoregistry.Files, messageType string) (protoreflect.MessageDescriptor, error) { //nolint:ireturn
-	if reg == nil {
-		reg = protoregistry.GlobalFiles
-	}
-
+func getMessageDescriptor(reg protodesc.Resolver, messageType string) (protoreflect.MessageDescriptor, error) { //nolint:ireturn
 	msgName := protoreflect.FullName(strings.TrimPrefix(messageType, "."))
 
 	desc, err := reg.FindDescriptorByName(msgName)
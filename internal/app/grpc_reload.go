@@ -0,0 +1,213 @@
+package app
+
+import (
+	"context"
+	"slices"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	protosetdom "github.com/bavix/gripmock/v3/internal/domain/protoset"
+	"github.com/bavix/gripmock/v3/internal/infra/stubcheck"
+)
+
+// SourceReload is the outcome of reloading the proto sources.
+type SourceReload struct {
+	// Services lists the services whose descriptors differ from the ones
+	// loaded at startup.
+	Services []string
+	// Stubs lists the stubs that no longer fit those descriptors.
+	Stubs []StaleStub
+}
+
+// StaleStub is a stub disagreeing with the reloaded descriptors of its method.
+type StaleStub struct {
+	ID      uuid.UUID
+	Service string
+	Method  string
+	Issues  []stubcheck.Issue
+}
+
+// SourcePaths returns the local proto sources the server was built from,
+// followed by its import paths: the files and directories a reload reads.
+func (s *GRPCServer) SourcePaths() []string {
+	imports, paths := s.localSources()
+
+	return append(paths, imports...)
+}
+
+func (s *GRPCServer) localSources() ([]string, []string) {
+	if s.params == nil {
+		return nil, nil
+	}
+
+	paths := slices.Concat(s.params.ProtoPath(), s.params.Sources())
+	for _, binding := range s.params.ProxyBindings() {
+		paths = append(paths, binding.Sources...)
+	}
+
+	return s.params.Imports(), slices.DeleteFunc(paths, func(path string) bool {
+		return !protosetdom.IsLocal(path)
+	})
+}
+
+// ReloadSources recompiles the local proto sources and serves what changed
+// without a restart. Changed and added files replace the previous reload in
+// the reloaded layer of the descriptor registry, which every lookup consults
+// before the startup descriptors; methods registered at startup switch to a mocker for their new
+// definition. A method whose streaming kind changed, and services or methods
+// removed from the sources, keep their startup definition until restart. On
+// error nothing changes.
+func (s *GRPCServer) ReloadSources(ctx context.Context) (SourceReload, error) {
+	s.reloadMu.Lock()
+	defer s.reloadMu.Unlock()
+
+	imports, paths := s.localSources()
+
+	files, err := protosetdom.Rebuild(ctx, s.descriptors.Startup(), imports, paths)
+	if err != nil {
+		return SourceReload{}, errors.Wrap(err, "failed to rebuild proto sources")
+	}
+
+	s.descriptors.SetReloaded(files)
+
+	return SourceReload{Services: serviceNames(files), Stubs: s.staleStubs(files)}, nil
+}
+
+// runtimeMockers are the mockers built for one generation of the registry.
+type runtimeMockers struct {
+	gen     uint64
+	mockers map[string]*grpcMocker
+}
+
+// redefinedMockers builds a mocker for every method of files registered at
+// startup, keyed by full method name.
+func (s *GRPCServer) redefinedMockers(ctx context.Context, files []protoreflect.FileDescriptor) map[string]*grpcMocker {
+	mockers := make(map[string]*grpcMocker)
+	startupFiles := s.descriptors.Startup()
+
+	for _, file := range files {
+		services := file.Services()
+		for i := range services.Len() {
+			service := services.Get(i)
+			serviceDesc := &grpc.ServiceDesc{ServiceName: string(service.FullName())}
+			serviceProto := protodesc.ToServiceDescriptorProto(service)
+
+			methods := service.Methods()
+			for j := range methods.Len() {
+				method := methods.Get(j)
+
+				startup := findMethodInFiles(startupFiles, serviceDesc.ServiceName, string(method.Name()))
+				if startup == nil {
+					continue
+				}
+
+				if startup.IsStreamingClient() != method.IsStreamingClient() ||
+					startup.IsStreamingServer() != method.IsStreamingServer() {
+					zerolog.Ctx(ctx).Warn().
+						Str("service", serviceDesc.ServiceName).
+						Str("method", string(method.Name())).
+						Msg("method changed its streaming kind; the new definition is served after a restart")
+
+					continue
+				}
+
+				m := s.createGrpcMocker(ctx, serviceDesc, serviceProto,
+					protodesc.ToMethodDescriptorProto(method), method.Input(), method.Output())
+				mockers[m.fullMethod] = m
+			}
+		}
+	}
+
+	return mockers
+}
+
+// staleStubs checks every stored stub of a service in files against its
+// reloaded method.
+func (s *GRPCServer) staleStubs(files []protoreflect.FileDescriptor) []StaleStub {
+	if s.budgerigar == nil {
+		return nil
+	}
+
+	checker := stubcheck.New(nil, false, fileList(files))
+	stale := make([]StaleStub, 0)
+
+	for _, stub := range s.budgerigar.All() {
+		if issues := checker.Check(stub); len(issues) > 0 {
+			stale = append(stale, StaleStub{ID: stub.ID, Service: stub.Service, Method: stub.Method, Issues: issues})
+		}
+	}
+
+	return stale
+}
+
+// current returns the mocker serving m's method: one built for the runtime
+// registry's definition once a reload or an upload redefines the method, m
+// itself otherwise.
+func (s *GRPCServer) current(ctx context.Context, m *grpcMocker) *grpcMocker {
+	// Generation is read before the files: a mutation racing the rebuild
+	// leaves the cached generation stale, so the next call rebuilds.
+	gen := s.descriptors.Generation()
+
+	cached := s.runtime.Load()
+	if cached == nil || cached.gen != gen {
+		files := make([]protoreflect.FileDescriptor, 0)
+
+		s.descriptors.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+			files = append(files, file)
+
+			return true
+		})
+
+		cached = &runtimeMockers{gen: gen, mockers: s.redefinedMockers(ctx, files)}
+		s.runtime.Store(cached)
+	}
+
+	if mocker, ok := cached.mockers[m.fullMethod]; ok {
+		return mocker
+	}
+
+	return m
+}
+
+func (s *GRPCServer) reloadableUnary(m *grpcMocker) grpc.MethodHandler {
+	return func(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+		return s.current(ctx, m).unaryHandler()(srv, ctx, dec, interceptor)
+	}
+}
+
+func (s *GRPCServer) reloadableStream(m *grpcMocker) grpc.StreamHandler {
+	return func(srv any, stream grpc.ServerStream) error {
+		return s.current(stream.Context(), m).streamHandler(srv, stream)
+	}
+}
+
+func serviceNames(files []protoreflect.FileDescriptor) []string {
+	names := make([]string, 0)
+
+	for _, file := range files {
+		services := file.Services()
+		for i := range services.Len() {
+			names = append(names, string(services.Get(i).FullName()))
+		}
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// fileList lets stubcheck resolve methods from a plain list of files.
+type fileList []protoreflect.FileDescriptor
+
+func (l fileList) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	for _, file := range l {
+		if !f(file) {
+			return
+		}
+	}
+}
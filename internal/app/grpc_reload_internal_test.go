@@ -0,0 +1,192 @@
+package app
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
+	protoloc "github.com/bavix/gripmock/v3/internal/domain/proto"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func writeReloadSet(t *testing.T, path string, fields ...string) {
+	t.Helper()
+
+	item := &descriptorpb.DescriptorProto{Name: new("Item")}
+	for i, field := range fields {
+		item.Field = append(item.Field, &descriptorpb.FieldDescriptorProto{
+			Name:     new(field),
+			JsonName: new(field),
+			Number:   new(int32(i + 1)),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		})
+	}
+
+	data, err := proto.Marshal(&descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{{
+		Name:        new("reload_items.proto"),
+		Package:     new("reloadtest"),
+		Syntax:      new("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{item},
+		Service: []*descriptorpb.ServiceDescriptorProto{{
+			Name: new("Items"),
+			Method: []*descriptorpb.MethodDescriptorProto{{
+				Name:       new("Get"),
+				InputType:  new(".reloadtest.Item"),
+				OutputType: new(".reloadtest.Item"),
+			}},
+		}},
+	}}})
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+//nolint:funlen
+func TestReloadSourcesServesChangedDescriptors(t *testing.T) {
+	t.Parallel()
+
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	descFile := filepath.Join(t.TempDir(), "items.pb")
+	writeReloadSet(t, descFile, "id")
+
+	budgerigar := stuber.NewBudgerigar()
+	budgerigar.PutMany(
+		&stuber.Stub{
+			Service: "reloadtest.Items",
+			Method:  "Get",
+			Input:   stuber.InputData{Equals: map[string]any{"id": "1"}},
+			Output:  stuber.Output{Data: map[string]any{"id": "1", "name": "widget"}},
+		},
+		&stuber.Stub{
+			Service: "reloadtest.Items",
+			Method:  "Get",
+			Input:   stuber.InputData{Equals: map[string]any{"id": "2"}},
+			Output:  stuber.Output{Data: map[string]any{"title": "gone"}},
+		},
+	)
+
+	registry := descriptors.NewRegistry()
+	grpcServer := NewGRPCServer("tcp", "127.0.0.1:0", protoloc.New([]string{descFile}, nil, nil), budgerigar, nil, nil,
+		registry, nil, nil, false, 256, nil, nil, DefaultServerLimits())
+
+	server, err := grpcServer.Build(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{descFile}, grpcServer.SourcePaths())
+
+	listener, err := (&net.ListenConfig{}).Listen(ctx, "tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+
+	go func() { _ = server.Serve(listener) }()
+
+	t.Cleanup(server.Stop)
+
+	result, err := grpcServer.ReloadSources(ctx)
+	require.NoError(t, err)
+	require.Empty(t, result.Services, "nothing changed on disk")
+
+	writeReloadSet(t, descFile, "id", "name")
+
+	result, err = grpcServer.ReloadSources(ctx)
+	require.NoError(t, err)
+	require.Equal(t, []string{"reloadtest.Items"}, result.Services)
+	require.Len(t, result.Stubs, 1, "the stub answering with an undefined field is reported")
+	require.Equal(t, "$.output.data.title", result.Stubs[0].Issues[0].Path)
+
+	method, err := findMethodDescriptor(registry, "reloadtest.Items", "Get")
+	require.NoError(t, err)
+	require.NotNil(t, method.Output().Fields().ByName("name"), "the gateways resolve the reloaded definition")
+
+	conn, err := grpc.NewClient(listener.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+
+	t.Cleanup(func() { _ = conn.Close() })
+
+	req := dynamicpb.NewMessage(method.Input())
+	req.Set(method.Input().Fields().ByName("id"), protoreflect.ValueOfString("1"))
+
+	resp := dynamicpb.NewMessage(method.Output())
+	require.NoError(t, conn.Invoke(ctx, "/reloadtest.Items/Get", req, resp))
+	require.Equal(t, "widget", resp.Get(method.Output().Fields().ByName("name")).String(),
+		"the service registered at startup answers with the new field")
+
+	writeReloadSet(t, descFile, "id")
+
+	result, err = grpcServer.ReloadSources(ctx)
+	require.NoError(t, err)
+	require.Empty(t, result.Services, "reverting the change drops the reloaded files")
+
+	method, err = findMethodDescriptor(registry, "reloadtest.Items", "Get")
+	require.NoError(t, err)
+	require.Nil(t, method.Output().Fields().ByName("name"))
+}
+
+//nolint:paralleltest // restores the process-wide session tracker.
+func TestReloadedDescriptorsStayOutOfSnapshots(t *testing.T) {
+	ctx, cancel := context.WithTimeout(t.Context(), 10*time.Second)
+	defer cancel()
+
+	descFile := filepath.Join(t.TempDir(), "items.pb")
+	writeReloadSet(t, descFile, "id")
+
+	budgerigar := stuber.NewBudgerigar()
+	registry := descriptors.NewRegistry()
+	grpcServer := NewGRPCServer("tcp", "127.0.0.1:0", protoloc.New([]string{descFile}, nil, nil), budgerigar, nil, nil,
+		registry, nil, nil, false, 256, nil, nil, DefaultServerLimits())
+
+	_, err := grpcServer.Build(ctx)
+	require.NoError(t, err)
+
+	restServer, err := NewRestServer(ctx, budgerigar, nil, nil, nil, registry, nil)
+	require.NoError(t, err)
+
+	hasName := func() bool {
+		method, err := findMethodDescriptor(registry, "reloadtest.Items", "Get")
+		require.NoError(t, err)
+
+		return method.Output().Fields().ByName("name") != nil
+	}
+
+	info := createSnapshot(t, restServer)
+
+	writeReloadSet(t, descFile, "id", "name")
+
+	_, err = grpcServer.ReloadSources(ctx)
+	require.NoError(t, err)
+	require.True(t, hasName())
+
+	require.Empty(t, registry.ServiceIDs(), "GET /descriptors lists uploads only")
+
+	w := httptest.NewRecorder()
+	restServer.DeleteService(w, scenarioRequest(t, http.MethodDelete, "/api/services/reloadtest.Items", "", ""),
+		"reloadtest.Items")
+	require.Equal(t, http.StatusNotFound, w.Code, "a reloaded service cannot be deleted")
+
+	require.Zero(t, createSnapshot(t, restServer).Descriptors, "a snapshot leaves reloaded files out")
+
+	w = httptest.NewRecorder()
+	restServer.RestoreSnapshot(w, scenarioRequest(t, http.MethodPost, "/api/snapshots/"+info.Id+"/restore", "", ""), info.Id)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.True(t, hasName(), "a restore keeps the reloaded files")
+
+	writeReloadSet(t, descFile, "id")
+
+	_, err = grpcServer.ReloadSources(ctx)
+	require.NoError(t, err)
+	require.False(t, hasName(), "the next reload replaces the files the restore kept")
+}
@@ -0,0 +1,28 @@
+package app
+
+import (
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/infra/requestcheck"
+)
+
+// validateRequests checks msgs against their protovalidate rules before any
+// stub is matched. Violations become INVALID_ARGUMENT with BadRequest details;
+// rules that cannot be evaluated are the mock's fault and become INTERNAL.
+func (m *grpcMocker) validateRequests(msgs ...*dynamicpb.Message) error {
+	for _, msg := range msgs {
+		err := m.requests.Validate(msg)
+		if errors.Is(err, requestcheck.ErrInvalidRules) {
+			return status.Error(codes.Internal, err.Error())
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
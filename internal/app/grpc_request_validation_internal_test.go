@@ -0,0 +1,74 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/infra/requestcheck"
+	"github.com/bavix/gripmock/v3/internal/pbs"
+)
+
+func newValidatedMocker(t *testing.T) *grpcMocker {
+	t.Helper()
+
+	resolver, err := pbs.NewResolver()
+	require.NoError(t, err)
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.CompositeResolver{
+			&protocompile.SourceResolver{Accessor: protocompile.SourceAccessorFromMap(map[string]string{
+				"greet.proto": `syntax = "proto3";
+import "buf/validate/validate.proto";
+message GreetRequest { string name = 1 [(buf.validate.field).string.min_len = 2]; }`,
+			})},
+			resolver,
+		},
+	}
+
+	files, err := compiler.Compile(t.Context(), "greet.proto")
+	require.NoError(t, err)
+
+	requests, err := requestcheck.New()
+	require.NoError(t, err)
+
+	mocker := newHookedMocker(t, nil)
+	mocker.inputDesc = files[0].Messages().ByName("GreetRequest")
+	mocker.requests = requests
+
+	return mocker
+}
+
+func TestRequestValidationRejectsUnaryCall(t *testing.T) {
+	t.Parallel()
+
+	mocker := newValidatedMocker(t)
+	putHookStub(mocker)
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	details := status.Convert(err).Details()
+	require.Len(t, details, 1)
+
+	badRequest, ok := details[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Equal(t, "name", badRequest.GetFieldViolations()[0].GetField())
+	require.Equal(t, "string.min_len", badRequest.GetFieldViolations()[0].GetReason())
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 1)
+	require.Equal(t, uint32(codes.InvalidArgument), calls[0].Code)
+
+	req := dynamicpb.NewMessage(mocker.inputDesc)
+	req.Set(mocker.inputDesc.Fields().ByName("name"), protoreflect.ValueOfString("Bob"))
+
+	_, err = mocker.handleUnary(t.Context(), nil, req)
+	require.NoError(t, err)
+}
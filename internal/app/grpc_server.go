@@ -6,6 +6,7 @@ import (
 	"crypto/tls"
 	"runtime"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -13,14 +14,21 @@ import (
 	"google.golang.org/grpc"
 	_ "google.golang.org/grpc/encoding/gzip" // registers the gzip compressor for gRPC
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/descriptorpb"
 
 	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
 	"github.com/bavix/gripmock/v3/internal/domain/history"
 	protoloc "github.com/bavix/gripmock/v3/internal/domain/proto"
 	protosetdom "github.com/bavix/gripmock/v3/internal/domain/protoset"
+	"github.com/bavix/gripmock/v3/internal/infra/automock"
+	"github.com/bavix/gripmock/v3/internal/infra/drift"
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
+	"github.com/bavix/gripmock/v3/internal/infra/plugins"
 	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
 	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+	"github.com/bavix/gripmock/v3/internal/infra/requestcheck"
+	"github.com/bavix/gripmock/v3/internal/infra/script"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
 )
@@ -96,6 +104,21 @@ type GRPCServer struct {
 
 	templateOnce   sync.Once
 	templateEngine *template.Engine
+
+	hooks    *plugins.Hooks
+	scripts  *script.Runner
+	faults   *faults.Injector
+	requests *requestcheck.Validator
+	autoMock *automock.Synthesizer
+	drift    *drift.Store
+
+	proxyRules string
+
+	// reloadMu serializes source reloads, so the last one to start is the one
+	// served. runtime holds the mockers of the startup methods the registry
+	// redefines, for one registry generation.
+	reloadMu sync.Mutex
+	runtime  atomic.Pointer[runtimeMockers]
 }
 
 type grpcMocker struct {
@@ -106,6 +129,12 @@ type grpcMocker struct {
 	typeResolver   *protosetinfra.TypeResolver
 	proxies        *proxyroutes.Registry
 	validator      *validator.Validate
+	hooks          *plugins.Hooks
+	scripts        *script.Runner
+	faults         *faults.Injector
+	requests       *requestcheck.Validator
+	autoMock       *automock.Synthesizer
+	drift          *drift.Store
 
 	inputDesc  protoreflect.MessageDescriptor
 	outputDesc protoreflect.MessageDescriptor
@@ -195,6 +224,31 @@ func NewGRPCServer(
 	}
 }
 
+// SetHooks installs the plugin lifecycle hooks run for every mocked call.
+func (s *GRPCServer) SetHooks(hooks *plugins.Hooks) { s.hooks = hooks }
+
+// SetScripts installs the runner for output scripts.
+func (s *GRPCServer) SetScripts(scripts *script.Runner) { s.scripts = scripts }
+
+// SetFaults installs the fault injector shared with the gateways and the API.
+func (s *GRPCServer) SetFaults(injector *faults.Injector) { s.faults = injector }
+
+// SetRequestValidator checks incoming requests against their protovalidate
+// rules before matching; nil disables the check.
+func (s *GRPCServer) SetRequestValidator(v *requestcheck.Validator) { s.requests = v }
+
+// SetAutoMock answers calls no stub matches with responses synthesized from
+// the output descriptor, for the methods synth covers; nil disables it.
+func (s *GRPCServer) SetAutoMock(synth *automock.Synthesizer) { s.autoMock = synth }
+
+// SetProxyRules loads the routing rules in file into the proxy routes on
+// Build; empty leaves the routes as bound by the proxy sources.
+func (s *GRPCServer) SetProxyRules(file string) { s.proxyRules = file }
+
+// SetDrift records the comparisons of shadow-mode calls; nil disables shadow
+// calls, which are then served like replay.
+func (s *GRPCServer) SetDrift(store *drift.Store) { s.drift = store }
+
 func (s *GRPCServer) Proxies() *proxyroutes.Registry {
 	return s.proxies
 }
@@ -209,6 +263,10 @@ func (s *GRPCServer) Build(ctx context.Context) (*grpc.Server, error) {
 
 	var descriptors []*descriptorpb.FileDescriptorSet
 
+	// The server owns its descriptors: another server in the process may
+	// define the same files differently.
+	files := new(protoregistry.Files)
+
 	if s.params != nil {
 		imports = s.params.Imports()
 		protoPaths = s.params.ProtoPath()
@@ -217,18 +275,34 @@ func (s *GRPCServer) Build(ctx context.Context) (*grpc.Server, error) {
 
 	hasBindings := s.params != nil && s.params.HasProxyBindings()
 	if hasBindings {
-		descriptors, s.proxies, err = s.buildProxiesWithBindings(ctx, imports)
+		descriptors, s.proxies, err = s.buildProxiesWithBindings(ctx, files, imports)
 	} else {
-		descriptors, s.proxies, err = s.buildProxiesFromSources(ctx, imports, protoPaths, sources)
+		descriptors, s.proxies, err = s.buildProxiesFromSources(ctx, files, imports, protoPaths, sources)
 	}
 
 	if err != nil {
 		return nil, err
 	}
 
+	if s.proxies == nil && s.proxyRules != "" {
+		// Rules may send stub-only services to a rule upstream, so they
+		// need a registry even without a proxy source.
+		if s.proxies, err = proxyroutes.New(ctx, nil, s.remoteClient, nil); err != nil {
+			return nil, errors.Wrap(err, "failed to initialize proxy routes")
+		}
+	}
+
+	if s.proxyRules != "" {
+		if err := s.proxies.LoadRules(s.proxyRules); err != nil {
+			s.proxies.Close()
+
+			return nil, errors.Wrap(err, "failed to load proxy routing rules")
+		}
+	}
+
 	if s.proxies != nil {
 		s.startProxyCleanup(ctx)
-		s.registerProxyDescriptors(ctx)
+		s.registerProxyDescriptors(ctx, files)
 	}
 
 	if hasBindings {
@@ -237,7 +311,7 @@ func (s *GRPCServer) Build(ctx context.Context) (*grpc.Server, error) {
 		// build here. The from-sources branch compiled protoPaths+sources
 		// but lacks the reflection-fetched proxy descriptors.
 		if len(protoPaths) > 0 {
-			nonProxyDescriptors, err := protosetdom.Build(ctx, imports, protoPaths, s.remoteClient)
+			nonProxyDescriptors, err := protosetdom.BuildInto(ctx, files, imports, protoPaths, s.remoteClient)
 			if err != nil {
 				return nil, errors.Wrap(err, "failed to build descriptors")
 			}
@@ -252,9 +326,11 @@ func (s *GRPCServer) Build(ctx context.Context) (*grpc.Server, error) {
 		s.waiter.Wait(ctx)
 	}
 
+	s.descriptors.SetStartup(files)
+
 	server := s.createServer(ctx)
-	s.setupHealthCheck(ctx, server, nil)
-	s.registerServices(ctx, server, descriptors, nil)
+	s.setupHealthCheck(ctx, server)
+	s.registerServices(ctx, server, descriptors)
 	s.markServerReady(ctx)
 
 	return server, nil
@@ -273,10 +349,6 @@ func (s *GRPCServer) templates(ctx context.Context) *template.Engine {
 	return s.templateEngine
 }
 
-// BuildFromDescriptorSet creates a gRPC server from a pre-built FileDescriptorSet.
-// Used by the SDK for embedded mode. Does not use GlobalFiles.
-// If recorder is non-nil, all gRPC calls are recorded for History/Verify.
-
 // methodFilesLister abstracts a descriptor registry that supports iteration
 // over file descriptors. Implemented by *protoregistry.Files and
 // *descriptors.Registry.
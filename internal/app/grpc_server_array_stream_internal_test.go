@@ -95,7 +95,7 @@ func TestHandleArrayStreamDataSendsAllMessages(t *testing.T) {
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
 
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	require.NoError(t, err)
 	require.Len(t, stream.sentMessages, 3)
 }
@@ -119,7 +119,7 @@ func TestHandleArrayStreamDataEmptyStream(t *testing.T) {
 	}
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	require.NoError(t, err)
 	require.Empty(t, stream.sentMessages)
 }
@@ -149,7 +149,7 @@ func TestHandleArrayStreamDataWithDelay(t *testing.T) {
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
 	start := time.Now()
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	duration := time.Since(start)
 
 	require.NoError(t, err)
@@ -180,7 +180,7 @@ func TestHandleArrayStreamDataWithTemplates(t *testing.T) {
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
 
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	require.NoError(t, err)
 	require.Len(t, stream.sentMessages, 2)
 
@@ -215,7 +215,7 @@ func TestHandleArrayStreamDataInvalidDataType(t *testing.T) {
 	}
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	require.Error(t, err)
 
 	st, ok := status.FromError(err)
@@ -247,7 +247,7 @@ func TestHandleArrayStreamDataSendMsgError(t *testing.T) {
 	}
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "send error")
 }
@@ -274,7 +274,7 @@ func TestHandleArrayStreamDataContextCancelled(t *testing.T) {
 	}
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	require.Error(t, err)
 	require.Equal(t, context.Canceled, err)
 }
@@ -302,7 +302,7 @@ func TestHandleArrayStreamDataMessageIndexInTemplates(t *testing.T) {
 	}
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	require.NoError(t, err)
 	require.Len(t, stream.sentMessages, 3)
 
@@ -335,7 +335,7 @@ func TestHandleArrayStreamDataWithHeaders(t *testing.T) {
 	}
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	require.NoError(t, err)
 	require.Len(t, stream.sentMessages, 1)
 
@@ -365,7 +365,7 @@ func TestHandleArrayStreamDataEOFError(t *testing.T) {
 	}
 
 	inputMsg := dynamicpb.NewMessage(mocker.inputDesc)
-	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, inputMsg, time.Now(), 1, nil)
 	require.Error(t, err)
 	require.Contains(t, err.Error(), "failed to send response")
 }
@@ -398,7 +398,7 @@ func TestHandleArrayStreamDataComputesDelayPerElement(t *testing.T) {
 
 	started := time.Now()
 
-	_, err := mocker.handleArrayStreamData(stream, stub, dynamicpb.NewMessage(mocker.inputDesc), time.Now(), 1)
+	_, err := mocker.handleArrayStreamData(stream, stub, dynamicpb.NewMessage(mocker.inputDesc), time.Now(), 1, nil)
 	elapsed := time.Since(started)
 
 	require.NoError(t, err)
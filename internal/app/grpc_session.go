@@ -13,6 +13,7 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/dynamicpb"
 
+	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
 	"github.com/bavix/gripmock/v3/internal/infra/session"
 )
 
@@ -39,6 +40,7 @@ func sessionFromContext(ctx context.Context) string {
 type bidiRecordingStream struct {
 	grpc.ServerStream
 
+	resolver      *protosetinfra.TypeResolver
 	requests      []map[string]any
 	responses     []map[string]any
 	respHeader    metadata.MD
@@ -47,6 +49,7 @@ type bidiRecordingStream struct {
 	maxItems      int
 	stubTrailers  map[string]string
 	recordHeaders bool
+	synthesized   bool
 }
 
 func (s *bidiRecordingStream) SetHeader(md metadata.MD) error {
@@ -71,7 +74,7 @@ func (s *bidiRecordingStream) RecvMsg(m any) error {
 		return err
 	}
 
-	if msgMap := protoToMap(m); msgMap != nil && len(s.requests) < s.maxItems {
+	if msgMap := protoToMap(m, s.resolver); msgMap != nil && len(s.requests) < s.maxItems {
 		s.requests = append(s.requests, msgMap)
 	}
 
@@ -84,7 +87,7 @@ func (s *bidiRecordingStream) SendMsg(m any) error {
 		return err
 	}
 
-	if msgMap := protoToMap(m); msgMap != nil && len(s.responses) < s.maxItems {
+	if msgMap := protoToMap(m, s.resolver); msgMap != nil && len(s.responses) < s.maxItems {
 		s.responses = append(s.responses, msgMap)
 	}
 
@@ -136,7 +139,7 @@ func (m *grpcMocker) recordCall(
 		}
 	}
 
-	recordCall(m.recorder, m.fullServiceName, m.methodName, sessionFromContext(ctx),
+	recordCall(m.recorder, m.fullServiceName, m.methodName, callMetaFromContext(ctx),
 		stubID, code, timestamp, requests, recordedResponses, respHeaders, errMsg)
 }
 
@@ -146,7 +149,7 @@ func (m *grpcMocker) recordUnmatched(
 	requests []map[string]any,
 	callErr error,
 ) {
-	if m.proxyFallbackWillServe(callErr) {
+	if m.proxyFallbackWillServe(ctx, callErr) {
 		return
 	}
 
@@ -263,6 +263,8 @@ func (grpcwebResponse) WriteError(w http.ResponseWriter, r *http.Request, code c
 	writeGRPCWebTrailers(w, code, msg)
 }
 
+func (grpcwebResponse) Transport() string { return history.TransportGRPCWeb }
+
 func (grpcwebResponse) WriteSuccess(w http.ResponseWriter, r *http.Request) {
 	setGRPCWebContentType(w, r)
 	w.WriteHeader(http.StatusOK)
@@ -379,7 +381,7 @@ type grpcwebAdapter struct {
 }
 
 func newGRPCWebAdapter(r *http.Request, w http.ResponseWriter, mocker *grpcMocker) *grpcwebAdapter {
-	ctx := httpHeadersToGRPCContext(r.Context(), r.Header)
+	ctx := gatewayCallContext(r, history.TransportGRPCWeb)
 
 	return &grpcwebAdapter{
 		baseStreamAdapter: baseStreamAdapter{
@@ -15,6 +15,7 @@ import (
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/reflect/protodesc"
 
+	protosetinfra "github.com/bavix/gripmock/v3/internal/infra/protoset"
 	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
 	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
@@ -34,6 +35,7 @@ type mockableHealthServer struct {
 	templateEngine *template.Engine
 	storage        *stuber.Budgerigar
 	resolver       protodesc.Resolver
+	typeResolver   *protosetinfra.TypeResolver
 	proxies        *proxyroutes.Registry
 }
 
@@ -49,6 +51,7 @@ func newMockableHealthServer(
 		templateEngine: templateEngine,
 		storage:        storage,
 		resolver:       resolver,
+		typeResolver:   protosetinfra.NewTypeResolver(resolver),
 		proxies:        proxies,
 	}
 }
@@ -212,7 +215,7 @@ func (s *mockableHealthServer) proxyCheck(
 	}
 
 	s.captureProxyHealthStub(
-		ctx, req, healthMethodCheck, proxycapture.MessageToMap(resp),
+		ctx, req, healthMethodCheck, proxycapture.MessageToMap(resp, s.typeResolver),
 		nil, err, captureMetadata(header, trailer), route, elapsed,
 	)
 
@@ -267,7 +270,7 @@ func (s *mockableHealthServer) proxyWatch(
 			return recvErr
 		}
 
-		responses = append(responses, proxycapture.MessageToMap(resp))
+		responses = append(responses, proxycapture.MessageToMap(resp, s.typeResolver))
 
 		if sendErr := stream.Send(resp); sendErr != nil {
 			return sendErr
@@ -301,6 +304,7 @@ func (s *mockableHealthServer) captureProxyHealthStub(
 		stub.Output.Delay = types.NewDelay(elapsed)
 	}
 
+	finishCapturedStub(ctx, route, stub)
 	s.storage.PutMany(stub)
 }
 
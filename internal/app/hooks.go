@@ -0,0 +1,118 @@
+package app
+
+import (
+	"context"
+
+	"github.com/cockroachdb/errors"
+	"github.com/goccy/go-json"
+	"google.golang.org/grpc/codes"
+
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+)
+
+// hookStub is the JSON form of stub handed to hooks.
+func hookStub(stub *stuber.Stub) map[string]any {
+	raw, err := json.Marshal(stub)
+	if err != nil {
+		return nil
+	}
+
+	var out map[string]any
+	if err := json.Unmarshal(raw, &out); err != nil {
+		return nil
+	}
+
+	return out
+}
+
+// stubFromHook replaces stub with what an on-stub-upsert hook handed back.
+// Session and source stay as the API assigned them.
+func stubFromHook(raw map[string]any, stub *stuber.Stub) error {
+	if raw == nil {
+		return nil
+	}
+
+	data, err := json.Marshal(raw)
+	if err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	next := new(stuber.Stub)
+	if err := jsondecoder.Unmarshal(data, next); err != nil {
+		return err //nolint:wrapcheck
+	}
+
+	next.Session, next.Source = stub.Session, stub.Source
+	*stub = *next
+
+	return nil
+}
+
+// applyUpsertHooks runs on-stub-upsert hooks over stubs about to be stored and
+// validates what they hand back.
+func (h *RestServer) applyUpsertHooks(ctx context.Context, transport string, stubs []*stuber.Stub) error {
+	if !h.hooks.Has(pkgplugins.HookOnStubUpsert) {
+		return nil
+	}
+
+	for _, stub := range stubs {
+		event, err := h.hooks.Run(ctx, &pkgplugins.HookEvent{
+			Hook:      pkgplugins.HookOnStubUpsert,
+			Transport: transport,
+			Service:   stub.Service,
+			Method:    stub.Method,
+			Session:   stub.Session,
+			Stub:      hookStub(stub),
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := stubFromHook(event.Stub, stub); err != nil {
+			return errors.Wrap(err, "on-stub-upsert hook returned an invalid stub")
+		}
+
+		if err := h.validateStub(stub); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hookResponse describes a rendered output to before-response hooks. It is a
+// copy: rendered data may still share maps with the stored stub.
+func hookResponse(output stuber.Output, data any) *pkgplugins.HookResponse {
+	resp := &pkgplugins.HookResponse{
+		Data:     deepCopyAny(data),
+		Headers:  deepCopyStringMap(output.Headers),
+		Trailers: deepCopyStringMap(output.Trailers),
+		Error:    output.Error,
+	}
+
+	if output.Code != nil {
+		resp.Code = new(uint32(*output.Code))
+	}
+
+	return resp
+}
+
+// applyHookResponse writes what a hook answered back onto output and data.
+// A nil response leaves both untouched.
+func applyHookResponse(resp *pkgplugins.HookResponse, output *stuber.Output, data *any) {
+	if resp == nil {
+		return
+	}
+
+	*data = resp.Data
+	output.Headers = resp.Headers
+	output.Trailers = resp.Trailers
+	output.Error = resp.Error
+	output.Code = nil
+
+	if resp.Code != nil {
+		output.Code = new(codes.Code(*resp.Code))
+	}
+}
@@ -10,7 +10,13 @@ import (
 
 	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
 	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/automock"
+	"github.com/bavix/gripmock/v3/internal/infra/drift"
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
+	"github.com/bavix/gripmock/v3/internal/infra/plugins"
 	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+	"github.com/bavix/gripmock/v3/internal/infra/requestcheck"
+	"github.com/bavix/gripmock/v3/internal/infra/script"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
 )
@@ -53,6 +59,42 @@ func (g *MultiProtocolGateway) RequireProtocolVersion(require bool) {
 	g.connect.RequireProtocolVersion(require)
 }
 
+// SetHooks installs the plugin lifecycle hooks on both protocols.
+func (g *MultiProtocolGateway) SetHooks(hooks *plugins.Hooks) {
+	g.connect.SetHooks(hooks)
+	g.grpcweb.SetHooks(hooks)
+}
+
+// SetScripts installs the runner for output scripts on both protocols.
+func (g *MultiProtocolGateway) SetScripts(scripts *script.Runner) {
+	g.connect.SetScripts(scripts)
+	g.grpcweb.SetScripts(scripts)
+}
+
+// SetFaults installs the fault injector on both protocols.
+func (g *MultiProtocolGateway) SetFaults(injector *faults.Injector) {
+	g.connect.SetFaults(injector)
+	g.grpcweb.SetFaults(injector)
+}
+
+// SetRequestValidator installs the protovalidate check on both protocols.
+func (g *MultiProtocolGateway) SetRequestValidator(v *requestcheck.Validator) {
+	g.connect.SetRequestValidator(v)
+	g.grpcweb.SetRequestValidator(v)
+}
+
+// SetAutoMock installs the synthesizer answering misses on both protocols.
+func (g *MultiProtocolGateway) SetAutoMock(synth *automock.Synthesizer) {
+	g.connect.SetAutoMock(synth)
+	g.grpcweb.SetAutoMock(synth)
+}
+
+// SetDrift installs the store of shadow-mode comparisons on both protocols.
+func (g *MultiProtocolGateway) SetDrift(store *drift.Store) {
+	g.connect.SetDrift(store)
+	g.grpcweb.SetDrift(store)
+}
+
 func (g *MultiProtocolGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if r.Method != http.MethodPost && r.Method != http.MethodGet {
 		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
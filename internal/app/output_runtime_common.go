@@ -8,6 +8,7 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
+	"github.com/bavix/gripmock/v3/internal/infra/faker"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
 	"github.com/bavix/gripmock/v3/internal/infra/types"
@@ -47,9 +48,28 @@ func newTemplateData(
 		Requests:      requests,
 		StubID:        stubID,
 		RequestID:     stubID,
+		Faker:         templateFaker(stub, attemptNumber),
+		Session:       sessionOf(headers),
 	}
 }
 
+// templateFaker is the sequence faker and uuid draw from for a stub with a
+// seed. Nil leaves the engine's sequences in charge: the caller's session
+// seed, then the server one.
+func templateFaker(stub *stuber.Stub, matchNumber int) *faker.Sequence {
+	if stub != nil && stub.Seed != nil {
+		return faker.Derive(*stub.Seed, uint64(max(matchNumber, 0)))
+	}
+
+	return nil
+}
+
+func sessionOf(headers map[string]any) string {
+	sessionID, _ := headers[sessionHeaderKey].(string)
+
+	return sessionID
+}
+
 func outputStatusBase(output stuber.Output) *status.Status {
 	if output.Error == "" && output.Code == nil {
 		return nil
@@ -0,0 +1,54 @@
+package app
+
+import (
+	"context"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/gripmock/v3/internal/infra/script"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	"github.com/bavix/gripmock/v3/internal/infra/template"
+)
+
+// runOutputScript runs output.script, if any, and lets the response it assigns
+// override the rendered output. It runs after templates, so values a script
+// copies from the request are never rendered as templates.
+func runOutputScript(
+	ctx context.Context,
+	runner *script.Runner,
+	output *stuber.Output,
+	data *any,
+	templateData template.Data,
+) error {
+	if output.Script == "" {
+		return nil
+	}
+
+	res, err := runner.Run(ctx, output.Script, templateData)
+	if err != nil {
+		return status.Error(codes.Internal, "output script: "+err.Error())
+	}
+
+	if res.HasData {
+		*data = res.Data
+	}
+
+	if res.Headers != nil {
+		output.Headers = res.Headers
+	}
+
+	if res.Trailers != nil {
+		output.Trailers = res.Trailers
+	}
+
+	if res.Error != nil {
+		output.Error = *res.Error
+	}
+
+	if res.Code != nil {
+		output.Code = res.Code
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/infra/script"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func TestOutputScriptOverridesOutput(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, nil)
+	mocker.scripts = script.New(script.Limits{})
+
+	stub := putHookStub(mocker)
+	stub.Output.Headers = map[string]string{"x-stub": "kept"}
+	stub.Output.Script = `
+if attemptNumber > 1:
+    response = {"code": "UNAVAILABLE", "error": "try later"}
+else:
+    response = {"data": {"result": "attempt %d" % attemptNumber}}
+`
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.NoError(t, err)
+
+	_, err = mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.Equal(t, codes.Unavailable, status.Code(err))
+
+	calls := hookCalls(t, mocker)
+	require.Len(t, calls, 2)
+	require.Equal(t, "attempt 1", calls[0].Responses[0]["result"])
+	require.Equal(t, "kept", calls[0].ResponseHeaders["x-stub"])
+	require.Equal(t, uint32(codes.Unavailable), calls[1].Code)
+}
+
+func TestOutputScriptRunawayFails(t *testing.T) {
+	t.Parallel()
+
+	mocker := newHookedMocker(t, nil)
+	mocker.scripts = script.New(script.Limits{MaxSteps: 100})
+
+	stub := putHookStub(mocker)
+	stub.Output.Script = "while True:\n    pass\n"
+
+	_, err := mocker.handleUnary(t.Context(), nil, dynamicpb.NewMessage(mocker.inputDesc))
+	require.Equal(t, codes.Internal, status.Code(err))
+	require.Contains(t, status.Convert(err).Message(), "script limit exceeded")
+}
+
+func TestValidateStubRejectsBadScript(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	stub := &stuber.Stub{
+		Service: testServiceName,
+		Method:  testMethodName,
+		Input:   stuber.InputData{Contains: map[string]any{}},
+		Output:  stuber.Output{Script: `response = {"data": request`},
+	}
+
+	var validationErr *ValidationError
+	require.ErrorAs(t, server.validateStub(stub), &validationErr)
+	require.Equal(t, "script", validationErr.Field)
+
+	stub.Output.Script = `response = {"data": request}`
+	require.NoError(t, server.validateStub(stub))
+}
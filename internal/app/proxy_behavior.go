@@ -1,6 +1,8 @@
 package app
 
 import (
+	"context"
+
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 
@@ -11,6 +13,7 @@ type proxyBehavior interface {
 	proxyOnly() bool
 	captureMiss() bool
 	canFallback(err error) bool
+	shadows() bool
 }
 
 type proxyOnlyBehavior struct{}
@@ -18,24 +21,36 @@ type proxyOnlyBehavior struct{}
 func (proxyOnlyBehavior) proxyOnly() bool            { return true }
 func (proxyOnlyBehavior) captureMiss() bool          { return false }
 func (proxyOnlyBehavior) canFallback(err error) bool { return false }
+func (proxyOnlyBehavior) shadows() bool              { return false }
 
 type replayBehavior struct{}
 
 func (replayBehavior) proxyOnly() bool            { return false }
 func (replayBehavior) captureMiss() bool          { return false }
 func (replayBehavior) canFallback(err error) bool { return status.Code(err) == codes.NotFound }
+func (replayBehavior) shadows() bool              { return false }
 
 type captureBehavior struct{}
 
 func (captureBehavior) proxyOnly() bool            { return false }
 func (captureBehavior) captureMiss() bool          { return true }
 func (captureBehavior) canFallback(err error) bool { return status.Code(err) == codes.NotFound }
+func (captureBehavior) shadows() bool              { return false }
+
+// shadowBehavior serves like replay and also sends the calls stubs answered
+// to the upstream, comparing both responses.
+type shadowBehavior struct{}
+
+func (shadowBehavior) proxyOnly() bool            { return false }
+func (shadowBehavior) captureMiss() bool          { return false }
+func (shadowBehavior) canFallback(err error) bool { return status.Code(err) == codes.NotFound }
+func (shadowBehavior) shadows() bool              { return true }
 
 // proxyFallbackWillServe reports whether the given stub-matching error will be
 // retried through the proxy by streamHandler (mirrors its behavior+canFallback
 // check). When true, the proxy leg owns the call's history record.
-func (m *grpcMocker) proxyFallbackWillServe(err error) bool {
-	behavior := newProxyBehavior(m.proxyRoute())
+func (m *grpcMocker) proxyFallbackWillServe(ctx context.Context, err error) bool {
+	behavior := newProxyBehavior(m.proxyRoute(ctx))
 
 	return behavior != nil && behavior.canFallback(err)
 }
@@ -53,6 +68,8 @@ func newProxyBehavior(route *proxyroutes.Route) proxyBehavior {
 		return captureBehavior{}
 	case proxyroutes.ModeReplay:
 		return replayBehavior{}
+	case proxyroutes.ModeShadow:
+		return shadowBehavior{}
 	default:
 		return proxyOnlyBehavior{}
 	}
@@ -1,10 +1,10 @@
 package app
 
 import (
-	"fmt"
 	"net/http"
 	"sort"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/cockroachdb/errors"
@@ -119,10 +119,14 @@ func (h *RestServer) ListHistory(w http.ResponseWriter, r *http.Request, params
 	}
 
 	calls, total := historyWindow(h.history, history.FilterOpts{
-		Session:   muxmiddleware.FromRequest(r),
-		Service:   stringFromPtr(params.Service),
-		Method:    stringFromPtr(params.Method),
-		ErrorOnly: params.Error != nil && *params.Error,
+		Session:      muxmiddleware.FromRequest(r),
+		Service:      stringFromPtr(params.Service),
+		Method:       stringFromPtr(params.Method),
+		ErrorOnly:    params.Error != nil && *params.Error,
+		Transport:    stringFromPtr(params.Transport),
+		Peer:         stringFromPtr(params.Peer),
+		Headers:      historyHeaderFilter(params.Header),
+		DeadlineOnly: params.Deadline != nil && *params.Deadline,
 	}, intFromPtr(params.Limit), intFromPtr(params.Offset))
 
 	w.Header().Set("X-Total-Count", strconv.Itoa(total))
@@ -135,6 +139,25 @@ func (h *RestServer) ListHistory(w http.ResponseWriter, r *http.Request, params
 	h.writeResponse(r.Context(), w, out)
 }
 
+// historyHeaderFilter turns repeated `name` / `name=value` parameters into
+// FilterOpts.Headers.
+func historyHeaderFilter(params *[]string) map[string]string {
+	if params == nil || len(*params) == 0 {
+		return nil
+	}
+
+	out := make(map[string]string, len(*params))
+
+	for _, param := range *params {
+		name, value, _ := strings.Cut(param, "=")
+		if name = strings.ToLower(strings.TrimSpace(name)); name != "" {
+			out[name] = value
+		}
+	}
+
+	return out
+}
+
 type historyCounter interface {
 	CountFilter(opts history.FilterOpts) int
 }
@@ -225,6 +248,26 @@ func restCallMessages(c history.CallRecord, r *rest.CallRecord) {
 	if len(c.ResponseHeaders) > 0 {
 		r.ResponseHeaders = c.ResponseHeaders
 	}
+
+	if len(c.RequestHeaders) > 0 {
+		r.RequestHeaders = c.RequestHeaders
+	}
+}
+
+func restCallOrigin(c history.CallRecord, r *rest.CallRecord) {
+	if c.Peer != "" {
+		r.Peer = &c.Peer
+	}
+
+	if c.Transport != "" {
+		r.Transport = &c.Transport
+	}
+
+	r.Deadline = c.Deadline
+
+	if c.Synthesized {
+		r.Synthesized = &c.Synthesized
+	}
 }
 
 func historyCallRecordToRest(c history.CallRecord) rest.CallRecord {
@@ -244,6 +287,7 @@ func historyCallRecordToRest(c history.CallRecord) rest.CallRecord {
 	}
 
 	restCallMessages(c, &r)
+	restCallOrigin(c, &r)
 
 	if c.Error != "" {
 		r.Error = &c.Error
@@ -265,7 +309,8 @@ func historyCallRecordToRest(c history.CallRecord) rest.CallRecord {
 	return r
 }
 
-// VerifyCalls verifies that a method was called the expected number of times.
+// VerifyCalls verifies that a method was called the expected number of times,
+// counting only calls that satisfy the request's payload and header matchers.
 func (h *RestServer) VerifyCalls(w http.ResponseWriter, r *http.Request) {
 	if h.history == nil {
 		w.WriteHeader(http.StatusBadRequest)
@@ -282,17 +327,69 @@ func (h *RestServer) VerifyCalls(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	actual := countHistory(h.history, history.FilterOpts{
-		Service: req.Service,
-		Method:  req.Method,
-		Session: muxmiddleware.FromRequest(r),
-	})
-	if actual != req.ExpectedCount {
+	exp, err := restVerifyExpectation(req, muxmiddleware.FromRequest(r))
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.writeResponseError(r.Context(), w, errors.Wrap(err, "invalid verify request"))
+
+		return
+	}
+
+	result := history.Verify(h.history, exp)
+	if !exp.Met(result.Actual) {
+		verifyErr := rest.VerifyError{
+			Message:  new(verifyFailureMessage(exp, result.Actual)),
+			Expected: req.ExpectedCount,
+			Actual:   &result.Actual,
+		}
+
+		if len(result.Closest) > 0 {
+			verifyErr.Closest = new(restVerifyClosest(result.Closest))
+		}
+
+		w.WriteHeader(http.StatusBadRequest)
+		h.writeResponse(r.Context(), w, verifyErr)
+
+		return
+	}
+
+	h.writeResponse(r.Context(), w, rest.MessageOK{Message: "ok", Time: time.Now()})
+}
+
+// VerifyCallOrder verifies that calls happened in the order of the request's steps.
+func (h *RestServer) VerifyCallOrder(w http.ResponseWriter, r *http.Request) {
+	if h.history == nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.writeResponse(r.Context(), w, rest.VerifyOrderError{Message: new("history is disabled")})
+
+		return
+	}
+
+	var req rest.VerifyOrderRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.writeResponseError(r.Context(), w, errors.Wrap(err, "invalid verify order request"))
+
+		return
+	}
+
+	session := muxmiddleware.FromRequest(r)
+
+	steps, err := restVerifySteps(req.Steps, session)
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.writeResponseError(r.Context(), w, errors.Wrap(err, "invalid verify order request"))
+
+		return
+	}
+
+	result := history.VerifyOrder(h.history, session, steps)
+	if !result.OK() {
 		w.WriteHeader(http.StatusBadRequest)
-		h.writeResponse(r.Context(), w, rest.VerifyError{
-			Message:  new(fmt.Sprintf("expected %s/%s to be called %d times, got %d", req.Service, req.Method, req.ExpectedCount, actual)),
-			Expected: &req.ExpectedCount,
-			Actual:   &actual,
+		h.writeResponse(r.Context(), w, rest.VerifyOrderError{
+			Message: new(result.Message(steps)),
+			Step:    &result.Failed,
+			Counts:  &result.Counts,
 		})
 
 		return
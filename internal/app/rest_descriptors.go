@@ -85,7 +85,7 @@ func registerDescriptorBytes(h *RestServer, byt []byte) ([]string, error) {
 		return nil, ErrFileDescriptorSetNoFiles
 	}
 
-	files, err := decodeDescriptorFiles(&fds)
+	files, err := decodeDescriptorFiles(&fds, h.restDescriptors.Startup())
 	if err != nil {
 		return nil, err
 	}
@@ -106,7 +106,9 @@ func registerDescriptorBytes(h *RestServer, byt []byte) ([]string, error) {
 	return serviceIDs, nil
 }
 
-func decodeDescriptorFiles(fds *descriptorpb.FileDescriptorSet) ([]protoreflect.FileDescriptor, error) {
+// decodeDescriptorFiles links the files of fds. Imports the set does not
+// carry resolve against startup.
+func decodeDescriptorFiles(fds *descriptorpb.FileDescriptorSet, startup protodesc.Resolver) ([]protoreflect.FileDescriptor, error) {
 	registry := new(protoregistry.Files)
 	pending := make([]*descriptorpb.FileDescriptorProto, 0, len(fds.GetFile()))
 
@@ -120,7 +122,7 @@ func decodeDescriptorFiles(fds *descriptorpb.FileDescriptorSet) ([]protoreflect.
 		progress := false
 		nextPending := make([]*descriptorpb.FileDescriptorProto, 0, len(pending))
 
-		resolver := &protosetinfra.Fallback{Primary: registry, Fallback: protoregistry.GlobalFiles}
+		resolver := &protosetinfra.Fallback{Primary: registry, Fallback: startup}
 
 		for _, fd := range pending {
 			fileDesc, err := protodesc.NewFile(fd, resolver)
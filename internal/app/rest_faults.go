@@ -0,0 +1,137 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
+	"github.com/bavix/gripmock/v3/internal/infra/httputil"
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// ClearFaultProfile stops injecting global faults.
+func (h *RestServer) ClearFaultProfile(w http.ResponseWriter, _ *http.Request) {
+	h.faults.SetGlobal(nil)
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFaultProfile returns the global fault profile and the seed in use.
+func (h *RestServer) GetFaultProfile(w http.ResponseWriter, r *http.Request) {
+	h.writeResponse(r.Context(), w, h.faultProfile())
+}
+
+// SetFaultProfile replaces the global fault profile, reseeding the fault
+// source when the request carries a seed.
+func (h *RestServer) SetFaultProfile(w http.ResponseWriter, r *http.Request) {
+	byt, err := httputil.RequestBody(r)
+	if err != nil {
+		h.responseError(r.Context(), w, err)
+
+		return
+	}
+
+	var req rest.SetFaultProfileJSONRequestBody
+	if err := jsondecoder.Unmarshal(byt, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.writeResponseError(r.Context(), w, errors.Wrap(err, "invalid fault profile"))
+
+		return
+	}
+
+	profile := &stuber.Faults{
+		Error:   faultErrorFromRest(req.Error),
+		Latency: faultLatencyFromRest(req.Latency),
+		Reset:   req.Reset,
+		Drop:    req.Drop,
+	}
+
+	if err := faults.Validate(profile); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.writeResponseError(r.Context(), w, err)
+
+		return
+	}
+
+	if req.Seed != nil {
+		h.faults.Reseed(*req.Seed)
+	}
+
+	h.faults.SetGlobal(profile)
+
+	h.writeResponse(r.Context(), w, h.faultProfile())
+}
+
+func (h *RestServer) faultProfile() rest.FaultProfile {
+	out := rest.FaultProfile{Seed: new(h.faults.Seed())}
+
+	if global := h.faults.Global(); global != nil {
+		out.Error = faultErrorToRest(global.Error)
+		out.Latency = faultLatencyToRest(global.Latency)
+		out.Reset = global.Reset
+		out.Drop = global.Drop
+	}
+
+	return out
+}
+
+func faultErrorFromRest(in *rest.FaultError) *stuber.FaultError {
+	if in == nil {
+		return nil
+	}
+
+	return &stuber.FaultError{Probability: in.Probability, Code: in.Code, Message: in.Message}
+}
+
+func faultErrorToRest(in *stuber.FaultError) *rest.FaultError {
+	if in == nil {
+		return nil
+	}
+
+	return &rest.FaultError{Probability: in.Probability, Code: in.Code, Message: in.Message}
+}
+
+func faultLatencyFromRest(in *rest.FaultLatency) *stuber.FaultLatency {
+	if in == nil {
+		return nil
+	}
+
+	out := &stuber.FaultLatency{
+		Probability: in.Probability,
+		Min:         in.Min,
+		Max:         in.Max,
+		Mean:        in.Mean,
+		StdDev:      in.Stddev,
+		Percentiles: in.Percentiles,
+	}
+
+	if in.Distribution != nil {
+		out.Distribution = string(*in.Distribution)
+	}
+
+	return out
+}
+
+func faultLatencyToRest(in *stuber.FaultLatency) *rest.FaultLatency {
+	if in == nil {
+		return nil
+	}
+
+	out := &rest.FaultLatency{
+		Probability: in.Probability,
+		Min:         in.Min,
+		Max:         in.Max,
+		Mean:        in.Mean,
+		Stddev:      in.StdDev,
+		Percentiles: in.Percentiles,
+	}
+
+	if in.Distribution != "" {
+		out.Distribution = new(rest.FaultLatencyDistribution(in.Distribution))
+	}
+
+	return out
+}
@@ -67,7 +67,7 @@ func newMCPToolHandler(h *RestServer, name string) mcp.ToolHandler {
 
 		args = mcpusecase.ApplySession(name, args, mcpSessionFromContext(ctx, req))
 
-		result, err := callMCPToolDispatch(h, name, args)
+		result, err := callMCPToolDispatch(ctx, h, name, args)
 		if err != nil {
 			return nil, mcpJSONRPCError(name, err)
 		}
@@ -124,10 +124,10 @@ func mcpJSONRPCError(toolName string, err error) error {
 	return &jsonrpc.Error{Code: jsonrpc.CodeInternalError, Message: err.Error(), Data: data}
 }
 
-func callMCPToolDispatch(h *RestServer, name string, args map[string]any) (map[string]any, error) {
+func callMCPToolDispatch(ctx context.Context, h *RestServer, name string, args map[string]any) (map[string]any, error) {
 	handlers := mcpToolHandlers(h)
 
-	result, err, found := mcpusecase.DispatchTool(name, args, handlers)
+	result, err, found := mcpusecase.DispatchTool(ctx, name, args, handlers)
 	if !found {
 		return nil, mcpUnknownTool(name)
 	}
@@ -135,11 +135,11 @@ func callMCPToolDispatch(h *RestServer, name string, args map[string]any) (map[s
 	return result, err
 }
 
-type mcpToolFunc func(*RestServer, map[string]any) (map[string]any, error)
+type mcpToolFunc func(context.Context, *RestServer, map[string]any) (map[string]any, error)
 
 func bindTool(h *RestServer, fn mcpToolFunc) mcpusecase.ToolHandler {
-	return func(args map[string]any) (map[string]any, error) {
-		return fn(h, args)
+	return func(ctx context.Context, args map[string]any) (map[string]any, error) {
+		return fn(ctx, h, args)
 	}
 }
 
@@ -280,6 +280,32 @@ func filterHistoryWindow(h *RestServer, opts history.FilterOpts, limit, offset i
 	return out, total
 }
 
+// mcpStringMapArg reads an optional object of string values, such as header filters.
+func mcpStringMapArg(args map[string]any, key string) (map[string]string, error) {
+	raw, ok := args[key]
+	if !ok || raw == nil {
+		return nil, nil //nolint:nilnil
+	}
+
+	object, ok := raw.(map[string]any)
+	if !ok {
+		return nil, mcpInvalidArgError(key + " must be an object")
+	}
+
+	out := make(map[string]string, len(object))
+
+	for k, v := range object {
+		value, ok := v.(string)
+		if !ok {
+			return nil, mcpInvalidArgError(key + "." + k + " must be a string")
+		}
+
+		out[strings.ToLower(k)] = value
+	}
+
+	return out, nil
+}
+
 func mcpIntArg(args map[string]any, key string, defaultValue int) (int, error) {
 	raw, ok := args[key]
 	if !ok || raw == nil {
@@ -5,13 +5,14 @@ import (
 	"time"
 
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 
 	"github.com/bavix/gripmock/v3/internal/domain/history"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
 )
 
-func mcpMockCall(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpMockCall(ctx context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	service, _ := args["service"].(string)
 	if service == "" {
 		return nil, mcpRequiredArgError("service")
@@ -55,11 +56,12 @@ func mcpMockCall(h *RestServer, args map[string]any) (map[string]any, error) {
 		return response, nil
 	}
 
-	return mcpRenderMockResponse(h, found, service, method, session, input, headers, result.MatchNumber()), nil
+	return mcpRenderMockResponse(ctx, h, found, service, method, session, input, headers, result.MatchNumber()), nil
 }
 
 //nolint:cyclop,funlen
 func mcpRenderMockResponse(
+	ctx context.Context,
 	h *RestServer,
 	found *stuber.Stub,
 	service, method, session string,
@@ -121,6 +123,10 @@ func mcpRenderMockResponse(
 		output.Error = errorStr
 	}
 
+	if err := runOutputScript(ctx, h.scripts, &output, &dataCopy, templateData); err != nil {
+		return h.mockRenderError(found, service, method, session, input, requestTime, status.Convert(err).Message())
+	}
+
 	code := codes.OK
 	errMsg := ""
 
@@ -165,7 +171,7 @@ func mcpRenderMockResponse(
 	}
 
 	h.recordMockCall(found, service, method, session, input, recordedData, uint32(code), errMsg, requestTime)
-	h.effects().apply(context.Background(), found, templateData)
+	h.effects().apply(ctx, found, templateData)
 
 	return response
 }
@@ -177,8 +183,17 @@ func (h *RestServer) mockTemplateError(
 	requestTime time.Time,
 	cause error,
 ) map[string]any {
-	errMsg := "failed to process templates: " + cause.Error()
+	return h.mockRenderError(found, service, method, session, input, requestTime,
+		"failed to process templates: "+cause.Error())
+}
 
+func (h *RestServer) mockRenderError(
+	found *stuber.Stub,
+	service, method, session string,
+	input []map[string]any,
+	requestTime time.Time,
+	errMsg string,
+) map[string]any {
 	h.recordMockCall(found, service, method, session, input, nil, uint32(codes.Internal), errMsg, requestTime)
 
 	return map[string]any{
@@ -213,6 +228,7 @@ func (h *RestServer) recordMockCall(
 		Code:      code,
 		Error:     errMsg,
 		Timestamp: requestTime,
+		Transport: history.TransportMCP,
 	}
 
 	if dataMap, ok := data.(map[string]any); ok {
@@ -1,9 +1,12 @@
 package app
 
 import (
+	"context"
+
 	"github.com/goccy/go-json"
 	"github.com/google/uuid"
 
+	"github.com/bavix/gripmock/v3/internal/domain/history"
 	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 )
@@ -32,18 +35,28 @@ func decodeAndValidateMCPStubs(h *RestServer, args map[string]any) ([]*stuber.St
 	return stubs, nil
 }
 
-func mcpStubsUpsert(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsUpsert(ctx context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	stubs, err := decodeAndValidateMCPStubs(h, args)
 	if err != nil {
 		return nil, err
 	}
 
+	if err := h.applyUpsertHooks(ctx, history.TransportMCP, stubs); err != nil {
+		return nil, mcpInvalidArgErrorWithCause(err.Error(), err)
+	}
+
+	for _, stub := range stubs {
+		if stub.Source == "" {
+			stub.Source = stuber.SourceMCP
+		}
+	}
+
 	ids := h.budgerigar.PutMany(stubs...)
 
 	return map[string]any{"ids": uuidListToStringSlice(ids)}, nil
 }
 
-func mcpStubsValidate(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsValidate(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	stubs, err := decodeAndValidateMCPStubs(h, args)
 	if err != nil {
 		return nil, err
@@ -78,15 +91,15 @@ func normalizeMCPStubs(stubs []*stuber.Stub) ([]map[string]any, error) {
 	return result, nil
 }
 
-func mcpStubsList(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsList(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	return mcpStubsListResponse(h, h.budgerigar.All(), args)
 }
 
-func mcpStubsUsed(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsUsed(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	return mcpStubsListResponse(h, h.budgerigar.Used(), args)
 }
 
-func mcpStubsUnused(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsUnused(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	return mcpStubsListResponse(h, h.budgerigar.Unused(), args)
 }
 
@@ -111,7 +124,7 @@ func (h *RestServer) decorateStubsUsed(stubs []*stuber.Stub) []stuber.Stub {
 	return out
 }
 
-func mcpStubsGet(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsGet(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	id, err := mcpUUIDArg(args, "id")
 	if err != nil {
 		return nil, err
@@ -126,7 +139,7 @@ func mcpStubsGet(h *RestServer, args map[string]any) (map[string]any, error) {
 	return map[string]any{"found": true, "stub": found}, nil
 }
 
-func mcpStubsDelete(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsDelete(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	id, err := mcpUUIDArg(args, "id")
 	if err != nil {
 		return nil, err
@@ -137,7 +150,7 @@ func mcpStubsDelete(h *RestServer, args map[string]any) (map[string]any, error)
 	return map[string]any{"deleted": deleted, "id": id.String()}, nil
 }
 
-func mcpStubsBatchDelete(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsBatchDelete(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	idStrings, err := mcpStringSliceArg(args, "ids")
 	if err != nil {
 		return nil, err
@@ -172,7 +185,7 @@ func mcpStubsBatchDelete(h *RestServer, args map[string]any) (map[string]any, er
 	}, nil
 }
 
-func mcpStubsPurge(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsPurge(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	sessionID, _ := args["session"].(string)
 	if sessionID != "" {
 		deletedCount := h.budgerigar.DeleteSession(sessionID)
@@ -186,7 +199,7 @@ func mcpStubsPurge(h *RestServer, args map[string]any) (map[string]any, error) {
 	return map[string]any{"deletedCount": deletedCount}, nil
 }
 
-func mcpStubsSearch(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsSearch(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	service, _ := args["service"].(string)
 	if service == "" {
 		return nil, mcpRequiredArgError("service")
@@ -238,7 +251,7 @@ func mcpStubsSearch(h *RestServer, args map[string]any) (map[string]any, error)
 	}, nil
 }
 
-func mcpStubsInspect(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpStubsInspect(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	query, err := mcpInspectQuery(args)
 	if err != nil {
 		return nil, err
@@ -1,15 +1,14 @@
 package app
 
 import (
+	"context"
 	"encoding/base64"
-	"fmt"
 	"net/http"
 	"sort"
 	"strings"
 	"time"
 
 	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/reflect/protoregistry"
 
 	mcpusecase "github.com/bavix/gripmock/v3/internal/app/usecase/mcp"
 	"github.com/bavix/gripmock/v3/internal/domain/history"
@@ -17,15 +16,15 @@ import (
 	"github.com/bavix/gripmock/v3/internal/infra/muxmiddleware"
 )
 
-func mcpSchemaStub(_ *RestServer, _ map[string]any) (map[string]any, error) {
+func mcpSchemaStub(_ context.Context, _ *RestServer, _ map[string]any) (map[string]any, error) {
 	return map[string]any{"schemaUrl": stubSchemaURL}, nil
 }
 
-func mcpHealthLiveness(_ *RestServer, _ map[string]any) (map[string]any, error) {
+func mcpHealthLiveness(_ context.Context, _ *RestServer, _ map[string]any) (map[string]any, error) {
 	return map[string]any{"message": "ok", "time": time.Now()}, nil
 }
 
-func mcpHealthReadiness(h *RestServer, _ map[string]any) (map[string]any, error) {
+func mcpHealthReadiness(_ context.Context, h *RestServer, _ map[string]any) (map[string]any, error) {
 	ready := h.ok.Load()
 	if !ready {
 		return map[string]any{"ready": false, "message": "not ready", "time": time.Now()}, nil
@@ -34,7 +33,7 @@ func mcpHealthReadiness(h *RestServer, _ map[string]any) (map[string]any, error)
 	return map[string]any{"ready": true, "message": "ok", "time": time.Now()}, nil
 }
 
-func mcpHealthStatus(h *RestServer, _ map[string]any) (map[string]any, error) {
+func mcpHealthStatus(_ context.Context, h *RestServer, _ map[string]any) (map[string]any, error) {
 	ready := h.ok.Load()
 
 	readiness := "ok"
@@ -50,11 +49,11 @@ func mcpHealthStatus(h *RestServer, _ map[string]any) (map[string]any, error) {
 	}, nil
 }
 
-func mcpDashboard(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpDashboard(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	return map[string]any{"dashboard": h.dashboardPayload(mcpSessionRequest(args))}, nil
 }
 
-func mcpDashboardOverview(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpDashboardOverview(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	payload := h.dashboardPayload(mcpSessionRequest(args))
 
 	return map[string]any{"overview": rest.DashboardOverview{
@@ -69,7 +68,7 @@ func mcpDashboardOverview(h *RestServer, args map[string]any) (map[string]any, e
 	}}, nil
 }
 
-func mcpDashboardInfo(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpDashboardInfo(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	payload := h.dashboardPayload(mcpSessionRequest(args))
 
 	return map[string]any{"info": rest.DashboardInfo{
@@ -91,11 +90,11 @@ func mcpDashboardInfo(h *RestServer, args map[string]any) (map[string]any, error
 	}}, nil
 }
 
-func mcpSessionsList(h *RestServer, _ map[string]any) (map[string]any, error) {
+func mcpSessionsList(_ context.Context, h *RestServer, _ map[string]any) (map[string]any, error) {
 	return map[string]any{"sessions": h.budgerigar.Sessions()}, nil
 }
 
-func mcpGripmockInfo(h *RestServer, _ map[string]any) (map[string]any, error) {
+func mcpGripmockInfo(_ context.Context, h *RestServer, _ map[string]any) (map[string]any, error) {
 	overview := h.dashboardPayload(nil)
 
 	return map[string]any{
@@ -112,12 +111,12 @@ func mcpGripmockInfo(h *RestServer, _ map[string]any) (map[string]any, error) {
 	}, nil
 }
 
-func mcpReflectInfo(h *RestServer, _ map[string]any) (map[string]any, error) {
+func mcpReflectInfo(_ context.Context, h *RestServer, _ map[string]any) (map[string]any, error) {
 	runtimePaths, reflectionPrefixes, reflectionFiles := runtimeDescriptorStats(h)
 
 	globalCount := 0
 
-	protoregistry.GlobalFiles.RangeFiles(func(_ protoreflect.FileDescriptor) bool {
+	h.restDescriptors.Startup().RangeFiles(func(_ protoreflect.FileDescriptor) bool {
 		globalCount++
 
 		return true
@@ -133,7 +132,7 @@ func mcpReflectInfo(h *RestServer, _ map[string]any) (map[string]any, error) {
 	}, nil
 }
 
-func mcpReflectSources(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpReflectSources(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	runtimePaths, reflectionPrefixes, _ := runtimeDescriptorStats(h)
 	reflectionPaths, dynamicPaths, _ := splitRuntimeDescriptorPaths(runtimePaths)
 
@@ -244,7 +243,7 @@ func mcpSessionRequest(args map[string]any) *http.Request {
 	return req
 }
 
-func mcpDescriptorsAdd(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpDescriptorsAdd(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	descriptorSetBase64, _ := args["descriptorSetBase64"].(string)
 	if descriptorSetBase64 == "" {
 		return nil, mcpRequiredArgError("descriptorSetBase64")
@@ -263,15 +262,15 @@ func mcpDescriptorsAdd(h *RestServer, args map[string]any) (map[string]any, erro
 	return map[string]any{"serviceIDs": serviceIDs}, nil
 }
 
-func mcpDescriptorsList(h *RestServer, _ map[string]any) (map[string]any, error) {
+func mcpDescriptorsList(_ context.Context, h *RestServer, _ map[string]any) (map[string]any, error) {
 	return map[string]any{"serviceIDs": h.restDescriptors.ServiceIDs()}, nil
 }
 
-func mcpServicesList(h *RestServer, _ map[string]any) (map[string]any, error) {
+func mcpServicesList(_ context.Context, h *RestServer, _ map[string]any) (map[string]any, error) {
 	return map[string]any{"services": h.collectAllServices()}, nil
 }
 
-func mcpServicesDelete(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpServicesDelete(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	serviceID, _ := args["serviceID"].(string)
 	if serviceID == "" {
 		return nil, mcpRequiredArgError("serviceID")
@@ -282,7 +281,7 @@ func mcpServicesDelete(h *RestServer, args map[string]any) (map[string]any, erro
 	return map[string]any{"removed": removed > 0, "serviceID": serviceID}, nil
 }
 
-func mcpServicesGet(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpServicesGet(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	serviceID, _ := args["serviceID"].(string)
 	if serviceID == "" {
 		return nil, mcpRequiredArgError("serviceID")
@@ -296,7 +295,7 @@ func mcpServicesGet(h *RestServer, args map[string]any) (map[string]any, error)
 	return map[string]any{"service": service}, nil
 }
 
-func mcpServicesMethods(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpServicesMethods(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	serviceID, _ := args["serviceID"].(string)
 	if serviceID == "" {
 		return nil, mcpRequiredArgError("serviceID")
@@ -310,7 +309,7 @@ func mcpServicesMethods(h *RestServer, args map[string]any) (map[string]any, err
 	return map[string]any{"methods": h.serviceFromDescriptor(serviceDescriptor, false).Methods}, nil
 }
 
-func mcpServicesMethod(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpServicesMethod(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	serviceID, _ := args["serviceID"].(string)
 	if serviceID == "" {
 		return nil, mcpRequiredArgError("serviceID")
@@ -335,7 +334,7 @@ func mcpServicesMethod(h *RestServer, args map[string]any) (map[string]any, erro
 	return nil, mcpInvalidArgError(errMethodNotFound.Error() + " " + methodID + " in service " + serviceID)
 }
 
-func mcpHistoryList(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpHistoryList(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	service, _ := args["service"].(string)
 	method, _ := args["method"].(string)
 	session, _ := args["session"].(string)
@@ -350,16 +349,29 @@ func mcpHistoryList(h *RestServer, args map[string]any) (map[string]any, error)
 		return nil, err
 	}
 
+	headers, err := mcpStringMapArg(args, "headers")
+	if err != nil {
+		return nil, err
+	}
+
+	transport, _ := args["transport"].(string)
+	peer, _ := args["peer"].(string)
+	deadline, _ := args["deadline"].(bool)
+
 	records, total := filterHistoryWindow(h, history.FilterOpts{
-		Service: service,
-		Method:  method,
-		Session: session,
+		Service:      service,
+		Method:       method,
+		Session:      session,
+		Transport:    transport,
+		Peer:         peer,
+		Headers:      headers,
+		DeadlineOnly: deadline,
 	}, limit, offset)
 
 	return map[string]any{"records": records, "total": total}, nil
 }
 
-func mcpHistoryErrors(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpHistoryErrors(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	session, _ := args["session"].(string)
 
 	limit, err := mcpIntArg(args, "limit", 0)
@@ -375,7 +387,7 @@ func mcpHistoryErrors(h *RestServer, args map[string]any) (map[string]any, error
 	return map[string]any{"records": errorsOnly}, nil
 }
 
-func mcpHistoryPurge(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpHistoryPurge(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	session, _ := args["session"].(string)
 
 	if h.history == nil {
@@ -390,7 +402,7 @@ func mcpHistoryPurge(h *RestServer, args map[string]any) (map[string]any, error)
 	return result, nil
 }
 
-func mcpVerifyCalls(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpVerifyCalls(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	service, _ := args["service"].(string)
 	if service == "" {
 		return nil, mcpRequiredArgError("service")
@@ -401,35 +413,69 @@ func mcpVerifyCalls(h *RestServer, args map[string]any) (map[string]any, error)
 		return nil, mcpRequiredArgError("method")
 	}
 
-	expectedCount, err := mcpIntArg(args, "expectedCount", -1)
+	bounds, err := mcpVerifyBounds(args)
 	if err != nil {
 		return nil, err
 	}
 
-	if expectedCount < 0 {
-		return nil, mcpRequiredArgError("expectedCount")
-	}
-
 	if h.history == nil {
-		return map[string]any{"verified": false, "message": "history is disabled", "expected": expectedCount, "actual": 0}, nil
+		return map[string]any{"verified": false, "message": "history is disabled", "expected": bounds.times, "actual": 0}, nil
 	}
 
 	session, _ := args["session"].(string)
-	actual := countHistory(h.history, history.FilterOpts{Service: service, Method: method, Session: session})
 
-	if actual != expectedCount {
-		return map[string]any{
+	exp, err := newVerifyExpectation(service, method, session, bounds, args["input"], args["headers"])
+	if err != nil {
+		return nil, mcpInvalidArgError(err.Error())
+	}
+
+	result := history.Verify(h.history, exp)
+	if !exp.Met(result.Actual) {
+		out := map[string]any{
 			"verified": false,
-			"message":  fmt.Sprintf("expected %s/%s to be called %d times, got %d", service, method, expectedCount, actual),
-			"expected": expectedCount,
-			"actual":   actual,
-		}, nil
+			"message":  verifyFailureMessage(exp, result.Actual),
+			"expected": bounds.times,
+			"actual":   result.Actual,
+		}
+
+		if len(result.Closest) > 0 {
+			out["closest"] = restVerifyClosest(result.Closest)
+		}
+
+		return out, nil
+	}
+
+	return map[string]any{"verified": true, "message": "ok", "expected": bounds.times, "actual": result.Actual}, nil
+}
+
+func mcpVerifyBounds(args map[string]any) (verifyBounds, error) {
+	var bounds verifyBounds
+
+	keys := []string{"expectedCount", "atLeast", "atMost"}
+	for i, dst := range []**int{&bounds.times, &bounds.atLeast, &bounds.atMost} {
+		value, err := mcpIntArg(args, keys[i], -1)
+		if err != nil {
+			return verifyBounds{}, err
+		}
+
+		if value >= 0 {
+			*dst = &value
+		}
+	}
+
+	if raw, ok := args["never"]; ok && raw != nil {
+		never, ok := raw.(bool)
+		if !ok {
+			return verifyBounds{}, mcpInvalidArgError("never must be a boolean")
+		}
+
+		bounds.never = never
 	}
 
-	return map[string]any{"verified": true, "message": "ok", "expected": expectedCount, "actual": actual}, nil
+	return bounds, nil
 }
 
-func mcpDebugCall(h *RestServer, args map[string]any) (map[string]any, error) {
+func mcpDebugCall(_ context.Context, h *RestServer, args map[string]any) (map[string]any, error) {
 	service, _ := args["service"].(string)
 	if service == "" {
 		return nil, mcpRequiredArgError("service")
@@ -0,0 +1,93 @@
+package app
+
+import (
+	stderrors "errors"
+	"net/http"
+	"sync/atomic"
+
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+)
+
+// SetProxyRoutes shares the proxy routes of the gRPC server, stored once it
+// is built, for inspection and rule reloads.
+func (h *RestServer) SetProxyRoutes(ref *atomic.Pointer[proxyroutes.Registry]) { h.proxyRoutes = ref }
+
+// ListProxyRoutes returns the proxy sources and the routing rules in place.
+func (h *RestServer) ListProxyRoutes(w http.ResponseWriter, r *http.Request) {
+	h.writeResponse(r.Context(), w, proxyRoutesResponse(h.proxies()))
+}
+
+// ReloadProxyRules reads the routing rules file again; an invalid file keeps
+// the rules in place.
+func (h *RestServer) ReloadProxyRules(w http.ResponseWriter, r *http.Request) {
+	proxies := h.proxies()
+	if proxies == nil {
+		w.WriteHeader(http.StatusConflict)
+		h.writeResponseError(r.Context(), w, proxyroutes.ErrNoRoutingRules)
+
+		return
+	}
+
+	if err := proxies.ReloadRules(); err != nil {
+		if stderrors.Is(err, proxyroutes.ErrNoRoutingRules) {
+			w.WriteHeader(http.StatusConflict)
+		} else {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+
+		h.writeResponseError(r.Context(), w, err)
+
+		return
+	}
+
+	h.writeResponse(r.Context(), w, proxyRoutesResponse(proxies))
+}
+
+func (h *RestServer) proxies() *proxyroutes.Registry {
+	if h.proxyRoutes == nil {
+		return nil
+	}
+
+	return h.proxyRoutes.Load()
+}
+
+func proxyRoutesResponse(proxies *proxyroutes.Registry) rest.ProxyRoutes {
+	bindings := proxies.Bindings()
+
+	response := rest.ProxyRoutes{Bindings: make([]rest.ProxyBinding, 0, len(bindings))}
+
+	for _, binding := range bindings {
+		methods := binding.Methods
+		if methods == nil {
+			methods = []string{}
+		}
+
+		response.Bindings = append(response.Bindings, rest.ProxyBinding{
+			Address: binding.Address,
+			Tls:     binding.TLS,
+			Mode:    binding.Mode.String(),
+			Methods: methods,
+		})
+	}
+
+	set, ok := proxies.RoutingRules()
+	if !ok {
+		return response
+	}
+
+	rules := make([]rest.ProxyRoutingRule, 0, len(set.Rules))
+	for _, rule := range set.Rules {
+		rules = append(rules, rest.ProxyRoutingRule{
+			Service:  rule.Service,
+			Method:   rule.Method,
+			Headers:  rule.Headers,
+			Mode:     rule.Mode,
+			Upstream: rule.Upstream,
+		})
+	}
+
+	response.Rules = &rest.ProxyRoutingRules{File: set.File, LoadedAt: set.LoadedAt, Rules: rules}
+
+	return response
+}
@@ -0,0 +1,109 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
+	protoloc "github.com/bavix/gripmock/v3/internal/domain/proto"
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func TestRestProxyRulesReload(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	server.ReloadProxyRules(w, scenarioRequest(t, http.MethodPost, "/api/proxy/rules/reload", "", ""))
+	require.Equal(t, http.StatusConflict, w.Code, "routes are not built yet")
+
+	proxies, err := proxyroutes.New(t.Context(), nil, nil, nil)
+	require.NoError(t, err)
+	t.Cleanup(proxies.Close)
+
+	var ref atomic.Pointer[proxyroutes.Registry]
+	ref.Store(proxies)
+	server.SetProxyRoutes(&ref)
+
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	require.NoError(t, os.WriteFile(file, []byte(`
+- service: shop.Payments
+  headers: {x-env: staging}
+  upstream: grpc+proxy://staging:50051?bearer=secret
+`), 0o600))
+	require.NoError(t, proxies.LoadRules(file))
+
+	w = httptest.NewRecorder()
+	server.ListProxyRoutes(w, scenarioRequest(t, http.MethodGet, "/api/proxy/routes", "", ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.NotContains(t, w.Body.String(), "secret")
+
+	var routes rest.ProxyRoutes
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &routes))
+	require.Empty(t, routes.Bindings)
+	require.NotNil(t, routes.Rules)
+	require.Equal(t, file, routes.Rules.File)
+	require.Len(t, routes.Rules.Rules, 1)
+	require.Equal(t, map[string]string{"x-env": "staging"}, routes.Rules.Rules[0].Headers)
+
+	require.NoError(t, os.WriteFile(file, []byte(`- mode: mirror`), 0o600))
+
+	w = httptest.NewRecorder()
+	server.ReloadProxyRules(w, scenarioRequest(t, http.MethodPost, "/api/proxy/rules/reload", "", ""))
+	require.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	rules, ok := proxies.RoutingRules()
+	require.True(t, ok)
+	require.Len(t, rules.Rules, 1, "an invalid file keeps the rules in place")
+
+	require.NoError(t, os.WriteFile(file, []byte(`- service: shop.Payments
+  mode: stub
+`), 0o600))
+
+	w = httptest.NewRecorder()
+	server.ReloadProxyRules(w, scenarioRequest(t, http.MethodPost, "/api/proxy/rules/reload", "", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &routes))
+	require.Equal(t, "stub", routes.Rules.Rules[0].Mode)
+}
+
+func TestBuildLoadsProxyRulesWithoutProxySources(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	descFile := filepath.Join(dir, "items.pb")
+	writeReloadSet(t, descFile, "id")
+
+	rulesFile := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, os.WriteFile(rulesFile, []byte(`
+- headers: {x-env: staging}
+  upstream: grpc+proxy://staging:50051
+`), 0o600))
+
+	grpcServer := NewGRPCServer("tcp", "127.0.0.1:0", protoloc.New([]string{descFile}, nil, nil), stuber.NewBudgerigar(),
+		nil, nil, descriptors.NewRegistry(), nil, nil, false, 256, nil, nil, DefaultServerLimits())
+	grpcServer.SetProxyRules(rulesFile)
+
+	server, err := grpcServer.Build(t.Context())
+	require.NoError(t, err)
+	t.Cleanup(server.Stop)
+	t.Cleanup(grpcServer.Proxies().Close)
+
+	require.Nil(t, grpcServer.Proxies().RouteByMethod("/reloadtest.Items/Get", nil))
+
+	staging := grpcServer.Proxies().RouteByMethod("/reloadtest.Items/Get", metadata.Pairs("x-env", "staging"))
+	require.NotNil(t, staging, "a stub-only service is routed by the rules file")
+	require.Equal(t, "staging:50051", staging.Source.ReflectAddress)
+}
@@ -0,0 +1,66 @@
+package app
+
+import (
+	"bytes"
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/httputil"
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/muxmiddleware"
+)
+
+// ListScenarios returns the scenarios visible to the request's session.
+func (h *RestServer) ListScenarios(w http.ResponseWriter, r *http.Request) {
+	scenarios := h.budgerigar.Scenarios(muxmiddleware.FromRequest(r))
+
+	out := make(rest.ScenarioList, 0, len(scenarios))
+	for _, scenario := range scenarios {
+		out = append(out, rest.Scenario{
+			Name:           scenario.Name,
+			State:          scenario.State,
+			PossibleStates: scenario.PossibleStates,
+		})
+	}
+
+	h.writeResponse(r.Context(), w, out)
+}
+
+// ResetScenarios returns every scenario of the request's session to Started.
+func (h *RestServer) ResetScenarios(w http.ResponseWriter, r *http.Request) {
+	h.budgerigar.ResetScenarios(muxmiddleware.FromRequest(r))
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// SetScenarioState moves one scenario of the request's session to the given state.
+func (h *RestServer) SetScenarioState(w http.ResponseWriter, r *http.Request, name string) {
+	byt, err := httputil.RequestBody(r)
+	if err != nil {
+		h.responseError(r.Context(), w, err)
+
+		return
+	}
+
+	var req rest.SetScenarioStateJSONRequestBody
+
+	if len(bytes.TrimSpace(byt)) > 0 {
+		if err := jsondecoder.Unmarshal(byt, &req); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			h.writeResponseError(r.Context(), w, errors.Wrap(err, "invalid scenario state request"))
+
+			return
+		}
+	}
+
+	if !h.budgerigar.SetScenarioState(name, muxmiddleware.FromRequest(r), req.State) {
+		w.WriteHeader(http.StatusNotFound)
+		h.writeResponseError(r.Context(), w, scenarioNotFound(name))
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
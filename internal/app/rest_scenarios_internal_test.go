@@ -0,0 +1,97 @@
+package app
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/muxmiddleware"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func scenarioRequest(t *testing.T, method, target, session, body string) *http.Request {
+	t.Helper()
+
+	r := httptest.NewRequestWithContext(t.Context(), method, target, bytes.NewBufferString(body))
+	r.Header.Set("Content-Type", "application/json")
+
+	if session != "" {
+		r.Header.Set(muxmiddleware.HeaderName, session)
+	}
+
+	return r
+}
+
+func listScenarios(t *testing.T, server *RestServer, session string) rest.ScenarioList {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	server.ListScenarios(w, scenarioRequest(t, http.MethodGet, "/api/scenarios", session, ""))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var list rest.ScenarioList
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+
+	return list
+}
+
+func TestRestScenarios(t *testing.T) {
+	t.Parallel()
+
+	budgerigar := stuber.NewBudgerigar()
+	server, err := NewRestServer(t.Context(), budgerigar, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	server.AddStub(w, scenarioRequest(t, http.MethodPost, "/api/stubs", "s1", `[
+		{"service":"shop.Orders","method":"Pay","scenario":"checkout","requiredState":"Started","newState":"Paid",
+		 "input":{"equals":{"id":"1"}},"output":{"data":{"ok":true}}},
+		{"service":"shop.Orders","method":"Ship","scenario":"checkout","requiredState":"Paid",
+		 "input":{"equals":{"id":"1"}},"output":{"data":{"ok":true}}}
+	]`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.Empty(t, listScenarios(t, server, ""))
+	require.Equal(t, rest.ScenarioList{{
+		Name:           "checkout",
+		State:          stuber.ScenarioStarted,
+		PossibleStates: []string{"Paid", stuber.ScenarioStarted},
+	}}, listScenarios(t, server, "s1"))
+
+	w = httptest.NewRecorder()
+	server.SetScenarioState(w, scenarioRequest(t, http.MethodPut, "/api/scenarios/checkout/state", "s1", `{"state":"Paid"}`), "checkout")
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, "Paid", listScenarios(t, server, "s1")[0].State)
+
+	w = httptest.NewRecorder()
+	server.SetScenarioState(w, scenarioRequest(t, http.MethodPut, "/api/scenarios/checkout/state", "", `{"state":"Paid"}`), "checkout")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.SetScenarioState(w, scenarioRequest(t, http.MethodPut, "/api/scenarios/checkout/state", "s1", `{"state":`), "checkout")
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	server.ResetScenarios(w, scenarioRequest(t, http.MethodPost, "/api/scenarios/reset", "s1", ""))
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Equal(t, stuber.ScenarioStarted, listScenarios(t, server, "s1")[0].State)
+}
+
+func TestRestScenarioStateRequiresScenario(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	server.AddStub(w, scenarioRequest(t, http.MethodPost, "/api/stubs", "", `{
+		"service":"shop.Orders","method":"Pay","newState":"Paid","input":{"equals":{"id":"1"}},"output":{"data":{"ok":true}}
+	}`))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), "scenario is required")
+}
@@ -5,16 +5,22 @@ import (
 	"sort"
 
 	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/reflect/protoregistry"
 
 	"github.com/bavix/gripmock/v3/internal/domain/rest"
 )
 
-func (h *RestServer) collectServices(file protoreflect.FileDescriptor, results *[]rest.Service) bool {
+// collectServices appends the services of file not in seen yet.
+func (h *RestServer) collectServices(file protoreflect.FileDescriptor, results *[]rest.Service, seen map[string]struct{}) bool {
 	services := file.Services()
 
 	for i := range services.Len() {
-		*results = append(*results, h.serviceFromDescriptor(services.Get(i), false))
+		service := services.Get(i)
+		if _, ok := seen[string(service.FullName())]; ok {
+			continue
+		}
+
+		seen[string(service.FullName())] = struct{}{}
+		*results = append(*results, h.serviceFromDescriptor(service, false))
 	}
 
 	return true
@@ -22,13 +28,16 @@ func (h *RestServer) collectServices(file protoreflect.FileDescriptor, results *
 
 func (h *RestServer) collectAllServices() []rest.Service {
 	results := make([]rest.Service, 0, servicesListCap)
+	seen := make(map[string]struct{}, servicesListCap)
 
-	protoregistry.GlobalFiles.RangeFiles(func(file protoreflect.FileDescriptor) bool {
-		return h.collectServices(file, &results)
+	// Runtime descriptors first, so a reloaded service is listed once, with
+	// its current definition.
+	h.restDescriptors.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		return h.collectServices(file, &results, seen)
 	})
 
-	h.restDescriptors.RangeFiles(func(file protoreflect.FileDescriptor) bool {
-		return h.collectServices(file, &results)
+	h.restDescriptors.Startup().RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		return h.collectServices(file, &results, seen)
 	})
 
 	sort.Slice(results, func(i, j int) bool {
@@ -0,0 +1,64 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/httputil"
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/muxmiddleware"
+)
+
+// ClearFakerSeed drops the seed of the request's session.
+func (h *RestServer) ClearFakerSeed(w http.ResponseWriter, r *http.Request) {
+	if session := muxmiddleware.FromRequest(r); session != "" {
+		h.templateEngine.Seeds().Sessions().Forget(session)
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetFakerSeed returns the seed of the request's session, or the server-wide
+// seed when the session has none.
+func (h *RestServer) GetFakerSeed(w http.ResponseWriter, r *http.Request) {
+	session := muxmiddleware.FromRequest(r)
+	seeds := h.templateEngine.Seeds()
+
+	if seq := seeds.Sessions().Get(session); seq != nil {
+		h.writeResponse(r.Context(), w, rest.FakerSeed{Seed: seq.Seed(), Session: session})
+
+		return
+	}
+
+	h.writeResponse(r.Context(), w, rest.FakerSeed{Seed: seeds.Seed()})
+}
+
+// SetFakerSeed restarts the faker sequence of the request's session, or the
+// server-wide one for requests without a session.
+func (h *RestServer) SetFakerSeed(w http.ResponseWriter, r *http.Request) {
+	byt, err := httputil.RequestBody(r)
+	if err != nil {
+		h.responseError(r.Context(), w, err)
+
+		return
+	}
+
+	var req rest.SetFakerSeedJSONRequestBody
+	if err := jsondecoder.Unmarshal(byt, &req); err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		h.writeResponseError(r.Context(), w, errors.Wrap(err, "invalid seed"))
+
+		return
+	}
+
+	session := muxmiddleware.FromRequest(r)
+	if session == "" {
+		h.templateEngine.Seeds().Reseed(req.Seed)
+	} else {
+		h.templateEngine.Seeds().Sessions().Set(session, req.Seed)
+	}
+
+	h.writeResponse(r.Context(), w, rest.FakerSeed{Seed: req.Seed, Session: session})
+}
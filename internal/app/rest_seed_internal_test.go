@@ -0,0 +1,134 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/faker"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	"github.com/bavix/gripmock/v3/internal/infra/template"
+)
+
+// seededResponses runs calls unary calls against a fresh mocker rendering
+// through engine a single templated stub and returns the responses as JSON.
+func seededResponses(t *testing.T, engine *template.Engine, seed *uint64, session string, calls int) []string {
+	t.Helper()
+
+	mocker := newHookedMocker(t, nil)
+	mocker.templateEngine = engine
+	mocker.budgerigar.PutMany(&stuber.Stub{
+		ID:      uuid.New(),
+		Service: testServiceName,
+		Method:  testMethodName,
+		Input:   stuber.InputData{Contains: map[string]any{}},
+		Output:  stuber.Output{Data: map[string]any{"id": "{{ uuid }}", "name": "{{ faker.Person.Name }}"}},
+		Seed:    seed,
+	})
+
+	ctx := t.Context()
+	if session != "" {
+		ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(sessionHeaderKey, session))
+	}
+
+	out := make([]string, calls)
+
+	for i := range calls {
+		resp, err := mocker.handleUnary(ctx, nil, dynamicpb.NewMessage(mocker.inputDesc))
+		require.NoError(t, err)
+
+		byt, err := protojson.Marshal(resp)
+		require.NoError(t, err)
+
+		out[i] = string(byt)
+	}
+
+	return out
+}
+
+func TestStubSeedRepeatsAcrossRuns(t *testing.T) {
+	t.Parallel()
+
+	engine := template.New(t.Context(), nil)
+
+	first := seededResponses(t, engine, new(uint64(42)), "", 2)
+	require.Equal(t, first, seededResponses(t, engine, new(uint64(42)), "", 2))
+	require.NotEqual(t, first[0], first[1])
+	require.NotEqual(t, first, seededResponses(t, engine, new(uint64(43)), "", 2))
+}
+
+func TestSessionSeedRepeatsAcrossRuns(t *testing.T) {
+	t.Parallel()
+
+	engine := template.New(t.Context(), nil)
+
+	engine.Seeds().Sessions().Set("a", 9)
+	first := seededResponses(t, engine, nil, "a", 2)
+
+	engine.Seeds().Sessions().Set("a", 9)
+	require.Equal(t, first, seededResponses(t, engine, nil, "a", 2))
+	require.NotEqual(t, first[0], first[1])
+}
+
+func TestServerSeedIsPerEngine(t *testing.T) {
+	t.Parallel()
+
+	want := seededResponses(t, template.NewWithSeeds(t.Context(), nil, faker.NewSeeds(5)), nil, "", 2)
+	require.NotEqual(t, want[0], want[1])
+
+	engine := template.NewWithSeeds(t.Context(), nil, faker.NewSeeds(5))
+	other := template.NewWithSeeds(t.Context(), nil, faker.NewSeeds(5))
+
+	got := seededResponses(t, engine, nil, "", 1)
+	seededResponses(t, other, nil, "", 1)
+	got = append(got, seededResponses(t, engine, nil, "", 1)...)
+
+	require.Equal(t, want, got, "another server drawing in between does not advance this one's sequence")
+}
+
+func TestFakerSeedEndpoints(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	seeds := server.templateEngine.Seeds()
+	session := "seed-" + uuid.NewString()
+
+	w := httptest.NewRecorder()
+	server.SetFakerSeed(w, scenarioRequest(t, http.MethodPut, "/api/seed", session, `{"seed":77}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.JSONEq(t, `{"seed":77,"session":"`+session+`"}`, w.Body.String())
+	require.Equal(t, uint64(77), seeds.Sessions().Get(session).Seed())
+
+	w = httptest.NewRecorder()
+	server.GetFakerSeed(w, scenarioRequest(t, http.MethodGet, "/api/seed", session, ""))
+	require.Equal(t, http.StatusOK, w.Code)
+	require.JSONEq(t, `{"seed":77,"session":"`+session+`"}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	server.SetFakerSeed(w, scenarioRequest(t, http.MethodPut, "/api/seed", session, `{"seed":"x"}`))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	server.ClearFakerSeed(w, scenarioRequest(t, http.MethodDelete, "/api/seed", session, ""))
+	require.Equal(t, http.StatusNoContent, w.Code)
+	require.Nil(t, seeds.Sessions().Get(session))
+
+	w = httptest.NewRecorder()
+	server.GetFakerSeed(w, scenarioRequest(t, http.MethodGet, "/api/seed", session, ""))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	var seed rest.FakerSeed
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &seed))
+	require.Empty(t, seed.Session)
+	require.Equal(t, seeds.Seed(), seed.Seed)
+}
@@ -17,14 +17,19 @@ import (
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"google.golang.org/protobuf/reflect/protoreflect"
-	"google.golang.org/protobuf/reflect/protoregistry"
 
 	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
 	"github.com/bavix/gripmock/v3/internal/domain/history"
 	"github.com/bavix/gripmock/v3/internal/domain/rest"
 	"github.com/bavix/gripmock/v3/internal/infra/build"
+	"github.com/bavix/gripmock/v3/internal/infra/drift"
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
 	"github.com/bavix/gripmock/v3/internal/infra/httputil"
 	"github.com/bavix/gripmock/v3/internal/infra/muxmiddleware"
+	"github.com/bavix/gripmock/v3/internal/infra/plugins"
+	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+	"github.com/bavix/gripmock/v3/internal/infra/script"
+	"github.com/bavix/gripmock/v3/internal/infra/stubcheck"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
 )
@@ -47,6 +52,13 @@ type RestServer struct {
 	mcpHandler      http.Handler
 	errorFormatter  *ErrorFormatter
 	templateEngine  *template.Engine
+	hooks           *plugins.Hooks
+	scripts         *script.Runner
+	faults          *faults.Injector
+	stubCheck       *stubcheck.Checker
+	snapshots       *snapshotStore
+	proxyRoutes     *atomic.Pointer[proxyroutes.Registry]
+	drift           *drift.Store
 	ports           ServerPorts
 }
 
@@ -100,6 +112,9 @@ func NewRestServer(
 		validator:       v,
 		restDescriptors: r,
 		errorFormatter:  e,
+		faults:          faults.New(0),
+		stubCheck:       stubcheck.New(zerolog.Ctx(ctx), false, r, r.Startup()),
+		snapshots:       newSnapshotStore(),
 		// Built once with the server's lifetime context and reused for mock_call
 		// response rendering, so no context is fabricated per request.
 		templateEngine: engineOr(ctx, engines),
@@ -119,6 +134,18 @@ func NewRestServer(
 // SetPorts records the protocol listen addresses for the dashboard (optional).
 func (h *RestServer) SetPorts(p ServerPorts) { h.ports = p }
 
+// SetHooks installs the plugin lifecycle hooks run for stubs added through the API.
+func (h *RestServer) SetHooks(hooks *plugins.Hooks) { h.hooks = hooks }
+
+// SetScripts installs the runner for output scripts of MCP mock calls.
+func (h *RestServer) SetScripts(scripts *script.Runner) { h.scripts = scripts }
+
+// SetFaults installs the fault injector whose global profile the API manages.
+func (h *RestServer) SetFaults(injector *faults.Injector) { h.faults = injector }
+
+// SetStubCheck installs the checker validating stubs against their descriptors.
+func (h *RestServer) SetStubCheck(checker *stubcheck.Checker) { h.stubCheck = checker }
+
 const (
 	servicesListCap   = 16
 	serviceMethodsCap = 32
@@ -316,9 +343,70 @@ func (h *RestServer) validateStub(stub *stuber.Stub) error {
 		}
 	}
 
+	if stub.Output.Script != "" {
+		if err := script.Compile(stub.Output.Script); err != nil {
+			return &ValidationError{
+				Field:   "script",
+				Tag:     "script",
+				Value:   stub.Output.Script,
+				Message: err.Error(),
+			}
+		}
+	}
+
+	if err := validateUpstream(stub.Output); err != nil {
+		return &ValidationError{
+			Field:   "upstream",
+			Tag:     "upstream",
+			Value:   stub.Output.Upstream,
+			Message: err.Error(),
+		}
+	}
+
+	if issues := stubcheck.CheckCEL(stub); len(issues) > 0 {
+		return issueError("cel", issues)
+	}
+
+	if issues := stubcheck.CheckWhere(stub); len(issues) > 0 {
+		return issueError("where", issues)
+	}
+
+	if err := faults.Validate(stub.Faults); err != nil {
+		return &ValidationError{
+			Field:   "faults",
+			Tag:     "faults",
+			Value:   stub.Faults,
+			Message: err.Error(),
+		}
+	}
+
+	if err := h.stubCheck.Validate(stub); err != nil {
+		checkErr, ok := stderrors.AsType[*stubcheck.Error](err)
+		if !ok {
+			return err
+		}
+
+		return &ValidationError{
+			Field:   checkErr.Issues[0].Path,
+			Tag:     "descriptor",
+			Value:   checkErr.Issues,
+			Message: err.Error(),
+		}
+	}
+
 	return nil
 }
 
+// issueError rejects a stub with the first of issues.
+func issueError(tag string, issues []stubcheck.Issue) error {
+	return &ValidationError{
+		Field:   issues[0].Path,
+		Tag:     tag,
+		Value:   issues,
+		Message: issues[0].Message,
+	}
+}
+
 // methodCoverage counts how many known gRPC methods have at least one stub.
 // A stub's Service may be the FQN or the bare service name (package optional),
 // so a method is covered when a stub matches either form.
@@ -418,15 +506,11 @@ func (h *RestServer) findServiceDescriptor(serviceID string) (protoreflect.Servi
 		return true
 	}
 
+	// Runtime descriptors come first: an uploaded or reloaded file
+	// supersedes its startup copy.
 	if strings.Contains(serviceID, ".") {
 		packageName := splitLast(serviceID, ".")[0]
 
-		protoregistry.GlobalFiles.RangeFilesByPackage(protoreflect.FullName(packageName), collect)
-
-		if found != nil {
-			return found, true
-		}
-
 		h.restDescriptors.RangeFiles(func(file protoreflect.FileDescriptor) bool {
 			if string(file.Package()) != packageName {
 				return true
@@ -438,9 +522,15 @@ func (h *RestServer) findServiceDescriptor(serviceID string) (protoreflect.Servi
 		if found != nil {
 			return found, true
 		}
+
+		h.restDescriptors.Startup().RangeFilesByPackage(protoreflect.FullName(packageName), collect)
+
+		if found != nil {
+			return found, true
+		}
 	}
 
-	protoregistry.GlobalFiles.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+	h.restDescriptors.RangeFiles(func(file protoreflect.FileDescriptor) bool {
 		return collect(file)
 	})
 
@@ -448,7 +538,7 @@ func (h *RestServer) findServiceDescriptor(serviceID string) (protoreflect.Servi
 		return found, true
 	}
 
-	h.restDescriptors.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+	h.restDescriptors.Startup().RangeFiles(func(file protoreflect.FileDescriptor) bool {
 		return collect(file)
 	})
 
@@ -1081,6 +1081,94 @@ func (s *RestServerTestSuite) TestVerifyCallsWithHistory_SessionFromHeader() {
 	s.Equal(http.StatusOK, w.Code)
 }
 
+func (s *RestServerTestSuite) TestVerifyCallsWithMatchersAndBounds() {
+	server := s.newRestServerWithHistory(
+		history.CallRecord{
+			Service: "svc", Method: "M",
+			Requests:       []map[string]any{{"name": "alice"}},
+			RequestHeaders: map[string]string{"x-tenant": "acme"},
+		},
+		history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"name": "bob"}}},
+	)
+
+	ok := s.verifyCalls(server, map[string]any{
+		"service": "svc",
+		"method":  "M",
+		"input":   map[string]any{"equals": map[string]any{"name": "alice"}},
+		"headers": map[string]any{"equals": map[string]any{"x-tenant": "acme"}},
+		"atLeast": 1,
+	}, nil)
+	s.Equal(http.StatusOK, ok.Code)
+
+	never := s.verifyCalls(server, map[string]any{
+		"service": "svc",
+		"method":  "M",
+		"input":   map[string]any{"equals": map[string]any{"name": "carol"}},
+		"never":   true,
+	}, nil)
+	s.Equal(http.StatusOK, never.Code)
+
+	miss := s.verifyCalls(server, map[string]any{
+		"service": "svc",
+		"method":  "M",
+		"input":   map[string]any{"equals": map[string]any{"name": "bob"}},
+		"headers": map[string]any{"equals": map[string]any{"x-tenant": "acme"}},
+	}, nil)
+	s.Equal(http.StatusBadRequest, miss.Code)
+
+	var verifyErr rest.VerifyError
+	s.Require().NoError(json.Unmarshal(miss.Body.Bytes(), &verifyErr))
+	s.Equal("expected svc/M to be called at least once, got 0", *verifyErr.Message)
+	s.Nil(verifyErr.Expected)
+	s.Require().NotNil(verifyErr.Closest)
+	s.Require().Len(*verifyErr.Closest, 2)
+
+	closest := (*verifyErr.Closest)[0]
+	s.Nil(closest.Input)
+	s.Require().NotNil(closest.Headers)
+	s.Equal("x-tenant", *(*closest.Headers)[0].Field)
+
+	negative := s.verifyCalls(server, map[string]any{"service": "svc", "method": "M", "atMost": -1}, nil)
+	s.Equal(http.StatusBadRequest, negative.Code)
+}
+
+func (s *RestServerTestSuite) TestVerifyCallOrder() {
+	start := time.Now()
+	server := s.newRestServerWithHistory(
+		history.CallRecord{Service: "auth.Auth", Method: "Login", Timestamp: start},
+		history.CallRecord{Service: "pay.Payments", Method: "Charge", Timestamp: start.Add(time.Millisecond)},
+		history.CallRecord{Service: "shop.Orders", Method: "Create", Timestamp: start.Add(2 * time.Millisecond)},
+	)
+
+	verifyOrder := func(steps ...map[string]any) *httptest.ResponseRecorder {
+		body, err := json.Marshal(map[string]any{"steps": steps})
+		s.Require().NoError(err)
+
+		req := httptest.NewRequestWithContext(s.T().Context(), http.MethodPost, "/api/verify/order", bytes.NewReader(body))
+		w := httptest.NewRecorder()
+		server.VerifyCallOrder(w, req)
+
+		return w
+	}
+
+	login := map[string]any{"service": "auth.Auth", "method": "Login"}
+	charge := map[string]any{"service": "pay.Payments", "method": "Charge", "expectedCount": 1}
+	create := map[string]any{"service": "shop.Orders", "method": "Create"}
+
+	s.Equal(http.StatusOK, verifyOrder(login, charge, create).Code)
+
+	reversed := verifyOrder(create, login)
+	s.Equal(http.StatusBadRequest, reversed.Code)
+
+	var orderErr rest.VerifyOrderError
+	s.Require().NoError(json.Unmarshal(reversed.Body.Bytes(), &orderErr))
+	s.Equal(1, *orderErr.Step)
+	s.Equal([]int{1, 0}, *orderErr.Counts)
+	s.Equal("step 2: expected auth.Auth/Login to be called at least once after shop.Orders/Create, got 0", *orderErr.Message)
+
+	s.Equal(http.StatusBadRequest, verifyOrder().Code)
+}
+
 func (s *RestServerTestSuite) TestLiveness() {
 	w := httptest.NewRecorder()
 	s.server.Liveness(w, httptest.NewRequestWithContext(s.T().Context(), http.MethodGet, "/", nil))
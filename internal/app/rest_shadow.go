@@ -0,0 +1,59 @@
+package app
+
+import (
+	"net/http"
+
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/drift"
+)
+
+// SetDrift shares the store of shadow-mode comparisons with the gRPC server
+// and the gateways.
+func (h *RestServer) SetDrift(store *drift.Store) { h.drift = store }
+
+// ClearShadowDrift forgets the comparisons made so far.
+func (h *RestServer) ClearShadowDrift(w http.ResponseWriter, _ *http.Request) {
+	h.drift.Clear()
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListShadowDrift returns the drift report of every stub that answered a
+// shadowed call.
+func (h *RestServer) ListShadowDrift(w http.ResponseWriter, r *http.Request) {
+	reports := h.drift.Reports()
+
+	response := make(rest.DriftReportList, 0, len(reports))
+	for _, report := range reports {
+		response = append(response, rest.DriftReport{
+			StubId:    report.StubID,
+			Service:   report.Service,
+			Method:    report.Method,
+			Checks:    report.Checks,
+			Drifts:    report.Drifts,
+			CheckedAt: report.CheckedAt,
+			Last:      driftRecord(report.Last),
+		})
+	}
+
+	h.writeResponse(r.Context(), w, response)
+}
+
+func driftRecord(last *drift.Drift) *rest.DriftRecord {
+	if last == nil {
+		return nil
+	}
+
+	fields := make([]rest.DriftField, 0, len(last.Fields))
+	for _, field := range last.Fields {
+		fields = append(fields, rest.DriftField{Path: field.Path, Stub: field.Stub, Upstream: field.Upstream})
+	}
+
+	return &rest.DriftRecord{
+		At:           last.At,
+		StubCode:     last.StubCode,
+		UpstreamCode: last.UpstreamCode,
+		Fields:       fields,
+		Truncated:    last.Truncated,
+	}
+}
@@ -0,0 +1,91 @@
+package app
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/rs/zerolog"
+)
+
+// ListSnapshots returns the kept snapshots, oldest first.
+func (h *RestServer) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+	h.writeResponse(r.Context(), w, h.snapshots.list())
+}
+
+// CreateSnapshot captures the complete mutable state of the server.
+func (h *RestServer) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+	info, err := h.takeSnapshot()
+	if err != nil {
+		h.responseError(r.Context(), w, err)
+
+		return
+	}
+
+	h.writeResponse(r.Context(), w, info)
+}
+
+// DeleteSnapshot forgets a snapshot.
+func (h *RestServer) DeleteSnapshot(w http.ResponseWriter, r *http.Request, id string) {
+	if !h.snapshots.delete(id) {
+		w.WriteHeader(http.StatusNotFound)
+		h.writeResponseError(r.Context(), w, snapshotNotFound(id))
+
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// GetSnapshot downloads the archive of a snapshot, ready for SNAPSHOT_FILE.
+func (h *RestServer) GetSnapshot(w http.ResponseWriter, r *http.Request, id string) {
+	entry, ok := h.snapshots.get(id)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		h.writeResponseError(r.Context(), w, snapshotNotFound(id))
+
+		return
+	}
+
+	w.Header().Set("Content-Disposition", `attachment; filename="snapshot-`+id+`.json"`)
+
+	if _, err := w.Write(entry.archive); err != nil {
+		zerolog.Ctx(r.Context()).Err(err).Msg("failed to write snapshot")
+	}
+}
+
+// RestoreSnapshot replaces the state of the server with the snapshot's.
+func (h *RestServer) RestoreSnapshot(w http.ResponseWriter, r *http.Request, id string) {
+	entry, ok := h.snapshots.get(id)
+	if !ok {
+		w.WriteHeader(http.StatusNotFound)
+		h.writeResponseError(r.Context(), w, snapshotNotFound(id))
+
+		return
+	}
+
+	info, err := h.restoreSnapshot(entry.archive)
+	if err != nil {
+		w.WriteHeader(snapshotErrorStatus(err))
+		h.writeResponseError(r.Context(), w, err)
+
+		return
+	}
+
+	h.writeResponse(r.Context(), w, info)
+}
+
+// snapshotErrorStatus maps archive and descriptor decoding failures to 400.
+func snapshotErrorStatus(err error) int {
+	for _, kind := range []error{
+		ErrInvalidSnapshot,
+		ErrInvalidFileDescriptorSet,
+		ErrResolveDescriptorDeps,
+		ErrRegisterDescriptorFile,
+	} {
+		if stderrors.Is(err, kind) {
+			return http.StatusBadRequest
+		}
+	}
+
+	return errorStatus(err)
+}
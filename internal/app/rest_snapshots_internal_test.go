@@ -0,0 +1,121 @@
+package app
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/session"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func createSnapshot(t *testing.T, server *RestServer) rest.SnapshotInfo {
+	t.Helper()
+
+	w := httptest.NewRecorder()
+	server.CreateSnapshot(w, scenarioRequest(t, http.MethodPost, "/api/snapshots", "", ""))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var info rest.SnapshotInfo
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &info))
+
+	return info
+}
+
+//nolint:paralleltest // restores the process-wide session tracker.
+func TestRestSnapshotRoundTrip(t *testing.T) {
+	budgerigar := stuber.NewBudgerigar()
+	store := history.NewMemoryStore(0)
+	server, err := NewRestServer(t.Context(), budgerigar, nil, store, nil, nil, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	server.AddStub(w, scenarioRequest(t, http.MethodPost, "/api/stubs", "", `[
+		{"service":"shop.Orders","method":"Get","options":{"times":1},
+		 "input":{"equals":{"id":"1"}},"output":{"data":{"ok":true}}}
+	]`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	_, err = budgerigar.FindByQuery(stuber.Query{
+		Service: "shop.Orders",
+		Method:  "Get",
+		Input:   []map[string]any{{"id": "1"}},
+	})
+	require.NoError(t, err)
+
+	session.Touch("snapshot-test")
+	store.Record(history.CallRecord{Service: "shop.Orders", Method: "Get"})
+
+	info := createSnapshot(t, server)
+	require.Equal(t, 1, info.Stubs)
+	require.Equal(t, 1, info.History)
+	require.NotZero(t, info.Sessions)
+
+	budgerigar.Clear()
+	store.Clear()
+
+	w = httptest.NewRecorder()
+	server.RestoreSnapshot(w, scenarioRequest(t, http.MethodPost, "/api/snapshots/"+info.Id+"/restore", "", ""), info.Id)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	require.Len(t, budgerigar.All(), 1)
+	require.Len(t, budgerigar.Used(), 1, "used flag should survive the restore")
+	require.Equal(t, 1, store.Count())
+	require.Contains(t, session.IDs(), "snapshot-test")
+
+	_, err = budgerigar.FindByQuery(stuber.Query{
+		Service: "shop.Orders",
+		Method:  "Get",
+		Input:   []map[string]any{{"id": "1"}},
+	})
+	require.Error(t, err, "times counter should survive the restore")
+
+	w = httptest.NewRecorder()
+	server.GetSnapshot(w, scenarioRequest(t, http.MethodGet, "/api/snapshots/"+info.Id, "", ""), info.Id)
+	require.Equal(t, http.StatusOK, w.Code)
+
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, os.WriteFile(path, w.Body.Bytes(), 0o600))
+
+	fresh, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, history.NewMemoryStore(0), nil, nil, nil)
+	require.NoError(t, err)
+
+	restored, err := fresh.RestoreSnapshotFile(path)
+	require.NoError(t, err)
+	require.Equal(t, info.Id, restored.Id)
+	require.Len(t, fresh.budgerigar.Used(), 1)
+	require.Len(t, fresh.snapshots.list(), 1)
+}
+
+func TestRestSnapshotNotFound(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	server.RestoreSnapshot(w, scenarioRequest(t, http.MethodPost, "/api/snapshots/missing/restore", "", ""), "missing")
+	require.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	server.DeleteSnapshot(w, scenarioRequest(t, http.MethodDelete, "/api/snapshots/missing", "", ""), "missing")
+	require.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestRestoreSnapshotRejectsUnknownVersion(t *testing.T) {
+	t.Parallel()
+
+	server, err := NewRestServer(t.Context(), stuber.NewBudgerigar(), nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	_, err = server.restoreSnapshot([]byte(`{"version":99}`))
+	require.ErrorIs(t, err, ErrInvalidSnapshot)
+	require.Equal(t, http.StatusBadRequest, snapshotErrorStatus(err))
+}
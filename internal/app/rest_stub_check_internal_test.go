@@ -0,0 +1,63 @@
+package app
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stubcheck"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+const stubCheckProto = `
+syntax = "proto3";
+package check;
+message Request { string name = 1; int64 count = 2; }
+service Checked { rpc Call(Request) returns (Request); }
+`
+
+func TestRestRejectsStubAgainstDescriptorsInStrictMode(t *testing.T) {
+	t.Parallel()
+
+	compiler := protocompile.Compiler{
+		Resolver: &protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{"check.proto": stubCheckProto}),
+		},
+	}
+
+	compiled, err := compiler.Compile(t.Context(), "check.proto")
+	require.NoError(t, err)
+
+	files := new(protoregistry.Files)
+	require.NoError(t, files.RegisterFile(compiled[0]))
+
+	budgerigar := stuber.NewBudgerigar()
+	server, err := NewRestServer(t.Context(), budgerigar, nil, nil, nil, nil, nil)
+	require.NoError(t, err)
+
+	server.SetStubCheck(stubcheck.New(nil, true, files))
+
+	w := httptest.NewRecorder()
+	server.AddStub(w, scenarioRequest(t, http.MethodPost, "/api/stubs", "", `{
+		"service": "check.Checked", "method": "Call",
+		"input": {"equals": {"nmae": "bob"}},
+		"output": {"data": {"count": "many"}}
+	}`))
+	require.Equal(t, http.StatusBadRequest, w.Code)
+	require.Contains(t, w.Body.String(), `$.input.equals.nmae: unknown field \"nmae\" in check.Request`)
+	require.Contains(t, w.Body.String(), `$.output.data.count: expected int64`)
+	require.Empty(t, budgerigar.All())
+
+	w = httptest.NewRecorder()
+	server.AddStub(w, scenarioRequest(t, http.MethodPost, "/api/stubs", "", `{
+		"service": "check.Checked", "method": "Call",
+		"input": {"equals": {"name": "bob"}},
+		"output": {"data": {"count": "12"}}
+	}`))
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Len(t, budgerigar.All(), 1)
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/goccy/go-json"
 	"github.com/google/uuid"
 
+	"github.com/bavix/gripmock/v3/internal/domain/history"
 	"github.com/bavix/gripmock/v3/internal/domain/rest"
 	"github.com/bavix/gripmock/v3/internal/infra/httputil"
 	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
@@ -43,6 +44,12 @@ func (h *RestServer) AddStub(w http.ResponseWriter, r *http.Request) {
 		}
 	}
 
+	if err := h.applyUpsertHooks(r.Context(), history.TransportHTTP, inputs); err != nil {
+		h.validationError(r.Context(), w, err)
+
+		return
+	}
+
 	h.writeResponse(r.Context(), w, h.budgerigar.PutMany(inputs...))
 }
 
@@ -175,7 +182,7 @@ func parseMatcherKinds(s string) []string {
 	}
 
 	valid := map[string]struct{}{
-		"equals": {}, "contains": {}, "matches": {}, "glob": {}, "anyOf": {},
+		"equals": {}, "contains": {}, "matches": {}, "glob": {}, "anyOf": {}, "cel": {}, "where": {},
 	}
 
 	var out []string
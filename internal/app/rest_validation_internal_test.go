@@ -48,6 +48,40 @@ func (s *RestValidationTestSuite) TestAddStubValidationErrors() {
 			expectedStatus: http.StatusBadRequest,
 			expectedError:  "service name is missing",
 		},
+		{
+			name: "invalid cel expression",
+			jsonData: `[{
+				"service": "TestService",
+				"method": "TestMethod",
+				"input": {"cel": "request.amount >"},
+				"output": {"data": {"result": "success"}}
+			}]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "invalid cel expression",
+		},
+		{
+			name: "non-bool cel expression",
+			jsonData: `[{
+				"service": "TestService",
+				"method": "TestMethod",
+				"headers": {"cel": "headers['x-n'] + 'x'"},
+				"input": {"equals": {"key": "value"}},
+				"output": {"data": {"result": "success"}}
+			}]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "expression must evaluate to a bool",
+		},
+		{
+			name: "where operand of the wrong type",
+			jsonData: `[{
+				"service": "TestService",
+				"method": "TestMethod",
+				"input": {"where": {"amount": {"gt": "soon"}}},
+				"output": {"data": {"result": "success"}}
+			}]`,
+			expectedStatus: http.StatusBadRequest,
+			expectedError:  "want a number, a timestamp or a duration",
+		},
 		{
 			name: "missing method field",
 			jsonData: `[{
@@ -0,0 +1,277 @@
+package app
+
+import (
+	"cmp"
+	"encoding/json"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/uuid"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/session"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// snapshotVersion is bumped whenever the archive layout changes incompatibly.
+const snapshotVersion = 1
+
+// snapshotArchive is the complete mutable state of a server in one document:
+// stubs with their match counters and scenario states, tracked sessions,
+// recorded history and the descriptors added through POST /descriptors.
+type snapshotArchive struct {
+	Version   int       `json:"version"`
+	ID        string    `json:"id"`
+	CreatedAt time.Time `json:"createdAt"`
+
+	stuber.State
+
+	Sessions map[string]time.Time `json:"sessions,omitempty"`
+	History  []history.CallRecord `json:"history,omitempty"`
+	// Descriptors is a binary FileDescriptorSet of the runtime-added files.
+	Descriptors []byte `json:"descriptors,omitempty"`
+}
+
+func (a *snapshotArchive) info(descriptorFiles int) rest.SnapshotInfo {
+	return rest.SnapshotInfo{
+		Id:          a.ID,
+		CreatedAt:   a.CreatedAt,
+		Stubs:       len(a.Stubs),
+		Sessions:    len(a.Sessions),
+		History:     len(a.History),
+		Descriptors: descriptorFiles,
+	}
+}
+
+// snapshotStore keeps encoded archives, so later changes to live stubs never
+// leak into a snapshot taken earlier.
+type snapshotStore struct {
+	mu    sync.RWMutex
+	items map[string]snapshotEntry
+}
+
+type snapshotEntry struct {
+	info    rest.SnapshotInfo
+	archive []byte
+}
+
+func newSnapshotStore() *snapshotStore {
+	return &snapshotStore{items: make(map[string]snapshotEntry)}
+}
+
+func (s *snapshotStore) put(entry snapshotEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.items[entry.info.Id] = entry
+}
+
+func (s *snapshotStore) get(id string) (snapshotEntry, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	entry, ok := s.items[id]
+
+	return entry, ok
+}
+
+func (s *snapshotStore) delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, ok := s.items[id]
+	delete(s.items, id)
+
+	return ok
+}
+
+// list returns the snapshots oldest first.
+func (s *snapshotStore) list() []rest.SnapshotInfo {
+	s.mu.RLock()
+	out := make([]rest.SnapshotInfo, 0, len(s.items))
+
+	for _, entry := range s.items {
+		out = append(out, entry.info)
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(out, func(a, b rest.SnapshotInfo) int {
+		return cmp.Or(a.CreatedAt.Compare(b.CreatedAt), cmp.Compare(a.Id, b.Id))
+	})
+
+	return out
+}
+
+// takeSnapshot captures the current state of the server and keeps it.
+func (h *RestServer) takeSnapshot() (rest.SnapshotInfo, error) {
+	archive := &snapshotArchive{
+		Version:   snapshotVersion,
+		ID:        uuid.NewString(),
+		CreatedAt: time.Now(),
+		State:     h.budgerigar.State(),
+		Sessions:  session.LastSeen(),
+	}
+
+	if h.history != nil {
+		archive.History = h.history.All()
+	}
+
+	files, err := h.snapshotDescriptors(archive)
+	if err != nil {
+		return rest.SnapshotInfo{}, err
+	}
+
+	raw, err := json.Marshal(archive)
+	if err != nil {
+		return rest.SnapshotInfo{}, errors.Wrap(err, "failed to encode snapshot")
+	}
+
+	entry := snapshotEntry{info: archive.info(files), archive: raw}
+	h.snapshots.put(entry)
+
+	return entry.info, nil
+}
+
+func (h *RestServer) snapshotDescriptors(archive *snapshotArchive) (int, error) {
+	var fds descriptorpb.FileDescriptorSet
+
+	h.restDescriptors.RangeRegistered(func(fd protoreflect.FileDescriptor) bool {
+		fds.File = append(fds.File, protodesc.ToFileDescriptorProto(fd))
+
+		return true
+	})
+
+	if len(fds.GetFile()) == 0 {
+		return 0, nil
+	}
+
+	slices.SortFunc(fds.File, func(a, b *descriptorpb.FileDescriptorProto) int {
+		return cmp.Compare(a.GetName(), b.GetName())
+	})
+
+	raw, err := proto.Marshal(&fds)
+	if err != nil {
+		return 0, errors.Wrap(err, "failed to encode snapshot descriptors")
+	}
+
+	archive.Descriptors = raw
+
+	return len(fds.GetFile()), nil
+}
+
+// restoreSnapshot replaces the state of the server with the archive's.
+func (h *RestServer) restoreSnapshot(raw []byte) (rest.SnapshotInfo, error) {
+	archive, err := decodeSnapshot(raw)
+	if err != nil {
+		return rest.SnapshotInfo{}, err
+	}
+
+	files, err := h.restoreDescriptors(archive.Descriptors)
+	if err != nil {
+		return rest.SnapshotInfo{}, err
+	}
+
+	h.budgerigar.RestoreState(archive.State)
+	session.Replace(archive.Sessions)
+	h.restoreHistory(archive.History)
+
+	return archive.info(files), nil
+}
+
+func (h *RestServer) restoreDescriptors(raw []byte) (int, error) {
+	h.descriptorOpsMu.Lock()
+	defer h.descriptorOpsMu.Unlock()
+
+	if len(raw) == 0 {
+		h.restDescriptors.Replace(nil)
+
+		return 0, nil
+	}
+
+	var fds descriptorpb.FileDescriptorSet
+	if err := proto.Unmarshal(raw, &fds); err != nil {
+		return 0, invalidFileDescriptorSetError(err)
+	}
+
+	files, err := decodeDescriptorFiles(&fds, h.restDescriptors.Startup())
+	if err != nil {
+		return 0, err
+	}
+
+	h.restDescriptors.Replace(files)
+
+	return len(files), nil
+}
+
+func (h *RestServer) restoreHistory(calls []history.CallRecord) {
+	clearer, ok := h.history.(interface{ Clear() })
+	if !ok {
+		return
+	}
+
+	clearer.Clear()
+
+	recorder, ok := h.history.(history.Recorder)
+	if !ok {
+		return
+	}
+
+	for _, call := range calls {
+		recorder.Record(call)
+	}
+}
+
+// RestoreSnapshotFile restores an archive previously downloaded from
+// GET /snapshots/{id} and keeps it as a snapshot under its original ID.
+func (h *RestServer) RestoreSnapshotFile(path string) (rest.SnapshotInfo, error) {
+	raw, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return rest.SnapshotInfo{}, errors.Wrapf(err, "failed to read snapshot %s", path)
+	}
+
+	info, err := h.restoreSnapshot(raw)
+	if err != nil {
+		return rest.SnapshotInfo{}, errors.Wrapf(err, "failed to restore snapshot %s", path)
+	}
+
+	h.snapshots.put(snapshotEntry{info: info, archive: raw})
+
+	return info, nil
+}
+
+func decodeSnapshot(raw []byte) (*snapshotArchive, error) {
+	var archive snapshotArchive
+	if err := jsondecoder.Unmarshal(raw, &archive); err != nil {
+		return nil, invalidSnapshotError(err)
+	}
+
+	if archive.Version != snapshotVersion {
+		return nil, unsupportedSnapshotVersion(archive.Version)
+	}
+
+	if archive.ID == "" {
+		archive.ID = uuid.NewString()
+	}
+
+	// Stubs decode numbers as json.Number like every other stub source, but
+	// history is read back the way the history stores themselves decode it.
+	var calls struct {
+		History []history.CallRecord `json:"history"`
+	}
+	if err := json.Unmarshal(raw, &calls); err != nil {
+		return nil, invalidSnapshotError(err)
+	}
+
+	archive.History = calls.History
+
+	return &archive, nil
+}
@@ -0,0 +1,540 @@
+package app
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync/atomic"
+
+	"github.com/cockroachdb/errors"
+	"github.com/go-playground/validator/v10"
+	"github.com/goccy/go-json"
+	"github.com/gorilla/mux"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	"github.com/bavix/gripmock/v3/internal/infra/template"
+)
+
+const (
+	transcodingBodyWildcard = "*"
+	transcodingTrailerPfx   = "Grpc-Trailer-"
+)
+
+var (
+	errTranscodingField = errors.New("unknown field")
+	errTranscodingValue = errors.New("unsupported field type for path or query binding")
+)
+
+// TranscodingGateway serves plain HTTP/JSON requests mapped onto gRPC methods
+// by their google.api.http annotations, the way Envoy's transcoder and
+// grpc-gateway do. Matched requests go through the same grpcMocker pipeline
+// as native gRPC calls, so one set of stubs answers both kinds of client.
+type TranscodingGateway struct {
+	gatewayHandler
+
+	rules *transcodingRules
+}
+
+func NewTranscodingGateway(
+	ctx context.Context,
+	budgerigar *stuber.Budgerigar,
+	descriptorRegistry *descriptors.Registry,
+	recorder history.Recorder,
+	proxyRoutesRef *atomic.Pointer[proxyroutes.Registry],
+	validator *validator.Validate,
+	errorFormatter *ErrorFormatter,
+	engines ...*template.Engine,
+) *TranscodingGateway {
+	return &TranscodingGateway{
+		gatewayHandler: newGatewayHandler(ctx, budgerigar, descriptorRegistry, recorder,
+			proxyRoutesRef, validator, errorFormatter, engines...),
+		rules: newTranscodingRules(descriptorRegistry),
+	}
+}
+
+// Match is a mux.MatcherFunc: it claims a request only when an HTTP rule
+// binds its method and path. Connect and gRPC-Web traffic is left to the
+// protocol gateway even when a rule happens to cover the same path.
+func (g *TranscodingGateway) Match(r *http.Request, _ *mux.RouteMatch) bool {
+	if isProtocolGatewayRequest(r) {
+		return false
+	}
+
+	_, _, ok := g.rules.find(r.Method, r.URL.EscapedPath())
+
+	return ok
+}
+
+func isProtocolGatewayRequest(r *http.Request) bool {
+	if r.Header.Get(headerConnectProtocolVersion) != "" {
+		return true
+	}
+
+	ct := normalizeContentType(r.Header.Get(headerContentType))
+
+	return strings.HasPrefix(ct, "application/grpc") || strings.HasPrefix(ct, "application/connect+")
+}
+
+func (g *TranscodingGateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	rule, vars, ok := g.rules.find(r.Method, r.URL.EscapedPath())
+	if !ok {
+		writeTranscodingError(w, g.typeResolver.Marshal, status.New(codes.NotFound, "no http rule matches "+r.Method+" "+r.URL.Path))
+
+		return
+	}
+
+	service := string(rule.method.Parent().FullName())
+	method := string(rule.method.Name())
+
+	zerolog.Ctx(r.Context()).Debug().
+		Str("method", r.Method).
+		Str("path", r.URL.Path).
+		Str("protocol", "http-json").
+		Str("service", service).
+		Str("rpc", method).
+		Msg("gateway: handling transcoded request")
+
+	mocker := g.buildMocker(r, service, method, "/"+service+"/"+method, rule.method)
+
+	adapter := &transcodingStreamAdapter{
+		baseStreamAdapter: baseStreamAdapter{
+			ctx:          gatewayCallContext(r, history.TransportHTTP),
+			req:          r,
+			w:            w,
+			typeResolver: mocker.typeResolver,
+		},
+		rule:      rule,
+		streaming: mocker.serverStream,
+	}
+
+	if mocker.clientStream {
+		adapter.writeErrorStatus(status.New(codes.Unimplemented,
+			"client and bidirectional streaming are not supported over HTTP/JSON transcoding"))
+
+		return
+	}
+
+	timeout, hasTimeout, err := requestTimeout(r.Header)
+	if err != nil {
+		adapter.writeErrorStatus(status.New(codes.InvalidArgument, "invalid grpc-timeout"))
+
+		return
+	}
+
+	if hasTimeout && timeout > 0 {
+		timedCtx, cancel := context.WithTimeout(adapter.ctx, timeout)
+		defer cancel()
+
+		adapter.ctx = timedCtx
+	}
+
+	adapter.request, err = g.buildRequest(r, rule, vars)
+	if err != nil {
+		adapter.writeErrorStatus(status.New(codes.InvalidArgument, err.Error()))
+
+		return
+	}
+
+	if adapter.streaming {
+		if err := mocker.streamHandler(adapter.ctx, adapter); err != nil { //nolint:contextcheck
+			st, _ := status.FromError(err)
+			adapter.writeErrorStatus(st)
+
+			return
+		}
+
+		adapter.sendHeader()
+
+		return
+	}
+
+	resp, err := mocker.handleUnaryWithProxy(adapter.ctx, adapter, adapter.request)
+	if err != nil {
+		st, _ := status.FromError(err)
+		adapter.writeErrorStatus(st)
+
+		return
+	}
+
+	if err := adapter.SendMsg(resp); err != nil {
+		zerolog.Ctx(r.Context()).Debug().Err(err).Msg("transcoding.gateway: send unary response")
+	}
+}
+
+// buildRequest assembles the request message from the body selector, the
+// path variables and, unless the whole body is bound, the query string.
+// Path variables win over body and query values for the same field.
+func (g *TranscodingGateway) buildRequest(
+	r *http.Request, rule *transcodingRule, vars map[string]string,
+) (*dynamicpb.Message, error) {
+	inputDesc := rule.method.Input()
+	msg := dynamicpb.NewMessage(inputDesc)
+
+	if rule.body != "" {
+		if err := g.mergeBody(r, rule, msg); err != nil {
+			return nil, err
+		}
+	}
+
+	params := map[string]any{}
+
+	if rule.body != transcodingBodyWildcard {
+		if err := setQueryParams(params, inputDesc, r, rule, vars); err != nil {
+			return nil, err
+		}
+	}
+
+	for path, value := range vars {
+		if err := setFieldParam(params, inputDesc, strings.Split(path, "."), []string{value}); err != nil {
+			return nil, errors.Wrapf(err, "path variable %q", path)
+		}
+	}
+
+	if len(params) == 0 {
+		return msg, nil
+	}
+
+	data, err := json.Marshal(params)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encode bound fields")
+	}
+
+	bound := dynamicpb.NewMessage(inputDesc)
+	if err := g.typeResolver.Unmarshal(data, bound); err != nil {
+		return nil, errors.Wrap(err, "failed to bind path or query parameters")
+	}
+
+	proto.Merge(msg, bound)
+
+	return msg, nil
+}
+
+func (g *TranscodingGateway) mergeBody(r *http.Request, rule *transcodingRule, msg *dynamicpb.Message) error {
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		return errors.Wrap(err, "failed to read body")
+	}
+
+	if len(bytes.TrimSpace(body)) == 0 {
+		return nil
+	}
+
+	if rule.body != transcodingBodyWildcard {
+		fd := lookupField(msg.Descriptor(), rule.body)
+		if fd == nil {
+			return errors.Wrapf(errTranscodingField, "body field %q", rule.body)
+		}
+
+		key, _ := json.Marshal(fd.JSONName())
+		body = bytes.Join([][]byte{[]byte("{"), key, []byte(":"), body, []byte("}")}, nil)
+	}
+
+	decoded := dynamicpb.NewMessage(msg.Descriptor())
+	if err := g.typeResolver.Unmarshal(normalizeFieldMaskJSON(body, decoded), decoded); err != nil {
+		return errors.Wrap(err, "failed to unmarshal body")
+	}
+
+	proto.Merge(msg, decoded)
+
+	return nil
+}
+
+func setQueryParams(
+	params map[string]any, desc protoreflect.MessageDescriptor,
+	r *http.Request, rule *transcodingRule, vars map[string]string,
+) error {
+	for key, values := range r.URL.Query() {
+		if _, bound := vars[key]; bound || len(values) == 0 {
+			continue
+		}
+
+		if rule.body != "" && (key == rule.body || strings.HasPrefix(key, rule.body+".")) {
+			continue
+		}
+
+		// Unknown parameters are ignored rather than rejected: browsers and
+		// proxies append cache busters and tracking fields freely.
+		err := setFieldParam(params, desc, strings.Split(key, "."), values)
+		if errors.Is(err, errTranscodingField) {
+			continue
+		}
+
+		if err != nil {
+			return errors.Wrapf(err, "query parameter %q", key)
+		}
+	}
+
+	return nil
+}
+
+// setFieldParam stores string values from the path or query string under
+// the JSON name of the addressed field, converting them to the JSON shape
+// protojson expects for that field's kind.
+func setFieldParam(params map[string]any, desc protoreflect.MessageDescriptor, path []string, values []string) error {
+	current := params
+
+	for i, name := range path {
+		fd := lookupField(desc, name)
+		if fd == nil {
+			return errors.Wrapf(errTranscodingField, "%q", strings.Join(path[:i+1], "."))
+		}
+
+		if i == len(path)-1 {
+			return setLeafParam(current, fd, values)
+		}
+
+		if fd.Kind() != protoreflect.MessageKind || fd.IsList() || fd.IsMap() {
+			return errors.Wrapf(errTranscodingValue, "%q", strings.Join(path[:i+1], "."))
+		}
+
+		next, ok := current[fd.JSONName()].(map[string]any)
+		if !ok {
+			next = map[string]any{}
+			current[fd.JSONName()] = next
+		}
+
+		current = next
+		desc = fd.Message()
+	}
+
+	return nil
+}
+
+func setLeafParam(params map[string]any, fd protoreflect.FieldDescriptor, values []string) error {
+	if fd.IsMap() {
+		return errors.Wrapf(errTranscodingValue, "%q is a map", fd.Name())
+	}
+
+	if !fd.IsList() {
+		v, err := paramJSONValue(fd, values[0])
+		if err != nil {
+			return err
+		}
+
+		params[fd.JSONName()] = v
+
+		return nil
+	}
+
+	list := make([]any, 0, len(values))
+
+	for _, raw := range values {
+		v, err := paramJSONValue(fd, raw)
+		if err != nil {
+			return err
+		}
+
+		list = append(list, v)
+	}
+
+	params[fd.JSONName()] = list
+
+	return nil
+}
+
+//nolint:gochecknoglobals
+var stringEncodedMessages = map[protoreflect.FullName]struct{}{
+	"google.protobuf.Timestamp":   {},
+	"google.protobuf.Duration":    {},
+	"google.protobuf.FieldMask":   {},
+	"google.protobuf.StringValue": {},
+	"google.protobuf.BytesValue":  {},
+	"google.protobuf.Int32Value":  {},
+	"google.protobuf.Int64Value":  {},
+	"google.protobuf.UInt32Value": {},
+	"google.protobuf.UInt64Value": {},
+	"google.protobuf.FloatValue":  {},
+	"google.protobuf.DoubleValue": {},
+}
+
+// paramJSONValue relies on protojson accepting quoted numbers, so only bools,
+// numeric enums and wrapped bools need converting away from a JSON string.
+func paramJSONValue(fd protoreflect.FieldDescriptor, raw string) (any, error) {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return parseParamBool(fd, raw)
+	case protoreflect.EnumKind:
+		if n, err := strconv.ParseInt(raw, 10, 32); err == nil {
+			return n, nil
+		}
+
+		return raw, nil
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		name := fd.Message().FullName()
+		if name == "google.protobuf.BoolValue" {
+			return parseParamBool(fd, raw)
+		}
+
+		if _, ok := stringEncodedMessages[name]; ok {
+			return raw, nil
+		}
+
+		return nil, errors.Wrapf(errTranscodingValue, "%q has message type %s", fd.Name(), name)
+	default:
+		return raw, nil
+	}
+}
+
+func parseParamBool(fd protoreflect.FieldDescriptor, raw string) (bool, error) {
+	b, err := strconv.ParseBool(raw)
+	if err != nil {
+		return false, errors.Wrapf(err, "%q expects a bool", fd.Name())
+	}
+
+	return b, nil
+}
+
+// lookupField accepts either the proto name or the JSON name, as grpc-gateway does.
+//
+//nolint:ireturn
+func lookupField(desc protoreflect.MessageDescriptor, name string) protoreflect.FieldDescriptor {
+	fields := desc.Fields()
+	if fd := fields.ByName(protoreflect.Name(name)); fd != nil {
+		return fd
+	}
+
+	return fields.ByJSONName(name)
+}
+
+type transcodingStreamAdapter struct {
+	baseStreamAdapter
+
+	rule      *transcodingRule
+	request   *dynamicpb.Message
+	streaming bool
+	started   atomic.Bool
+}
+
+// SetTrailer folds trailers into Grpc-Trailer-* headers, the grpc-gateway
+// convention. Trailers set after a streamed body has started are dropped.
+func (a *transcodingStreamAdapter) SetTrailer(md metadata.MD) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	for k, values := range md {
+		for _, v := range values {
+			a.w.Header().Add(transcodingTrailerPfx+k, v)
+		}
+	}
+}
+
+// RecvMsg hands the transcoded request to the server-streaming handler once.
+func (a *transcodingStreamAdapter) RecvMsg(m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok || a.request == nil || a.endOfStream.Swap(true) {
+		return io.EOF
+	}
+
+	proto.Merge(msg, a.request)
+
+	return nil
+}
+
+// SendMsg writes a unary response as the body and each server-streamed
+// message as a newline-delimited {"result": ...} object.
+func (a *transcodingStreamAdapter) SendMsg(m any) error {
+	msg, ok := m.(proto.Message)
+	if !ok {
+		return nil
+	}
+
+	data, err := a.encodeResponse(msg)
+	if err != nil {
+		return err
+	}
+
+	a.sendHeader()
+
+	if a.streaming {
+		data = append(append([]byte(`{"result":`), data...), '}', '\n')
+	}
+
+	if _, err := a.w.Write(data); err != nil {
+		return err
+	}
+
+	if flusher, ok := a.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+
+	return nil
+}
+
+func (a *transcodingStreamAdapter) encodeResponse(msg proto.Message) ([]byte, error) {
+	if a.rule.responseBody == "" {
+		return a.typeResolver.Marshal(msg)
+	}
+
+	fd := lookupField(msg.ProtoReflect().Descriptor(), a.rule.responseBody)
+	if fd == nil {
+		return nil, errors.Wrapf(errTranscodingField, "response_body %q", a.rule.responseBody)
+	}
+
+	data, err := protojson.MarshalOptions{EmitUnpopulated: true, Resolver: a.typeResolver}.Marshal(msg)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, err
+	}
+
+	return fields[fd.JSONName()], nil
+}
+
+func (a *transcodingStreamAdapter) sendHeader() {
+	a.sendHeaderOnce.Do(func() {
+		a.mu.Lock()
+		defer a.mu.Unlock()
+
+		a.started.Store(true)
+		a.w.Header().Set(headerContentType, contentTypeJSON)
+		a.w.WriteHeader(http.StatusOK)
+	})
+}
+
+// writeErrorStatus answers with a google.rpc.Status body. Once a stream has
+// started the status can only be appended as a final {"error": ...} line.
+func (a *transcodingStreamAdapter) writeErrorStatus(st *status.Status) {
+	if !a.started.Load() {
+		a.sendHeaderOnce.Do(func() {
+			a.started.Store(true)
+			writeTranscodingError(a.w, a.typeResolver.Marshal, st)
+		})
+
+		return
+	}
+
+	data, err := a.typeResolver.Marshal(st.Proto())
+	if err != nil {
+		return
+	}
+
+	_, _ = a.w.Write(append(append([]byte(`{"error":`), data...), '}', '\n'))
+}
+
+func writeTranscodingError(w http.ResponseWriter, marshal func(proto.Message) ([]byte, error), st *status.Status) {
+	body, err := marshal(st.Proto())
+	if err != nil {
+		body, _ = json.Marshal(map[string]any{"code": st.Code(), "message": st.Message()})
+	}
+
+	w.Header().Set(headerContentType, contentTypeJSON)
+	w.WriteHeader(ErrorCodeToHTTPStatus(st.Code()))
+	_, _ = w.Write(body)
+}
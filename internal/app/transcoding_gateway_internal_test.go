@@ -0,0 +1,330 @@
+package app
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/goccy/go-json"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/domain/protoset"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+const transcodingTestProto = `syntax = "proto3";
+
+package transcoding.v1;
+
+import "google/api/annotations.proto";
+
+service Library {
+  rpc GetBook(GetBookRequest) returns (Book) {
+    option (google.api.http) = {
+      get: "/v1/{name=shelves/*/books/*}"
+      additional_bindings { get: "/v1/books/{name}" }
+    };
+  }
+
+  rpc CreateBook(CreateBookRequest) returns (Book) {
+    option (google.api.http) = {
+      post: "/v1/shelves/{shelf}/books"
+      body: "book"
+    };
+  }
+
+  rpc UpdateBook(Book) returns (Book) {
+    option (google.api.http) = {
+      patch: "/v1/{name=shelves/*/books/*}"
+      body: "*"
+    };
+  }
+
+  rpc ArchiveBook(GetBookRequest) returns (Book) {
+    option (google.api.http) = {
+      post: "/v1/{name=shelves/*/books/*}:archive"
+      response_body: "title"
+    };
+  }
+
+  rpc ListBooks(ListBooksRequest) returns (stream Book) {
+    option (google.api.http) = {
+      get: "/v1/shelves/{shelf}/books"
+    };
+  }
+}
+
+enum Genre {
+  GENRE_UNSPECIFIED = 0;
+  GENRE_SCIFI = 1;
+}
+
+message GetBookRequest {
+  string name = 1;
+}
+
+message Book {
+  string name = 1;
+  string title = 2;
+  Genre genre = 3;
+}
+
+message CreateBookRequest {
+  string shelf = 1;
+  Book book = 2;
+  bool validate_only = 3;
+}
+
+message ListBooksRequest {
+  string shelf = 1;
+  int32 page_size = 2;
+  repeated string tags = 3;
+  Genre genre = 4;
+}
+`
+
+func newTranscodingTestGateway(t *testing.T, stubs ...*stuber.Stub) (*TranscodingGateway, *history.MemoryStore) {
+	t.Helper()
+
+	dir := t.TempDir()
+	protoPath := filepath.Join(dir, "library.proto")
+	require.NoError(t, os.WriteFile(protoPath, []byte(transcodingTestProto), 0o600))
+
+	fdsList, err := protoset.Build(t.Context(), nil, []string{protoPath}, nil)
+	require.NoError(t, err)
+
+	var merged descriptorpb.FileDescriptorSet
+	for _, set := range fdsList {
+		merged.File = append(merged.File, set.GetFile()...)
+	}
+
+	registry := descriptors.NewRegistry()
+
+	files, err := decodeDescriptorFiles(&merged, registry.Startup())
+	require.NoError(t, err)
+
+	for _, fd := range files {
+		registry.Register(fd)
+	}
+
+	bg := stuber.NewBudgerigar()
+	bg.PutMany(stubs...)
+
+	store := history.NewMemoryStore(0)
+
+	return NewTranscodingGateway(t.Context(), bg, registry, store, nil, nil, nil), store
+}
+
+func serveTranscoded(t *testing.T, g *TranscodingGateway, method, target, body string) *httptest.ResponseRecorder {
+	t.Helper()
+
+	router := mux.NewRouter()
+	router.MatcherFunc(g.Match).Handler(g)
+
+	r := httptest.NewRequestWithContext(t.Context(), method, target, bytes.NewReader([]byte(body)))
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, r)
+
+	return w
+}
+
+func TestParsePathTemplate(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		template string
+		path     string
+		vars     map[string]string
+		ok       bool
+	}{
+		{"/v1/books/{name}", "/v1/books/42", map[string]string{"name": "42"}, true},
+		{"/v1/books/{name}", "/v1/books/a%2Fb", map[string]string{"name": "a/b"}, true},
+		{"/v1/books/{name}", "/v1/books/42/extra", nil, false},
+		{"/v1/{name=shelves/*/books/*}", "/v1/shelves/1/books/2", map[string]string{"name": "shelves/1/books/2"}, true},
+		{"/v1/{name=shelves/*/books/*}", "/v1/shelves/1/notes/2", nil, false},
+		{"/v1/{book.id}:archive", "/v1/7:archive", map[string]string{"book.id": "7"}, true},
+		{"/v1/{book.id}:archive", "/v1/7", nil, false},
+		{"/v1/files/{path=**}", "/v1/files/a/b/c", map[string]string{"path": "a/b/c"}, true},
+		{"/v1/*/items", "/v1/x/items", map[string]string{}, true},
+	}
+
+	for _, tc := range tests {
+		tpl, err := parsePathTemplate(tc.template)
+		require.NoError(t, err, tc.template)
+
+		vars, ok := tpl.match(tc.path)
+		require.Equal(t, tc.ok, ok, "%s against %s", tc.template, tc.path)
+
+		if tc.ok {
+			require.Equal(t, tc.vars, vars, tc.template)
+		}
+	}
+}
+
+func TestParsePathTemplateRejectsMalformed(t *testing.T) {
+	t.Parallel()
+
+	for _, raw := range []string{"v1/books", "/v1//books", "/v1/{name", "/v1/**/books", "/v1/{=*}", "/v1/"} {
+		_, err := parsePathTemplate(raw)
+		require.Error(t, err, raw)
+	}
+}
+
+func TestTranscodingGateway_PathVariablesAndBindings(t *testing.T) {
+	t.Parallel()
+
+	g, store := newTranscodingTestGateway(t, &stuber.Stub{
+		Service: "transcoding.v1.Library",
+		Method:  "GetBook",
+		Input:   stuber.InputData{Equals: map[string]any{"name": "shelves/1/books/42"}},
+		Output: stuber.Output{
+			Data:    map[string]any{"name": "shelves/1/books/42", "title": "Dune"},
+			Headers: map[string]string{"x-stub": "hit"},
+		},
+	})
+
+	w := serveTranscoded(t, g, http.MethodGet, "/v1/shelves/1/books/42", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.Equal(t, "application/json", w.Header().Get("Content-Type"))
+	require.Equal(t, "hit", w.Header().Get("X-Stub"))
+	require.JSONEq(t, `{"name":"shelves/1/books/42","title":"Dune"}`, w.Body.String())
+
+	// additional_bindings reach the same method.
+	w = serveTranscoded(t, g, http.MethodGet, "/v1/books/shelves%2F1%2Fbooks%2F42", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	calls := store.FilterByMethod("transcoding.v1.Library", "GetBook")
+	require.Len(t, calls, 2)
+	require.Equal(t, "shelves/1/books/42", calls[0].Requests[0]["name"])
+}
+
+func TestTranscodingGateway_BodyFieldAndQuery(t *testing.T) {
+	t.Parallel()
+
+	g, _ := newTranscodingTestGateway(t, &stuber.Stub{
+		Service: "transcoding.v1.Library",
+		Method:  "CreateBook",
+		Input: stuber.InputData{Contains: map[string]any{
+			"shelf":         "7",
+			"book":          map[string]any{"title": "Dune", "genre": "GENRE_SCIFI"},
+			"validate_only": true,
+		}},
+		Output: stuber.Output{Data: map[string]any{"name": "shelves/7/books/1", "title": "Dune"}},
+	})
+
+	w := serveTranscoded(t, g, http.MethodPost, "/v1/shelves/7/books?validateOnly=true&utm_source=x",
+		`{"title":"Dune","genre":1}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.JSONEq(t, `{"name":"shelves/7/books/1","title":"Dune"}`, w.Body.String())
+}
+
+func TestTranscodingGateway_WholeBodyAndPathOverride(t *testing.T) {
+	t.Parallel()
+
+	g, _ := newTranscodingTestGateway(t, &stuber.Stub{
+		Service: "transcoding.v1.Library",
+		Method:  "UpdateBook",
+		Input: stuber.InputData{Equals: map[string]any{
+			"name":  "shelves/1/books/2",
+			"title": "Emma",
+		}},
+		Output: stuber.Output{Data: map[string]any{"title": "Emma"}},
+	})
+
+	w := serveTranscoded(t, g, http.MethodPatch, "/v1/shelves/1/books/2", `{"name":"ignored","title":"Emma"}`)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+}
+
+func TestTranscodingGateway_VerbAndResponseBody(t *testing.T) {
+	t.Parallel()
+
+	g, _ := newTranscodingTestGateway(t, &stuber.Stub{
+		Service: "transcoding.v1.Library",
+		Method:  "ArchiveBook",
+		Output:  stuber.Output{Data: map[string]any{"name": "n", "title": "Archived"}},
+	})
+
+	w := serveTranscoded(t, g, http.MethodPost, "/v1/shelves/1/books/2:archive", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	require.JSONEq(t, `"Archived"`, w.Body.String())
+}
+
+func TestTranscodingGateway_ServerStream(t *testing.T) {
+	t.Parallel()
+
+	g, _ := newTranscodingTestGateway(t, &stuber.Stub{
+		Service: "transcoding.v1.Library",
+		Method:  "ListBooks",
+		Input: stuber.InputData{Equals: map[string]any{
+			"shelf":     "1",
+			"page_size": float64(2),
+			"tags":      []any{"a", "b"},
+			"genre":     "GENRE_SCIFI",
+		}},
+		Output: stuber.Output{Stream: []any{
+			map[string]any{"title": "One"},
+			map[string]any{"title": "Two"},
+		}},
+	})
+
+	w := serveTranscoded(t, g, http.MethodGet, "/v1/shelves/1/books?page_size=2&tags=a&tags=b&genre=GENRE_SCIFI", "")
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	lines := strings.Split(strings.TrimSpace(w.Body.String()), "\n")
+	require.Len(t, lines, 2)
+	require.JSONEq(t, `{"result":{"title":"One"}}`, lines[0])
+	require.JSONEq(t, `{"result":{"title":"Two"}}`, lines[1])
+}
+
+func TestTranscodingGateway_ErrorsUseRPCStatus(t *testing.T) {
+	t.Parallel()
+
+	code := codes.PermissionDenied
+	g, _ := newTranscodingTestGateway(t, &stuber.Stub{
+		Service: "transcoding.v1.Library",
+		Method:  "GetBook",
+		Output:  stuber.Output{Error: "nope", Code: &code},
+	})
+
+	w := serveTranscoded(t, g, http.MethodGet, "/v1/books/1", "")
+	require.Equal(t, http.StatusForbidden, w.Code)
+
+	var body struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, int(codes.PermissionDenied), body.Code)
+	require.Equal(t, "nope", body.Message)
+
+	w = serveTranscoded(t, g, http.MethodPost, "/v1/shelves/1/books", `{"title":`)
+	require.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestTranscodingGateway_MatchLeavesProtocolTrafficAlone(t *testing.T) {
+	t.Parallel()
+
+	g, _ := newTranscodingTestGateway(t)
+
+	r := httptest.NewRequestWithContext(t.Context(), http.MethodGet, "/v1/books/1", nil)
+	require.True(t, g.Match(r, nil))
+
+	r.Header.Set("Content-Type", "application/grpc-web+json")
+	require.False(t, g.Match(r, nil))
+
+	r = httptest.NewRequestWithContext(t.Context(), http.MethodDelete, "/v1/books/1", nil)
+	require.False(t, g.Match(r, nil))
+
+	r = httptest.NewRequestWithContext(t.Context(), http.MethodPost, "/transcoding.v1.Library/GetBook", nil)
+	require.False(t, g.Match(r, nil))
+}
@@ -0,0 +1,455 @@
+package app
+
+import (
+	"cmp"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/genproto/googleapis/api/annotations"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+
+	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
+)
+
+var (
+	errTemplateSyntax   = errors.New("invalid http path template")
+	errTemplateVariable = errors.New("invalid http path template variable")
+)
+
+type templateSegmentKind uint8
+
+const (
+	segmentLiteral templateSegmentKind = iota
+	segmentWildcard
+	segmentDeepWildcard
+)
+
+type templateSegment struct {
+	kind    templateSegmentKind
+	literal string
+}
+
+// templateVariable binds the path segments [start, end) to a request field.
+// end is -1 when the variable ends in "**" and runs to the end of the path.
+type templateVariable struct {
+	fieldPath []string
+	start     int
+	end       int
+}
+
+// pathTemplate is a compiled google.api.http path template:
+//
+//	Template = "/" Segments [ Verb ] ;
+//	Segments = Segment { "/" Segment } ;
+//	Segment  = "*" | "**" | LITERAL | Variable ;
+//	Variable = "{" FieldPath [ "=" Segments ] "}" ;
+//	Verb     = ":" LITERAL ;
+type pathTemplate struct {
+	raw       string
+	segments  []templateSegment
+	variables []templateVariable
+	verb      string
+}
+
+func parsePathTemplate(raw string) (*pathTemplate, error) {
+	if !strings.HasPrefix(raw, "/") {
+		return nil, errors.Wrapf(errTemplateSyntax, "%q must start with /", raw)
+	}
+
+	tpl := &pathTemplate{raw: raw}
+	body := raw[1:]
+
+	// The verb is the ":" suffix after the last segment; a colon inside a
+	// variable binding is not a verb.
+	if idx := strings.LastIndexByte(body, ':'); idx >= 0 && !strings.ContainsAny(body[idx:], "/}") {
+		tpl.verb = body[idx+1:]
+		body = body[:idx]
+	}
+
+	if err := tpl.parseSegments(body); err != nil {
+		return nil, errors.Wrapf(err, "template %q", raw)
+	}
+
+	for i, seg := range tpl.segments {
+		if seg.kind == segmentDeepWildcard && i != len(tpl.segments)-1 {
+			return nil, errors.Wrapf(errTemplateSyntax, "%q: ** must be the last segment", raw)
+		}
+	}
+
+	return tpl, nil
+}
+
+func (t *pathTemplate) parseSegments(body string) error {
+	for body != "" {
+		if body[0] == '{' {
+			end := strings.IndexByte(body, '}')
+			if end < 0 {
+				return errors.Wrap(errTemplateVariable, "unterminated variable")
+			}
+
+			if err := t.parseVariable(body[1:end]); err != nil {
+				return err
+			}
+
+			body = body[end+1:]
+		} else {
+			end := strings.IndexByte(body, '/')
+			if end < 0 {
+				end = len(body)
+			}
+
+			if err := t.appendSegment(body[:end]); err != nil {
+				return err
+			}
+
+			body = body[end:]
+		}
+
+		if body == "" {
+			break
+		}
+
+		if body[0] != '/' || len(body) == 1 {
+			return errors.Wrap(errTemplateSyntax, "segments must be separated by a single /")
+		}
+
+		body = body[1:]
+	}
+
+	if len(t.segments) == 0 {
+		return errors.Wrap(errTemplateSyntax, "empty template")
+	}
+
+	return nil
+}
+
+func (t *pathTemplate) appendSegment(raw string) error {
+	switch raw {
+	case "":
+		return errors.Wrap(errTemplateSyntax, "empty segment")
+	case "*":
+		t.segments = append(t.segments, templateSegment{kind: segmentWildcard})
+	case "**":
+		t.segments = append(t.segments, templateSegment{kind: segmentDeepWildcard})
+	default:
+		if strings.ContainsAny(raw, "{}=") {
+			return errors.Wrapf(errTemplateSyntax, "unexpected character in %q", raw)
+		}
+
+		t.segments = append(t.segments, templateSegment{kind: segmentLiteral, literal: raw})
+	}
+
+	return nil
+}
+
+func (t *pathTemplate) parseVariable(raw string) error {
+	fieldPath, pattern, hasPattern := strings.Cut(raw, "=")
+	if fieldPath == "" {
+		return errors.Wrap(errTemplateVariable, "missing field path")
+	}
+
+	if !hasPattern {
+		pattern = "*"
+	}
+
+	variable := templateVariable{
+		fieldPath: strings.Split(fieldPath, "."),
+		start:     len(t.segments),
+	}
+
+	for segment := range strings.SplitSeq(pattern, "/") {
+		if err := t.appendSegment(segment); err != nil {
+			return err
+		}
+	}
+
+	variable.end = len(t.segments)
+	if t.segments[len(t.segments)-1].kind == segmentDeepWildcard {
+		variable.end = -1
+	}
+
+	t.variables = append(t.variables, variable)
+
+	return nil
+}
+
+// match checks an escaped request path against the template and returns the
+// unescaped variable values keyed by their dotted field path.
+func (t *pathTemplate) match(escapedPath string) (map[string]string, bool) {
+	if !strings.HasPrefix(escapedPath, "/") {
+		return nil, false
+	}
+
+	path := escapedPath[1:]
+
+	if t.verb != "" {
+		var ok bool
+		if path, ok = strings.CutSuffix(path, ":"+t.verb); !ok {
+			return nil, false
+		}
+	}
+
+	parts := strings.Split(path, "/")
+
+	deep := t.segments[len(t.segments)-1].kind == segmentDeepWildcard
+	if deep && len(parts) < len(t.segments)-1 || !deep && len(parts) != len(t.segments) {
+		return nil, false
+	}
+
+	for i, seg := range t.segments {
+		switch seg.kind {
+		case segmentLiteral:
+			if parts[i] != seg.literal {
+				return nil, false
+			}
+		case segmentWildcard:
+			if parts[i] == "" {
+				return nil, false
+			}
+		case segmentDeepWildcard:
+		}
+	}
+
+	values := make(map[string]string, len(t.variables))
+
+	for _, v := range t.variables {
+		end := v.end
+		if end < 0 {
+			end = len(parts)
+		}
+
+		unescaped := make([]string, 0, end-v.start)
+
+		for _, part := range parts[v.start:end] {
+			s, err := url.PathUnescape(part)
+			if err != nil {
+				return nil, false
+			}
+
+			unescaped = append(unescaped, s)
+		}
+
+		values[strings.Join(v.fieldPath, ".")] = strings.Join(unescaped, "/")
+	}
+
+	return values, true
+}
+
+// specificity orders templates so literal segments win over wildcards and a
+// verb wins over its absence, the way Envoy and grpc-gateway resolve overlaps.
+func (t *pathTemplate) specificity() (int, int, int) {
+	literals, wildcards := 0, 0
+
+	for _, seg := range t.segments {
+		switch seg.kind {
+		case segmentLiteral:
+			literals++
+		case segmentWildcard:
+			wildcards++
+		case segmentDeepWildcard:
+		}
+	}
+
+	verb := 0
+	if t.verb != "" {
+		verb = 1
+	}
+
+	return literals, wildcards, verb
+}
+
+// transcodingRule is one HTTP binding of a gRPC method, either the primary
+// google.api.http rule or one of its additional_bindings.
+type transcodingRule struct {
+	httpMethod   string
+	template     *pathTemplate
+	body         string
+	responseBody string
+	method       protoreflect.MethodDescriptor
+}
+
+func compareTranscodingRules(a, b *transcodingRule) int {
+	aLit, aWild, aVerb := a.template.specificity()
+	bLit, bWild, bVerb := b.template.specificity()
+
+	return cmp.Or(
+		cmp.Compare(bLit, aLit),
+		cmp.Compare(bVerb, aVerb),
+		cmp.Compare(bWild, aWild),
+		cmp.Compare(a.template.raw, b.template.raw),
+		cmp.Compare(a.httpMethod, b.httpMethod),
+		cmp.Compare(a.method.FullName(), b.method.FullName()),
+	)
+}
+
+// transcodingRules indexes the google.api.http bindings of every loaded
+// service. The index is rebuilt when the global registry grows or the
+// dynamic registry changes generation.
+type transcodingRules struct {
+	registry *descriptors.Registry
+
+	mu           sync.Mutex
+	rules        []*transcodingRule
+	startupFiles int
+	dynamicGen   uint64
+	built        bool
+}
+
+func newTranscodingRules(registry *descriptors.Registry) *transcodingRules {
+	return &transcodingRules{registry: registry}
+}
+
+func (r *transcodingRules) find(httpMethod, escapedPath string) (*transcodingRule, map[string]string, bool) {
+	for _, rule := range r.snapshot() {
+		if rule.httpMethod != httpMethod {
+			continue
+		}
+
+		if values, ok := rule.template.match(escapedPath); ok {
+			return rule, values, true
+		}
+	}
+
+	return nil, nil, false
+}
+
+func (r *transcodingRules) snapshot() []*transcodingRule {
+	startup := r.registry.Startup()
+	startupFiles := startup.NumFiles()
+
+	var gen uint64
+	if r.registry != nil {
+		gen = r.registry.Generation()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.built && r.startupFiles == startupFiles && r.dynamicGen == gen {
+		return r.rules
+	}
+
+	seen := make(map[protoreflect.FullName]struct{})
+
+	var rules []*transcodingRule
+
+	collect := func(file protoreflect.FileDescriptor) bool {
+		services := file.Services()
+		for i := range services.Len() {
+			methods := services.Get(i).Methods()
+			for j := range methods.Len() {
+				method := methods.Get(j)
+				if _, dup := seen[method.FullName()]; dup {
+					continue
+				}
+
+				seen[method.FullName()] = struct{}{}
+				rules = append(rules, methodTranscodingRules(method)...)
+			}
+		}
+
+		return true
+	}
+
+	// The dynamic registry goes first so a descriptor fetched at runtime
+	// overrides a stale compiled copy of the same method.
+	if r.registry != nil {
+		r.registry.RangeFiles(collect)
+	}
+
+	startup.RangeFiles(collect)
+
+	slices.SortStableFunc(rules, compareTranscodingRules)
+
+	r.rules = rules
+	r.startupFiles = startupFiles
+	r.dynamicGen = gen
+	r.built = true
+
+	return rules
+}
+
+func methodTranscodingRules(method protoreflect.MethodDescriptor) []*transcodingRule {
+	httpRule := methodHTTPRule(method)
+	if httpRule == nil {
+		return nil
+	}
+
+	bindings := append([]*annotations.HttpRule{httpRule}, httpRule.GetAdditionalBindings()...)
+	rules := make([]*transcodingRule, 0, len(bindings))
+
+	for _, binding := range bindings {
+		httpMethod, rawPath := httpRulePattern(binding)
+		if httpMethod == "" || rawPath == "" {
+			continue
+		}
+
+		tpl, err := parsePathTemplate(rawPath)
+		if err != nil {
+			continue
+		}
+
+		rules = append(rules, &transcodingRule{
+			httpMethod:   httpMethod,
+			template:     tpl,
+			body:         binding.GetBody(),
+			responseBody: binding.GetResponseBody(),
+			method:       method,
+		})
+	}
+
+	return rules
+}
+
+// methodHTTPRule reads the google.api.http option. Descriptors compiled at
+// runtime or fetched over reflection carry the extension either as unknown
+// bytes or as a dynamic message, so the options are always round-tripped
+// through the global type registry to get the generated HttpRule.
+func methodHTTPRule(method protoreflect.MethodDescriptor) *annotations.HttpRule {
+	opts, ok := method.Options().(*descriptorpb.MethodOptions)
+	if !ok || opts == nil {
+		return nil
+	}
+
+	raw, err := proto.Marshal(opts)
+	if err != nil || len(raw) == 0 {
+		return nil
+	}
+
+	resolved := &descriptorpb.MethodOptions{}
+	if err := (proto.UnmarshalOptions{Resolver: protoregistry.GlobalTypes}).Unmarshal(raw, resolved); err != nil {
+		return nil
+	}
+
+	rule, ok := proto.GetExtension(resolved, annotations.E_Http).(*annotations.HttpRule)
+	if !ok || rule == nil {
+		return nil
+	}
+
+	return rule
+}
+
+func httpRulePattern(rule *annotations.HttpRule) (string, string) {
+	switch pattern := rule.GetPattern().(type) {
+	case *annotations.HttpRule_Get:
+		return "GET", pattern.Get
+	case *annotations.HttpRule_Put:
+		return "PUT", pattern.Put
+	case *annotations.HttpRule_Post:
+		return "POST", pattern.Post
+	case *annotations.HttpRule_Delete:
+		return "DELETE", pattern.Delete
+	case *annotations.HttpRule_Patch:
+		return "PATCH", pattern.Patch
+	case *annotations.HttpRule_Custom:
+		return strings.ToUpper(pattern.Custom.GetKind()), pattern.Custom.GetPath()
+	default:
+		return "", ""
+	}
+}
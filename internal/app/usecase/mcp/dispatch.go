@@ -1,14 +1,21 @@
 package mcp
 
-type ToolHandler func(map[string]any) (map[string]any, error)
+import "context"
 
-func DispatchTool(name string, args map[string]any, handlers map[string]ToolHandler) (map[string]any, error, bool) {
+type ToolHandler func(context.Context, map[string]any) (map[string]any, error)
+
+func DispatchTool(
+	ctx context.Context,
+	name string,
+	args map[string]any,
+	handlers map[string]ToolHandler,
+) (map[string]any, error, bool) {
 	handler, ok := handlers[name]
 	if !ok {
 		return map[string]any{}, nil, false
 	}
 
-	result, err := handler(args)
+	result, err := handler(ctx, args)
 
 	return result, err, true
 }
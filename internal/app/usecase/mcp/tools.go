@@ -119,6 +119,14 @@ func nonNegativeIntegerProp() map[string]any {
 	return map[string]any{"type": "integer", "minimum": 0}
 }
 
+func booleanProp() map[string]any {
+	return map[string]any{"type": "boolean"}
+}
+
+func stringMapProp() map[string]any {
+	return map[string]any{"type": "object", "additionalProperties": stringProp()}
+}
+
 func objectAnyProp() map[string]any {
 	return map[string]any{"type": "object", "additionalProperties": true}
 }
@@ -225,11 +233,15 @@ func servicesDeleteTool() map[string]any {
 
 func historyListTool() map[string]any {
 	return newTool(ToolHistoryList, "List recent gRPC call history for debugging", objectSchema(map[string]any{
-		"service": stringProp(),
-		"method":  stringProp(),
-		"session": stringProp(),
-		"limit":   nonNegativeIntegerProp(),
-		"offset":  nonNegativeIntegerProp(),
+		"service":   stringProp(),
+		"method":    stringProp(),
+		"session":   stringProp(),
+		"limit":     nonNegativeIntegerProp(),
+		"offset":    nonNegativeIntegerProp(),
+		"transport": stringProp(),
+		"peer":      stringProp(),
+		"headers":   stringMapProp(),
+		"deadline":  booleanProp(),
 	}))
 }
 
@@ -260,8 +272,13 @@ func verifyCallsTool() map[string]any {
 		"service":       stringProp(),
 		"method":        stringProp(),
 		"expectedCount": nonNegativeIntegerProp(),
+		"atLeast":       nonNegativeIntegerProp(),
+		"atMost":        nonNegativeIntegerProp(),
+		"never":         booleanProp(),
+		"input":         objectAnyProp(),
+		"headers":       objectAnyProp(),
 		"session":       stringProp(),
-	}, "service", "method", "expectedCount"))
+	}, "service", "method"))
 }
 
 func schemaStubTool() map[string]any {
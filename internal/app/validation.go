@@ -5,6 +5,7 @@ import (
 
 	"github.com/go-playground/validator/v10"
 
+	"github.com/bavix/gripmock/v3/internal/infra/jsonpatch"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 )
 
@@ -62,7 +63,8 @@ func validateOutputConfiguration(fl validator.FieldLevel) bool {
 		return false
 	}
 
-	hasDataOutput := v.Output.Error != "" || v.Output.Data != nil || v.Output.Code != nil || len(v.Output.Details) > 0
+	hasDataOutput := v.Output.Error != "" || v.Output.Data != nil || v.Output.Code != nil ||
+		len(v.Output.Details) > 0 || v.Output.Script != "" || v.Output.Upstream != nil
 	hasStreamOutput := len(v.Output.Stream) > 0
 
 	return hasDataOutput != hasStreamOutput
@@ -92,6 +94,20 @@ func validateEffectsConfiguration(fl validator.FieldLevel) bool {
 	return true
 }
 
+// validateUpstream checks the upstream patch of an output: the response comes
+// from the upstream, so the output carries no data of its own.
+func validateUpstream(output stuber.Output) error {
+	if output.Upstream == nil {
+		return nil
+	}
+
+	if output.Data != nil || len(output.Stream) > 0 {
+		return ErrUpstreamWithData
+	}
+
+	return jsonpatch.Validate(output.Upstream.Patch) //nolint:wrapcheck
+}
+
 func stubFromFieldLevel(fl validator.FieldLevel) *stuber.Stub {
 	if v, ok := fl.Top().Interface().(*stuber.Stub); ok {
 		return v
@@ -101,7 +117,8 @@ func stubFromFieldLevel(fl validator.FieldLevel) *stuber.Stub {
 }
 
 func hasValidInputData(input stuber.InputData) bool {
-	if input.Contains != nil || input.Equals != nil || input.Matches != nil || input.Glob != nil {
+	if input.Contains != nil || input.Equals != nil || input.Matches != nil || input.Glob != nil || input.CEL != "" ||
+		input.Where != nil {
 		return true
 	}
 
@@ -126,6 +143,8 @@ func getValidationMessage(fe validator.FieldError) string {
 		return "Invalid effects configuration: upsert requires 'stub', delete requires 'id'"
 	case "gte":
 		return "Options.Times must be >= 0 (0 = unlimited matches)"
+	case "required_with":
+		return "scenario is required when requiredState or newState is set"
 	default:
 		return fmt.Sprintf("Validation failed for field %s with tag %s", fe.Field(), fe.Tag())
 	}
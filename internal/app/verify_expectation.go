@@ -0,0 +1,149 @@
+package app
+
+import (
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+	"github.com/goccy/go-json"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/domain/rest"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+var (
+	errVerifyNegativeBound = errors.New("count bounds must not be negative")
+	errVerifyNoSteps       = errors.New("steps must not be empty")
+)
+
+// verifyBounds are the count constraints of a verify request, shared by REST and MCP.
+type verifyBounds struct {
+	times   *int
+	atLeast *int
+	atMost  *int
+	never   bool
+}
+
+// newVerifyExpectation builds the history expectation for one method. input and
+// headers are stub-style matcher objects in any JSON-compatible form; nil skips them.
+func newVerifyExpectation(
+	service, method, session string, bounds verifyBounds, input, headers any,
+) (history.Expectation, error) {
+	exp := history.Expectation{
+		Service: service,
+		Method:  method,
+		Session: session,
+		Times:   bounds.times,
+		AtLeast: bounds.atLeast,
+		AtMost:  bounds.atMost,
+	}
+
+	if bounds.never {
+		exp.AtMost = new(0)
+	}
+
+	for _, bound := range []*int{exp.Times, exp.AtLeast, exp.AtMost} {
+		if bound != nil && *bound < 0 {
+			return history.Expectation{}, errVerifyNegativeBound
+		}
+	}
+
+	if err := decodeVerifyMatcher(input, &exp.Input); err != nil {
+		return history.Expectation{}, errors.Wrap(err, "invalid input matcher")
+	}
+
+	if err := decodeVerifyMatcher(headers, &exp.Headers); err != nil {
+		return history.Expectation{}, errors.Wrap(err, "invalid headers matcher")
+	}
+
+	return exp, nil
+}
+
+// decodeVerifyMatcher re-reads a matcher through JSON so REST, MCP and stub
+// files all share the stuber field names.
+func decodeVerifyMatcher(raw any, dst any) error {
+	if raw == nil {
+		return nil
+	}
+
+	payload, err := json.Marshal(raw)
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(payload, dst)
+}
+
+func restVerifyExpectation(req rest.VerifyRequest, session string) (history.Expectation, error) {
+	bounds := verifyBounds{times: req.ExpectedCount, atLeast: req.AtLeast, atMost: req.AtMost}
+	if req.Never != nil {
+		bounds.never = *req.Never
+	}
+
+	var input, headers any
+	if req.Input != nil {
+		input = req.Input
+	}
+
+	if req.Headers != nil {
+		headers = req.Headers
+	}
+
+	return newVerifyExpectation(req.Service, req.Method, session, bounds, input, headers)
+}
+
+func restVerifySteps(reqs []rest.VerifyRequest, session string) ([]history.Expectation, error) {
+	if len(reqs) == 0 {
+		return nil, errVerifyNoSteps
+	}
+
+	steps := make([]history.Expectation, 0, len(reqs))
+
+	for i, req := range reqs {
+		step, err := restVerifyExpectation(req, session)
+		if err != nil {
+			return nil, errors.Wrapf(err, "step %d", i)
+		}
+
+		steps = append(steps, step)
+	}
+
+	return steps, nil
+}
+
+func verifyFailureMessage(exp history.Expectation, actual int) string {
+	return fmt.Sprintf("expected %s/%s to be called %s, got %d", exp.Service, exp.Method, exp.Describe(), actual)
+}
+
+func restVerifyClosest(diffs []history.CallDiff) []rest.VerifyCallDiff {
+	out := make([]rest.VerifyCallDiff, 0, len(diffs))
+
+	for _, diff := range diffs {
+		out = append(out, rest.VerifyCallDiff{
+			Call:    historyCallRecordToRest(diff.Call),
+			Input:   restVerifyMismatches(diff.Input),
+			Headers: restVerifyMismatches(diff.Headers),
+		})
+	}
+
+	return out
+}
+
+func restVerifyMismatches(mismatches []stuber.Mismatch) *[]rest.VerifyMismatch {
+	if len(mismatches) == 0 {
+		return nil
+	}
+
+	out := make([]rest.VerifyMismatch, 0, len(mismatches))
+
+	for _, m := range mismatches {
+		item := rest.VerifyMismatch{Operator: m.Operator, Expected: m.Expected, Actual: m.Actual}
+		if m.Field != "" {
+			item.Field = &m.Field
+		}
+
+		out = append(out, item)
+	}
+
+	return &out
+}
@@ -61,6 +61,7 @@ type Config struct {
 	GatewayTLS TLSConfig    `envPrefix:"GATEWAY_TLS_"`
 
 	ConnectRequireProtocolVersion bool `env:"CONNECT_REQUIRE_PROTOCOL_VERSION" envDefault:"false"`
+	GatewayTranscodingEnabled     bool `env:"GATEWAY_TRANSCODING_ENABLED"      envDefault:"true"`
 
 	CORSAllowedOrigins []string `env:"CORS_ALLOWED_ORIGINS" envDefault:"*"`
 	CORSAllowedMethods []string `env:"CORS_ALLOWED_METHODS" envDefault:"GET,POST,DELETE,PATCH"`
@@ -71,6 +72,10 @@ type Config struct {
 	StubWatcherInterval time.Duration `env:"STUB_WATCHER_INTERVAL" envDefault:"1s"`
 	StubWatcherType     watcherType   `env:"STUB_WATCHER_TYPE"     envDefault:"fsnotify"`
 
+	ProtoWatcherEnabled  bool          `env:"PROTO_WATCHER_ENABLED"  envDefault:"false"`
+	ProtoWatcherInterval time.Duration `env:"PROTO_WATCHER_INTERVAL" envDefault:"1s"`
+	ProtoWatcherType     watcherType   `env:"PROTO_WATCHER_TYPE"     envDefault:"fsnotify"`
+
 	MaxNestingDepth uint32 `env:"MAX_NESTING_DEPTH" envDefault:"256"`
 
 	HistoryEnabled         bool     `env:"HISTORY_ENABLED"           envDefault:"true"`
@@ -78,11 +83,36 @@ type Config struct {
 	HistoryMessageMaxBytes int64    `env:"HISTORY_MESSAGE_MAX_BYTES" envDefault:"262144"`
 	HistoryRedactKeys      []string `env:"HISTORY_REDACT_KEYS"`
 
+	HistoryStore       historyStoreType `env:"HISTORY_STORE"        envDefault:"memory"`
+	HistoryDir         string           `env:"HISTORY_DIR"`
+	HistorySegmentSize ByteSize         `env:"HISTORY_SEGMENT_SIZE" envDefault:"16M"`
+	HistoryFileLimit   ByteSize         `env:"HISTORY_FILE_LIMIT"   envDefault:"1G"`
+
+	StubStore    stubStoreType `env:"STUB_STORE"     envDefault:"memory"`
+	StubStoreDir string        `env:"STUB_STORE_DIR"`
+
+	SnapshotFile string `env:"SNAPSHOT_FILE"`
+
+	ProxyRules string `env:"PROXY_RULES"`
+
 	SessionGCInterval time.Duration `env:"SESSION_GC_INTERVAL" envDefault:"30s"`
 	SessionGCTTL      time.Duration `env:"SESSION_GC_TTL"      envDefault:"60s"`
 
 	TemplatePluginPaths []string `env:"TEMPLATE_PLUGIN_PATHS"`
 
+	ScriptMaxSteps uint64        `env:"SCRIPT_MAX_STEPS" envDefault:"1000000"`
+	ScriptTimeout  time.Duration `env:"SCRIPT_TIMEOUT"   envDefault:"1s"`
+
+	FaultsSeed uint64 `env:"FAULTS_SEED"`
+	FakerSeed  uint64 `env:"FAKER_SEED"`
+
+	StubValidation stubValidationMode `env:"STUB_VALIDATION" envDefault:"lenient"`
+
+	RequestValidation bool `env:"REQUEST_VALIDATION" envDefault:"false"`
+
+	AutoMock            []string `env:"AUTO_MOCK"`
+	AutoMockStreamCount uint     `env:"AUTO_MOCK_STREAM_COUNT" envDefault:"3"`
+
 	BSR BSRConfig `envPrefix:"BSR_"`
 }
 
@@ -13,12 +13,18 @@ func TestParseHistoryDefaults(t *testing.T) {
 	t.Setenv("HISTORY_REDACT_KEYS", "")
 	t.Setenv("HISTORY_MESSAGE_MAX_BYTES", "")
 	t.Setenv("HISTORY_ENABLED", "")
+	t.Setenv("HISTORY_STORE", "")
+	t.Setenv("HISTORY_SEGMENT_SIZE", "")
+	t.Setenv("HISTORY_FILE_LIMIT", "")
 
 	cfg := Load()
 	require.True(t, cfg.HistoryEnabled, "history should be enabled by default")
 	require.Equal(t, int64(64*1024*1024), cfg.HistoryLimit.Int64(), "unexpected default limit")
 	require.EqualValues(t, 262144, cfg.HistoryMessageMaxBytes, "unexpected default max bytes")
 	require.Empty(t, cfg.HistoryRedactKeys, "expected no redact keys by default")
+	require.Equal(t, HistoryStoreMemory, cfg.HistoryStore, "history should stay in memory by default")
+	require.Equal(t, int64(16*1024*1024), cfg.HistorySegmentSize.Int64(), "unexpected default segment size")
+	require.Equal(t, int64(1024*1024*1024), cfg.HistoryFileLimit.Int64(), "unexpected default file limit")
 }
 
 func TestParseHistoryEnv(t *testing.T) {
@@ -70,3 +76,12 @@ func TestParseGRPCLimitsEnv(t *testing.T) {
 	require.Equal(t, time.Minute, cfg.GRPCLimits.KeepaliveMaxConnectionAge)
 	require.Equal(t, 10*time.Second, cfg.GRPCLimits.KeepaliveTimeout, "untouched var keeps its default")
 }
+
+func TestParseStubStoreEnv(t *testing.T) {
+	t.Setenv("STUB_STORE", "dir")
+	t.Setenv("STUB_STORE_DIR", "/state/stubs")
+
+	cfg := Load()
+	require.Equal(t, StubStoreDir, cfg.StubStore)
+	require.Equal(t, "/state/stubs", cfg.StubStoreDir)
+}
@@ -6,3 +6,24 @@ const (
 	WatcherFSNotify watcherType = "fsnotify"
 	WatcherTimer    watcherType = "timer"
 )
+
+type historyStoreType string
+
+const (
+	HistoryStoreMemory historyStoreType = "memory"
+	HistoryStoreFile   historyStoreType = "file"
+)
+
+type stubStoreType string
+
+const (
+	StubStoreMemory stubStoreType = "memory"
+	StubStoreDir    stubStoreType = "dir"
+)
+
+type stubValidationMode string
+
+const (
+	StubValidationLenient stubValidationMode = "lenient"
+	StubValidationStrict  stubValidationMode = "strict"
+)
@@ -10,20 +10,28 @@ import (
 	"github.com/go-playground/validator/v10"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/rs/zerolog"
 
 	"github.com/bavix/gripmock/v3/internal/app"
 	"github.com/bavix/gripmock/v3/internal/config"
 	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
 	"github.com/bavix/gripmock/v3/internal/domain/history"
 	protosetdom "github.com/bavix/gripmock/v3/internal/domain/protoset"
+	"github.com/bavix/gripmock/v3/internal/infra/automock"
 	bufclient "github.com/bavix/gripmock/v3/internal/infra/bufclient"
 	"github.com/bavix/gripmock/v3/internal/infra/build"
+	"github.com/bavix/gripmock/v3/internal/infra/drift"
+	"github.com/bavix/gripmock/v3/internal/infra/faker"
+	"github.com/bavix/gripmock/v3/internal/infra/faults"
 	"github.com/bavix/gripmock/v3/internal/infra/lifecycle"
 	internalplugins "github.com/bavix/gripmock/v3/internal/infra/plugins"
 	"github.com/bavix/gripmock/v3/internal/infra/proxyroutes"
 	reflectclient "github.com/bavix/gripmock/v3/internal/infra/reflectclient"
+	"github.com/bavix/gripmock/v3/internal/infra/requestcheck"
+	"github.com/bavix/gripmock/v3/internal/infra/script"
 	sourceclient "github.com/bavix/gripmock/v3/internal/infra/sourceclient"
 	"github.com/bavix/gripmock/v3/internal/infra/storage"
+	"github.com/bavix/gripmock/v3/internal/infra/stubcheck"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/telemetry"
 	"github.com/bavix/gripmock/v3/internal/infra/template"
@@ -46,12 +54,26 @@ type Builder struct {
 
 	// lazy stateful — guarded fields together, then all sync.Once
 	budgerigar     *stuber.Budgerigar
-	historyStore   *history.MemoryStore
+	historyStore   history.Store
 	remoteClient   protosetdom.RemoteClient
 	extender       *storage.Extender
 	pluginRegistry *internalplugins.Registry
 	templateEngine *template.Engine
 	templateOnce   sync.Once
+	hooks          *internalplugins.Hooks
+	hooksOnce      sync.Once
+	scripts        *script.Runner
+	scriptsOnce    sync.Once
+	faults         *faults.Injector
+	faultsOnce     sync.Once
+	stubCheck      *stubcheck.Checker
+	stubCheckOnce  sync.Once
+	requests       *requestcheck.Validator
+	requestsOnce   sync.Once
+	autoMock       *automock.Synthesizer
+	autoMockOnce   sync.Once
+	drift          *drift.Store
+	driftOnce      sync.Once
 	pluginPaths    []string
 
 	budgerigarOnce   sync.Once
@@ -119,6 +141,7 @@ func (b *Builder) LoadPlugins(ctx context.Context) {
 		allPaths := slices.Concat(b.config.TemplatePluginPaths, b.pluginPaths)
 		loader := internalplugins.NewLoader(allPaths)
 		loader.Load(ctx, reg)
+		b.ender.Add(func(_ context.Context) error { return loader.Close() })
 		b.pluginRegistry = reg
 	})
 }
@@ -126,12 +149,105 @@ func (b *Builder) LoadPlugins(ctx context.Context) {
 func (b *Builder) TemplateEngine(ctx context.Context) *template.Engine {
 	b.templateOnce.Do(func() {
 		b.LoadPlugins(ctx)
-		b.templateEngine = template.New(context.WithoutCancel(ctx), b.pluginRegistry)
+		// The engine owns the server's faker sequences, so servers in one
+		// process never draw from each other's seed.
+		b.templateEngine = template.NewWithSeeds(context.WithoutCancel(ctx), b.pluginRegistry,
+			faker.NewSeeds(b.config.FakerSeed))
 	})
 
 	return b.templateEngine
 }
 
+// Hooks returns the plugin lifecycle hooks, nil when no plugin registers any.
+func (b *Builder) Hooks(ctx context.Context) *internalplugins.Hooks {
+	b.hooksOnce.Do(func() {
+		b.LoadPlugins(ctx)
+
+		if b.pluginRegistry != nil {
+			b.hooks = internalplugins.NewHooks(b.pluginRegistry)
+		}
+	})
+
+	return b.hooks
+}
+
+// Scripts returns the runner for output scripts, bounded by the configured limits.
+func (b *Builder) Scripts() *script.Runner {
+	b.scriptsOnce.Do(func() {
+		b.scripts = script.New(script.Limits{
+			MaxSteps: b.config.ScriptMaxSteps,
+			Timeout:  b.config.ScriptTimeout,
+		})
+	})
+
+	return b.scripts
+}
+
+// Faults returns the fault injector shared by every protocol, seeded from the config.
+func (b *Builder) Faults() *faults.Injector {
+	b.faultsOnce.Do(func() {
+		b.faults = faults.New(b.config.FaultsSeed)
+	})
+
+	return b.faults
+}
+
+// Drift returns the store of shadow-mode comparisons shared by every protocol
+// and the API.
+func (b *Builder) Drift() *drift.Store {
+	b.driftOnce.Do(func() {
+		b.drift = drift.NewStore()
+	})
+
+	return b.drift
+}
+
+// StubCheck returns the checker validating stubs against the descriptors of
+// the methods they mock, strict or lenient as configured.
+func (b *Builder) StubCheck(ctx context.Context) *stubcheck.Checker {
+	b.stubCheckOnce.Do(func() {
+		b.stubCheck = stubcheck.New(
+			zerolog.Ctx(ctx),
+			b.config.StubValidation == config.StubValidationStrict,
+			b.DescriptorRegistry(),
+			b.DescriptorRegistry().Startup(),
+		)
+	})
+
+	return b.stubCheck
+}
+
+// RequestValidator returns the protovalidate check run on incoming requests,
+// nil unless request validation is enabled.
+func (b *Builder) RequestValidator(ctx context.Context) *requestcheck.Validator {
+	b.requestsOnce.Do(func() {
+		if !b.config.RequestValidation {
+			return
+		}
+
+		v, err := requestcheck.New(b.DescriptorRegistry(), b.DescriptorRegistry().Startup())
+		if err != nil {
+			zerolog.Ctx(ctx).Err(err).Msg("request validation is disabled")
+
+			return
+		}
+
+		b.requests = v
+	})
+
+	return b.requests
+}
+
+// AutoMock returns the synthesizer answering calls no stub matches, nil unless
+// AUTO_MOCK names at least one service or method.
+func (b *Builder) AutoMock() *automock.Synthesizer {
+	b.autoMockOnce.Do(func() {
+		b.autoMock = automock.New(b.config.AutoMock, b.config.AutoMockStreamCount)
+	})
+
+	return b.autoMock
+}
+
 func (b *Builder) InitTelemetry(ctx context.Context) {
 	b.otelInstr = telemetry.InitMetrics(ctx, build.Version, b.promReg)
 
@@ -157,19 +273,37 @@ func (b *Builder) PluginInfos(ctx context.Context) []pkgplugins.PluginWithFuncs
 	return b.pluginRegistry.Groups(ctx)
 }
 
-func (b *Builder) HistoryStore() *history.MemoryStore {
+//nolint:ireturn
+func (b *Builder) HistoryStore() history.Store {
 	if !b.config.HistoryEnabled {
 		return nil
 	}
 
 	b.historyStoreOnce.Do(func() {
-		opts := []history.MemoryStoreOption{
+		opts := []history.StoreOption{
 			history.WithMessageMaxBytes(b.config.HistoryMessageMaxBytes),
 		}
 		if len(b.config.HistoryRedactKeys) > 0 {
 			opts = append(opts, history.WithRedactKeys(b.config.HistoryRedactKeys))
 		}
 
+		if b.config.HistoryStore == config.HistoryStoreFile {
+			store, err := history.OpenFileStore(
+				b.config.HistoryDir,
+				b.config.HistorySegmentSize.Int64(),
+				b.config.HistoryFileLimit.Int64(),
+				opts...,
+			)
+			if err == nil {
+				b.ender.Add(func(_ context.Context) error { return store.Close() })
+				b.historyStore = store
+
+				return
+			}
+
+			log.Printf("[gripmock] history file store init failed: %v; using in-memory history", err)
+		}
+
 		b.historyStore = history.NewMemoryStore(b.config.HistoryLimit.Int64(), opts...)
 	})
 
@@ -35,3 +35,30 @@ func TestBuilderHIstoryStoreWithRedactKeys(t *testing.T) {
 	require.Equal(t, "alice", all[0].Requests[0]["user"])
 	require.Equal(t, "[REDACTED]", all[0].Requests[0]["password"])
 }
+
+func TestBuilderHistoryStoreFile(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	cfg := config.Config{
+		HistoryEnabled:     true,
+		HistoryStore:       config.HistoryStoreFile,
+		HistoryDir:         dir,
+		HistorySegmentSize: config.ByteSize{Bytes: 1 << 20},
+	}
+	store := deps.NewBuilder(deps.WithConfig(cfg)).HistoryStore()
+	require.IsType(t, &history.FileStore{}, store)
+
+	store.Record(history.CallRecord{Service: "svc", Method: "M"})
+
+	reopened := deps.NewBuilder(deps.WithConfig(cfg)).HistoryStore()
+	require.Equal(t, 1, reopened.Count())
+}
+
+func TestBuilderHistoryStoreFileWithoutDirFallsBack(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Config{HistoryEnabled: true, HistoryStore: config.HistoryStoreFile}
+	store := deps.NewBuilder(deps.WithConfig(cfg)).HistoryStore()
+	require.IsType(t, &history.MemoryStore{}, store)
+}
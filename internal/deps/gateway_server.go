@@ -41,8 +41,8 @@ const (
 	gatewayMaxHeaderBytes    = 1 << 20
 )
 
-// GatewayServe starts the unified HTTP endpoint that handles both
-// ConnectRPC and gRPC-web protocols on a single port.
+// GatewayServe starts the unified HTTP endpoint that handles ConnectRPC,
+// gRPC-web and google.api.http transcoded requests on a single port.
 func (b *Builder) GatewayServe(ctx context.Context) error {
 	if b.config.Gateway.Port == "0" {
 		return nil
@@ -51,6 +51,14 @@ func (b *Builder) GatewayServe(ctx context.Context) error {
 	gateway := b.newMultiProtocolGateway(ctx)
 
 	router := mux.NewRouter()
+
+	// HTTP rules may bind any path, so they are tried before the fixed
+	// /{service}/{method} shape the Connect and gRPC-Web clients use.
+	if b.config.GatewayTranscodingEnabled {
+		transcoder := b.newTranscodingGateway(ctx)
+		router.MatcherFunc(transcoder.Match).Handler(transcoder)
+	}
+
 	router.Handle("/{service}/{method}", gateway).Methods(http.MethodPost, http.MethodGet)
 
 	srv := b.newGatewayServer(ctx, router)
@@ -72,15 +80,10 @@ func (b *Builder) GatewayServe(ctx context.Context) error {
 }
 
 func (b *Builder) newMultiProtocolGateway(ctx context.Context) *app.MultiProtocolGateway {
-	var recorder history.Recorder
-	if store := b.HistoryStore(); store != nil {
-		recorder = store
-	}
-
 	g := app.NewMultiProtocolGateway(ctx,
-		b.Budgerigar(),
+		b.Budgerigar(ctx),
 		b.DescriptorRegistry(),
-		recorder,
+		b.gatewayRecorder(),
 		b.ProxyRoutesRef(),
 		b.StubValidator(),
 		b.ErrorFormatter(),
@@ -88,6 +91,41 @@ func (b *Builder) newMultiProtocolGateway(ctx context.Context) *app.MultiProtoco
 	)
 
 	g.RequireProtocolVersion(b.config.ConnectRequireProtocolVersion)
+	g.SetHooks(b.Hooks(ctx))
+	g.SetScripts(b.Scripts())
+	g.SetFaults(b.Faults())
+	g.SetRequestValidator(b.RequestValidator(ctx))
+	g.SetAutoMock(b.AutoMock())
+	g.SetDrift(b.Drift())
+
+	return g
+}
+
+// gatewayRecorder keeps a nil store from turning into a non-nil interface.
+func (b *Builder) gatewayRecorder() history.Recorder {
+	if store := b.HistoryStore(); store != nil {
+		return store
+	}
+
+	return nil
+}
+
+func (b *Builder) newTranscodingGateway(ctx context.Context) *app.TranscodingGateway {
+	g := app.NewTranscodingGateway(ctx,
+		b.Budgerigar(ctx),
+		b.DescriptorRegistry(),
+		b.gatewayRecorder(),
+		b.ProxyRoutesRef(),
+		b.StubValidator(),
+		b.ErrorFormatter(),
+		b.TemplateEngine(ctx),
+	)
+	g.SetHooks(b.Hooks(ctx))
+	g.SetScripts(b.Scripts())
+	g.SetFaults(b.Faults())
+	g.SetRequestValidator(b.RequestValidator(ctx))
+	g.SetAutoMock(b.AutoMock())
+	g.SetDrift(b.Drift())
 
 	return g
 }
@@ -11,11 +11,12 @@ import (
 	"github.com/bavix/gripmock/v3/internal/app"
 	"github.com/bavix/gripmock/v3/internal/domain/history"
 	"github.com/bavix/gripmock/v3/internal/domain/proto"
+	"github.com/bavix/gripmock/v3/internal/infra/watcher"
 )
 
 //nolint:funlen,cyclop
 func (b *Builder) GRPCServe(ctx context.Context, param *proto.Arguments) error {
-	StartSessionGC(ctx, b.config, b.Budgerigar(), b.HistoryStore(), b.ender)
+	StartSessionGC(ctx, b.config, b.Budgerigar(ctx), b.HistoryStore(), b.TemplateEngine(ctx).Seeds(), b.ender)
 
 	grpcTLS := b.grpcTLSConfig()
 	grpcTLS.ClientAuth = b.config.GRPCTLS.ClientAuth
@@ -54,7 +55,7 @@ func (b *Builder) GRPCServe(ctx context.Context, param *proto.Arguments) error {
 		b.config.GRPCNetwork,
 		b.config.GRPC.Addr,
 		param,
-		b.Budgerigar(),
+		b.Budgerigar(ctx),
 		b.Extender(ctx),
 		recorder,
 		b.DescriptorRegistry(),
@@ -67,12 +68,25 @@ func (b *Builder) GRPCServe(ctx context.Context, param *proto.Arguments) error {
 		b.serverLimits(),
 		b.TemplateEngine(ctx),
 	)
+	grpcServer.SetHooks(b.Hooks(ctx))
+	grpcServer.SetScripts(b.Scripts())
+	grpcServer.SetFaults(b.Faults())
+	grpcServer.SetRequestValidator(b.RequestValidator(ctx))
+	grpcServer.SetAutoMock(b.AutoMock())
+	grpcServer.SetDrift(b.Drift())
+	grpcServer.SetProxyRules(b.config.ProxyRules)
 
 	server, err := grpcServer.Build(ctx)
 	if err != nil {
 		return errors.Wrap(err, "failed to build gRPC server")
 	}
 
+	// Stubs load before the descriptors are registered, so file stubs are
+	// checked only now.
+	b.Extender(ctx).EnableChecks(ctx, b.StubCheck(ctx))
+
+	WatchProtos(ctx, watcher.NewProtoWatcher(b.config), grpcServer)
+
 	// Share proxy routes with the gateway.
 	// The gateway reads the atomic pointer directly, so it picks up
 	// the routes as soon as they are stored here.
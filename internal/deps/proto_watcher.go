@@ -0,0 +1,81 @@
+package deps
+
+import (
+	"context"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/gripmock/v3/internal/app"
+	"github.com/bavix/gripmock/v3/internal/infra/stubcheck"
+	"github.com/bavix/gripmock/v3/internal/infra/watcher"
+)
+
+// protoReloader is the part of the gRPC server a proto watcher drives.
+type protoReloader interface {
+	SourcePaths() []string
+	ReloadSources(ctx context.Context) (app.SourceReload, error)
+}
+
+// WatchProtos reloads the proto sources of server whenever they change on
+// disk, when PROTO_WATCHER_ENABLED is set.
+func WatchProtos(ctx context.Context, w *watcher.ProtoWatcher, server protoReloader) {
+	ch, err := w.Watch(ctx, server.SourcePaths())
+	if err != nil {
+		zerolog.Ctx(ctx).Warn().Err(err).Msg("Failed to watch proto sources")
+
+		return
+	}
+
+	go func() {
+		for path := range ch {
+			// Saving a change often touches several files: reload once for
+			// every event already queued.
+			if !drain(ch) {
+				return
+			}
+
+			reloadProtos(ctx, server, path)
+		}
+	}()
+}
+
+// drain discards the values ready on ch. It reports false once ch is closed.
+func drain(ch <-chan string) bool {
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return false
+			}
+		default:
+			return true
+		}
+	}
+}
+
+func reloadProtos(ctx context.Context, server protoReloader, path string) {
+	logger := zerolog.Ctx(ctx)
+
+	result, err := server.ReloadSources(ctx)
+	if err != nil {
+		logger.Error().Err(err).Str("path", path).Msg("Proto reload failed; the previous descriptors stay in use")
+
+		return
+	}
+
+	logger.Info().
+		Str("path", path).
+		Strs("services", result.Services).
+		Int("stale_stubs", len(result.Stubs)).
+		Msg("Reloaded proto sources")
+
+	for _, stub := range result.Stubs {
+		err := &stubcheck.Error{Service: stub.Service, Method: stub.Method, Issues: stub.Issues}
+
+		logger.Warn().
+			Str("service", stub.Service).
+			Str("method", stub.Method).
+			Str("id", stub.ID.String()).
+			Msg(err.Error())
+	}
+}
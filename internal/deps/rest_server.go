@@ -63,7 +63,7 @@ func (b *Builder) RestServe(
 	ctx context.Context,
 	stubPath string,
 ) (*RestServer, error) {
-	StartSessionGC(ctx, b.config, b.Budgerigar(), b.HistoryStore(), b.ender)
+	StartSessionGC(ctx, b.config, b.Budgerigar(ctx), b.HistoryStore(), b.TemplateEngine(ctx).Seeds(), b.ender)
 
 	extender := b.Extender(ctx)
 
@@ -88,7 +88,7 @@ func (b *Builder) RestServe(
 
 	apiServer, err := app.NewRestServer(
 		ctx,
-		b.Budgerigar(),
+		b.Budgerigar(ctx),
 		extender,
 		historyReader,
 		b.StubValidator(),
@@ -100,6 +100,12 @@ func (b *Builder) RestServe(
 		return nil, errors.Wrapf(err, "failed to create rest server")
 	}
 
+	apiServer.SetHooks(b.Hooks(ctx))
+	apiServer.SetScripts(b.Scripts())
+	apiServer.SetFaults(b.Faults())
+	apiServer.SetStubCheck(b.StubCheck(ctx))
+	apiServer.SetProxyRoutes(b.ProxyRoutesRef())
+	apiServer.SetDrift(b.Drift())
 	apiServer.SetPorts(app.ServerPorts{
 		GRPC:    b.config.GRPC.Addr,
 		Gateway: b.config.Gateway.Addr,
@@ -107,6 +113,15 @@ func (b *Builder) RestServe(
 	})
 	zerolog.Ctx(ctx).Info().Msg("startup: API server created")
 
+	if b.config.SnapshotFile != "" {
+		info, err := apiServer.RestoreSnapshotFile(b.config.SnapshotFile)
+		if err != nil {
+			return nil, err
+		}
+
+		zerolog.Ctx(ctx).Info().Str("id", info.Id).Int("stubs", info.Stubs).Msg("startup: snapshot restored")
+	}
+
 	// Phase 3: load UI assets
 	zerolog.Ctx(ctx).Info().Msg("startup: loading UI assets")
 
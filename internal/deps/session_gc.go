@@ -8,12 +8,20 @@ import (
 
 	"github.com/bavix/gripmock/v3/internal/config"
 	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/faker"
 	"github.com/bavix/gripmock/v3/internal/infra/lifecycle"
 	"github.com/bavix/gripmock/v3/internal/infra/session"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 )
 
-func StartSessionGC(ctx context.Context, cfg config.Config, bg *stuber.Budgerigar, hs *history.MemoryStore, ender *lifecycle.Manager) {
+func StartSessionGC(
+	ctx context.Context,
+	cfg config.Config,
+	bg *stuber.Budgerigar,
+	hs history.SessionCleaner,
+	seeds *faker.Seeds,
+	ender *lifecycle.Manager,
+) {
 	interval := cfg.SessionGCInterval
 	ttl := cfg.SessionGCTTL
 
@@ -35,13 +43,20 @@ func StartSessionGC(ctx context.Context, cfg config.Config, bg *stuber.Budgeriga
 			case <-ctx.Done():
 				return
 			case now := <-ticker.C:
-				cleanupExpiredSessions(ctx, now, ttl, bg, hs)
+				cleanupExpiredSessions(ctx, now, ttl, bg, hs, seeds)
 			}
 		}
 	}()
 }
 
-func cleanupExpiredSessions(ctx context.Context, now time.Time, ttl time.Duration, bg *stuber.Budgerigar, hs *history.MemoryStore) {
+func cleanupExpiredSessions(
+	ctx context.Context,
+	now time.Time,
+	ttl time.Duration,
+	bg *stuber.Budgerigar,
+	hs history.SessionCleaner,
+	seeds *faker.Seeds,
+) {
 	expired := session.Expired(now, ttl)
 	if len(expired) == 0 {
 		return
@@ -57,6 +72,7 @@ func cleanupExpiredSessions(ctx context.Context, now time.Time, ttl time.Duratio
 		}
 
 		deletedStubs := bg.DeleteSession(sessionID)
+		seeds.Sessions().Forget(sessionID)
 		deletedHistory := 0
 
 		if hs != nil {
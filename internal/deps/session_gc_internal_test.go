@@ -13,8 +13,10 @@ import (
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 )
 
-func putStub(b *Builder, sessionID, message string) {
-	b.Budgerigar().PutMany(&stuber.Stub{
+func putStub(t *testing.T, b *Builder, sessionID, message string) {
+	t.Helper()
+
+	b.Budgerigar(t.Context()).PutMany(&stuber.Stub{
 		ID:      uuid.New(),
 		Service: "svc.Greeter",
 		Method:  "SayHello",
@@ -34,16 +36,17 @@ func putHistory(b *Builder, sessionID string) {
 //nolint:paralleltest
 func TestBuilderCleanupExpiredSessionsRemovesTouchedSessionData(t *testing.T) {
 	b := NewBuilder(WithConfig(config.Config{HistoryEnabled: true}))
-	putStub(b, "A", "A")
-	putStub(b, "B", "B")
+	putStub(t, b, "A", "A")
+	putStub(t, b, "B", "B")
 	putHistory(b, "A")
 	putHistory(b, "B")
 
 	session.Touch("A")
 
-	cleanupExpiredSessions(t.Context(), time.Now(), 0, b.Budgerigar(), b.HistoryStore())
+	cleanupExpiredSessions(t.Context(), time.Now(), 0, b.Budgerigar(t.Context()), b.HistoryStore(),
+		b.TemplateEngine(t.Context()).Seeds())
 
-	all := b.Budgerigar().All()
+	all := b.Budgerigar(t.Context()).All()
 	require.Len(t, all, 1)
 	require.Equal(t, "B", all[0].Session)
 
@@ -55,16 +58,17 @@ func TestBuilderCleanupExpiredSessionsRemovesTouchedSessionData(t *testing.T) {
 //nolint:paralleltest
 func TestBuilderCleanupExpiredSessionsDoesNotDeleteGlobalSession(t *testing.T) {
 	b := NewBuilder(WithConfig(config.Config{HistoryEnabled: true}))
-	putStub(b, "", "GLOBAL")
-	putStub(b, "A", "A")
+	putStub(t, b, "", "GLOBAL")
+	putStub(t, b, "A", "A")
 	putHistory(b, "")
 	putHistory(b, "A")
 
 	session.Touch("A")
 
-	cleanupExpiredSessions(t.Context(), time.Now(), 0, b.Budgerigar(), b.HistoryStore())
+	cleanupExpiredSessions(t.Context(), time.Now(), 0, b.Budgerigar(t.Context()), b.HistoryStore(),
+		b.TemplateEngine(t.Context()).Seeds())
 
-	all := b.Budgerigar().All()
+	all := b.Budgerigar(t.Context()).All()
 	require.Len(t, all, 1)
 	require.Empty(t, all[0].Session)
 	require.Equal(t, "GLOBAL", all[0].Output.Data.(map[string]any)["message"]) //nolint:forcetypeassert
@@ -3,6 +3,9 @@ package deps
 import (
 	"context"
 
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/gripmock/v3/internal/config"
 	internalplugins "github.com/bavix/gripmock/v3/internal/infra/plugins"
 	"github.com/bavix/gripmock/v3/internal/infra/storage"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
@@ -11,14 +14,38 @@ import (
 	"github.com/bavix/gripmock/v3/pkg/plugins"
 )
 
-func (b *Builder) Budgerigar() *stuber.Budgerigar {
+func (b *Builder) Budgerigar(ctx context.Context) *stuber.Budgerigar {
 	b.budgerigarOnce.Do(func() {
 		b.budgerigar = stuber.NewBudgerigar()
+
+		if b.config.StubStore == config.StubStoreDir {
+			b.enableStubStore(zerolog.Ctx(ctx))
+		}
 	})
 
 	return b.budgerigar
 }
 
+// enableStubStore restores runtime-created stubs from STUB_STORE_DIR and
+// writes later changes back. Without a usable directory stubs stay in memory.
+func (b *Builder) enableStubStore(logger *zerolog.Logger) {
+	store, err := stuber.OpenDirStore(b.config.StubStoreDir)
+	if err != nil {
+		logger.Err(err).Msg("stub store is disabled, keeping stubs in memory")
+
+		return
+	}
+
+	restored, err := b.budgerigar.EnablePersistence(store, func(err error) {
+		logger.Err(err).Msg("failed to write to the stub store")
+	})
+	if err != nil {
+		logger.Err(err).Msg("some stubs could not be restored from the stub store")
+	}
+
+	logger.Info().Int("stubs", restored).Str("dir", b.config.StubStoreDir).Msg("stub store restored")
+}
+
 func (b *Builder) Extender(ctx context.Context) *storage.Extender {
 	b.extenderOnce.Do(func() {
 		b.LoadPlugins(ctx)
@@ -31,7 +58,7 @@ func (b *Builder) Extender(ctx context.Context) *storage.Extender {
 			internalplugins.RegisterBuiltins(reg)
 		}
 
-		b.extender = storage.NewStub(b.Budgerigar(), yaml2json.New(reg), watcher.NewStubWatcher(b.config))
+		b.extender = storage.NewStub(b.Budgerigar(ctx), yaml2json.New(reg), watcher.NewStubWatcher(b.config))
 	})
 
 	return b.extender
@@ -5,14 +5,16 @@ import (
 
 	"github.com/stretchr/testify/require"
 
+	"github.com/bavix/gripmock/v3/internal/config"
 	"github.com/bavix/gripmock/v3/internal/deps"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 )
 
 func TestBuilderBudgerigar(t *testing.T) {
 	t.Parallel()
 
 	builder := deps.NewBuilder()
-	budgerigar := builder.Budgerigar()
+	budgerigar := builder.Budgerigar(t.Context())
 	require.NotNil(t, budgerigar)
 }
 
@@ -29,11 +31,27 @@ func TestBuilderSingletonPattern(t *testing.T) {
 
 	builder := deps.NewBuilder()
 
-	budgerigar1 := builder.Budgerigar()
-	budgerigar2 := builder.Budgerigar()
+	budgerigar1 := builder.Budgerigar(t.Context())
+	budgerigar2 := builder.Budgerigar(t.Context())
 	require.Equal(t, budgerigar1, budgerigar2)
 
 	extender1 := builder.Extender(t.Context())
 	extender2 := builder.Extender(t.Context())
 	require.Equal(t, extender1, extender2)
 }
+
+func TestBuilderBudgerigarStubStore(t *testing.T) {
+	t.Parallel()
+
+	cfg := config.Config{StubStore: config.StubStoreDir, StubStoreDir: t.TempDir()}
+
+	ids := deps.NewBuilder(deps.WithConfig(cfg)).Budgerigar(t.Context()).PutMany(&stuber.Stub{
+		Service: "svc",
+		Method:  "M",
+		Source:  stuber.SourceRest,
+	})
+
+	restored := deps.NewBuilder(deps.WithConfig(cfg)).Budgerigar(t.Context()).FindByID(ids[0])
+	require.NotNil(t, restored)
+	require.Equal(t, stuber.SourceRest, restored.Source)
+}
@@ -5,19 +5,28 @@ import (
 	"sync"
 
 	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
 )
 
 // Registry holds descriptors added via REST API. Supports add and remove.
-// GlobalFiles (startup descriptors) are separate; list operations merge both.
+// The files the server was built from are kept apart, see Startup; list
+// operations merge both. Files recompiled by a source reload form a layer of
+// their own, see SetReloaded: lookups through RangeFiles see them, while the
+// operations on added files (Paths, ServiceIDs, Replace, Unregister*) do not.
 type Registry struct {
-	mu    sync.RWMutex
-	files map[string]protoreflect.FileDescriptor // path -> file
-	gen   uint64                                 // bumped on every mutation; lets consumers cache derived views
+	mu       sync.RWMutex
+	files    map[string]protoreflect.FileDescriptor // path -> file
+	reloaded map[string]protoreflect.FileDescriptor // path -> file
+	startup  *protoregistry.Files
+	gen      uint64 // bumped on every mutation; lets consumers cache derived views
 }
 
 // NewRegistry creates an empty registry.
 func NewRegistry() *Registry {
-	return &Registry{files: make(map[string]protoreflect.FileDescriptor)}
+	return &Registry{
+		files:    make(map[string]protoreflect.FileDescriptor),
+		reloaded: make(map[string]protoreflect.FileDescriptor),
+	}
 }
 
 // Register adds a file descriptor. Replaces if path exists.
@@ -29,6 +38,52 @@ func (r *Registry) Register(fd protoreflect.FileDescriptor) {
 	r.gen++
 }
 
+// Replace drops every registered file and registers files instead. Reloaded
+// files stay.
+func (r *Registry) Replace(files []protoreflect.FileDescriptor) {
+	next := make(map[string]protoreflect.FileDescriptor, len(files))
+	for _, fd := range files {
+		next[fd.Path()] = fd
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.files = next
+	r.gen++
+}
+
+// SetReloaded replaces the files of the last source reload with files.
+func (r *Registry) SetReloaded(files []protoreflect.FileDescriptor) {
+	next := make(map[string]protoreflect.FileDescriptor, len(files))
+	for _, fd := range files {
+		next[fd.Path()] = fd
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.reloaded = next
+	r.gen++
+}
+
+// SetStartup installs the files the server was built from. files is read
+// without locking afterwards, so it must not be modified once installed.
+func (r *Registry) SetStartup(files *protoregistry.Files) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.startup = files
+	r.gen++
+}
+
+// Startup returns a view of the files the server was built from. The view
+// follows SetStartup; a nil registry has no files of its own, leaving only
+// the generated code linked into the binary.
+func (r *Registry) Startup() Startup {
+	return Startup{registry: r}
+}
+
 // UnregisterByPath removes a file by path.
 func (r *Registry) UnregisterByPath(path string) bool {
 	r.mu.Lock()
@@ -77,10 +132,33 @@ func (r *Registry) Generation() uint64 {
 	return r.gen
 }
 
-// RangeFiles calls f for each registered file.
+// RangeFiles calls f for each registered file, then for each reloaded file
+// whose path no registered file takes.
 func (r *Registry) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
 	r.mu.RLock()
 
+	files := make([]protoreflect.FileDescriptor, 0, len(r.files)+len(r.reloaded))
+
+	for _, fd := range r.files {
+		files = append(files, fd)
+	}
+
+	for path, fd := range r.reloaded {
+		if _, ok := r.files[path]; !ok {
+			files = append(files, fd)
+		}
+	}
+
+	r.mu.RUnlock()
+
+	rangeList(files, f)
+}
+
+// RangeRegistered calls f for each registered file, leaving reloaded files
+// out.
+func (r *Registry) RangeRegistered(f func(protoreflect.FileDescriptor) bool) {
+	r.mu.RLock()
+
 	files := make([]protoreflect.FileDescriptor, 0, len(r.files))
 
 	for _, fd := range r.files {
@@ -89,6 +167,10 @@ func (r *Registry) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
 
 	r.mu.RUnlock()
 
+	rangeList(files, f)
+}
+
+func rangeList(files []protoreflect.FileDescriptor, f func(protoreflect.FileDescriptor) bool) {
 	for _, fd := range files {
 		if !f(fd) {
 			return
@@ -2,12 +2,14 @@ package descriptors_test
 
 import (
 	"path/filepath"
+	"slices"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
 
 	"github.com/bavix/gripmock/v3/internal/domain/descriptors"
 	"github.com/bavix/gripmock/v3/internal/domain/protoset"
@@ -120,3 +122,121 @@ func TestRegistryServiceIDs(t *testing.T) {
 	reg.UnregisterByService("helloworld.Greeter")
 	require.Empty(t, reg.ServiceIDs())
 }
+
+func TestRegistryReplace(t *testing.T) {
+	t.Parallel()
+
+	reg := descriptors.NewRegistry()
+	fdA := mustFileDesc(t, filepath.Join("..", "..", "..", "examples", "projects", "calculator", "service.proto"))
+	fdB := mustFileDesc(t, filepath.Join("..", "..", "..", "examples", "projects", "greeter", "service.proto"))
+
+	reg.Register(fdA)
+	gen := reg.Generation()
+
+	reg.Replace([]protoreflect.FileDescriptor{fdB})
+	require.Equal(t, []string{fdB.Path()}, reg.Paths())
+	require.Greater(t, reg.Generation(), gen)
+
+	reg.Replace(nil)
+	require.Empty(t, reg.Paths())
+}
+
+func inlineFileDesc(t *testing.T, name, service string) protoreflect.FileDescriptor { //nolint:ireturn
+	t.Helper()
+
+	fd, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    new(name),
+		Package: new("registry.swap"),
+		Syntax:  new("proto3"),
+		Service: []*descriptorpb.ServiceDescriptorProto{{Name: new(service)}},
+	}, nil)
+	require.NoError(t, err)
+
+	return fd
+}
+
+func rangePaths(reg *descriptors.Registry) []string {
+	var paths []string
+
+	reg.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		paths = append(paths, file.Path())
+
+		return true
+	})
+
+	slices.Sort(paths)
+
+	return paths
+}
+
+func TestRegistryReloadedLayer(t *testing.T) {
+	t.Parallel()
+
+	reg := descriptors.NewRegistry()
+	fdA := inlineFileDesc(t, "a.proto", "A")
+	fdB := inlineFileDesc(t, "b.proto", "B")
+
+	reg.Register(fdA)
+	gen := reg.Generation()
+
+	reg.SetReloaded([]protoreflect.FileDescriptor{fdA, fdB})
+	require.Equal(t, gen+1, reg.Generation(), "a reload is a single mutation")
+	require.Equal(t, []string{"a.proto", "b.proto"}, rangePaths(reg), "lookups see reloaded files")
+	require.Equal(t, []string{"a.proto"}, reg.Paths(), "reloaded files are not registrations")
+	require.Equal(t, []string{"registry.swap.A"}, reg.ServiceIDs())
+	require.Zero(t, reg.UnregisterByService("registry.swap.B"))
+
+	var registered []string
+
+	reg.RangeRegistered(func(file protoreflect.FileDescriptor) bool {
+		registered = append(registered, file.Path())
+
+		return true
+	})
+	require.Equal(t, []string{"a.proto"}, registered)
+
+	reg.Replace(nil)
+	require.Equal(t, []string{"a.proto", "b.proto"}, rangePaths(reg), "replacing registrations keeps the reload")
+
+	reg.SetReloaded(nil)
+	require.Empty(t, rangePaths(reg))
+}
+
+func TestRegistryStartup(t *testing.T) {
+	t.Parallel()
+
+	reg := descriptors.NewRegistry()
+
+	_, err := reg.Startup().FindFileByPath("google/protobuf/descriptor.proto")
+	require.NoError(t, err, "generated code linked into the binary resolves without startup files")
+
+	_, err = reg.Startup().FindDescriptorByName("registry.swap.Alpha")
+	require.Error(t, err)
+
+	files := new(protoregistry.Files)
+	require.NoError(t, files.RegisterFile(inlineFileDesc(t, "startup_alpha.proto", "Alpha")))
+
+	startup := reg.Startup()
+	gen := reg.Generation()
+
+	reg.SetStartup(files)
+	require.Greater(t, reg.Generation(), gen)
+
+	_, err = startup.FindDescriptorByName("registry.swap.Alpha")
+	require.NoError(t, err, "a view taken before SetStartup follows it")
+	require.Empty(t, reg.Paths(), "startup files are not runtime registrations")
+
+	var paths []string
+
+	startup.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		paths = append(paths, file.Path())
+
+		return true
+	})
+
+	require.Equal(t, "startup_alpha.proto", paths[0], "the server's files come before GlobalFiles")
+	require.Contains(t, paths, "google/protobuf/descriptor.proto")
+
+	_, err = protoregistry.GlobalFiles.FindFileByPath("startup_alpha.proto")
+	require.ErrorIs(t, err, protoregistry.NotFound)
+}
@@ -0,0 +1,97 @@
+package descriptors
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// Startup resolves descriptors from the files a server was built from, then
+// from protoregistry.GlobalFiles, where generated code linked into the binary
+// registers itself (grpc.health.v1, the well-known types). Every server owns
+// its startup files, so two servers in one process may carry different
+// versions of the same file or package.
+type Startup struct {
+	registry *Registry
+}
+
+// Files returns the files the server was built from, nil if there are none.
+func (s Startup) Files() *protoregistry.Files {
+	if s.registry == nil {
+		return nil
+	}
+
+	s.registry.mu.RLock()
+	defer s.registry.mu.RUnlock()
+
+	return s.registry.startup
+}
+
+func (s Startup) FindFileByPath(path string) (protoreflect.FileDescriptor, error) { //nolint:ireturn
+	if file, err := s.Files().FindFileByPath(path); err == nil {
+		return file, nil
+	}
+
+	return protoregistry.GlobalFiles.FindFileByPath(path) //nolint:wrapcheck
+}
+
+func (s Startup) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) { //nolint:ireturn
+	if desc, err := s.Files().FindDescriptorByName(name); err == nil {
+		return desc, nil
+	}
+
+	return protoregistry.GlobalFiles.FindDescriptorByName(name) //nolint:wrapcheck
+}
+
+// NumFiles counts the files RangeFiles visits at most.
+func (s Startup) NumFiles() int {
+	return s.Files().NumFiles() + protoregistry.GlobalFiles.NumFiles()
+}
+
+// RangeFiles calls f for the server's files, then for the GlobalFiles ones
+// whose path the server does not define.
+func (s Startup) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	files := s.Files()
+	stopped := false
+
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		stopped = !f(file)
+
+		return !stopped
+	})
+
+	if stopped {
+		return
+	}
+
+	protoregistry.GlobalFiles.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		if _, err := files.FindFileByPath(file.Path()); err == nil {
+			return true
+		}
+
+		return f(file)
+	})
+}
+
+// RangeFilesByPackage is RangeFiles restricted to the files of package name.
+func (s Startup) RangeFilesByPackage(name protoreflect.FullName, f func(protoreflect.FileDescriptor) bool) {
+	files := s.Files()
+	stopped := false
+
+	files.RangeFilesByPackage(name, func(file protoreflect.FileDescriptor) bool {
+		stopped = !f(file)
+
+		return !stopped
+	})
+
+	if stopped {
+		return
+	}
+
+	protoregistry.GlobalFiles.RangeFilesByPackage(name, func(file protoreflect.FileDescriptor) bool {
+		if _, err := files.FindFileByPath(file.Path()); err == nil {
+			return true
+		}
+
+		return f(file)
+	})
+}
@@ -0,0 +1,515 @@
+package history
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/goccy/go-json"
+)
+
+const (
+	segmentPrefix = "history-"
+	segmentSuffix = ".jsonl"
+
+	segmentDirPerm  = 0o750
+	segmentFilePerm = 0o640
+)
+
+// ErrHistoryDirRequired is returned when a FileStore is opened without a directory.
+var ErrHistoryDirRequired = errors.New("history directory is required")
+
+// FileStore implements both Recorder and Reader on disk. Calls are appended as
+// JSON lines to numbered segment files in one directory; a segment is closed
+// once it reaches segmentBytes and the oldest segments are deleted while the
+// directory holds more than limitBytes. Only per-segment sizes and counts live
+// in memory: every read streams the segments, so history survives restarts and
+// is bounded by disk rather than by the heap.
+type FileStore struct {
+	recordPolicy
+
+	mu           sync.RWMutex
+	dir          string
+	segmentBytes int64
+	limitBytes   int64
+	segments     []segment
+	totalBytes   int64
+	active       *os.File
+	nextSeq      uint64
+}
+
+type segment struct {
+	seq   uint64
+	size  int64
+	count int
+}
+
+// OpenFileStore opens the history kept in dir, creating the directory when it
+// does not exist. Records written by a previous run are kept; a line cut short
+// by a crash is dropped. segmentBytes <= 0 disables rotation and limitBytes <= 0
+// disables eviction.
+func OpenFileStore(dir string, segmentBytes, limitBytes int64, opts ...StoreOption) (*FileStore, error) {
+	if dir == "" {
+		return nil, ErrHistoryDirRequired
+	}
+
+	if err := os.MkdirAll(dir, segmentDirPerm); err != nil {
+		return nil, errors.Wrap(err, "failed to create history directory")
+	}
+
+	s := &FileStore{dir: dir, segmentBytes: segmentBytes, limitBytes: limitBytes}
+
+	for _, opt := range opts {
+		opt(&s.recordPolicy)
+	}
+
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+
+	if err := s.openActive(); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+func (s *FileStore) load() error {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return errors.Wrap(err, "failed to read history directory")
+	}
+
+	for _, entry := range entries {
+		seq, ok := parseSegmentName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		seg, err := s.recoverSegment(seq)
+		if err != nil {
+			return err
+		}
+
+		s.segments = append(s.segments, seg)
+		s.totalBytes += seg.size
+	}
+
+	slices.SortFunc(s.segments, func(a, b segment) int {
+		return cmp.Compare(a.seq, b.seq)
+	})
+
+	if n := len(s.segments); n > 0 {
+		s.nextSeq = s.segments[n-1].seq + 1
+	}
+
+	return nil
+}
+
+// recoverSegment counts the complete lines of a segment and cuts off a trailing
+// partial line left behind by an interrupted write.
+func (s *FileStore) recoverSegment(seq uint64) (segment, error) {
+	path := s.segmentPath(seq)
+
+	f, err := os.Open(path) //nolint:gosec // path is built from the configured directory.
+	if err != nil {
+		return segment{}, errors.Wrap(err, "failed to open history segment")
+	}
+	defer f.Close()
+
+	seg := segment{seq: seq}
+	reader := bufio.NewReader(f)
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return segment{}, errors.Wrap(err, "failed to read history segment")
+		}
+
+		seg.size += int64(len(line))
+		seg.count++
+	}
+
+	if info, err := f.Stat(); err == nil && info.Size() != seg.size {
+		if err := os.Truncate(path, seg.size); err != nil {
+			return segment{}, errors.Wrap(err, "failed to repair history segment")
+		}
+	}
+
+	return seg, nil
+}
+
+// openActive opens the newest segment for appending, or starts a new one when
+// there is none or it is already full. Caller holds s.mu for writing or owns s.
+func (s *FileStore) openActive() error {
+	if n := len(s.segments); n > 0 && !s.full(s.segments[n-1]) {
+		f, err := os.OpenFile(s.segmentPath(s.segments[n-1].seq), os.O_WRONLY|os.O_APPEND, segmentFilePerm)
+		if err != nil {
+			return errors.Wrap(err, "failed to open history segment")
+		}
+
+		s.active = f
+
+		return nil
+	}
+
+	return s.rotate()
+}
+
+// rotate closes the active segment and starts the next one. Caller holds s.mu for writing.
+func (s *FileStore) rotate() error {
+	if s.active != nil {
+		_ = s.active.Close()
+		s.active = nil
+	}
+
+	seq := s.nextSeq
+
+	f, err := os.OpenFile(s.segmentPath(seq), os.O_WRONLY|os.O_APPEND|os.O_CREATE|os.O_EXCL, segmentFilePerm)
+	if err != nil {
+		return errors.Wrap(err, "failed to create history segment")
+	}
+
+	s.active = f
+	s.nextSeq++
+	s.segments = append(s.segments, segment{seq: seq})
+
+	return nil
+}
+
+func (s *FileStore) full(seg segment) bool {
+	return s.segmentBytes > 0 && seg.size >= s.segmentBytes
+}
+
+func (s *FileStore) segmentPath(seq uint64) string {
+	return filepath.Join(s.dir, fmt.Sprintf("%s%010d%s", segmentPrefix, seq, segmentSuffix))
+}
+
+func parseSegmentName(name string) (uint64, bool) {
+	digits, ok := strings.CutPrefix(name, segmentPrefix)
+	if !ok {
+		return 0, false
+	}
+
+	digits, ok = strings.CutSuffix(digits, segmentSuffix)
+	if !ok {
+		return 0, false
+	}
+
+	seq, err := strconv.ParseUint(digits, 10, 64)
+
+	return seq, err == nil
+}
+
+// Record implements Recorder. The record is encoded before Record returns, so
+// the caller may keep mutating its message maps. A record that cannot be
+// written is dropped: Recorder has no way to report the failure.
+func (s *FileStore) Record(call CallRecord) {
+	line, err := json.Marshal(s.apply(call))
+	if err != nil {
+		return
+	}
+
+	line = append(line, '\n')
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_ = s.append(line)
+}
+
+// append writes one line, rotating before a segment would grow past
+// segmentBytes and evicting whole segments past limitBytes. Caller holds s.mu for writing.
+func (s *FileStore) append(line []byte) error {
+	if s.active == nil {
+		if err := s.openActive(); err != nil {
+			return err
+		}
+	}
+
+	last := &s.segments[len(s.segments)-1]
+	if last.size > 0 && s.segmentBytes > 0 && last.size+int64(len(line)) > s.segmentBytes {
+		if err := s.rotate(); err != nil {
+			return err
+		}
+
+		last = &s.segments[len(s.segments)-1]
+	}
+
+	if _, err := s.active.Write(line); err != nil {
+		return errors.Wrap(err, "failed to write history record")
+	}
+
+	last.size += int64(len(line))
+	last.count++
+	s.totalBytes += int64(len(line))
+
+	for s.limitBytes > 0 && s.totalBytes > s.limitBytes && len(s.segments) > 1 {
+		oldest := s.segments[0]
+		if err := os.Remove(s.segmentPath(oldest.seq)); err != nil && !os.IsNotExist(err) {
+			return errors.Wrap(err, "failed to evict history segment")
+		}
+
+		s.totalBytes -= oldest.size
+		s.segments = s.segments[1:]
+	}
+
+	return nil
+}
+
+// All implements Reader.
+func (s *FileStore) All() []CallRecord {
+	return s.Filter(FilterOpts{})
+}
+
+// Count implements Reader. It is answered from segment metadata without reading the files.
+func (s *FileStore) Count() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	total := 0
+	for _, seg := range s.segments {
+		total += seg.count
+	}
+
+	return total
+}
+
+// Filter implements Reader.
+func (s *FileStore) Filter(opts FilterOpts) []CallRecord {
+	return slices.Collect(s.FilterSeq(opts))
+}
+
+// FilterByMethod implements Reader.
+func (s *FileStore) FilterByMethod(service, method string) []CallRecord {
+	return s.Filter(FilterOpts{Service: service, Method: method})
+}
+
+// FilterSeq streams matching records oldest first, decoding one line at a time.
+// Lines that no longer decode are skipped. The segments are listed under the
+// lock and read without it, so recording goes on during a scan and yield may
+// record itself; records appended after the scan started are not visited, and
+// segments evicted or cleared meanwhile are skipped.
+func (s *FileStore) FilterSeq(opts FilterOpts) iter.Seq[CallRecord] {
+	return func(yield func(CallRecord) bool) {
+		s.mu.RLock()
+		segments := slices.Clone(s.segments)
+		s.mu.RUnlock()
+
+		for _, seg := range segments {
+			if !s.scanSegment(seg, opts, yield) {
+				return
+			}
+		}
+	}
+}
+
+func (s *FileStore) scanSegment(seg segment, opts FilterOpts, yield func(CallRecord) bool) bool {
+	f, err := os.Open(s.segmentPath(seg.seq))
+	if err != nil {
+		return true
+	}
+	defer f.Close()
+
+	reader := bufio.NewReader(io.LimitReader(f, seg.size))
+
+	for {
+		line, err := reader.ReadBytes('\n')
+		if err != nil {
+			// The limit may cut a line rewritten by DeleteSession since the
+			// listing; only complete lines are decoded.
+			return true
+		}
+
+		var c CallRecord
+		if json.Unmarshal(line, &c) == nil && opts.matches(c) && !yield(c) {
+			return false
+		}
+	}
+}
+
+// CountFilter counts matching records without holding them.
+func (s *FileStore) CountFilter(opts FilterOpts) int {
+	total := 0
+
+	for range s.FilterSeq(opts) {
+		total++
+	}
+
+	return total
+}
+
+// FilterWindow returns the newest limit records after skipping offset of them,
+// together with the total number of matches. Only the window is held in memory.
+func (s *FileStore) FilterWindow(opts FilterOpts, limit, offset int) ([]CallRecord, int) {
+	return filterWindow(s.FilterSeq(opts), limit, offset)
+}
+
+// DeleteSession removes records that belong strictly to the provided session.
+// Each affected segment is rewritten line by line into a temporary file that
+// then replaces it; segments left empty are removed.
+func (s *FileStore) DeleteSession(session string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if session == "" || len(s.segments) == 0 {
+		return 0
+	}
+
+	deleted := 0
+	kept := s.segments[:0]
+	activeSeq := s.segments[len(s.segments)-1].seq
+
+	for _, seg := range s.segments {
+		rewritten, n, err := s.dropSession(seg, session)
+		if err != nil {
+			kept = append(kept, seg)
+
+			continue
+		}
+
+		deleted += n
+		s.totalBytes -= seg.size - rewritten.size
+
+		if rewritten.count == 0 && seg.seq != activeSeq {
+			_ = os.Remove(s.segmentPath(seg.seq))
+
+			continue
+		}
+
+		kept = append(kept, rewritten)
+	}
+
+	s.segments = kept
+
+	// The active file was replaced by rename; reopen the new one for appending.
+	if s.active != nil {
+		_ = s.active.Close()
+		s.active = nil
+	}
+
+	_ = s.openActive()
+
+	return deleted
+}
+
+// sessionProbe decodes only the field DeleteSession needs.
+type sessionProbe struct {
+	Session string `json:"session"`
+}
+
+func (s *FileStore) dropSession(seg segment, session string) (segment, int, error) {
+	path := s.segmentPath(seg.seq)
+
+	src, err := os.Open(path)
+	if err != nil {
+		return seg, 0, errors.Wrap(err, "failed to open history segment")
+	}
+	defer src.Close()
+
+	tmpPath := path + ".tmp"
+
+	dst, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, segmentFilePerm)
+	if err != nil {
+		return seg, 0, errors.Wrap(err, "failed to create history segment")
+	}
+
+	out := segment{seq: seg.seq}
+	deleted := 0
+	reader := bufio.NewReader(src)
+	writer := bufio.NewWriter(dst)
+
+	for {
+		line, readErr := reader.ReadBytes('\n')
+		if len(line) > 0 {
+			var probe sessionProbe
+			if json.Unmarshal(bytes.TrimSpace(line), &probe) == nil && probe.Session == session {
+				deleted++
+			} else {
+				_, _ = writer.Write(line)
+				out.size += int64(len(line))
+				out.count++
+			}
+		}
+
+		if readErr != nil {
+			break
+		}
+	}
+
+	if err := writer.Flush(); err != nil {
+		_ = dst.Close()
+		_ = os.Remove(tmpPath)
+
+		return seg, 0, errors.Wrap(err, "failed to write history segment")
+	}
+
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(tmpPath)
+
+		return seg, 0, errors.Wrap(err, "failed to write history segment")
+	}
+
+	if deleted == 0 {
+		_ = os.Remove(tmpPath)
+
+		return seg, 0, nil
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+
+		return seg, 0, errors.Wrap(err, "failed to replace history segment")
+	}
+
+	return out, deleted, nil
+}
+
+// Clear removes every segment and starts a new one.
+func (s *FileStore) Clear() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active != nil {
+		_ = s.active.Close()
+		s.active = nil
+	}
+
+	for _, seg := range s.segments {
+		_ = os.Remove(s.segmentPath(seg.seq))
+	}
+
+	s.segments = nil
+	s.totalBytes = 0
+
+	_ = s.rotate()
+}
+
+// Close releases the active segment. Records written afterwards reopen it.
+func (s *FileStore) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.active == nil {
+		return nil
+	}
+
+	err := s.active.Close()
+	s.active = nil
+
+	return errors.Wrap(err, "failed to close history segment")
+}
@@ -0,0 +1,224 @@
+package history_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+)
+
+func openFileStore(t *testing.T, dir string, segmentBytes, limitBytes int64, opts ...history.StoreOption) *history.FileStore {
+	t.Helper()
+
+	store, err := history.OpenFileStore(dir, segmentBytes, limitBytes, opts...)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	return store
+}
+
+func segmentFiles(t *testing.T, dir string) []string {
+	t.Helper()
+
+	files, err := filepath.Glob(filepath.Join(dir, "history-*.jsonl"))
+	require.NoError(t, err)
+
+	return files
+}
+
+func TestFileStoreRequiresDir(t *testing.T) {
+	t.Parallel()
+
+	_, err := history.OpenFileStore("", 0, 0)
+	require.ErrorIs(t, err, history.ErrHistoryDirRequired)
+}
+
+func TestFileStoreSurvivesReopen(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	store := openFileStore(t, dir, 0, 0)
+	store.Record(history.CallRecord{Service: "svc", Method: "A", Requests: []map[string]any{{"id": "1"}}})
+	store.Record(history.CallRecord{Service: "svc", Method: "B", Session: "s1"})
+	require.NoError(t, store.Close())
+
+	reopened := openFileStore(t, dir, 0, 0)
+	require.Equal(t, 2, reopened.Count())
+
+	all := reopened.All()
+	require.Len(t, all, 2)
+	require.Equal(t, "A", all[0].Method)
+	require.Equal(t, "1", all[0].Requests[0]["id"])
+	require.Equal(t, "s1", all[1].Session)
+
+	reopened.Record(history.CallRecord{Service: "svc", Method: "C"})
+	require.Equal(t, "C", reopened.All()[2].Method)
+}
+
+func TestFileStoreDropsPartialLine(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	store := openFileStore(t, dir, 0, 0)
+	store.Record(history.CallRecord{Service: "svc", Method: "A"})
+	require.NoError(t, store.Close())
+
+	files := segmentFiles(t, dir)
+	require.Len(t, files, 1)
+
+	f, err := os.OpenFile(files[0], os.O_WRONLY|os.O_APPEND, 0o600)
+	require.NoError(t, err)
+	_, err = f.WriteString(`{"service":"svc","meth`)
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+
+	reopened := openFileStore(t, dir, 0, 0)
+	reopened.Record(history.CallRecord{Service: "svc", Method: "B"})
+
+	all := reopened.All()
+	require.Len(t, all, 2)
+	require.Equal(t, "B", all[1].Method)
+}
+
+func TestFileStoreRotatesAndEvictsSegments(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := openFileStore(t, dir, 256, 1024)
+
+	for i := range 50 {
+		store.Record(history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"i": i}}})
+	}
+
+	files := segmentFiles(t, dir)
+	require.Greater(t, len(files), 1, "segments must rotate")
+
+	var total int64
+
+	for _, file := range files {
+		info, err := os.Stat(file)
+		require.NoError(t, err)
+		require.LessOrEqual(t, info.Size(), int64(256))
+
+		total += info.Size()
+	}
+
+	require.LessOrEqual(t, total, int64(1024))
+
+	all := store.All()
+	require.Less(t, len(all), 50, "oldest segments must be evicted")
+	require.Len(t, all, store.Count())
+	require.InDelta(t, 49, all[len(all)-1].Requests[0]["i"], 0)
+}
+
+func TestFileStoreScanDoesNotHoldTheLock(t *testing.T) {
+	t.Parallel()
+
+	store := openFileStore(t, t.TempDir(), 128, 512)
+
+	for i := range 5 {
+		store.Record(history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"i": i}}})
+	}
+
+	done := make(chan int)
+
+	go func() {
+		seen := 0
+
+		for range store.FilterSeq(history.FilterOpts{}) {
+			// Recording from inside the loop rotates and evicts the
+			// segments being scanned.
+			for range 10 {
+				store.Record(history.CallRecord{Service: "svc", Method: "N"})
+			}
+
+			seen++
+		}
+
+		done <- seen
+	}()
+
+	select {
+	case seen := <-done:
+		require.Positive(t, seen)
+		require.LessOrEqual(t, seen, 5, "records appended during the scan are not visited")
+	case <-time.After(5 * time.Second):
+		t.Fatal("recording blocked on a running scan")
+	}
+}
+
+func TestFileStoreFilterWindowAndCount(t *testing.T) {
+	t.Parallel()
+
+	store := openFileStore(t, t.TempDir(), 128, 0)
+
+	for i := range 10 {
+		call := history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"i": i}}}
+		if i%2 == 1 {
+			call.Code = 5
+		}
+
+		store.Record(call)
+	}
+
+	require.Equal(t, 5, store.CountFilter(history.FilterOpts{ErrorOnly: true}))
+
+	page, total := store.FilterWindow(history.FilterOpts{}, 3, 2)
+	require.Equal(t, 10, total)
+	require.Len(t, page, 3)
+	require.InDelta(t, 5, page[0].Requests[0]["i"], 0)
+	require.InDelta(t, 7, page[2].Requests[0]["i"], 0)
+}
+
+func TestFileStoreDeleteSession(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := openFileStore(t, dir, 128, 0)
+
+	for _, session := range []string{"s1", "s2", "", "s1", "s1", "s2"} {
+		store.Record(history.CallRecord{Service: "svc", Method: "M", Session: session})
+	}
+
+	require.Zero(t, store.DeleteSession(""))
+	require.Equal(t, 3, store.DeleteSession("s1"))
+	require.Zero(t, store.DeleteSession("s1"))
+	require.Equal(t, 3, store.Count())
+	require.Equal(t, 3, store.CountFilter(history.FilterOpts{Session: "s2"}), "global records stay visible")
+
+	store.Record(history.CallRecord{Service: "svc", Method: "N", Session: "s1"})
+	require.NoError(t, store.Close())
+
+	reopened := openFileStore(t, dir, 128, 0)
+	all := reopened.All()
+	require.Len(t, all, 4)
+	require.Equal(t, "N", all[3].Method)
+}
+
+func TestFileStoreRedactsAndClears(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	store := openFileStore(t, dir, 0, 0, history.WithRedactKeys([]string{"password"}))
+
+	req := map[string]any{"user": "a", "password": "sekret"}
+	store.Record(history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{req}})
+
+	raw, err := os.ReadFile(segmentFiles(t, dir)[0])
+	require.NoError(t, err)
+	require.NotContains(t, string(raw), "sekret", "secrets must never reach the disk")
+	require.Equal(t, "[REDACTED]", store.All()[0].Requests[0]["password"])
+
+	store.Clear()
+	require.Zero(t, store.Count())
+	require.Empty(t, store.All())
+
+	store.Record(history.CallRecord{Service: "svc", Method: "M"})
+	require.Equal(t, 1, store.Count())
+}
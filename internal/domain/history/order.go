@@ -0,0 +1,120 @@
+package history
+
+import (
+	"fmt"
+	"slices"
+)
+
+// OrderResult is the outcome of VerifyOrder.
+type OrderResult struct {
+	// Failed is the index of the first step that was not satisfied, -1 when
+	// the whole sequence was.
+	Failed int
+	// Counts holds the matching calls attributed to each step that was reached.
+	Counts []int
+}
+
+// OK reports whether every step was satisfied.
+func (r OrderResult) OK() bool {
+	return r.Failed < 0
+}
+
+// Message explains the failed step for humans; empty when the sequence held.
+func (r OrderResult) Message(steps []Expectation) string {
+	if r.OK() {
+		return ""
+	}
+
+	step := steps[r.Failed]
+	actual := r.Counts[r.Failed]
+
+	where := "from the start"
+	if r.Failed > 0 {
+		prev := steps[r.Failed-1]
+		where = fmt.Sprintf("after %s/%s", prev.Service, prev.Method)
+	}
+
+	return fmt.Sprintf("step %d: expected %s/%s to be called %s %s, got %d",
+		r.Failed+1, step.Service, step.Method, step.Describe(), where, actual)
+}
+
+// VerifyOrder checks that the calls in scope happened in the order of steps.
+// Calls are ordered by their start time; calls that do not fit the sequence
+// are skipped rather than failing it.
+//
+// Each step takes the calls it matches from where the previous step stopped
+// up to the first call the next step matches, and its count bounds apply to
+// that stretch. So [Login, Charge(Times 1), Create] requires a login, then
+// exactly one charge, then an order, and a charge after the order does not
+// count against the middle step. A step bounded by AtMost(0) asserts a call is
+// absent between its neighbours.
+func VerifyOrder(reader Reader, session string, steps []Expectation) OrderResult {
+	calls := reader.Filter(FilterOpts{Session: session})
+	slices.SortStableFunc(calls, func(a, b CallRecord) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return VerifyOrderCalls(calls, steps)
+}
+
+// VerifyOrderCalls is VerifyOrder over calls that are already scoped and
+// ordered, such as history fetched from a remote server.
+func VerifyOrderCalls(calls []CallRecord, steps []Expectation) OrderResult {
+	result := OrderResult{Failed: -1, Counts: make([]int, 0, len(steps))}
+
+	steps = slices.Clone(steps)
+	for i := range steps {
+		steps[i].Headers = lowerHeaderNames(steps[i].Headers)
+	}
+
+	pos := 0
+
+	for i, step := range steps {
+		count := 0
+
+		for ; pos < len(calls); pos++ {
+			call := calls[pos]
+
+			// Hand over once this step has what it needs; a call both steps
+			// match goes to the later one.
+			if count >= step.minimum() && startsLater(steps[i+1:], call) {
+				break
+			}
+
+			if step.matches(call) {
+				count++
+			}
+		}
+
+		result.Counts = append(result.Counts, count)
+
+		if !step.Met(count) {
+			result.Failed = i
+
+			return result
+		}
+	}
+
+	return result
+}
+
+// startsLater reports whether call opens one of the following steps. Steps
+// that may be empty are looked through, so a Never step does not hide the
+// step after it.
+func startsLater(following []Expectation, call CallRecord) bool {
+	for _, step := range following {
+		if step.matches(call) {
+			return true
+		}
+
+		if step.minimum() > 0 {
+			return false
+		}
+	}
+
+	return false
+}
+
+func (e Expectation) matches(call CallRecord) bool {
+	return call.Service == e.Service && call.Method == e.Method && e.explain(call).Len() == 0
+}
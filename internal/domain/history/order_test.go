@@ -0,0 +1,86 @@
+package history_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func orderedCalls(methods ...string) []history.CallRecord {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	calls := make([]history.CallRecord, len(methods))
+
+	for i, method := range methods {
+		calls[i] = history.CallRecord{Service: "svc", Method: method, Timestamp: start.Add(time.Duration(i) * time.Second)}
+	}
+
+	return calls
+}
+
+func step(method string) history.Expectation {
+	return history.Expectation{Service: "svc", Method: method}
+}
+
+func TestVerifyOrderCalls(t *testing.T) {
+	t.Parallel()
+
+	once := step("Charge")
+	once.Times = new(1)
+
+	noLogout := step("Logout")
+	noLogout.AtMost = new(0)
+
+	tests := []struct {
+		name   string
+		calls  []string
+		steps  []history.Expectation
+		failed int
+	}{
+		{name: "in order", calls: []string{"Login", "Charge", "Create"}, steps: []history.Expectation{step("Login"), once, step("Create")}, failed: -1},
+		{name: "noise is skipped", calls: []string{"Ping", "Login", "Ping", "Charge", "Create", "Charge"}, steps: []history.Expectation{step("Login"), once, step("Create")}, failed: -1},
+		{name: "reversed", calls: []string{"Create", "Login"}, steps: []history.Expectation{step("Login"), step("Create")}, failed: 1},
+		{name: "charged twice between", calls: []string{"Login", "Charge", "Charge", "Create"}, steps: []history.Expectation{step("Login"), once, step("Create")}, failed: 1},
+		{name: "missing first", calls: []string{"Charge", "Create"}, steps: []history.Expectation{step("Login"), step("Create")}, failed: 0},
+		{name: "absent between", calls: []string{"Login", "Create"}, steps: []history.Expectation{step("Login"), noLogout, step("Create")}, failed: -1},
+		{name: "present between", calls: []string{"Login", "Logout", "Create"}, steps: []history.Expectation{step("Login"), noLogout, step("Create")}, failed: 1},
+		{name: "repeated method", calls: []string{"Login", "Login"}, steps: []history.Expectation{step("Login"), step("Login")}, failed: -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			result := history.VerifyOrderCalls(orderedCalls(tt.calls...), tt.steps)
+			require.Equal(t, tt.failed, result.Failed)
+			require.Equal(t, tt.failed < 0, result.OK())
+		})
+	}
+}
+
+func TestVerifyOrderUsesTimestampsAndMatchers(t *testing.T) {
+	t.Parallel()
+
+	calls := orderedCalls("Create", "Login")
+	calls[0].Timestamp = calls[1].Timestamp.Add(time.Second)
+	calls[1].Requests = []map[string]any{{"user": "alice"}}
+	calls[1].Session = "s1"
+
+	store := verifyStore(calls...)
+
+	login := step("Login")
+	login.Input = stuber.InputData{Equals: map[string]any{"user": "alice"}}
+
+	result := history.VerifyOrder(store, "s1", []history.Expectation{login, step("Create")})
+	require.True(t, result.OK())
+	require.Equal(t, []int{1, 1}, result.Counts)
+
+	login.Input.Equals["user"] = "bob"
+	steps := []history.Expectation{login, step("Create")}
+	result = history.VerifyOrder(store, "", steps)
+	require.Equal(t, 0, result.Failed)
+	require.Equal(t, "step 1: expected svc/Login to be called at least once from the start, got 0", result.Message(steps))
+}
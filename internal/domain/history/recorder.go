@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"iter"
 	"maps"
+	"net"
 	"slices"
 	"strings"
 	"sync"
@@ -26,8 +27,29 @@ type CallRecord struct {
 	Error           string            `json:"error,omitempty"`
 	ElapsedMS       int64             `json:"elapsedMs,omitempty"`
 	Timestamp       time.Time         `json:"timestamp"`
+
+	// RequestHeaders is the incoming metadata with lowercase keys; repeated
+	// values are joined with ", ".
+	RequestHeaders map[string]string `json:"requestHeaders,omitempty"`
+	// Peer is the client address as host:port.
+	Peer string `json:"peer,omitempty"`
+	// Transport is the protocol that served the call, one of the Transport constants.
+	Transport string `json:"transport,omitempty"`
+	// Deadline is when the client gives up on the call; nil when it set none.
+	Deadline *time.Time `json:"deadline,omitempty"`
+	// Synthesized marks responses that auto-mock made up because no stub matched.
+	Synthesized bool `json:"synthesized,omitempty"`
 }
 
+// Transports a call can arrive over.
+const (
+	TransportGRPC    = "grpc"
+	TransportConnect = "connect"
+	TransportGRPCWeb = "grpc-web"
+	TransportHTTP    = "http"
+	TransportMCP     = "mcp"
+)
+
 // Recorder records gRPC calls for inspection and verification.
 type Recorder interface {
 	Record(call CallRecord)
@@ -40,6 +62,17 @@ type FilterOpts struct {
 	Session string
 
 	ErrorOnly bool
+
+	// Transport keeps calls served over this protocol.
+	Transport string
+	// Peer keeps calls from this client, given as host:port or just host.
+	Peer string
+	// Headers keeps calls whose request carried every listed header. An empty
+	// value only requires the header to be present; otherwise the recorded
+	// value must be equal, so a redacted header can only be matched by presence.
+	Headers map[string]string
+	// DeadlineOnly keeps calls whose client set a deadline.
+	DeadlineOnly bool
 }
 
 // Reader provides read access to recorded calls.
@@ -55,6 +88,23 @@ type SessionCleaner interface {
 	DeleteSession(session string) int
 }
 
+// Store is a complete history backend: the servers record into it, the REST
+// and MCP handlers page through it, and session GC prunes it.
+type Store interface {
+	Recorder
+	Reader
+	SessionCleaner
+
+	CountFilter(opts FilterOpts) int
+	FilterWindow(opts FilterOpts, limit, offset int) ([]CallRecord, int)
+	Clear()
+}
+
+var (
+	_ Store = (*MemoryStore)(nil)
+	_ Store = (*FileStore)(nil)
+)
+
 // MemoryStore implements both Recorder and Reader (in-memory).
 type storedRecord struct {
 	call CallRecord
@@ -62,25 +112,43 @@ type storedRecord struct {
 }
 
 type MemoryStore struct {
-	mu              sync.RWMutex
-	calls           []storedRecord
-	limitBytes      int64
+	recordPolicy
+
+	mu           sync.RWMutex
+	calls        []storedRecord
+	limitBytes   int64
+	currentBytes int64
+}
+
+// recordPolicy is the redaction and truncation every store applies on write.
+type recordPolicy struct {
 	messageMaxBytes int64
 	redactKeys      map[string]struct{}
-	currentBytes    int64
 }
 
-// MemoryStoreOption configures MemoryStore.
-type MemoryStoreOption func(*MemoryStore)
+func (p *recordPolicy) apply(call CallRecord) CallRecord {
+	if len(p.redactKeys) > 0 {
+		call = redactRecord(call, p.redactKeys)
+	}
+
+	if p.messageMaxBytes > 0 {
+		call = truncateRecord(call, p.messageMaxBytes)
+	}
+
+	return call
+}
+
+// StoreOption configures MemoryStore and FileStore.
+type StoreOption func(*recordPolicy)
 
 // WithMessageMaxBytes limits Request/Response size; excess is replaced with truncation marker.
-func WithMessageMaxBytes(n int64) MemoryStoreOption {
-	return func(s *MemoryStore) {
-		s.messageMaxBytes = n
+func WithMessageMaxBytes(n int64) StoreOption {
+	return func(p *recordPolicy) {
+		p.messageMaxBytes = n
 	}
 }
 
-func WithRedactKeys(keys []string) MemoryStoreOption {
+func WithRedactKeys(keys []string) StoreOption {
 	m := make(map[string]struct{}, len(keys))
 	for _, k := range keys {
 		if k != "" {
@@ -88,17 +156,17 @@ func WithRedactKeys(keys []string) MemoryStoreOption {
 		}
 	}
 
-	return func(s *MemoryStore) {
-		s.redactKeys = m
+	return func(p *recordPolicy) {
+		p.redactKeys = m
 	}
 }
 
 // NewMemoryStore creates a store with optional byte limit.
-func NewMemoryStore(limitBytes int64, opts ...MemoryStoreOption) *MemoryStore {
+func NewMemoryStore(limitBytes int64, opts ...StoreOption) *MemoryStore {
 	s := &MemoryStore{limitBytes: limitBytes}
 
 	for _, opt := range opts {
-		opt(s)
+		opt(&s.recordPolicy)
 	}
 
 	return s
@@ -117,13 +185,7 @@ func (s *MemoryStore) Record(call CallRecord) {
 // RecordOwned stores the record without cloning its message maps: the caller
 // hands them over and must not mutate them afterwards.
 func (s *MemoryStore) RecordOwned(call CallRecord) {
-	if len(s.redactKeys) > 0 {
-		call = redactRecord(call, s.redactKeys)
-	}
-
-	if s.messageMaxBytes > 0 {
-		call = truncateRecord(call, s.messageMaxBytes)
-	}
+	call = s.apply(call)
 
 	s.mu.Lock()
 	defer s.mu.Unlock()
@@ -156,6 +218,7 @@ func redactRecord(c CallRecord, keys map[string]struct{}) CallRecord {
 	c.Requests = redactMaps(c.Requests, keys)
 	c.Responses = redactMaps(c.Responses, keys)
 	c.ResponseHeaders = redactStringMap(c.ResponseHeaders, keys)
+	c.RequestHeaders = redactStringMap(c.RequestHeaders, keys)
 
 	return c
 }
@@ -164,6 +227,7 @@ func cloneRecordMessages(c CallRecord) CallRecord {
 	c.Requests = cloneMaps(c.Requests)
 	c.Responses = cloneMaps(c.Responses)
 	c.ResponseHeaders = maps.Clone(c.ResponseHeaders)
+	c.RequestHeaders = maps.Clone(c.RequestHeaders)
 
 	return c
 }
@@ -328,7 +392,7 @@ func estimateRecordSize(c CallRecord) int64 {
 	const recordOverhead = 160
 
 	size := int64(recordOverhead)
-	size += int64(len(c.Service) + len(c.Method) + len(c.Session) + len(c.Error))
+	size += int64(len(c.Service) + len(c.Method) + len(c.Session) + len(c.Error) + len(c.Peer) + len(c.Transport))
 
 	for _, m := range c.Requests {
 		size += estimateMapSize(m)
@@ -342,6 +406,10 @@ func estimateRecordSize(c CallRecord) int64 {
 		size += int64(len(k) + len(v) + kvOverhead)
 	}
 
+	for k, v := range c.RequestHeaders {
+		size += int64(len(k) + len(v) + kvOverhead)
+	}
+
 	return size
 }
 
@@ -413,7 +481,6 @@ func (s *MemoryStore) Filter(opts FilterOpts) []CallRecord {
 	return slices.Collect(s.FilterSeq(opts))
 }
 
-//nolint:cyclop
 func (s *MemoryStore) FilterSeq(opts FilterOpts) iter.Seq[CallRecord] {
 	return func(yield func(CallRecord) bool) {
 		s.mu.RLock()
@@ -422,27 +489,72 @@ func (s *MemoryStore) FilterSeq(opts FilterOpts) iter.Seq[CallRecord] {
 		for i := range s.calls {
 			c := s.calls[i].call
 
-			if opts.Service != "" && c.Service != opts.Service {
+			if !opts.matches(c) {
 				continue
 			}
 
-			if opts.Method != "" && c.Method != opts.Method {
-				continue
+			if !yield(c) {
+				return
 			}
+		}
+	}
+}
 
-			if opts.Session != "" && c.Session != "" && c.Session != opts.Session {
-				continue
-			}
+// matches reports whether the record passes every criterion. A record without
+// a session is global and visible from every session.
+//
+//nolint:cyclop
+func (opts FilterOpts) matches(c CallRecord) bool {
+	if opts.Service != "" && c.Service != opts.Service {
+		return false
+	}
 
-			if opts.ErrorOnly && c.Code == 0 && c.Error == "" {
-				continue
-			}
+	if opts.Method != "" && c.Method != opts.Method {
+		return false
+	}
 
-			if !yield(c) {
-				return
-			}
+	if opts.Session != "" && c.Session != "" && c.Session != opts.Session {
+		return false
+	}
+
+	if opts.ErrorOnly && c.Code == 0 && c.Error == "" {
+		return false
+	}
+
+	if opts.Transport != "" && c.Transport != opts.Transport {
+		return false
+	}
+
+	if opts.Peer != "" && !peerMatches(c.Peer, opts.Peer) {
+		return false
+	}
+
+	if opts.DeadlineOnly && c.Deadline == nil {
+		return false
+	}
+
+	return headersMatch(c.RequestHeaders, opts.Headers)
+}
+
+func peerMatches(peer, want string) bool {
+	if peer == want {
+		return true
+	}
+
+	host, _, err := net.SplitHostPort(peer)
+
+	return err == nil && host == want
+}
+
+func headersMatch(got, want map[string]string) bool {
+	for k, v := range want {
+		value, ok := got[strings.ToLower(k)]
+		if !ok || (v != "" && value != v) {
+			return false
 		}
 	}
+
+	return true
 }
 
 // CountFilter counts matching records without materializing them.
@@ -460,19 +572,23 @@ func (s *MemoryStore) CountFilter(opts FilterOpts) int {
 // together with the total number of matches. Only the window is held: paginating
 // through Filter used to copy the whole history to hand back a page.
 func (s *MemoryStore) FilterWindow(opts FilterOpts, limit, offset int) ([]CallRecord, int) {
+	return filterWindow(s.FilterSeq(opts), limit, offset)
+}
+
+func filterWindow(matches iter.Seq[CallRecord], limit, offset int) ([]CallRecord, int) {
 	offset = max(offset, 0)
 
 	if limit <= 0 {
-		matches := s.Filter(opts)
+		all := slices.Collect(matches)
 
-		return matches[:max(len(matches)-offset, 0)], len(matches)
+		return all[:max(len(all)-offset, 0)], len(all)
 	}
 
 	size := limit + offset
 	ring := make([]CallRecord, size)
 	total := 0
 
-	for record := range s.FilterSeq(opts) {
+	for record := range matches {
 		ring[total%size] = record
 		total++
 	}
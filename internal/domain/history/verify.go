@@ -0,0 +1,238 @@
+package history
+
+import (
+	"fmt"
+	"iter"
+	"slices"
+	"strings"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// closestCallsLimit caps how many non-matching calls a failed verification reports.
+const closestCallsLimit = 3
+
+// Expectation describes calls a test expects to find in history: which method,
+// what the request looked like and how many times. A call matches Input when
+// any of its request messages does, so one expectation covers unary and
+// streaming calls alike. Headers are matched against the recorded request
+// headers with the semantics of a stub's headers.
+type Expectation struct {
+	Service string
+	Method  string
+	Session string
+
+	Input   stuber.InputData
+	Headers stuber.InputHeader
+
+	// Times requires an exact count; AtLeast and AtMost bound it. With no
+	// bound at all the expectation means "at least once".
+	Times   *int
+	AtLeast *int
+	AtMost  *int
+}
+
+// VerifyResult is the outcome of checking one Expectation.
+type VerifyResult struct {
+	Actual int
+	// Closest holds the non-matching calls to the method that came nearest,
+	// fewest mismatches first. It is only filled when too few calls matched.
+	Closest []CallDiff
+}
+
+// CallDiff is a recorded call together with what kept it from matching.
+type CallDiff struct {
+	Call    CallRecord
+	Input   []stuber.Mismatch
+	Headers []stuber.Mismatch
+}
+
+// Len is the total number of failed matcher entries.
+func (d CallDiff) Len() int {
+	return len(d.Input) + len(d.Headers)
+}
+
+// Met reports whether the actual count satisfies every bound of e.
+func (e Expectation) Met(actual int) bool {
+	if e.Times != nil && actual != *e.Times {
+		return false
+	}
+
+	if e.AtMost != nil && actual > *e.AtMost {
+		return false
+	}
+
+	return actual >= e.minimum()
+}
+
+func (e Expectation) minimum() int {
+	switch {
+	case e.Times != nil:
+		return *e.Times
+	case e.AtLeast != nil:
+		return *e.AtLeast
+	case e.AtMost != nil:
+		return 0
+	default:
+		return 1
+	}
+}
+
+// Describe renders the count bounds for messages, e.g. "at least 2 times".
+func (e Expectation) Describe() string {
+	var parts []string
+
+	if e.Times != nil {
+		parts = append(parts, fmt.Sprintf("%d times", *e.Times))
+	}
+
+	if e.AtLeast != nil {
+		parts = append(parts, fmt.Sprintf("at least %d times", *e.AtLeast))
+	}
+
+	if e.AtMost != nil {
+		if *e.AtMost == 0 {
+			parts = append(parts, "never")
+		} else {
+			parts = append(parts, fmt.Sprintf("at most %d times", *e.AtMost))
+		}
+	}
+
+	if len(parts) == 0 {
+		return "at least once"
+	}
+
+	return strings.Join(parts, " and ")
+}
+
+// Verify counts the calls in reader that satisfy e and, when too few did,
+// explains the closest misses.
+func Verify(reader Reader, e Expectation) VerifyResult {
+	return VerifySeq(scopedCalls(reader, FilterOpts{Service: e.Service, Method: e.Method, Session: e.Session}), e)
+}
+
+// VerifySeq is Verify over calls that are already narrowed to e's method and
+// session, such as a page fetched from a remote server. Calls are streamed, so
+// only the closest misses are held.
+func VerifySeq(calls iter.Seq[CallRecord], e Expectation) VerifyResult {
+	var result VerifyResult
+
+	// Recorded header names are lowercase, as gRPC metadata is.
+	e.Headers = lowerHeaderNames(e.Headers)
+
+	for call := range calls {
+		diff := e.explain(call)
+		if diff.Len() == 0 {
+			result.Actual++
+
+			continue
+		}
+
+		result.Closest = keepClosest(result.Closest, diff)
+	}
+
+	if result.Actual >= e.minimum() {
+		result.Closest = nil
+	}
+
+	return result
+}
+
+func scopedCalls(reader Reader, opts FilterOpts) iter.Seq[CallRecord] {
+	if streamer, ok := reader.(interface {
+		FilterSeq(opts FilterOpts) iter.Seq[CallRecord]
+	}); ok {
+		return streamer.FilterSeq(opts)
+	}
+
+	return slices.Values(reader.Filter(opts))
+}
+
+// keepClosest inserts diff into the list ordered by mismatch count. Among
+// equally close calls the newest goes first: the latest attempt is usually the
+// one under test.
+func keepClosest(closest []CallDiff, diff CallDiff) []CallDiff {
+	at := slices.IndexFunc(closest, func(c CallDiff) bool {
+		return c.Len() >= diff.Len()
+	})
+	if at < 0 {
+		at = len(closest)
+	}
+
+	if at >= closestCallsLimit {
+		return closest
+	}
+
+	closest = slices.Insert(closest, at, diff)
+
+	return closest[:min(len(closest), closestCallsLimit)]
+}
+
+// explain returns why call does not match e; the diff is empty when it does.
+// For a call with several requests the request with the fewest mismatches is
+// reported.
+func (e Expectation) explain(call CallRecord) CallDiff {
+	headers := make(map[string]any, len(call.RequestHeaders))
+	for k, v := range call.RequestHeaders {
+		headers[k] = v
+	}
+
+	diff := CallDiff{Call: call, Headers: e.Headers.Explain(headers)}
+
+	if e.Input.Equals == nil && e.Input.Contains == nil && e.Input.Matches == nil &&
+		e.Input.Glob == nil && len(e.Input.AnyOf) == 0 && e.Input.CEL == "" && e.Input.Where == nil {
+		return diff
+	}
+
+	requests := call.Requests
+	if len(requests) == 0 {
+		requests = []map[string]any{{}}
+	}
+
+	for i, request := range requests {
+		mismatches := e.Input.Explain(request)
+		if i == 0 || len(mismatches) < len(diff.Input) {
+			diff.Input = mismatches
+		}
+
+		if len(diff.Input) == 0 {
+			break
+		}
+	}
+
+	return diff
+}
+
+func lowerHeaderNames(h stuber.InputHeader) stuber.InputHeader {
+	lower := func(m map[string]any) map[string]any {
+		if m == nil {
+			return nil
+		}
+
+		out := make(map[string]any, len(m))
+		for k, v := range m {
+			out[strings.ToLower(k)] = v
+		}
+
+		return out
+	}
+
+	out := stuber.InputHeader{
+		Equals:   lower(h.Equals),
+		Contains: lower(h.Contains),
+		Matches:  lower(h.Matches),
+		Glob:     lower(h.Glob),
+		CEL:      h.CEL,
+	}
+
+	for _, alt := range h.AnyOf {
+		out.AnyOf = append(out.AnyOf, stuber.AnyOfHeaderElement{
+			Equals:   lower(alt.Equals),
+			Contains: lower(alt.Contains),
+			Matches:  lower(alt.Matches),
+			Glob:     lower(alt.Glob),
+		})
+	}
+
+	return out
+}
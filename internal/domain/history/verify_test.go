@@ -0,0 +1,120 @@
+package history_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func verifyStore(calls ...history.CallRecord) *history.MemoryStore {
+	store := history.NewMemoryStore(0)
+	for _, call := range calls {
+		store.Record(call)
+	}
+
+	return store
+}
+
+func TestVerifyCountBounds(t *testing.T) {
+	t.Parallel()
+
+	store := verifyStore(
+		history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"id": "1"}}},
+		history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"id": "2"}}},
+		history.CallRecord{Service: "svc", Method: "Other"},
+	)
+
+	tests := []struct {
+		name string
+		exp  history.Expectation
+		met  bool
+	}{
+		{name: "at least once by default", met: true},
+		{name: "exact", exp: history.Expectation{Times: new(2)}, met: true},
+		{name: "exact miss", exp: history.Expectation{Times: new(1)}},
+		{name: "at least", exp: history.Expectation{AtLeast: new(3)}},
+		{name: "at most", exp: history.Expectation{AtMost: new(2)}, met: true},
+		{name: "never", exp: history.Expectation{AtMost: new(0)}},
+		{name: "range", exp: history.Expectation{AtLeast: new(1), AtMost: new(2)}, met: true},
+		{
+			name: "payload filter",
+			exp:  history.Expectation{Input: stuber.InputData{Equals: map[string]any{"id": "2"}}, Times: new(1)},
+			met:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			exp := tt.exp
+			exp.Service, exp.Method = "svc", "M"
+
+			result := history.Verify(store, exp)
+			require.Equal(t, tt.met, exp.Met(result.Actual))
+		})
+	}
+}
+
+func TestVerifyDescribe(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "at least once", history.Expectation{}.Describe())
+	require.Equal(t, "3 times", history.Expectation{Times: new(3)}.Describe())
+	require.Equal(t, "never", history.Expectation{AtMost: new(0)}.Describe())
+	require.Equal(t, "at least 1 times and at most 2 times",
+		history.Expectation{AtLeast: new(1), AtMost: new(2)}.Describe())
+}
+
+func TestVerifyClosestCalls(t *testing.T) {
+	t.Parallel()
+
+	store := verifyStore(
+		history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"id": "1", "name": "x"}}},
+		history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"id": "2", "name": "a"}}},
+		history.CallRecord{
+			Service: "svc", Method: "M",
+			Requests:       []map[string]any{{"id": "3", "name": "a"}},
+			RequestHeaders: map[string]string{"x-tenant": "acme"},
+		},
+		history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"id": "4", "name": "a"}}},
+		history.CallRecord{Service: "svc", Method: "M", Requests: []map[string]any{{"id": "5", "name": "z"}}},
+	)
+
+	result := history.Verify(store, history.Expectation{
+		Service: "svc",
+		Method:  "M",
+		Input:   stuber.InputData{Equals: map[string]any{"id": "9", "name": "a"}},
+		Headers: stuber.InputHeader{Equals: map[string]any{"X-Tenant": "acme"}},
+	})
+
+	require.Zero(t, result.Actual)
+	require.Len(t, result.Closest, 3)
+	require.Equal(t, "3", result.Closest[0].Call.Requests[0]["id"], "fewest mismatches first")
+	require.Empty(t, result.Closest[0].Headers)
+	require.Equal(t, []stuber.Mismatch{{Field: "id", Operator: "equals", Expected: "9", Actual: "3"}}, result.Closest[0].Input)
+	require.Equal(t, "4", result.Closest[1].Call.Requests[0]["id"], "newest first among ties")
+	require.Equal(t, "2", result.Closest[2].Call.Requests[0]["id"])
+	require.Equal(t, []stuber.Mismatch{{Field: "x-tenant", Operator: "equals", Expected: "acme"}}, result.Closest[2].Headers)
+}
+
+func TestVerifyStreamUsesBestRequest(t *testing.T) {
+	t.Parallel()
+
+	store := verifyStore(history.CallRecord{
+		Service:  "svc",
+		Method:   "Stream",
+		Requests: []map[string]any{{"n": 1.0}, {"n": 2.0}},
+	})
+
+	exp := history.Expectation{Service: "svc", Method: "Stream", Input: stuber.InputData{Equals: map[string]any{"n": 2.0}}}
+	require.Equal(t, 1, history.Verify(store, exp).Actual)
+
+	exp.Input.Equals["n"] = 3.0
+	result := history.Verify(store, exp)
+	require.Zero(t, result.Actual)
+	require.Len(t, result.Closest, 1)
+}
@@ -10,12 +10,18 @@ import (
 	"github.com/cockroachdb/errors"
 )
 
-var errProxySourceInvalidTLS = errors.New("proxy source insecureSkipVerify must be true or false")
+var (
+	errProxySourceInvalidTLS           = errors.New("proxy source insecureSkipVerify must be true or false")
+	errProxySourceInvalidCaptureFormat = errors.New("proxy source captureFormat must be yaml or json")
+)
 
 const (
 	proxySchemeParts = 2
 	transportGRPC    = "grpc"
 	transportGRPCS   = "grpcs"
+
+	captureFormatYAML = "yaml"
+	captureFormatJSON = "json"
 )
 
 type ProxyHandler struct{}
@@ -75,6 +81,15 @@ func (h *ProxyHandler) Parse(raw string) (*Source, error) {
 		}
 	}
 
+	captureFormat := captureFormatYAML
+	if rawFormat := parsed.Query().Get("captureFormat"); rawFormat != "" {
+		if rawFormat != captureFormatYAML && rawFormat != captureFormatJSON {
+			return nil, errProxySourceInvalidCaptureFormat
+		}
+
+		captureFormat = rawFormat
+	}
+
 	return &Source{
 		Type:              SourceProxy,
 		Raw:               raw,
@@ -86,9 +101,29 @@ func (h *ProxyHandler) Parse(raw string) (*Source, error) {
 		ReflectInsecure:   insecure,
 		ProxyMode:         proxyMode,
 		RecordDelay:       recordDelay,
+		CaptureDir:        parsed.Query().Get("captureDir"),
+		CaptureFormat:     captureFormat,
+		CaptureRules:      parsed.Query().Get("captureRules"),
+		ShadowIgnore:      parseShadowIgnore(parsed.Query()["shadowIgnore"]),
 	}, nil
 }
 
+// parseShadowIgnore accepts the paths both as repeated parameters and as a
+// comma-separated list.
+func parseShadowIgnore(values []string) []string {
+	var paths []string
+
+	for _, value := range values {
+		for field := range strings.SplitSeq(value, ",") {
+			if field = strings.TrimSpace(field); field != "" {
+				paths = append(paths, field)
+			}
+		}
+	}
+
+	return paths
+}
+
 func (h *ProxyHandler) Process(_ context.Context, _ *Source, _ SourceProcessor) error {
 	return nil
 }
@@ -120,6 +155,8 @@ func parseProxyScheme(scheme string) (string, bool, error) {
 		return "replay", tlsEnabled, nil
 	case "capture":
 		return "capture", tlsEnabled, nil
+	case "shadow":
+		return "shadow", tlsEnabled, nil
 	default:
 		return "", false, errors.Wrap(errUnsupportedScheme, scheme)
 	}
@@ -37,6 +37,31 @@ func TestProxyHandlerParse(t *testing.T) {
 	require.Equal(t, "api.company.local", src.ReflectServerName)
 	require.Equal(t, "token", src.ReflectBearer)
 	require.True(t, src.ReflectInsecure)
+	require.Empty(t, src.CaptureDir)
+	require.Equal(t, "yaml", src.CaptureFormat)
+}
+
+func TestProxyHandlerParseCaptureDir(t *testing.T) {
+	t.Parallel()
+
+	h := &ProxyHandler{}
+
+	src, err := h.Parse("grpc+capture://localhost:50051?captureDir=./stubs/captured&captureFormat=json&captureRules=rules.yaml")
+	require.NoError(t, err)
+	require.Equal(t, "./stubs/captured", src.CaptureDir)
+	require.Equal(t, "json", src.CaptureFormat)
+	require.Equal(t, "rules.yaml", src.CaptureRules)
+}
+
+func TestProxyHandlerParseShadowIgnore(t *testing.T) {
+	t.Parallel()
+
+	h := &ProxyHandler{}
+
+	src, err := h.Parse("grpc+shadow://localhost:50051?shadowIgnore=meta.updatedAt,items.*.id&shadowIgnore=etag")
+	require.NoError(t, err)
+	require.Equal(t, "shadow", src.ProxyMode)
+	require.Equal(t, []string{"meta.updatedAt", "items.*.id", "etag"}, src.ShadowIgnore)
 }
 
 func TestProxyHandlerParseErrors(t *testing.T) {
@@ -55,4 +80,7 @@ func TestProxyHandlerParseErrors(t *testing.T) {
 
 	_, err = h.Parse("grpcs+proxy://localhost:50051?insecureSkipVerify=oops")
 	require.ErrorContains(t, err, "insecureSkipVerify")
+
+	_, err = h.Parse("grpc+capture://localhost:50051?captureDir=out&captureFormat=xml")
+	require.ErrorContains(t, err, "captureFormat")
 }
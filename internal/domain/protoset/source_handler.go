@@ -54,7 +54,7 @@ func ParseSource(raw string) (*Source, error) {
 	if scheme, _, hasScheme := strings.Cut(raw, "://"); hasScheme {
 		return nil, errors.Wrapf(errUnknownSourceScheme,
 			"%q: use grpc:// or grpcs:// for reflection, "+
-				"or {grpc,grpcs}+{proxy,replay,capture}:// for an upstream mode", scheme)
+				"or {grpc,grpcs}+{proxy,replay,capture,shadow}:// for an upstream mode", scheme)
 	}
 
 	return &Source{Type: SourceProto, Path: raw, Raw: raw}, nil
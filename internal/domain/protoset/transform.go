@@ -9,9 +9,11 @@ import (
 	"strings"
 
 	"github.com/bufbuild/protocompile"
+	"github.com/bufbuild/protocompile/linker"
 	"github.com/cockroachdb/errors"
 	"google.golang.org/protobuf/proto"
 	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/descriptorpb"
 
 	"github.com/bavix/gripmock/v3/internal/pbs"
@@ -38,7 +40,7 @@ func (c *Configure) Protos() []string                                  { return
 func (c *Configure) Descriptors() []string                             { return c.descriptors }
 func (c *Configure) DescriptorSets() []*descriptorpb.FileDescriptorSet { return c.descriptorSets }
 
-func createDescriptorSet(ctx context.Context, configure *Configure) (*descriptorpb.FileDescriptorSet, error) {
+func compileProtos(ctx context.Context, configure *Configure) (linker.Files, error) {
 	failbackResolver, err := pbs.NewResolver()
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to create fallback resolver")
@@ -58,6 +60,19 @@ func createDescriptorSet(ctx context.Context, configure *Configure) (*descriptor
 		return nil, errors.Wrap(err, "failed to compile descriptors")
 	}
 
+	return files, nil
+}
+
+func createDescriptorSet(
+	ctx context.Context,
+	registry *protoregistry.Files,
+	configure *Configure,
+) (*descriptorpb.FileDescriptorSet, error) {
+	files, err := compileProtos(ctx, configure)
+	if err != nil {
+		return nil, err
+	}
+
 	fds := &descriptorpb.FileDescriptorSet{
 		File: make([]*descriptorpb.FileDescriptorProto, len(files)),
 	}
@@ -66,7 +81,7 @@ func createDescriptorSet(ctx context.Context, configure *Configure) (*descriptor
 		fdp := protodesc.ToFileDescriptorProto(file)
 		fds.File[i] = fdp
 
-		err = registerGlobalFileOnce(ctx, fdp.GetName(), file.Path(), file)
+		err = registerFileOnce(ctx, registry, fdp.GetName(), file.Path(), file)
 		if err != nil {
 			return nil, err
 		}
@@ -75,7 +90,11 @@ func createDescriptorSet(ctx context.Context, configure *Configure) (*descriptor
 	return fds, nil
 }
 
-func compile(ctx context.Context, configure *Configure) ([]*descriptorpb.FileDescriptorSet, error) {
+func compile(
+	ctx context.Context,
+	files *protoregistry.Files,
+	configure *Configure,
+) ([]*descriptorpb.FileDescriptorSet, error) {
 	capacity := len(configure.Descriptors()) + len(configure.DescriptorSets())
 	if len(configure.Protos()) > 0 {
 		capacity++
@@ -96,7 +115,7 @@ func compile(ctx context.Context, configure *Configure) ([]*descriptorpb.FileDes
 			return nil, errors.Wrapf(err, "failed to unmarshal descriptor: %s", descriptor)
 		}
 
-		err = RegisterDescriptorSetFiles(ctx, descriptor, fds)
+		err = RegisterDescriptorSetFiles(ctx, files, descriptor, fds)
 		if err != nil {
 			return nil, err
 		}
@@ -106,7 +125,7 @@ func compile(ctx context.Context, configure *Configure) ([]*descriptorpb.FileDes
 
 	for i, fds := range configure.DescriptorSets() {
 		source := "remote-descriptor-set"
-		if err := RegisterDescriptorSetFiles(ctx, source, fds); err != nil {
+		if err := RegisterDescriptorSetFiles(ctx, files, source, fds); err != nil {
 			return nil, errors.Wrapf(err, "failed to register in-memory descriptor set: %d", i)
 		}
 
@@ -114,7 +133,7 @@ func compile(ctx context.Context, configure *Configure) ([]*descriptorpb.FileDes
 	}
 
 	if len(configure.Protos()) > 0 {
-		fds, err := createDescriptorSet(ctx, configure)
+		fds, err := createDescriptorSet(ctx, files, configure)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to create descriptor set")
 		}
@@ -167,11 +186,25 @@ func findMinimalPaths(paths []string) []string {
 	return result
 }
 
+// Build compiles paths and registers the resulting files into GlobalFiles.
+// A server builds into files of its own with BuildInto instead.
 func Build(
 	ctx context.Context,
 	imports []string,
 	paths []string,
 	remoteClient RemoteClient,
+) ([]*descriptorpb.FileDescriptorSet, error) {
+	return BuildInto(ctx, protoregistry.GlobalFiles, imports, paths, remoteClient)
+}
+
+// BuildInto compiles paths and registers the resulting files into files,
+// linking their imports against files first and GlobalFiles second.
+func BuildInto(
+	ctx context.Context,
+	files *protoregistry.Files,
+	imports []string,
+	paths []string,
+	remoteClient RemoteClient,
 ) ([]*descriptorpb.FileDescriptorSet, error) {
 	roots, err := absoluteImportRoots(imports)
 	if err != nil {
@@ -188,7 +221,7 @@ func Build(
 		return nil, errors.Wrap(err, "failed to create configuration")
 	}
 
-	return compile(ctx, configure)
+	return compile(ctx, files, configure)
 }
 
 func absoluteImportRoots(imports []string) ([]string, error) {
@@ -0,0 +1,239 @@
+package protoset
+
+import (
+	"context"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// IsLocal reports whether path names a source on disk: a proto file, a
+// descriptor set or a directory, as opposed to a remote or proxy source.
+func IsLocal(path string) bool {
+	source, err := ParseSource(path)
+	if err != nil {
+		return false
+	}
+
+	switch source.Type {
+	case SourceProto, SourceDescriptor, SourceDirectory:
+		return true
+	default:
+		return false
+	}
+}
+
+// Rebuild compiles local sources the way Build does, but registers nothing.
+// It returns the files that differ from the ones startup resolves: files
+// changed or added since startup, and the files importing them, which link
+// against the new definitions. Unchanged files are left out. Remote sources
+// are skipped.
+func Rebuild(
+	ctx context.Context,
+	startup protodesc.Resolver,
+	imports []string,
+	paths []string,
+) ([]protoreflect.FileDescriptor, error) {
+	local := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		if IsLocal(path) {
+			local = append(local, path)
+		}
+	}
+
+	roots, err := absoluteImportRoots(imports)
+	if err != nil {
+		return nil, err
+	}
+
+	sources, err := resolveSources(local)
+	if err != nil {
+		return nil, err
+	}
+
+	configure, err := newConfigure(ctx, findMinimalPaths(roots), sources, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create configuration")
+	}
+
+	files := new(protoregistry.Files)
+
+	for _, descriptor := range configure.Descriptors() {
+		if err := addDescriptorFile(files, startup, descriptor); err != nil {
+			return nil, err
+		}
+	}
+
+	if len(configure.Protos()) > 0 {
+		compiled, err := compileProtos(ctx, configure)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, file := range compiled {
+			if err := addWithImports(files, file); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return changedFiles(files, startup), nil
+}
+
+func addDescriptorFile(files *protoregistry.Files, startup protodesc.Resolver, descriptor string) error {
+	descriptorBytes, err := os.ReadFile(descriptor) //nolint:gosec
+	if err != nil {
+		return errors.Wrapf(err, "failed to read descriptor: %s", descriptor)
+	}
+
+	fds := &descriptorpb.FileDescriptorSet{}
+	if err := proto.Unmarshal(descriptorBytes, fds); err != nil {
+		return errors.Wrapf(err, "failed to unmarshal descriptor: %s", descriptor)
+	}
+
+	// Files may precede their imports in the set; retry until no file links.
+	// Imports the set carries must link to the set's copy, not startup's.
+	pending := fds.GetFile()
+	resolver := &fallbackResolver{primary: files, fallback: startup, own: make(map[string]struct{}, len(pending))}
+
+	for _, fdp := range pending {
+		resolver.own[fdp.GetName()] = struct{}{}
+	}
+
+	for len(pending) > 0 {
+		next := make([]*descriptorpb.FileDescriptorProto, 0, len(pending))
+
+		var lastErr error
+
+		for _, fdp := range pending {
+			if _, err := files.FindFileByPath(fdp.GetName()); err == nil {
+				continue
+			}
+
+			file, err := protodesc.NewFile(fdp, resolver)
+			if err != nil {
+				lastErr = err
+				next = append(next, fdp)
+
+				continue
+			}
+
+			if err := files.RegisterFile(file); err != nil {
+				return errors.Wrapf(err, "failed to register file %s from %s", fdp.GetName(), descriptor)
+			}
+		}
+
+		if len(next) == len(pending) {
+			return errors.Wrapf(lastErr, "%w: failed to link %s", errUnresolvedDescriptorDependencies, descriptor)
+		}
+
+		pending = next
+	}
+
+	return nil
+}
+
+// addWithImports registers file and, before it, every import not registered
+// yet, so a changed import is compared with startup as well.
+func addWithImports(files *protoregistry.Files, file protoreflect.FileDescriptor) error {
+	if _, err := files.FindFileByPath(file.Path()); err == nil {
+		return nil
+	}
+
+	imports := file.Imports()
+	for i := range imports.Len() {
+		if err := addWithImports(files, imports.Get(i).FileDescriptor); err != nil {
+			return err
+		}
+	}
+
+	return errors.Wrapf(files.RegisterFile(file), "failed to register file %s", file.Path())
+}
+
+// changedFiles returns the files of files that startup lacks or holds with
+// other contents, together with the files importing one of them.
+func changedFiles(files *protoregistry.Files, startup protodesc.Resolver) []protoreflect.FileDescriptor {
+	changed := make(map[string]bool, files.NumFiles())
+
+	var isChanged func(file protoreflect.FileDescriptor) bool
+
+	isChanged = func(file protoreflect.FileDescriptor) bool {
+		if result, ok := changed[file.Path()]; ok {
+			return result
+		}
+
+		// Guards import cycles, which protodesc rejects anyway.
+		changed[file.Path()] = false
+
+		result := !identicalIn(startup, file)
+
+		imports := file.Imports()
+		for i := 0; i < imports.Len() && !result; i++ {
+			result = isChanged(imports.Get(i).FileDescriptor)
+		}
+
+		changed[file.Path()] = result
+
+		return result
+	}
+
+	out := make([]protoreflect.FileDescriptor, 0)
+
+	files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+		if isChanged(file) {
+			out = append(out, file)
+		}
+
+		return true
+	})
+
+	return out
+}
+
+func identicalIn(startup protodesc.Resolver, file protoreflect.FileDescriptor) bool {
+	if file.IsPlaceholder() {
+		return true
+	}
+
+	existing, err := startup.FindFileByPath(file.Path())
+	if err != nil {
+		return false
+	}
+
+	return proto.Equal(protodesc.ToFileDescriptorProto(existing), protodesc.ToFileDescriptorProto(file))
+}
+
+// fallbackResolver resolves from primary, then from fallback for the paths
+// not in own.
+type fallbackResolver struct {
+	primary  *protoregistry.Files
+	fallback protodesc.Resolver
+	own      map[string]struct{}
+}
+
+func (r *fallbackResolver) FindFileByPath(path string) (protoreflect.FileDescriptor, error) { //nolint:ireturn
+	file, err := r.primary.FindFileByPath(path)
+	if err == nil {
+		return file, nil
+	}
+
+	if _, ok := r.own[path]; ok {
+		return nil, err //nolint:wrapcheck
+	}
+
+	return r.fallback.FindFileByPath(path) //nolint:wrapcheck
+}
+
+func (r *fallbackResolver) FindDescriptorByName(name protoreflect.FullName) (protoreflect.Descriptor, error) { //nolint:ireturn
+	if desc, err := r.primary.FindDescriptorByName(name); err == nil {
+		return desc, nil
+	}
+
+	return r.fallback.FindDescriptorByName(name) //nolint:wrapcheck
+}
@@ -0,0 +1,112 @@
+package protoset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+func writeRebuildSet(t *testing.T, path string, fields ...string) {
+	t.Helper()
+
+	message := &descriptorpb.DescriptorProto{Name: new("Item")}
+	for i, field := range fields {
+		message.Field = append(message.Field, &descriptorpb.FieldDescriptorProto{
+			Name:     new(field),
+			JsonName: new(field),
+			Number:   new(int32(i + 1)),
+			Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+			Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+		})
+	}
+
+	fds := &descriptorpb.FileDescriptorSet{File: []*descriptorpb.FileDescriptorProto{
+		{
+			Name:       new("rebuild_service.proto"),
+			Package:    new("rebuild"),
+			Syntax:     new("proto3"),
+			Dependency: []string{"rebuild_messages.proto"},
+			Service: []*descriptorpb.ServiceDescriptorProto{{
+				Name: new("Items"),
+				Method: []*descriptorpb.MethodDescriptorProto{{
+					Name:       new("Get"),
+					InputType:  new(".rebuild.Item"),
+					OutputType: new(".rebuild.Item"),
+				}},
+			}},
+		},
+		{
+			Name:        new("rebuild_messages.proto"),
+			Package:     new("rebuild"),
+			Syntax:      new("proto3"),
+			MessageType: []*descriptorpb.DescriptorProto{message},
+		},
+	}}
+
+	data, err := proto.Marshal(fds)
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+}
+
+func rebuiltPaths(files []protoreflect.FileDescriptor) []string {
+	paths := make([]string, 0, len(files))
+	for _, file := range files {
+		paths = append(paths, file.Path())
+	}
+
+	return paths
+}
+
+func TestRebuildReturnsChangedFilesAndImporters(t *testing.T) {
+	t.Parallel()
+
+	descFile := filepath.Join(t.TempDir(), "rebuild.pb")
+	writeRebuildSet(t, descFile, "id")
+
+	startup := new(protoregistry.Files)
+
+	_, err := BuildInto(t.Context(), startup, nil, []string{descFile}, nil)
+	require.NoError(t, err)
+
+	files, err := Rebuild(t.Context(), startup, nil, []string{descFile, "grpc://localhost:1"})
+	require.NoError(t, err)
+	require.Empty(t, files, "nothing changed since Build; remote sources are skipped")
+
+	writeRebuildSet(t, descFile, "id", "name")
+
+	files, err = Rebuild(t.Context(), startup, nil, []string{descFile})
+	require.NoError(t, err)
+	require.ElementsMatch(t, []string{"rebuild_messages.proto", "rebuild_service.proto"}, rebuiltPaths(files),
+		"the unchanged service file links against the changed messages, so it is returned too")
+
+	for _, file := range files {
+		if file.Path() != "rebuild_service.proto" {
+			continue
+		}
+
+		input := file.Services().Get(0).Methods().Get(0).Input()
+		require.NotNil(t, input.Fields().ByName("name"))
+	}
+
+	desc, err := startup.FindDescriptorByName("rebuild.Item")
+	require.NoError(t, err)
+
+	item, ok := desc.(protoreflect.MessageDescriptor)
+	require.True(t, ok)
+	require.Nil(t, item.Fields().ByName("name"), "the startup files keep their definitions")
+}
+
+func TestIsLocal(t *testing.T) {
+	t.Parallel()
+
+	require.True(t, IsLocal("service.proto"))
+	require.True(t, IsLocal("api.pb"))
+	require.False(t, IsLocal("grpc://localhost:50051"))
+	require.False(t, IsLocal("buf.build/acme/api"))
+}
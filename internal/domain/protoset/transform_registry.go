@@ -22,8 +22,12 @@ const (
 	fileRegisteredDifferent
 )
 
-func registeredFileState(name string, candidate *descriptorpb.FileDescriptorProto) fileRegistration {
-	existing, err := protoregistry.GlobalFiles.FindFileByPath(name)
+func registeredFileState(
+	files *protoregistry.Files,
+	name string,
+	candidate *descriptorpb.FileDescriptorProto,
+) fileRegistration {
+	existing, err := files.FindFileByPath(name)
 	if err != nil || existing == nil {
 		return fileNotRegistered
 	}
@@ -53,7 +57,10 @@ func logAlreadyRegistered(ctx context.Context, name, path string, state fileRegi
 			"were compiled under, so give the two files distinct names or distinct import paths")
 }
 
-//nolint:gochecknoglobals // shared lock is required for GlobalFiles concurrent registration safety
+// protoRegistryMu serializes registration: builds may run concurrently, and
+// only GlobalFiles guards itself.
+//
+//nolint:gochecknoglobals
 var protoRegistryMu sync.Mutex
 
 var (
@@ -61,13 +68,18 @@ var (
 	errDescriptorSymbolConflict         = errors.New("descriptor symbol conflict")
 )
 
+// RegisterDescriptorSetFiles registers the files of fds into files. Imports
+// the set does not carry link against files, then against GlobalFiles.
+//
 //nolint:funlen,wsl_v5
 func RegisterDescriptorSetFiles(
 	ctx context.Context,
+	files *protoregistry.Files,
 	descriptorPath string,
 	fds *descriptorpb.FileDescriptorSet,
 ) error {
 	pending := slices.Clone(fds.GetFile())
+	resolver := &fallbackResolver{primary: files, fallback: protoregistry.GlobalFiles}
 
 	for len(pending) > 0 {
 		next := make([]*descriptorpb.FileDescriptorProto, 0, len(pending))
@@ -77,7 +89,7 @@ func RegisterDescriptorSetFiles(
 		for _, fd := range pending {
 			protoRegistryMu.Lock()
 
-			if state := registeredFileState(fd.GetName(), fd); state != fileNotRegistered {
+			if state := registeredFileState(files, fd.GetName(), fd); state != fileNotRegistered {
 				protoRegistryMu.Unlock()
 
 				logAlreadyRegistered(ctx, fd.GetName(), descriptorPath, state)
@@ -87,7 +99,7 @@ func RegisterDescriptorSetFiles(
 				continue
 			}
 
-			fileDesc, err := protodesc.NewFile(fd, protoregistry.GlobalFiles)
+			fileDesc, err := protodesc.NewFile(fd, resolver)
 			if err != nil {
 				protoRegistryMu.Unlock()
 				lastErr = err
@@ -96,7 +108,7 @@ func RegisterDescriptorSetFiles(
 				continue
 			}
 
-			conflict, registerErr := registerGlobalFile(fileDesc)
+			conflict, registerErr := registerFile(files, fileDesc)
 			protoRegistryMu.Unlock()
 
 			if conflict {
@@ -134,7 +146,7 @@ func RegisterDescriptorSetFiles(
 	return nil
 }
 
-func registerGlobalFile(file protoreflect.FileDescriptor) (bool, error) {
+func registerFile(files *protoregistry.Files, file protoreflect.FileDescriptor) (bool, error) {
 	var (
 		conflict bool
 		err      error
@@ -147,13 +159,14 @@ func registerGlobalFile(file protoreflect.FileDescriptor) (bool, error) {
 		}
 	}()
 
-	err = protoregistry.GlobalFiles.RegisterFile(file)
+	err = files.RegisterFile(file)
 
 	return conflict, err
 }
 
-func registerGlobalFileOnce(
+func registerFileOnce(
 	ctx context.Context,
+	files *protoregistry.Files,
 	fileName string,
 	filePath string,
 	file protoreflect.FileDescriptor,
@@ -161,13 +174,13 @@ func registerGlobalFileOnce(
 	protoRegistryMu.Lock()
 	defer protoRegistryMu.Unlock()
 
-	if state := registeredFileState(fileName, protodesc.ToFileDescriptorProto(file)); state != fileNotRegistered {
+	if state := registeredFileState(files, fileName, protodesc.ToFileDescriptorProto(file)); state != fileNotRegistered {
 		logAlreadyRegistered(ctx, fileName, filePath, state)
 
 		return nil
 	}
 
-	conflict, err := registerGlobalFile(file)
+	conflict, err := registerFile(files, file)
 	if conflict {
 		zerolog.Ctx(ctx).Warn().
 			Str("name", fileName).
@@ -1,10 +1,12 @@
 package protoset
 
 import (
+	"path/filepath"
 	"testing"
 
 	"github.com/stretchr/testify/require"
 	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/descriptorpb"
 )
@@ -22,22 +24,56 @@ func probeDescriptor(t *testing.T, serviceName string) *descriptorpb.FileDescrip
 	}
 }
 
-//nolint:paralleltest
 func TestRegisteredFileState(t *testing.T) {
-	require.Equal(t, fileNotRegistered, registeredFileState(clashProbeFile, probeDescriptor(t, "First")),
-		"probe name must be unused before the test registers it")
+	t.Parallel()
 
-	protoRegistryMu.Lock()
+	files := new(protoregistry.Files)
 
-	file, err := protodesc.NewFile(probeDescriptor(t, "First"), protoregistry.GlobalFiles)
-	require.NoError(t, err)
-	require.NoError(t, protoregistry.GlobalFiles.RegisterFile(file))
+	require.Equal(t, fileNotRegistered, registeredFileState(files, clashProbeFile, probeDescriptor(t, "First")))
 
-	protoRegistryMu.Unlock()
+	file, err := protodesc.NewFile(probeDescriptor(t, "First"), files)
+	require.NoError(t, err)
+	require.NoError(t, files.RegisterFile(file))
 
-	require.Equal(t, fileRegisteredIdentical, registeredFileState(clashProbeFile, probeDescriptor(t, "First")),
+	require.Equal(t, fileRegisteredIdentical, registeredFileState(files, clashProbeFile, probeDescriptor(t, "First")),
 		"the identical file arriving twice is routine, not a clash")
 
-	require.Equal(t, fileRegisteredDifferent, registeredFileState(clashProbeFile, probeDescriptor(t, "Second")),
+	require.Equal(t, fileRegisteredDifferent, registeredFileState(files, clashProbeFile, probeDescriptor(t, "Second")),
 		"a different file under the same name costs the caller its services")
 }
+
+func TestBuildIntoKeepsVersionsApart(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	v1 := filepath.Join(dir, "v1.pb")
+	v2 := filepath.Join(dir, "v2.pb")
+
+	writeRebuildSet(t, v1, "id")
+	writeRebuildSet(t, v2, "id", "name")
+
+	first := new(protoregistry.Files)
+	second := new(protoregistry.Files)
+
+	_, err := BuildInto(t.Context(), first, nil, []string{v1}, nil)
+	require.NoError(t, err)
+
+	_, err = BuildInto(t.Context(), second, nil, []string{v2}, nil)
+	require.NoError(t, err)
+
+	fieldCount := func(files *protoregistry.Files) int {
+		desc, err := files.FindDescriptorByName("rebuild.Item")
+		require.NoError(t, err)
+
+		item, ok := desc.(protoreflect.MessageDescriptor)
+		require.True(t, ok)
+
+		return item.Fields().Len()
+	}
+
+	require.Equal(t, 1, fieldCount(first))
+	require.Equal(t, 2, fieldCount(second), "the same file in another registry is not an already registered clash")
+
+	_, err = protoregistry.GlobalFiles.FindFileByPath("rebuild_messages.proto")
+	require.ErrorIs(t, err, protoregistry.NotFound, "nothing leaks into GlobalFiles")
+}
@@ -28,4 +28,13 @@ type Source struct {
 	ReflectInsecure   bool
 	ProxyMode         string
 	RecordDelay       bool
+	// CaptureDir, when set, receives every stub recorded in capture mode as
+	// one CaptureFormat (yaml or json) file per service method.
+	CaptureDir    string
+	CaptureFormat string
+	// CaptureRules is a file of rules normalizing captured stubs.
+	CaptureRules string
+	// ShadowIgnore lists the response field paths shadow mode leaves out of
+	// the comparison with the upstream, e.g. "meta.updatedAt" or "items.*.id".
+	ShadowIgnore []string
 }
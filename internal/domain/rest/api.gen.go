@@ -17,6 +17,27 @@ import (
 	codes "google.golang.org/grpc/codes"
 )
 
+// Defines values for FaultLatencyDistribution.
+const (
+	Normal      FaultLatencyDistribution = "normal"
+	Percentiles FaultLatencyDistribution = "percentiles"
+	Uniform     FaultLatencyDistribution = "uniform"
+)
+
+// Valid indicates whether the value is a known member of the FaultLatencyDistribution enum.
+func (e FaultLatencyDistribution) Valid() bool {
+	switch e {
+	case Normal:
+		return true
+	case Percentiles:
+		return true
+	case Uniform:
+		return true
+	default:
+		return false
+	}
+}
+
 // Defines values for MethodMethodType.
 const (
 	BidiStreaming   MethodMethodType = "bidi_streaming"
@@ -97,6 +118,9 @@ type CallRecord struct {
 	// Code gRPC status code (e.g., 0 for OK, 5 for NotFound)
 	Code *int `json:"code,omitempty"`
 
+	// Deadline When the client gives up on the call (RFC 3339); absent when it set no deadline.
+	Deadline *time.Time `json:"deadline,omitempty"`
+
 	// ElapsedMs Handler duration in milliseconds
 	ElapsedMs *int64 `json:"elapsedMs,omitempty"`
 
@@ -106,6 +130,12 @@ type CallRecord struct {
 	// Method gRPC method name.
 	Method *string `json:"method,omitempty"`
 
+	// Peer Client address as `host:port`
+	Peer *string `json:"peer,omitempty"`
+
+	// RequestHeaders Incoming request metadata with lowercase keys; repeated values are joined with `, `. Keys listed in `HISTORY_REDACT_KEYS` hold `[REDACTED]`.
+	RequestHeaders map[string]string `json:"requestHeaders,omitempty"`
+
 	// Requests Request messages; a unary call carries exactly one
 	Requests *[]map[string]any `json:"requests,omitempty"`
 
@@ -124,8 +154,14 @@ type CallRecord struct {
 	// StubId Stub identifier
 	StubId *uuid.UUID `json:"stubId,omitempty"`
 
+	// Synthesized True when no stub matched and auto-mock made up the responses from the output descriptor.
+	Synthesized *bool `json:"synthesized,omitempty"`
+
 	// Timestamp When the call was received (RFC 3339).
 	Timestamp *time.Time `json:"timestamp,omitempty"`
+
+	// Transport Protocol that served the call: `grpc`, `connect`, `grpc-web`, `http` (HTTP/JSON transcoding) or `mcp`.
+	Transport *string `json:"transport,omitempty"`
 }
 
 // Dashboard Overview and runtime info in a single payload.
@@ -299,6 +335,146 @@ type DescriptorServiceIDs struct {
 	ServiceIDs []string `json:"serviceIDs"`
 }
 
+// DriftField A response field on which the stub and the upstream disagree.
+type DriftField struct {
+	// Path Dot-separated path of the field; list items are numbered from 0.
+	Path string `json:"path"`
+
+	// Stub Value in the stub response, omitted when the stub lacks the field.
+	Stub any `json:"stub,omitempty"`
+
+	// Upstream Value in the upstream response, omitted when the upstream lacks the field.
+	Upstream any `json:"upstream,omitempty"`
+}
+
+// DriftRecord One call whose stub response differed from the upstream's.
+type DriftRecord struct {
+	// At When the upstream response was compared.
+	At time.Time `json:"at"`
+
+	// Fields Differing fields, compared only when both sides answered OK.
+	Fields []DriftField `json:"fields"`
+
+	// StubCode gRPC status code of the stub response.
+	StubCode codes.Code `json:"stubCode"`
+
+	// Truncated Whether `fields` holds only the first differences.
+	Truncated bool `json:"truncated"`
+
+	// UpstreamCode gRPC status code of the upstream response.
+	UpstreamCode codes.Code `json:"upstreamCode"`
+}
+
+// DriftReport Comparisons of the calls one stub answered in shadow mode. `last` is omitted when every one matched.
+type DriftReport struct {
+	// CheckedAt When the last call was compared.
+	CheckedAt time.Time `json:"checkedAt"`
+
+	// Checks Number of calls compared.
+	Checks int `json:"checks"`
+
+	// Drifts Number of compared calls that differed.
+	Drifts int `json:"drifts"`
+
+	// Last One call whose stub response differed from the upstream's.
+	Last *DriftRecord `json:"last,omitempty"`
+
+	// Method Method name.
+	Method string `json:"method"`
+
+	// Service Full service name.
+	Service string `json:"service"`
+
+	// StubId Stub identifier (UUID).
+	//
+	// Example: 51c50050-ec27-4dae-a583-a32ca71a1dd5
+	StubId ID `json:"stubId"`
+}
+
+// DriftReportList defines model for DriftReportList.
+type DriftReportList = []DriftReport
+
+// FaultError Fails the call with a gRPC status.
+type FaultError struct {
+	// Code Non-OK gRPC status code.
+	Code codes.Code `json:"code"`
+
+	// Message gRPC status message; `injected fault` when empty.
+	Message string `json:"message,omitempty"`
+
+	// Probability Chance the error fires; omitted means always.
+	Probability *float64 `json:"probability,omitempty"`
+}
+
+// FaultLatency Delays the first response by a sampled duration.
+type FaultLatency struct {
+	// Distribution How the delay is sampled. Omitted, it is inferred: `percentiles` when given, `normal` when `mean` or `stddev` is set, `uniform` otherwise.
+	Distribution *FaultLatencyDistribution `json:"distribution,omitempty"`
+
+	// Max Upper bound of the delay; `0` means unbounded.
+	Max gptypes.Duration `json:"max,omitempty,omitzero"`
+
+	// Mean Mean of the normal distribution.
+	Mean gptypes.Duration `json:"mean,omitempty,omitzero"`
+
+	// Min Lower bound of the delay.
+	Min gptypes.Duration `json:"min,omitempty,omitzero"`
+
+	// Percentiles Delay at each percentile, such as `p50` or `p99.9`. Values between them are interpolated, and below the first from `min`.
+	Percentiles map[string]gptypes.Duration `json:"percentiles,omitempty"`
+
+	// Probability Chance the delay applies; omitted means always.
+	Probability *float64 `json:"probability,omitempty"`
+
+	// Stddev Standard deviation of the normal distribution.
+	Stddev gptypes.Duration `json:"stddev,omitempty,omitzero"`
+}
+
+// FaultLatencyDistribution How the delay is sampled. Omitted, it is inferred: `percentiles` when given, `normal` when `mean` or `stddev` is set, `uniform` otherwise.
+type FaultLatencyDistribution string
+
+// FakerSeed Seed of the faker and `uuid` template functions.
+type FakerSeed struct {
+	// Seed Seed the faker sequence starts from.
+	Seed uint64 `json:"seed"`
+
+	// Session Session the seed belongs to; empty for the server-wide seed.
+	Session string `json:"session,omitempty"`
+}
+
+// FaultProfile Global fault profile, applied to every stub under the stub's own `faults`.
+type FaultProfile struct {
+	// Drop Probability of silently dropping each server stream message.
+	Drop float64 `json:"drop,omitempty"`
+
+	// Error Fails the call with a gRPC status.
+	Error *FaultError `json:"error,omitempty"`
+
+	// Latency Delays the first response by a sampled duration.
+	Latency *FaultLatency `json:"latency,omitempty"`
+
+	// Reset Probability of resetting the call.
+	Reset float64 `json:"reset,omitempty"`
+
+	// Seed Seed of the fault source. On input it restarts the source; `0` picks a random seed. Responses carry the seed in use.
+	Seed *uint64 `json:"seed,omitempty"`
+}
+
+// Faults Failures injected into matched calls. Each fault fires independently with its probability.
+type Faults struct {
+	// Drop Probability of silently dropping each server stream message.
+	Drop float64 `json:"drop,omitempty"`
+
+	// Error Fails the call with a gRPC status.
+	Error *FaultError `json:"error,omitempty"`
+
+	// Latency Delays the first response by a sampled duration.
+	Latency *FaultLatency `json:"latency,omitempty"`
+
+	// Reset Probability of resetting the call. Server streams are reset before a randomly chosen message; native gRPC loses the connection, the HTTP gateways abort the stream.
+	Reset float64 `json:"reset,omitempty"`
+}
+
 // HistoryList A page of recorded calls, newest first.
 type HistoryList = []CallRecord
 
@@ -447,6 +623,25 @@ type InspectStage struct {
 	Removed int `json:"removed"`
 }
 
+// JsonPatchOperation One JSON patch operation.
+type JsonPatchOperation struct {
+	// From JSON pointer to the source location, for `move` and `copy`.
+	From string `json:"from,omitempty"`
+
+	// Op One of `add`, `remove`, `replace`, `move`, `copy` or `test`.
+	//
+	// Example: replace
+	Op string `json:"op"`
+
+	// Path JSON pointer to the target location.
+	//
+	// Example: /status
+	Path string `json:"path"`
+
+	// Value Value for `add`, `replace` and `test`.
+	Value any `json:"value,omitempty"`
+}
+
 // ListID A list of stub UUIDs.
 type ListID = []ID
 
@@ -552,6 +747,81 @@ type ProtoMessageSchema struct {
 	TypeName string `json:"typeName"`
 }
 
+// ProxyBinding A proxy source and the methods it serves when no rule selects them.
+type ProxyBinding struct {
+	// Address Upstream address.
+	Address string `json:"address"`
+
+	// Methods Full method names bound to the source, e.g. `/shop.Orders/List`.
+	Methods []string `json:"methods"`
+
+	// Mode Mode from the source scheme — `proxy`, `replay`, `capture` or `shadow`.
+	Mode string `json:"mode"`
+
+	// Tls Whether the upstream is reached over TLS.
+	Tls bool `json:"tls"`
+}
+
+// ProxyRoutes Proxy routing of the server. `rules` is omitted when no rules file is loaded.
+type ProxyRoutes struct {
+	// Bindings Proxy sources, in the order they were given.
+	Bindings []ProxyBinding `json:"bindings"`
+
+	// Rules The routing rules in place.
+	Rules *ProxyRoutingRules `json:"rules,omitempty"`
+}
+
+// ProxyRoutingRule Overrides how the calls it selects are served. The first selecting rule wins.
+type ProxyRoutingRule struct {
+	// Headers Globs on request metadata values, all of which must match.
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Method Glob on the method name; empty matches every method.
+	Method string `json:"method,omitempty"`
+
+	// Mode `proxy`, `replay`, `capture`, `shadow` or `stub`; empty keeps the mode of the upstream.
+	Mode string `json:"mode,omitempty"`
+
+	// Service Glob on the full service name; empty matches every service.
+	Service string `json:"service,omitempty"`
+
+	// Upstream Proxy source URL the calls go to, with its bearer token redacted.
+	Upstream string `json:"upstream,omitempty"`
+}
+
+// ProxyRoutingRules The routing rules in place.
+type ProxyRoutingRules struct {
+	// File Rules file, from `PROXY_RULES`.
+	File string `json:"file"`
+
+	// LoadedAt When the file was last loaded.
+	LoadedAt time.Time `json:"loadedAt"`
+
+	// Rules Rules in evaluation order.
+	Rules []ProxyRoutingRule `json:"rules"`
+}
+
+// Scenario A stub scenario and its current state.
+type Scenario struct {
+	// Name Scenario name.
+	Name string `json:"name"`
+
+	// PossibleStates `Started` plus every state the scenario's stubs require or move to, sorted.
+	PossibleStates []string `json:"possibleStates"`
+
+	// State Current state in the requested session.
+	State string `json:"state"`
+}
+
+// ScenarioList Scenarios sorted by name.
+type ScenarioList = []Scenario
+
+// ScenarioStateRequest defines model for ScenarioStateRequest.
+type ScenarioStateRequest struct {
+	// State Target state; empty resets the scenario to `Started`.
+	State string `json:"state,omitempty"`
+}
+
 // SearchRequest A synthetic gRPC request. The server resolves it against the loaded stubs and returns the output of the winning stub, without performing the call.
 type SearchRequest struct {
 	// Data Request body to match against stub `input`.
@@ -616,11 +886,38 @@ type Sessions struct {
 	Sessions []string `json:"sessions"`
 }
 
+// SnapshotInfo Summary of a server snapshot.
+type SnapshotInfo struct {
+	// CreatedAt When the snapshot was taken.
+	CreatedAt time.Time `json:"createdAt"`
+
+	// Descriptors Number of descriptor files added through `POST /descriptors` that the snapshot carries.
+	Descriptors int `json:"descriptors"`
+
+	// History Number of recorded calls.
+	History int `json:"history"`
+
+	// Id Snapshot identifier.
+	Id string `json:"id"`
+
+	// Sessions Number of tracked sessions.
+	Sessions int `json:"sessions"`
+
+	// Stubs Number of stubs, including stubs loaded from files.
+	Stubs int `json:"stubs"`
+}
+
+// SnapshotList defines model for SnapshotList.
+type SnapshotList = []SnapshotInfo
+
 // Stub A single stub: which method it answers, which requests it accepts, and what it returns.
 type Stub struct {
 	// Effects Side effects applied after successful stub match
 	Effects []StubEffect `json:"effects,omitempty"`
 
+	// Faults Failures injected into matched calls. Each fault fires independently with its probability.
+	Faults *Faults `json:"faults,omitempty"`
+
 	// Headers Matchers applied to gRPC request metadata. Header names are case-insensitive. All blocks present are AND-ed; an omitted or empty block always passes.
 	Headers StubHeaders `json:"headers,omitempty"`
 
@@ -640,6 +937,9 @@ type Stub struct {
 	// Example: SayHello
 	Method string `json:"method"`
 
+	// NewState State the scenario moves to when the stub matches. The check and the transition are atomic.
+	NewState string `json:"newState,omitempty"`
+
 	// Options Optional behavior settings for a stub
 	Options *StubOptions `json:"options,omitempty,omitzero"`
 
@@ -649,6 +949,15 @@ type Stub struct {
 	// Priority Tie-breaker among equally specific stubs; higher wins. Specificity is compared first, so an `equals` stub still beats a `contains` stub with a higher priority.
 	Priority int `json:"priority,omitempty"`
 
+	// RequiredState State the scenario must be in for the stub to match; empty matches in any state.
+	RequiredState string `json:"requiredState,omitempty"`
+
+	// Scenario Scenario (state machine) the stub belongs to. State is tracked per session and starts at `Started`. Required when `requiredState` or `newState` is set.
+	Scenario string `json:"scenario,omitempty"`
+
+	// Seed Seeds the faker and `uuid` template functions: the Nth match of the stub renders the same values on every run.
+	Seed *uint64 `json:"seed,omitempty"`
+
 	// Service Fully qualified gRPC service name.
 	//
 	// Example: Gripmock
@@ -684,6 +993,9 @@ type StubHeaders struct {
 	// AnyOf Alternative header matchers (OR). The stub matches when the blocks above pass AND at least one element here passes.
 	AnyOf []StubHeadersAnyOfElement `json:"anyOf,omitempty"`
 
+	// Cel CEL expression over the headers, bound as `headers` with lowercase names. The stub matches only when it evaluates to `true`.
+	Cel string `json:"cel,omitempty"`
+
 	// Contains Subset match. The request must carry at least these header names; values match on substring.
 	Contains map[string]string `json:"contains,omitempty"`
 
@@ -717,6 +1029,9 @@ type StubInput struct {
 	// AnyOf Alternative matchers (OR). The stub matches when the blocks above pass AND at least one element here passes. Depth is exactly one — an element cannot itself contain `anyOf`.
 	AnyOf []StubInputAnyOfElement `json:"anyOf,omitempty"`
 
+	// Cel CEL expression over the request, bound as `request`. The stub matches only when it evaluates to `true`.
+	Cel string `json:"cel,omitempty"`
+
 	// Contains Subset match. The request must carry at least these fields. Strings match on substring, arrays on containment, nested objects recursively. Extra fields in the request are ignored.
 	Contains map[string]any `json:"contains,omitempty"`
 
@@ -731,6 +1046,9 @@ type StubInput struct {
 
 	// Matches Regex match. Each leaf value is a Go regular expression applied to the corresponding request value.
 	Matches map[string]any `json:"matches,omitempty"`
+
+	// Where Field conditions. Each key names a field and maps to operators: `eq`, `gt`, `gte`, `lt`, `lte`, `between` (numbers, RFC 3339 timestamps or durations such as `1.5s`), `tolerance`, `exists`, `len`, `every` and `some`. Any other key names a nested field with a condition of its own.
+	Where map[string]any `json:"where,omitempty"`
 }
 
 // StubInputAnyOfElement One alternative of an `anyOf`. Its own blocks are AND-ed together.
@@ -749,6 +1067,9 @@ type StubInputAnyOfElement struct {
 
 	// Matches Regex match. Each leaf value is a Go regular expression applied to the corresponding request value.
 	Matches map[string]any `json:"matches,omitempty"`
+
+	// Where Field conditions, with the operators of the input's `where`.
+	Where map[string]any `json:"where,omitempty"`
 }
 
 // StubList A list of stubs.
@@ -760,7 +1081,7 @@ type StubOptions struct {
 	Times int `json:"times,omitempty"`
 }
 
-// StubOutput What the stub returns. Over this API exactly one side must be set: either the unary side (`data`, `error`, `code`, `details`) or `stream`. A stub carrying both is rejected with `400`.
+// StubOutput What the stub returns. Over this API exactly one side must be set: either the unary side (`data`, `error`, `code`, `details`, `upstream`) or `stream`. A stub carrying both is rejected with `400`.
 type StubOutput struct {
 	// Code gRPC status code; `0` (OK) is the default.
 	//
@@ -786,11 +1107,28 @@ type StubOutput struct {
 	// Headers Response metadata.
 	Headers map[string]string `json:"headers,omitempty"`
 
+	// Script Starlark program run after templates, for unary and client-streaming calls. The `response` dict it assigns overrides `data`, `headers`, `trailers`, `code` and `error`; a script alone counts as the unary side. Rejected with `400` when it does not compile.
+	//
+	// Example: response = {"data": {"sum": request["a"] + request["b"]}}
+	Script string `json:"script,omitempty"`
+
 	// Stream Response messages for server and bidirectional streaming, sent in order. Cannot be combined with `data`, `error`, `code` or `details` on this endpoint.
 	Stream []any `json:"stream,omitempty"`
 
 	// Trailers Trailing metadata, sent after the last message with the status. Independent of `headers`: the same key may appear in both, and each is delivered on its own channel.
 	Trailers map[string]string `json:"trailers,omitempty"`
+
+	// Upstream Forward the call to the method's proxy upstream and answer with its response, patched. `headers` and `trailers` of the output are laid over the upstream's; `code`, `error` and `details`, when set, replace the upstream status. The patches apply only when the final status is OK. Unary methods only; cannot be combined with `data` or `stream`, rejected with `400` otherwise.
+	Upstream *StubOutputUpstream `json:"upstream,omitempty"`
+}
+
+// StubOutputUpstream Forward the call to the method's proxy upstream and answer with its response, patched. `headers` and `trailers` of the output are laid over the upstream's; `code`, `error` and `details`, when set, replace the upstream status. The patches apply only when the final status is OK. Unary methods only; cannot be combined with `data` or `stream`, rejected with `400` otherwise.
+type StubOutputUpstream struct {
+	// Merge Merge patch (RFC 7396) applied after `patch`; a `null` member removes the field. Values may hold templates.
+	Merge map[string]any `json:"merge,omitempty"`
+
+	// Patch JSON patch (RFC 6902) applied to the upstream response message.
+	Patch []JsonPatchOperation `json:"patch,omitempty"`
 }
 
 // StubOutput_Details_Item defines model for StubOutput.details.Item.
@@ -800,26 +1138,89 @@ type StubOutput_Details_Item struct {
 	AdditionalProperties map[string]any `json:"-"`
 }
 
-// VerifyError Reported when the recorded count differs from the expectation.
+// VerifyCallDiff A recorded call to the method that did not match, with the reasons.
+type VerifyCallDiff struct {
+	// Call One gRPC call the server answered.
+	Call CallRecord `json:"call"`
+
+	// Headers Header matcher entries the call failed.
+	Headers *[]VerifyMismatch `json:"headers,omitempty"`
+
+	// Input Payload matcher entries the call failed. For streams, those of the closest request message.
+	Input *[]VerifyMismatch `json:"input,omitempty"`
+}
+
+// VerifyError Reported when the recorded calls do not satisfy the expectation.
 type VerifyError struct {
-	// Actual Count actually recorded.
+	// Actual Number of matching calls actually recorded.
 	Actual *int `json:"actual,omitempty"`
 
-	// Expected Count the caller asked for.
+	// Closest Up to three recorded calls that came nearest to matching, fewest mismatches first. Present only when too few calls matched.
+	Closest *[]VerifyCallDiff `json:"closest,omitempty"`
+
+	// Expected Count the caller asked for. Omitted when only bounds were given.
 	Expected *int `json:"expected,omitempty"`
 
 	// Message Human-readable summary of the mismatch.
 	Message *string `json:"message,omitempty"`
 }
 
-// VerifyRequest Expected number of calls to one method.
+// VerifyMismatch One matcher entry a recorded call failed.
+type VerifyMismatch struct {
+	// Actual Value found in the call, omitted when the field is missing.
+	Actual any `json:"actual,omitempty"`
+
+	// Expected Value the matcher asked for.
+	Expected any `json:"expected,omitempty"`
+
+	// Field Field or header name the matcher entry targets. Empty for `anyOf`.
+	Field *string `json:"field,omitempty"`
+
+	// Operator Matcher block the entry belongs to: `equals`, `contains`, `matches`, `glob` or `anyOf`.
+	Operator string `json:"operator"`
+}
+
+// VerifyOrderError Reported when the recorded calls do not follow the expected order.
+type VerifyOrderError struct {
+	// Counts Matching calls attributed to each step up to the failed one.
+	Counts *[]int `json:"counts,omitempty"`
+
+	// Message Human-readable summary of the failed step.
+	Message *string `json:"message,omitempty"`
+
+	// Step Zero-based index of the first step that was not satisfied.
+	Step *int `json:"step,omitempty"`
+}
+
+// VerifyOrderRequest Ordered sequence of call expectations.
+type VerifyOrderRequest struct {
+	// Steps Call expectations in the order they must happen. A step without count bounds must match at least once; `never` asserts a call is absent between its neighbours.
+	Steps []VerifyRequest `json:"steps"`
+}
+
+// VerifyRequest Expected calls to one method. Only calls whose request satisfies `input` and whose request headers satisfy `headers` are counted; both use stub matcher semantics. Without any count bound the call is expected at least once.
 type VerifyRequest struct {
-	// ExpectedCount Number of calls the method must have received.
-	ExpectedCount int `json:"expectedCount"`
+	// AtLeast Lower bound on the number of matching calls.
+	AtLeast *int `json:"atLeast,omitempty"`
+
+	// AtMost Upper bound on the number of matching calls.
+	AtMost *int `json:"atMost,omitempty"`
+
+	// ExpectedCount Number of calls the method must have received, exactly.
+	ExpectedCount *int `json:"expectedCount,omitempty"`
+
+	// Headers Matchers applied to gRPC request metadata. Header names are case-insensitive. All blocks present are AND-ed; an omitted or empty block always passes.
+	Headers *StubHeaders `json:"headers,omitempty"`
+
+	// Input Matchers applied to the request body. All blocks present are AND-ed; an omitted or empty block always passes, so a stub with every block empty matches any request.
+	Input *StubInput `json:"input,omitempty"`
 
 	// Method gRPC method name.
 	Method string `json:"method"`
 
+	// Never Shorthand for `atMost: 0`.
+	Never *bool `json:"never,omitempty"`
+
 	// Service Fully qualified gRPC service name.
 	Service string `json:"service"`
 }
@@ -840,6 +1241,18 @@ type ListHistoryParams struct {
 
 	// Error When true, return only calls that ended with a gRPC error
 	Error *bool `form:"error,omitempty" json:"error,omitempty"`
+
+	// Transport Keep only calls served over this protocol: `grpc`, `connect`, `grpc-web`, `http` or `mcp`.
+	Transport *string `form:"transport,omitempty" json:"transport,omitempty"`
+
+	// Peer Keep only calls from this client address, given as `host:port` or just `host`.
+	Peer *string `form:"peer,omitempty" json:"peer,omitempty"`
+
+	// Header Keep only calls whose request carried this header. `name` requires the header to be present, `name=value` requires that exact value. Repeat the parameter to require several headers.
+	Header *[]string `form:"header,omitempty" json:"header,omitempty"`
+
+	// Deadline When true, return only calls whose client set a deadline
+	Deadline *bool `form:"deadline,omitempty" json:"deadline,omitempty"`
 }
 
 // ListStubsParams defines parameters for ListStubs.
@@ -901,6 +1314,9 @@ type BatchStubsDeleteJSONRequestBody = ListID
 // InspectStubsJSONRequestBody defines body for InspectStubs for application/json ContentType.
 type InspectStubsJSONRequestBody = InspectRequest
 
+// SetScenarioStateJSONRequestBody defines body for SetScenarioState for application/json ContentType.
+type SetScenarioStateJSONRequestBody = ScenarioStateRequest
+
 // SearchStubsJSONRequestBody defines body for SearchStubs for application/json ContentType.
 type SearchStubsJSONRequestBody = SearchRequest
 
@@ -910,6 +1326,15 @@ type ValidateStubJSONRequestBody ValidateStubJSONBody
 // VerifyCallsJSONRequestBody defines body for VerifyCalls for application/json ContentType.
 type VerifyCallsJSONRequestBody = VerifyRequest
 
+// VerifyCallOrderJSONRequestBody defines body for VerifyCallOrder for application/json ContentType.
+type VerifyCallOrderJSONRequestBody = VerifyOrderRequest
+
+// SetFaultProfileJSONRequestBody defines body for SetFaultProfile for application/json ContentType.
+type SetFaultProfileJSONRequestBody = FaultProfile
+
+// SetFakerSeedJSONRequestBody defines body for SetFakerSeed for application/json ContentType.
+type SetFakerSeedJSONRequestBody = FakerSeed
+
 // Getter for additional properties for StubOutput_Details_Item. Returns the specified
 // element and whether it was found
 func (a StubOutput_Details_Item) Get(fieldName string) (value any, found bool) {
@@ -1189,6 +1614,15 @@ type ServerInterface interface {
 	// AddDescriptors Upload FileDescriptorSet
 	// (POST /descriptors)
 	AddDescriptors(w http.ResponseWriter, r *http.Request)
+	// ClearFaultProfile Clear the global fault profile
+	// (DELETE /faults)
+	ClearFaultProfile(w http.ResponseWriter, r *http.Request)
+	// GetFaultProfile Get the global fault profile
+	// (GET /faults)
+	GetFaultProfile(w http.ResponseWriter, r *http.Request)
+	// SetFaultProfile Set the global fault profile
+	// (PUT /faults)
+	SetFaultProfile(w http.ResponseWriter, r *http.Request)
 	// Liveness Liveness check
 	// (GET /health/liveness)
 	Liveness(w http.ResponseWriter, r *http.Request)
@@ -1201,6 +1635,30 @@ type ServerInterface interface {
 	// ListHistory Get call history
 	// (GET /history)
 	ListHistory(w http.ResponseWriter, r *http.Request, params ListHistoryParams)
+	// ListProxyRoutes List proxy routes
+	// (GET /proxy/routes)
+	ListProxyRoutes(w http.ResponseWriter, r *http.Request)
+	// ReloadProxyRules Reload routing rules
+	// (POST /proxy/rules/reload)
+	ReloadProxyRules(w http.ResponseWriter, r *http.Request)
+	// ListScenarios List scenarios
+	// (GET /scenarios)
+	ListScenarios(w http.ResponseWriter, r *http.Request)
+	// ResetScenarios Reset all scenarios
+	// (POST /scenarios/reset)
+	ResetScenarios(w http.ResponseWriter, r *http.Request)
+	// SetScenarioState Set scenario state
+	// (PUT /scenarios/{name}/state)
+	SetScenarioState(w http.ResponseWriter, r *http.Request, name string)
+	// ClearFakerSeed Clear the session seed
+	// (DELETE /seed)
+	ClearFakerSeed(w http.ResponseWriter, r *http.Request)
+	// GetFakerSeed Get the faker seed
+	// (GET /seed)
+	GetFakerSeed(w http.ResponseWriter, r *http.Request)
+	// SetFakerSeed Set the faker seed
+	// (PUT /seed)
+	SetFakerSeed(w http.ResponseWriter, r *http.Request)
 	// ServicesList Services
 	// (GET /services)
 	ServicesList(w http.ResponseWriter, r *http.Request)
@@ -1219,6 +1677,27 @@ type ServerInterface interface {
 	// SessionsList Session options
 	// (GET /sessions)
 	SessionsList(w http.ResponseWriter, r *http.Request)
+	// ClearShadowDrift Clear drift reports
+	// (DELETE /shadow/drift)
+	ClearShadowDrift(w http.ResponseWriter, r *http.Request)
+	// ListShadowDrift List drift reports
+	// (GET /shadow/drift)
+	ListShadowDrift(w http.ResponseWriter, r *http.Request)
+	// ListSnapshots List snapshots
+	// (GET /snapshots)
+	ListSnapshots(w http.ResponseWriter, r *http.Request)
+	// CreateSnapshot Take a snapshot
+	// (POST /snapshots)
+	CreateSnapshot(w http.ResponseWriter, r *http.Request)
+	// DeleteSnapshot Delete a snapshot
+	// (DELETE /snapshots/{id})
+	DeleteSnapshot(w http.ResponseWriter, r *http.Request, id string)
+	// GetSnapshot Download a snapshot
+	// (GET /snapshots/{id})
+	GetSnapshot(w http.ResponseWriter, r *http.Request, id string)
+	// RestoreSnapshot Restore a snapshot
+	// (POST /snapshots/{id}/restore)
+	RestoreSnapshot(w http.ResponseWriter, r *http.Request, id string)
 	// PurgeStubs Remove stubs
 	// (DELETE /stubs)
 	PurgeStubs(w http.ResponseWriter, r *http.Request)
@@ -1255,6 +1734,9 @@ type ServerInterface interface {
 	// VerifyCalls Verify call counts
 	// (POST /verify)
 	VerifyCalls(w http.ResponseWriter, r *http.Request)
+	// VerifyCallOrder Verify call order
+	// (POST /verify/order)
+	VerifyCallOrder(w http.ResponseWriter, r *http.Request)
 }
 
 // ServerInterfaceWrapper converts contexts to parameters.
@@ -1336,6 +1818,90 @@ func (siw *ServerInterfaceWrapper) AddDescriptors(w http.ResponseWriter, r *http
 	handler.ServeHTTP(w, r)
 }
 
+// ClearFaultProfile operation middleware
+func (siw *ServerInterfaceWrapper) ClearFaultProfile(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ClearFaultProfile(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetFaultProfile operation middleware
+func (siw *ServerInterfaceWrapper) GetFaultProfile(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFaultProfile(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SetFaultProfile operation middleware
+func (siw *ServerInterfaceWrapper) SetFaultProfile(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetFaultProfile(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ClearFakerSeed operation middleware
+func (siw *ServerInterfaceWrapper) ClearFakerSeed(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ClearFakerSeed(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetFakerSeed operation middleware
+func (siw *ServerInterfaceWrapper) GetFakerSeed(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetFakerSeed(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SetFakerSeed operation middleware
+func (siw *ServerInterfaceWrapper) SetFakerSeed(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetFakerSeed(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // Liveness operation middleware
 func (siw *ServerInterfaceWrapper) Liveness(w http.ResponseWriter, r *http.Request) {
 
@@ -1452,6 +2018,58 @@ func (siw *ServerInterfaceWrapper) ListHistory(w http.ResponseWriter, r *http.Re
 		return
 	}
 
+	// ------------- Optional query parameter "transport" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "transport", r.URL.Query(), &params.Transport, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "transport"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "transport", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "peer" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "peer", r.URL.Query(), &params.Peer, runtime.BindQueryParameterOptions{Type: "string", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "peer"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "peer", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "header" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "header", r.URL.Query(), &params.Header, runtime.BindQueryParameterOptions{Type: "array", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "header"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "header", Err: err})
+		}
+		return
+	}
+
+	// ------------- Optional query parameter "deadline" -------------
+
+	err = runtime.BindQueryParameterWithOptions("form", true, false, "deadline", r.URL.Query(), &params.Deadline, runtime.BindQueryParameterOptions{Type: "boolean", Format: ""})
+	if err != nil {
+		var requiredError *runtime.RequiredParameterError
+		if errors.As(err, &requiredError) {
+			siw.ErrorHandlerFunc(w, r, &RequiredParamError{ParamName: "deadline"})
+		} else {
+			siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "deadline", Err: err})
+		}
+		return
+	}
+
 	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		siw.Handler.ListHistory(w, r, params)
 	}))
@@ -1463,6 +2081,88 @@ func (siw *ServerInterfaceWrapper) ListHistory(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
+// ListProxyRoutes operation middleware
+func (siw *ServerInterfaceWrapper) ListProxyRoutes(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListProxyRoutes(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ReloadProxyRules operation middleware
+func (siw *ServerInterfaceWrapper) ReloadProxyRules(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ReloadProxyRules(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListScenarios operation middleware
+func (siw *ServerInterfaceWrapper) ListScenarios(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListScenarios(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ResetScenarios operation middleware
+func (siw *ServerInterfaceWrapper) ResetScenarios(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ResetScenarios(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// SetScenarioState operation middleware
+func (siw *ServerInterfaceWrapper) SetScenarioState(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "name" -------------
+	var name string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "name", mux.Vars(r)["name"], &name, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "", ValueIsUnescaped: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "name", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.SetScenarioState(w, r, name)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // ServicesList operation middleware
 func (siw *ServerInterfaceWrapper) ServicesList(w http.ResponseWriter, r *http.Request) {
 
@@ -1604,6 +2304,140 @@ func (siw *ServerInterfaceWrapper) SessionsList(w http.ResponseWriter, r *http.R
 	handler.ServeHTTP(w, r)
 }
 
+// ClearShadowDrift operation middleware
+func (siw *ServerInterfaceWrapper) ClearShadowDrift(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ClearShadowDrift(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListShadowDrift operation middleware
+func (siw *ServerInterfaceWrapper) ListShadowDrift(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListShadowDrift(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// ListSnapshots operation middleware
+func (siw *ServerInterfaceWrapper) ListSnapshots(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.ListSnapshots(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// CreateSnapshot operation middleware
+func (siw *ServerInterfaceWrapper) CreateSnapshot(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.CreateSnapshot(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// DeleteSnapshot operation middleware
+func (siw *ServerInterfaceWrapper) DeleteSnapshot(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "", ValueIsUnescaped: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.DeleteSnapshot(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// GetSnapshot operation middleware
+func (siw *ServerInterfaceWrapper) GetSnapshot(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "", ValueIsUnescaped: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.GetSnapshot(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
+// RestoreSnapshot operation middleware
+func (siw *ServerInterfaceWrapper) RestoreSnapshot(w http.ResponseWriter, r *http.Request) {
+
+	var err error
+	_ = err
+
+	// ------------- Path parameter "id" -------------
+	var id string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "id", mux.Vars(r)["id"], &id, runtime.BindStyledParameterOptions{ParamLocation: runtime.ParamLocationPath, Explode: false, Required: true, Type: "string", Format: "", ValueIsUnescaped: true})
+	if err != nil {
+		siw.ErrorHandlerFunc(w, r, &InvalidParamFormatError{ParamName: "id", Err: err})
+		return
+	}
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.RestoreSnapshot(w, r, id)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 // PurgeStubs operation middleware
 func (siw *ServerInterfaceWrapper) PurgeStubs(w http.ResponseWriter, r *http.Request) {
 
@@ -1919,6 +2753,20 @@ func (siw *ServerInterfaceWrapper) VerifyCalls(w http.ResponseWriter, r *http.Re
 	handler.ServeHTTP(w, r)
 }
 
+// VerifyCallOrder operation middleware
+func (siw *ServerInterfaceWrapper) VerifyCallOrder(w http.ResponseWriter, r *http.Request) {
+
+	handler := http.Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		siw.Handler.VerifyCallOrder(w, r)
+	}))
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		handler = middleware(handler)
+	}
+
+	handler.ServeHTTP(w, r)
+}
+
 type UnescapedCookieParamError struct {
 	ParamName string
 	Err       error
@@ -2076,15 +2924,53 @@ func HandlerWithOptions(si ServerInterface, options GorillaServerOptions) http.H
 
 	r.HandleFunc(options.BaseURL+"/stubs/inspect", wrapper.InspectStubs).Methods(http.MethodPost)
 
+	r.HandleFunc(options.BaseURL+"/scenarios", wrapper.ListScenarios).Methods(http.MethodGet)
+
+	r.HandleFunc(options.BaseURL+"/scenarios/reset", wrapper.ResetScenarios).Methods(http.MethodPost)
+
+	r.HandleFunc(options.BaseURL+"/scenarios/{name}/state", wrapper.SetScenarioState).Methods(http.MethodPut)
+
 	r.HandleFunc(options.BaseURL+"/history", wrapper.PurgeHistory).Methods(http.MethodDelete)
 
 	r.HandleFunc(options.BaseURL+"/history", wrapper.ListHistory).Methods(http.MethodGet)
 
 	r.HandleFunc(options.BaseURL+"/verify", wrapper.VerifyCalls).Methods(http.MethodPost)
 
+	r.HandleFunc(options.BaseURL+"/verify/order", wrapper.VerifyCallOrder).Methods(http.MethodPost)
+
 	r.HandleFunc(options.BaseURL+"/descriptors", wrapper.ListDescriptors).Methods(http.MethodGet)
 
 	r.HandleFunc(options.BaseURL+"/descriptors", wrapper.AddDescriptors).Methods(http.MethodPost)
 
+	r.HandleFunc(options.BaseURL+"/faults", wrapper.ClearFaultProfile).Methods(http.MethodDelete)
+
+	r.HandleFunc(options.BaseURL+"/faults", wrapper.GetFaultProfile).Methods(http.MethodGet)
+
+	r.HandleFunc(options.BaseURL+"/faults", wrapper.SetFaultProfile).Methods(http.MethodPut)
+
+	r.HandleFunc(options.BaseURL+"/seed", wrapper.ClearFakerSeed).Methods(http.MethodDelete)
+
+	r.HandleFunc(options.BaseURL+"/seed", wrapper.GetFakerSeed).Methods(http.MethodGet)
+
+	r.HandleFunc(options.BaseURL+"/seed", wrapper.SetFakerSeed).Methods(http.MethodPut)
+
+	r.HandleFunc(options.BaseURL+"/snapshots", wrapper.ListSnapshots).Methods(http.MethodGet)
+
+	r.HandleFunc(options.BaseURL+"/snapshots", wrapper.CreateSnapshot).Methods(http.MethodPost)
+
+	r.HandleFunc(options.BaseURL+"/snapshots/{id}", wrapper.DeleteSnapshot).Methods(http.MethodDelete)
+
+	r.HandleFunc(options.BaseURL+"/snapshots/{id}", wrapper.GetSnapshot).Methods(http.MethodGet)
+
+	r.HandleFunc(options.BaseURL+"/snapshots/{id}/restore", wrapper.RestoreSnapshot).Methods(http.MethodPost)
+
+	r.HandleFunc(options.BaseURL+"/proxy/routes", wrapper.ListProxyRoutes).Methods(http.MethodGet)
+
+	r.HandleFunc(options.BaseURL+"/proxy/rules/reload", wrapper.ReloadProxyRules).Methods(http.MethodPost)
+
+	r.HandleFunc(options.BaseURL+"/shadow/drift", wrapper.ClearShadowDrift).Methods(http.MethodDelete)
+
+	r.HandleFunc(options.BaseURL+"/shadow/drift", wrapper.ListShadowDrift).Methods(http.MethodGet)
+
 	return r
 }
@@ -121,6 +121,96 @@ func (m *mockServer) AddDescriptors(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+func (m *mockServer) ClearFaultProfile(w http.ResponseWriter, _ *http.Request) {
+	m.called["ClearFaultProfile"] = true
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockServer) GetFaultProfile(w http.ResponseWriter, _ *http.Request) {
+	m.called["GetFaultProfile"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) SetFaultProfile(w http.ResponseWriter, _ *http.Request) {
+	m.called["SetFaultProfile"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) ClearFakerSeed(w http.ResponseWriter, _ *http.Request) {
+	m.called["ClearFakerSeed"] = true
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockServer) GetFakerSeed(w http.ResponseWriter, _ *http.Request) {
+	m.called["GetFakerSeed"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) SetFakerSeed(w http.ResponseWriter, _ *http.Request) {
+	m.called["SetFakerSeed"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) ListSnapshots(w http.ResponseWriter, _ *http.Request) {
+	m.called["ListSnapshots"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) CreateSnapshot(w http.ResponseWriter, _ *http.Request) {
+	m.called["CreateSnapshot"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) DeleteSnapshot(w http.ResponseWriter, _ *http.Request, _ string) {
+	m.called["DeleteSnapshot"] = true
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockServer) GetSnapshot(w http.ResponseWriter, _ *http.Request, _ string) {
+	m.called["GetSnapshot"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) RestoreSnapshot(w http.ResponseWriter, _ *http.Request, _ string) {
+	m.called["RestoreSnapshot"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) ListProxyRoutes(w http.ResponseWriter, _ *http.Request) {
+	m.called["ListProxyRoutes"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) ReloadProxyRules(w http.ResponseWriter, _ *http.Request) {
+	m.called["ReloadProxyRules"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) ClearShadowDrift(w http.ResponseWriter, _ *http.Request) {
+	m.called["ClearShadowDrift"] = true
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockServer) ListShadowDrift(w http.ResponseWriter, _ *http.Request) {
+	m.called["ListShadowDrift"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
 func (m *mockServer) DeleteService(w http.ResponseWriter, _ *http.Request, _ string) {
 	m.called["DeleteService"] = true
 
@@ -175,6 +265,30 @@ func (m *mockServer) VerifyCalls(w http.ResponseWriter, _ *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+func (m *mockServer) VerifyCallOrder(w http.ResponseWriter, _ *http.Request) {
+	m.called["VerifyCallOrder"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) ListScenarios(w http.ResponseWriter, _ *http.Request) {
+	m.called["ListScenarios"] = true
+
+	w.WriteHeader(http.StatusOK)
+}
+
+func (m *mockServer) ResetScenarios(w http.ResponseWriter, _ *http.Request) {
+	m.called["ResetScenarios"] = true
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (m *mockServer) SetScenarioState(w http.ResponseWriter, _ *http.Request, _ string) {
+	m.called["SetScenarioState"] = true
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
 func (m *mockServer) DeleteStubByID(w http.ResponseWriter, _ *http.Request, _ ID) {
 	m.called["DeleteStubByID"] = true
 
@@ -218,6 +332,21 @@ func TestHandlerRoutes(t *testing.T) {
 		{http.MethodPost, "/stubs/validate", "ValidateStub"},
 		{http.MethodGet, "/descriptors", "ListDescriptors"},
 		{http.MethodPost, "/descriptors", "AddDescriptors"},
+		{http.MethodDelete, "/faults", "ClearFaultProfile"},
+		{http.MethodGet, "/faults", "GetFaultProfile"},
+		{http.MethodPut, "/faults", "SetFaultProfile"},
+		{http.MethodDelete, "/seed", "ClearFakerSeed"},
+		{http.MethodGet, "/seed", "GetFakerSeed"},
+		{http.MethodPut, "/seed", "SetFakerSeed"},
+		{http.MethodGet, "/snapshots", "ListSnapshots"},
+		{http.MethodPost, "/snapshots", "CreateSnapshot"},
+		{http.MethodDelete, "/snapshots/abc", "DeleteSnapshot"},
+		{http.MethodGet, "/snapshots/abc", "GetSnapshot"},
+		{http.MethodPost, "/snapshots/abc/restore", "RestoreSnapshot"},
+		{http.MethodGet, "/proxy/routes", "ListProxyRoutes"},
+		{http.MethodPost, "/proxy/rules/reload", "ReloadProxyRules"},
+		{http.MethodDelete, "/shadow/drift", "ClearShadowDrift"},
+		{http.MethodGet, "/shadow/drift", "ListShadowDrift"},
 		{http.MethodDelete, "/services/myservice", "DeleteService"},
 		{http.MethodPost, "/stubs/batchDelete", "BatchStubsDelete"},
 		{http.MethodPost, "/stubs/search", "SearchStubs"},
@@ -226,6 +355,11 @@ func TestHandlerRoutes(t *testing.T) {
 		{http.MethodGet, "/stubs/used", "ListUsedStubs"},
 		{http.MethodDelete, "/stubs/" + validUUID.String(), "DeleteStubByID"},
 		{http.MethodGet, "/stubs/" + validUUID.String(), "FindByID"},
+		{http.MethodGet, "/scenarios", "ListScenarios"},
+		{http.MethodPost, "/scenarios/reset", "ResetScenarios"},
+		{http.MethodPut, "/scenarios/checkout/state", "SetScenarioState"},
+		{http.MethodPost, "/verify", "VerifyCalls"},
+		{http.MethodPost, "/verify/order", "VerifyCallOrder"},
 	}
 
 	for _, tt := range tests {
@@ -0,0 +1,315 @@
+// Package automock synthesizes response messages from descriptors, for
+// methods that have no matching stub.
+package automock
+
+import (
+	"slices"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/infra/faker"
+)
+
+const (
+	// All enables auto-mock for every method.
+	All = "*"
+
+	// DefaultStreamCount is how many messages a stream sends per request
+	// message when no count is configured.
+	DefaultStreamCount = 3
+
+	maxDepth = 3
+	maxItems = 3
+)
+
+// Synthesizer builds fake responses for the methods it covers. A nil
+// *Synthesizer covers nothing.
+type Synthesizer struct {
+	all         bool
+	services    map[string]struct{}
+	methods     map[string]struct{}
+	streamCount int
+	faker       faker.Generator
+}
+
+// New returns a synthesizer covering targets: "*" for every method, a full
+// service name such as "shop.Orders", or a method as "shop.Orders/Get". It
+// returns nil when targets is empty. Streams send streamCount messages per
+// request message, DefaultStreamCount when streamCount is zero.
+func New(targets []string, streamCount uint) *Synthesizer {
+	s := &Synthesizer{
+		services:    make(map[string]struct{}),
+		methods:     make(map[string]struct{}),
+		streamCount: int(streamCount), //nolint:gosec
+		faker:       faker.New(),
+	}
+
+	if s.streamCount == 0 {
+		s.streamCount = DefaultStreamCount
+	}
+
+	covered := false
+
+	for _, target := range targets {
+		target = strings.TrimPrefix(strings.TrimSpace(target), "/")
+
+		switch {
+		case target == "":
+			continue
+		case target == All:
+			s.all = true
+		case strings.Contains(target, "/"):
+			s.methods[target] = struct{}{}
+		default:
+			s.services[target] = struct{}{}
+		}
+
+		covered = true
+	}
+
+	if !covered {
+		return nil
+	}
+
+	return s
+}
+
+// Covers reports whether calls to service/method that no stub matches are
+// answered with synthesized responses.
+func (s *Synthesizer) Covers(service, method string) bool {
+	if s == nil {
+		return false
+	}
+
+	if s.all {
+		return true
+	}
+
+	if _, ok := s.services[service]; ok {
+		return true
+	}
+
+	_, ok := s.methods[service+"/"+method]
+
+	return ok
+}
+
+// StreamCount is how many messages a stream sends per request message.
+func (s *Synthesizer) StreamCount() int {
+	return s.streamCount
+}
+
+// Message builds a message of type desc with every field populated: scalars,
+// enums, lists, maps, nested messages up to a fixed depth and well-known types.
+func (s *Synthesizer) Message(desc protoreflect.MessageDescriptor) *dynamicpb.Message {
+	msg := dynamicpb.NewMessage(desc)
+	s.fill(msg, 0)
+
+	return msg
+}
+
+func (s *Synthesizer) fill(msg protoreflect.Message, depth int) {
+	desc := msg.Descriptor()
+
+	if s.wellKnown(msg) {
+		return
+	}
+
+	fields := desc.Fields()
+	for i := range fields.Len() {
+		fd := fields.Get(i)
+
+		// One member per oneof: the one picked below.
+		if oneof := fd.ContainingOneof(); oneof != nil && !oneof.IsSynthetic() {
+			continue
+		}
+
+		s.field(msg, fd, depth)
+	}
+
+	oneofs := desc.Oneofs()
+	for i := range oneofs.Len() {
+		oneof := oneofs.Get(i)
+		if oneof.IsSynthetic() {
+			continue
+		}
+
+		s.field(msg, oneof.Fields().Get(s.faker.Number().IntN(oneof.Fields().Len())), depth)
+	}
+}
+
+func (s *Synthesizer) field(msg protoreflect.Message, fd protoreflect.FieldDescriptor, depth int) {
+	switch {
+	case fd.IsList():
+		if fd.Message() != nil && depth >= maxDepth {
+			return
+		}
+
+		list := msg.Mutable(fd).List()
+		for range s.count() {
+			list.Append(s.value(list.NewElement, fd, depth))
+		}
+	case fd.IsMap():
+		if fd.MapValue().Message() != nil && depth >= maxDepth {
+			return
+		}
+
+		m := msg.Mutable(fd).Map()
+		for range s.count() {
+			key := s.value(nil, fd.MapKey(), depth).MapKey()
+			m.Set(key, s.value(m.NewValue, fd.MapValue(), depth))
+		}
+	case fd.Message() != nil:
+		if depth >= maxDepth {
+			return
+		}
+
+		msg.Set(fd, s.value(func() protoreflect.Value { return msg.NewField(fd) }, fd, depth))
+	default:
+		msg.Set(fd, s.value(nil, fd, depth))
+	}
+}
+
+func (s *Synthesizer) count() int {
+	return 1 + s.faker.Number().IntN(maxItems)
+}
+
+// value is a fake value for a single fd; newMessage supplies an empty message
+// for message fields.
+func (s *Synthesizer) value(newMessage func() protoreflect.Value, fd protoreflect.FieldDescriptor, depth int) protoreflect.Value {
+	n := s.faker.Number()
+
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		return protoreflect.ValueOfBool(n.IntN(2) == 1) //nolint:mnd
+	case protoreflect.EnumKind:
+		return protoreflect.ValueOfEnum(s.enum(fd.Enum()))
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return protoreflect.ValueOfInt32(int32(n.IntRange(1, 1000))) //nolint:gosec,mnd
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return protoreflect.ValueOfInt64(int64(n.IntRange(1, 100000))) //nolint:mnd
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return protoreflect.ValueOfUint32(uint32(n.IntRange(1, 1000))) //nolint:gosec,mnd
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return protoreflect.ValueOfUint64(uint64(n.IntRange(1, 100000))) //nolint:gosec,mnd
+	case protoreflect.FloatKind:
+		return protoreflect.ValueOfFloat32(n.Float32Range(0, 1000)) //nolint:mnd
+	case protoreflect.DoubleKind:
+		return protoreflect.ValueOfFloat64(n.Float64Range(0, 1000)) //nolint:mnd
+	case protoreflect.StringKind:
+		return protoreflect.ValueOfString(s.text(fd.Name()))
+	case protoreflect.BytesKind:
+		return protoreflect.ValueOfBytes([]byte(s.faker.Text().Word()))
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+		v := newMessage()
+		s.fill(v.Message(), depth+1)
+
+		return v
+	default:
+		return fd.Default()
+	}
+}
+
+// enum picks a defined value, skipping the zero "unspecified" value when the
+// enum has others.
+func (s *Synthesizer) enum(desc protoreflect.EnumDescriptor) protoreflect.EnumNumber {
+	values := desc.Values()
+	if values.Len() == 1 {
+		return values.Get(0).Number()
+	}
+
+	return values.Get(1 + s.faker.Number().IntN(values.Len()-1)).Number()
+}
+
+// text picks a faker value that fits the words of the field name, falling
+// back to a single word.
+func (s *Synthesizer) text(name protoreflect.Name) string {
+	words := strings.Split(strings.ToLower(string(name)), "_")
+	has := func(candidates ...string) bool {
+		for _, candidate := range candidates {
+			if slices.Contains(words, candidate) {
+				return true
+			}
+		}
+
+		return false
+	}
+
+	switch last := words[len(words)-1]; {
+	case last == "id" || last == "uuid":
+		return s.faker.Identity().UUID()
+	case has("email"):
+		return s.faker.Contact().Email()
+	case has("phone"):
+		return s.faker.Contact().Phone()
+	case has("url", "uri", "link", "website"):
+		return s.faker.Contact().URL()
+	case has("username", "login"):
+		return s.faker.Contact().Username()
+	case has("first") && last == "name":
+		return s.faker.Person().FirstName()
+	case has("last") && last == "name":
+		return s.faker.Person().LastName()
+	case last == "name":
+		return s.faker.Person().Name()
+	case has("city"):
+		return s.faker.Geo().City()
+	case has("country"):
+		return s.faker.Geo().Country()
+	case has("street", "address"):
+		return s.faker.Geo().Street()
+	case has("zip", "postcode"):
+		return s.faker.Geo().Zip()
+	case has("company"):
+		return s.faker.Company().Company()
+	case has("currency"):
+		return s.faker.Commerce().CurrencyShort()
+	case has("ip"):
+		return s.faker.Network().IPv4()
+	case has("description", "message", "text", "comment", "summary"):
+		return s.faker.Text().Sentence(6) //nolint:mnd
+	default:
+		return s.faker.Text().Word()
+	}
+}
+
+// wellKnown fills the google.protobuf types that have a JSON form of their
+// own; it reports false for other messages.
+func (s *Synthesizer) wellKnown(msg protoreflect.Message) bool {
+	desc := msg.Descriptor()
+	if desc.ParentFile() == nil || desc.ParentFile().Package() != "google.protobuf" {
+		return false
+	}
+
+	fields := desc.Fields()
+	n := s.faker.Number()
+
+	switch desc.Name() {
+	case "Timestamp":
+		at := time.Now().Add(time.Duration(n.IntRange(-720, 720)) * time.Hour).Truncate(time.Second) //nolint:mnd
+		msg.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(at.Unix()))
+	case "Duration":
+		msg.Set(fields.ByName("seconds"), protoreflect.ValueOfInt64(int64(n.IntRange(1, 3600)))) //nolint:mnd
+	case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value", "UInt32Value",
+		"BoolValue", "StringValue", "BytesValue":
+		value := fields.ByName("value")
+		msg.Set(value, s.value(nil, value, 0))
+	case "Struct":
+		entries := msg.Mutable(fields.ByName("fields")).Map()
+		for range s.count() {
+			v := entries.NewValue()
+			v.Message().Set(v.Message().Descriptor().Fields().ByName("string_value"),
+				protoreflect.ValueOfString(s.faker.Text().Word()))
+			entries.Set(protoreflect.ValueOfString(s.faker.Text().Word()).MapKey(), v)
+		}
+	case "Value":
+		msg.Set(fields.ByName("string_value"), protoreflect.ValueOfString(s.faker.Text().Word()))
+	}
+
+	// Any, Empty, FieldMask and the rest stay empty: there is nothing to
+	// make up that a client could use.
+	return true
+}
@@ -0,0 +1,99 @@
+package automock_test
+
+import (
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/bavix/gripmock/v3/internal/infra/automock"
+)
+
+const userProto = `
+syntax = "proto3";
+package users;
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+enum Role { ROLE_UNSPECIFIED = 0; ADMIN = 1; }
+message User {
+  string id = 1;
+  string email = 2;
+  int64 age = 3;
+  bool active = 4;
+  Role role = 5;
+  repeated string tags = 6;
+  map<string, int32> scores = 7;
+  google.protobuf.Timestamp created_at = 8;
+  google.protobuf.StringValue nickname = 9;
+  User manager = 10;
+  oneof contact { string phone = 11; string fax = 12; }
+  bytes avatar = 13;
+}
+`
+
+func userDescriptor(t *testing.T) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{"users.proto": userProto}),
+		}),
+	}
+
+	files, err := compiler.Compile(t.Context(), "users.proto")
+	require.NoError(t, err)
+
+	return files[0].Messages().ByName("User")
+}
+
+func TestNewCovers(t *testing.T) {
+	t.Parallel()
+
+	require.Nil(t, automock.New(nil, 0))
+	require.Nil(t, automock.New([]string{" "}, 0))
+	require.False(t, (*automock.Synthesizer)(nil).Covers("users.Users", "Get"))
+
+	require.True(t, automock.New([]string{automock.All}, 0).Covers("users.Users", "Get"))
+
+	s := automock.New([]string{"users.Users", "/shop.Orders/Get"}, 0)
+	require.True(t, s.Covers("users.Users", "List"))
+	require.True(t, s.Covers("shop.Orders", "Get"))
+	require.False(t, s.Covers("shop.Orders", "List"))
+	require.Equal(t, automock.DefaultStreamCount, s.StreamCount())
+	require.Equal(t, 5, automock.New([]string{automock.All}, 5).StreamCount())
+}
+
+func TestMessagePopulatesFields(t *testing.T) {
+	t.Parallel()
+
+	desc := userDescriptor(t)
+	fields := desc.Fields()
+	msg := automock.New([]string{automock.All}, 0).Message(desc)
+
+	_, err := uuid.Parse(msg.Get(fields.ByName("id")).String())
+	require.NoError(t, err)
+	require.Contains(t, msg.Get(fields.ByName("email")).String(), "@")
+	require.Positive(t, msg.Get(fields.ByName("age")).Int())
+	require.Equal(t, protoreflect.EnumNumber(1), msg.Get(fields.ByName("role")).Enum())
+	require.NotZero(t, msg.Get(fields.ByName("tags")).List().Len())
+	require.NotZero(t, msg.Get(fields.ByName("scores")).Map().Len())
+	require.NotEmpty(t, msg.Get(fields.ByName("avatar")).Bytes())
+
+	createdAt := msg.Get(fields.ByName("created_at")).Message()
+	require.Positive(t, createdAt.Get(createdAt.Descriptor().Fields().ByName("seconds")).Int())
+
+	nickname := msg.Get(fields.ByName("nickname")).Message()
+	require.NotEmpty(t, nickname.Get(nickname.Descriptor().Fields().ByName("value")).String())
+
+	require.NotNil(t, msg.WhichOneof(desc.Oneofs().ByName("contact")))
+
+	// Recursive messages stop at a fixed depth.
+	depth := 0
+	for m := msg.ProtoReflect(); m.Has(fields.ByName("manager")); m = m.Get(fields.ByName("manager")).Message() {
+		depth++
+	}
+
+	require.Equal(t, 3, depth)
+}
@@ -0,0 +1,239 @@
+// Package celmatch evaluates the CEL expressions stubs use to match requests
+// and headers.
+package celmatch
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+
+	"github.com/cockroachdb/errors"
+	"github.com/google/cel-go/cel"
+	"github.com/google/cel-go/common/ast"
+	"github.com/google/cel-go/common/operators"
+	"github.com/google/cel-go/common/types"
+	"github.com/google/cel-go/ext"
+)
+
+const (
+	// Request is the variable an input expression reads the request from.
+	Request = "request"
+
+	// Headers is the variable a header expression reads the headers from.
+	Headers = "headers"
+
+	programCacheSize = 4096
+
+	// costLimit bounds the work of one evaluation, so an expression over a
+	// large request cannot stall matching.
+	costLimit = 100_000
+)
+
+// ErrNotBool is returned for expressions that cannot yield a bool.
+var ErrNotBool = errors.New("expression must evaluate to a bool")
+
+// Program is a compiled expression.
+type Program struct {
+	program cel.Program
+	weight  int
+}
+
+//nolint:gochecknoglobals
+var (
+	envOnce sync.Once
+	env     *cel.Env
+	envErr  error
+
+	programCache  sync.Map
+	programCached atomic.Int64
+)
+
+func environment() (*cel.Env, error) {
+	envOnce.Do(func() {
+		env, envErr = cel.NewEnv(
+			cel.Variable(Request, cel.MapType(cel.StringType, cel.DynType)),
+			cel.Variable(Headers, cel.MapType(cel.StringType, cel.DynType)),
+			cel.CrossTypeNumericComparisons(true),
+			ext.Strings(),
+		)
+	})
+
+	return env, envErr
+}
+
+// compiled is a cached compile result; an expression that does not compile is
+// cached with its error, so it is not recompiled for every request.
+type compiled struct {
+	program *Program
+	err     error
+}
+
+// Compile compiles expression, reusing the result of an earlier call.
+func Compile(expression string) (*Program, error) {
+	if cached, ok := programCache.Load(expression); ok {
+		result, _ := cached.(compiled)
+
+		return result.program, result.err
+	}
+
+	program, err := compile(expression)
+
+	if programCached.Load() < programCacheSize {
+		if _, loaded := programCache.LoadOrStore(expression, compiled{program: program, err: err}); !loaded {
+			programCached.Add(1)
+		}
+	}
+
+	return program, err
+}
+
+func compile(expression string) (*Program, error) {
+	env, err := environment()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create cel environment")
+	}
+
+	checked, issues := env.Compile(expression)
+	if issues.Err() != nil {
+		return nil, errors.Wrap(issues.Err(), "invalid cel expression")
+	}
+
+	if out := checked.OutputType(); !out.IsExactType(cel.BoolType) && !out.IsExactType(cel.DynType) {
+		return nil, errors.Wrapf(ErrNotBool, "got %s", out)
+	}
+
+	prg, err := env.Program(checked, cel.CostLimit(costLimit), cel.EvalOptions(cel.OptOptimize))
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid cel expression")
+	}
+
+	return &Program{program: prg, weight: weight(checked.NativeRep())}, nil
+}
+
+// Match reports whether expression holds with value bound to variable. An
+// expression that does not compile, fails or yields anything but true does
+// not match.
+func Match(expression, variable string, value map[string]any) bool {
+	program, err := Compile(expression)
+	if err != nil {
+		return false
+	}
+
+	return program.Match(variable, value)
+}
+
+// Weight is how many fields expression reads, zero when it does not compile.
+func Weight(expression string) int {
+	program, err := Compile(expression)
+	if err != nil {
+		return 0
+	}
+
+	return program.weight
+}
+
+// Match reports whether the program yields true with value bound to variable.
+func (p *Program) Match(variable string, value map[string]any) bool {
+	if value == nil {
+		value = map[string]any{}
+	}
+
+	out, _, err := p.program.Eval(map[string]any{variable: normalize(value)})
+	if err != nil {
+		return false
+	}
+
+	return out == types.True
+}
+
+// Weight is how many distinct fields the program reads, at least one. It
+// ranks an expression next to the key-by-key matchers.
+func (p *Program) Weight() int {
+	return p.weight
+}
+
+// normalize turns json.Number into the int or double CEL compares it as.
+func normalize(value any) any {
+	switch v := value.(type) {
+	case json.Number:
+		if n, err := v.Int64(); err == nil {
+			return n
+		}
+
+		if f, err := v.Float64(); err == nil {
+			return f
+		}
+
+		return v.String()
+	case map[string]any:
+		out := make(map[string]any, len(v))
+		for key, item := range v {
+			out[key] = normalize(item)
+		}
+
+		return out
+	case []any:
+		out := make([]any, len(v))
+		for i, item := range v {
+			out[i] = normalize(item)
+		}
+
+		return out
+	default:
+		return value
+	}
+}
+
+// weight counts the distinct field paths read off the request or the
+// headers; a path read only as the prefix of a longer one counts once.
+func weight(checked *ast.AST) int {
+	var paths []string
+
+	ast.PreOrderVisit(checked.Expr(), ast.NewExprVisitor(func(e ast.Expr) {
+		if p, ok := fieldPath(e); ok && strings.Contains(p, ".") && !slices.Contains(paths, p) {
+			paths = append(paths, p)
+		}
+	}))
+
+	n := 0
+
+	for _, p := range paths {
+		if !slices.ContainsFunc(paths, func(other string) bool { return strings.HasPrefix(other, p+".") }) {
+			n++
+		}
+	}
+
+	return max(n, 1)
+}
+
+// fieldPath renders request.a.b and request["a"]["b"] alike.
+func fieldPath(e ast.Expr) (string, bool) {
+	switch e.Kind() { //nolint:exhaustive
+	case ast.IdentKind:
+		name := e.AsIdent()
+
+		return name, name == Request || name == Headers
+	case ast.SelectKind:
+		parent, ok := fieldPath(e.AsSelect().Operand())
+
+		return parent + "." + e.AsSelect().FieldName(), ok
+	case ast.CallKind:
+		call := e.AsCall()
+		if call.FunctionName() != operators.Index || len(call.Args()) != 2 || call.Args()[1].Kind() != ast.LiteralKind {
+			return "", false
+		}
+
+		key, ok := call.Args()[1].AsLiteral().Value().(string)
+		if !ok {
+			return "", false
+		}
+
+		parent, ok := fieldPath(call.Args()[0])
+
+		return parent + "." + key, ok
+	default:
+		return "", false
+	}
+}
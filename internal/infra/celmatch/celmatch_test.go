@@ -0,0 +1,94 @@
+package celmatch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/celmatch"
+)
+
+func TestMatch(t *testing.T) {
+	t.Parallel()
+
+	request := map[string]any{
+		"amount":   json.Number("150"),
+		"ratio":    0.5,
+		"status":   "A",
+		"items":    []any{map[string]any{"qty": json.Number("1")}, map[string]any{"qty": json.Number("2")}},
+		"customer": map[string]any{"tier": "gold"},
+	}
+
+	tests := []struct {
+		expression string
+		want       bool
+	}{
+		{`request.amount > 100`, true},
+		{`request.amount > 100.5`, true},
+		{`request.amount == 150`, true},
+		{`request.ratio < 1`, true},
+		{`size(request.items) >= 3`, false},
+		{`request.status in ['A', 'B']`, true},
+		{`request.items.all(i, i.qty > 0)`, true},
+		{`request.customer.tier == 'gold' && request.amount < 200`, true},
+		{`request["customer"]["tier"].startsWith("go")`, true},
+		{`has(request.coupon)`, false},
+		{`request.coupon == "x"`, false},
+		{`request.status`, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.expression, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, celmatch.Match(tt.expression, celmatch.Request, request))
+		})
+	}
+}
+
+func TestMatchHeaders(t *testing.T) {
+	t.Parallel()
+
+	headers := map[string]any{"x-tenant": "acme", "x-version": "2"}
+
+	require.True(t, celmatch.Match(`headers["x-tenant"] == "acme" && int(headers["x-version"]) >= 2`, celmatch.Headers, headers))
+	require.True(t, celmatch.Match(`!("authorization" in headers)`, celmatch.Headers, nil))
+	require.False(t, celmatch.Match(`request.amount > 1`, celmatch.Headers, headers))
+}
+
+func TestCompileRejectsInvalidExpressions(t *testing.T) {
+	t.Parallel()
+
+	_, err := celmatch.Compile(`request.amount >`)
+	require.Error(t, err)
+
+	_, err = celmatch.Compile(`1 + 2`)
+	require.ErrorIs(t, err, celmatch.ErrNotBool)
+
+	_, err = celmatch.Compile(`body.amount > 1`)
+	require.Error(t, err)
+
+	require.False(t, celmatch.Match(`request.amount >`, celmatch.Request, nil))
+	require.Zero(t, celmatch.Weight(`request.amount >`))
+}
+
+func TestCompileCachesFailures(t *testing.T) {
+	t.Parallel()
+
+	_, first := celmatch.Compile(`request.total <`)
+	_, second := celmatch.Compile(`request.total <`)
+
+	require.Error(t, first)
+	require.Same(t, first, second)
+}
+
+func TestWeightCountsFields(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 1, celmatch.Weight(`true`))
+	require.Equal(t, 1, celmatch.Weight(`request.amount > 100`))
+	require.Equal(t, 1, celmatch.Weight(`request.amount > 100 && request.amount < 200`))
+	require.Equal(t, 2, celmatch.Weight(`request.customer.tier == "gold" && request["amount"] > 1`))
+	require.Equal(t, 2, celmatch.Weight(`has(request.customer.tier) && request.customer.id != ""`))
+}
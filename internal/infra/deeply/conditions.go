@@ -0,0 +1,329 @@
+package deeply
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/cockroachdb/errors"
+)
+
+// Condition operators. Any other key of a condition names a field of the
+// value, which is checked against the condition it maps to.
+const (
+	OpEq        = "eq"
+	OpGt        = "gt"
+	OpGte       = "gte"
+	OpLt        = "lt"
+	OpLte       = "lte"
+	OpBetween   = "between"
+	OpTolerance = "tolerance"
+	OpExists    = "exists"
+	OpLen       = "len"
+	OpEvery     = "every"
+	OpSome      = "some"
+)
+
+const maxConditionDepth = 32
+
+// ErrInvalidCondition is returned for conditions Satisfies cannot evaluate.
+var ErrInvalidCondition = errors.New("invalid condition")
+
+// Satisfies reports whether actual meets every operator and field of
+// condition. present is false when the value is missing from the message;
+// only {exists: false} holds for a missing value.
+//
+// Ordering operators compare numbers, RFC 3339 timestamps and durations such
+// as "1.5s", the way well-known Timestamp and Duration fields are encoded.
+// tolerance widens them by that much, in seconds for timestamps and durations.
+func Satisfies(condition map[string]any, actual any, present bool) bool {
+	return satisfies(condition, actual, present, 0)
+}
+
+func satisfies(condition map[string]any, actual any, present bool, depth int) bool {
+	if depth > maxConditionDepth {
+		return false
+	}
+
+	tolerance, _ := number(condition[OpTolerance])
+
+	for key, operand := range condition {
+		if !satisfiesOperator(key, operand, actual, present, tolerance, depth) {
+			return false
+		}
+	}
+
+	return true
+}
+
+//nolint:cyclop
+func satisfiesOperator(key string, operand, actual any, present bool, tolerance float64, depth int) bool {
+	switch key {
+	case OpTolerance:
+		return true
+	case OpExists:
+		want, _ := operand.(bool)
+
+		return present == want
+	case OpEq, OpGt, OpGte, OpLt, OpLte, OpBetween:
+		return present && compareOrdered(key, operand, actual, tolerance)
+	case OpLen:
+		n, ok := length(actual)
+
+		return present && ok && lengthHolds(operand, n, depth)
+	case OpEvery, OpSome:
+		items, ok := actual.([]any)
+		element, isCondition := operand.(map[string]any)
+
+		if !present || !ok || !isCondition {
+			return false
+		}
+
+		for _, item := range items {
+			if satisfies(element, item, true, depth+1) == (key == OpSome) {
+				return key == OpSome
+			}
+		}
+
+		return key == OpEvery
+	default:
+		field, ok := operand.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		fields, _ := actual.(map[string]any)
+		value, exists := fields[key]
+
+		return satisfies(field, value, exists, depth+1)
+	}
+}
+
+func compareOrdered(operator string, operand, actual any, tolerance float64) bool {
+	if operator == OpBetween {
+		bounds, ok := operand.([]any)
+		if !ok || len(bounds) != 2 { //nolint:mnd
+			return false
+		}
+
+		return compareOrdered(OpGte, bounds[0], actual, tolerance) &&
+			compareOrdered(OpLte, bounds[1], actual, tolerance)
+	}
+
+	diff, ok := difference(operand, actual)
+	if !ok {
+		return operator == OpEq && deepEqual(operand, actual)
+	}
+
+	switch operator {
+	case OpEq:
+		return math.Abs(diff) <= tolerance
+	case OpGt:
+		return diff > tolerance
+	case OpGte:
+		return diff >= -tolerance
+	case OpLt:
+		return diff < -tolerance
+	default:
+		return diff <= tolerance
+	}
+}
+
+// difference is actual minus operand, as numbers or in seconds for timestamps
+// and durations. It reports false when the two cannot be ordered.
+func difference(operand, actual any) (float64, bool) {
+	if want, ok := number(operand); ok {
+		got, ok := number(actual)
+
+		return got - want, ok
+	}
+
+	wantText, ok := operand.(string)
+	if !ok {
+		return 0, false
+	}
+
+	gotText, ok := actual.(string)
+	if !ok {
+		return 0, false
+	}
+
+	if want, err := time.Parse(time.RFC3339Nano, wantText); err == nil {
+		got, err := time.Parse(time.RFC3339Nano, gotText)
+
+		return got.Sub(want).Seconds(), err == nil
+	}
+
+	if want, err := time.ParseDuration(wantText); err == nil {
+		got, err := time.ParseDuration(gotText)
+
+		return (got - want).Seconds(), err == nil
+	}
+
+	return 0, false
+}
+
+// number reads value as a float, including int64 fields encoded as strings.
+func number(value any) (float64, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		f, err := v.Float64()
+
+		return f, err == nil
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+
+		return f, err == nil
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	}
+
+	reflected := reflect.ValueOf(value)
+
+	switch reflected.Kind() { //nolint:exhaustive
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(reflected.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(reflected.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+// length is the element count of a list or map, or the character count of a
+// string.
+func length(value any) (int, bool) {
+	switch v := value.(type) {
+	case []any:
+		return len(v), true
+	case map[string]any:
+		return len(v), true
+	case string:
+		return utf8.RuneCountInString(v), true
+	default:
+		return 0, false
+	}
+}
+
+// lengthHolds checks n against a len operand: a number to match exactly or a
+// condition of ordering operators.
+func lengthHolds(operand any, n int, depth int) bool {
+	if condition, ok := operand.(map[string]any); ok {
+		return satisfies(condition, n, true, depth+1)
+	}
+
+	want, ok := number(operand)
+
+	return ok && want == float64(n)
+}
+
+// ConditionWeight is how many fields condition checks, at least one. It ranks
+// a condition next to the key-by-key matchers.
+func ConditionWeight(condition map[string]any) int {
+	n := 0
+
+	for key, operand := range condition {
+		if isOperator(key) {
+			continue
+		}
+
+		if field, ok := operand.(map[string]any); ok {
+			n += ConditionWeight(field)
+		}
+	}
+
+	return max(n, 1)
+}
+
+// CheckCondition reports the first operator of condition with an operand it
+// cannot use.
+func CheckCondition(condition map[string]any) error {
+	return checkCondition(condition, "", 0)
+}
+
+//nolint:cyclop,funlen
+func checkCondition(condition map[string]any, path string, depth int) error {
+	if depth > maxConditionDepth {
+		return errors.Wrapf(ErrInvalidCondition, "%s: nested too deeply", path)
+	}
+
+	if len(condition) == 0 {
+		return errors.Wrapf(ErrInvalidCondition, "%s: empty condition", path)
+	}
+
+	for key, operand := range condition {
+		at := key
+		if path != "" {
+			at = path + "." + key
+		}
+
+		switch key {
+		case OpEq:
+		case OpGt, OpGte, OpLt, OpLte:
+			if !orderable(operand) {
+				return errors.Wrapf(ErrInvalidCondition, "%s: want a number, a timestamp or a duration, got %v", at, operand)
+			}
+		case OpBetween:
+			bounds, ok := operand.([]any)
+			if !ok || len(bounds) != 2 || !orderable(bounds[0]) || !orderable(bounds[1]) { //nolint:mnd
+				return errors.Wrapf(ErrInvalidCondition, "%s: want a pair of bounds, got %v", at, operand)
+			}
+		case OpTolerance:
+			if f, ok := number(operand); !ok || f < 0 {
+				return errors.Wrapf(ErrInvalidCondition, "%s: want a non-negative number, got %v", at, operand)
+			}
+		case OpExists:
+			if _, ok := operand.(bool); !ok {
+				return errors.Wrapf(ErrInvalidCondition, "%s: want a bool, got %v", at, operand)
+			}
+		case OpLen:
+			if nested, ok := operand.(map[string]any); ok {
+				if err := checkCondition(nested, at, depth+1); err != nil {
+					return err
+				}
+			} else if _, ok := number(operand); !ok {
+				return errors.Wrapf(ErrInvalidCondition, "%s: want a number or a condition, got %v", at, operand)
+			}
+		default:
+			nested, ok := operand.(map[string]any)
+			if !ok {
+				return errors.Wrapf(ErrInvalidCondition, "%s: want a condition, got %s", at, describe(operand))
+			}
+
+			if err := checkCondition(nested, at, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func orderable(operand any) bool {
+	_, ok := difference(operand, operand)
+
+	return ok
+}
+
+func isOperator(key string) bool {
+	switch key {
+	case OpEq, OpGt, OpGte, OpLt, OpLte, OpBetween, OpTolerance, OpExists, OpLen, OpEvery, OpSome:
+		return true
+	default:
+		return false
+	}
+}
+
+func describe(value any) string {
+	if value == nil {
+		return "null"
+	}
+
+	return fmt.Sprintf("%T", value)
+}
@@ -0,0 +1,122 @@
+package deeply_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/deeply"
+)
+
+func TestSatisfies(t *testing.T) {
+	t.Parallel()
+
+	order := map[string]any{
+		"amount":    json.Number("150"),
+		"price":     9.995,
+		"total":     "9007199254740993",
+		"createdAt": "2024-03-01T12:00:00Z",
+		"ttl":       "1.5s",
+		"note":      "héllo",
+		"items": []any{
+			map[string]any{"qty": json.Number("1")},
+			map[string]any{"qty": json.Number("4")},
+		},
+		"scores":   []any{json.Number("7"), json.Number("9")},
+		"customer": map[string]any{"tier": "gold"},
+	}
+
+	for name, testCase := range map[string]struct {
+		condition map[string]any
+		want      bool
+	}{
+		"gt":                  {map[string]any{"amount": map[string]any{"gt": 100}}, true},
+		"gt fails":            {map[string]any{"amount": map[string]any{"gt": 150}}, false},
+		"gte and lte":         {map[string]any{"amount": map[string]any{"gte": 150, "lte": 150.0}}, true},
+		"lt":                  {map[string]any{"amount": map[string]any{"lt": json.Number("151")}}, true},
+		"between":             {map[string]any{"amount": map[string]any{"between": []any{100, 200}}}, true},
+		"between fails":       {map[string]any{"amount": map[string]any{"between": []any{151, 200}}}, false},
+		"eq with tolerance":   {map[string]any{"price": map[string]any{"eq": 10, "tolerance": 0.01}}, true},
+		"eq out of tolerance": {map[string]any{"price": map[string]any{"eq": 10, "tolerance": 0.001}}, false},
+		"int64 as string":     {map[string]any{"total": map[string]any{"gt": 9007199254740000}}, true},
+		"eq on strings":       {map[string]any{"customer": map[string]any{"tier": map[string]any{"eq": "gold"}}}, true},
+		"timestamp after":     {map[string]any{"createdAt": map[string]any{"gt": "2024-01-01T00:00:00Z"}}, true},
+		"timestamp window": {map[string]any{"createdAt": map[string]any{
+			"between": []any{"2024-03-01T00:00:00Z", "2024-03-02T00:00:00+01:00"},
+		}}, true},
+		"timestamp before":    {map[string]any{"createdAt": map[string]any{"lt": "2024-01-01T00:00:00Z"}}, false},
+		"duration":            {map[string]any{"ttl": map[string]any{"gte": "1s", "lt": "1m"}}, true},
+		"duration tolerance":  {map[string]any{"ttl": map[string]any{"eq": "1s", "tolerance": 0.5}}, true},
+		"present":             {map[string]any{"amount": map[string]any{"exists": true}}, true},
+		"absent":              {map[string]any{"coupon": map[string]any{"exists": false}}, true},
+		"absent fails":        {map[string]any{"amount": map[string]any{"exists": false}}, false},
+		"missing compares":    {map[string]any{"coupon": map[string]any{"gt": 0}}, false},
+		"nested absent":       {map[string]any{"shipping": map[string]any{"city": map[string]any{"exists": false}}}, true},
+		"len exact":           {map[string]any{"items": map[string]any{"len": 2}}, true},
+		"len bounds":          {map[string]any{"items": map[string]any{"len": map[string]any{"gte": 1, "lte": 3}}}, true},
+		"len of string":       {map[string]any{"note": map[string]any{"len": 5}}, true},
+		"len fails":           {map[string]any{"items": map[string]any{"len": map[string]any{"gte": 3}}}, false},
+		"every":               {map[string]any{"items": map[string]any{"every": map[string]any{"qty": map[string]any{"gt": 0}}}}, true},
+		"every fails":         {map[string]any{"items": map[string]any{"every": map[string]any{"qty": map[string]any{"gt": 1}}}}, false},
+		"some":                {map[string]any{"items": map[string]any{"some": map[string]any{"qty": map[string]any{"gt": 3}}}}, true},
+		"some fails":          {map[string]any{"items": map[string]any{"some": map[string]any{"qty": map[string]any{"gt": 4}}}}, false},
+		"some scalar":         {map[string]any{"scores": map[string]any{"some": map[string]any{"gte": 9}}}, true},
+		"every on non-list":   {map[string]any{"amount": map[string]any{"every": map[string]any{"gt": 0}}}, false},
+		"nested field":        {map[string]any{"customer": map[string]any{"tier": map[string]any{"len": 4}}}, true},
+		"ordering a non-time": {map[string]any{"note": map[string]any{"gt": "a"}}, false},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, testCase.want, deeply.Satisfies(testCase.condition, order, true))
+		})
+	}
+}
+
+func TestSatisfiesEveryOnEmptyList(t *testing.T) {
+	t.Parallel()
+
+	condition := map[string]any{"gt": 0}
+
+	require.True(t, deeply.Satisfies(map[string]any{"every": condition}, []any{}, true))
+	require.False(t, deeply.Satisfies(map[string]any{"some": condition}, []any{}, true))
+}
+
+func TestCheckCondition(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, deeply.CheckCondition(map[string]any{
+		"gt": 1, "lte": "2024-01-01T00:00:00Z", "between": []any{"1s", "2s"}, "tolerance": 0.5,
+	}))
+	require.NoError(t, deeply.CheckCondition(map[string]any{
+		"items": map[string]any{"len": map[string]any{"gte": 1}, "every": map[string]any{"qty": map[string]any{"gt": 0}}},
+	}))
+
+	for name, condition := range map[string]map[string]any{
+		"empty":              {},
+		"text to order":      {"gt": "soon"},
+		"one bound":          {"between": []any{1}},
+		"negative tolerance": {"tolerance": -1},
+		"exists as string":   {"exists": "yes"},
+		"len as text":        {"len": "many"},
+		"field as value":     {"amount": 10},
+		"nested bad operand": {"items": map[string]any{"some": map[string]any{"qty": map[string]any{"lt": true}}}},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			require.ErrorIs(t, deeply.CheckCondition(condition), deeply.ErrInvalidCondition)
+		})
+	}
+}
+
+func TestConditionWeight(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, 1, deeply.ConditionWeight(map[string]any{"gt": 1, "lt": 5}))
+	require.Equal(t, 2, deeply.ConditionWeight(map[string]any{
+		"amount":   map[string]any{"gt": 1},
+		"customer": map[string]any{"tier": map[string]any{"eq": "gold"}},
+	}))
+}
@@ -0,0 +1,125 @@
+package drift
+
+import (
+	"path"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+)
+
+// Response is one side of a comparison: the status code of the call and, when
+// it is OK, the messages it returned in the JSON form of proxycapture.
+type Response struct {
+	Code     codes.Code
+	Messages []any
+}
+
+// Compare returns the drift between the stub's response and the upstream's,
+// nil when they agree. Fields whose path matches an ignore pattern are left
+// out; see Ignored.
+func Compare(stub, upstream Response, ignore []string) *Drift {
+	if stub.Code != upstream.Code {
+		return &Drift{StubCode: stub.Code, UpstreamCode: upstream.Code}
+	}
+
+	if stub.Code != codes.OK {
+		return nil
+	}
+
+	var fields []FieldDiff
+
+	if len(stub.Messages) == 1 && len(upstream.Messages) == 1 {
+		fields = compareValue(fields, nil, stub.Messages[0], upstream.Messages[0], ignore)
+	} else {
+		fields = compareValue(fields, nil, stub.Messages, upstream.Messages, ignore)
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return &Drift{StubCode: stub.Code, UpstreamCode: upstream.Code, Fields: fields}
+}
+
+// Ignored reports whether a dot-separated field path is covered by one of the
+// patterns. Each pattern segment is a Go path.Match glob on one path segment
+// (list indexes are segments too), and a pattern covers the fields nested
+// under what it matches: "meta" ignores "meta.updatedAt", "items.*.id" the id
+// of every item.
+func Ignored(fieldPath string, patterns []string) bool {
+	segments := strings.Split(fieldPath, ".")
+
+	return slices.ContainsFunc(patterns, func(pattern string) bool {
+		parts := strings.Split(pattern, ".")
+		if len(parts) > len(segments) {
+			return false
+		}
+
+		for i, part := range parts {
+			if matched, _ := path.Match(part, segments[i]); !matched {
+				return false
+			}
+		}
+
+		return true
+	})
+}
+
+func compareValue(fields []FieldDiff, keys []string, stub, upstream any, ignore []string) []FieldDiff {
+	if len(keys) > 0 && Ignored(strings.Join(keys, "."), ignore) {
+		return fields
+	}
+
+	stubMap, stubIsMap := stub.(map[string]any)
+	upstreamMap, upstreamIsMap := upstream.(map[string]any)
+
+	if stubIsMap && upstreamIsMap {
+		names := make([]string, 0, len(stubMap)+len(upstreamMap))
+		for name := range stubMap {
+			names = append(names, name)
+		}
+
+		for name := range upstreamMap {
+			if _, ok := stubMap[name]; !ok {
+				names = append(names, name)
+			}
+		}
+
+		slices.Sort(names)
+
+		for _, name := range names {
+			fields = compareValue(fields, append(keys, name), stubMap[name], upstreamMap[name], ignore)
+		}
+
+		return fields
+	}
+
+	stubList, stubIsList := stub.([]any)
+	upstreamList, upstreamIsList := upstream.([]any)
+
+	if stubIsList && upstreamIsList {
+		for i := range max(len(stubList), len(upstreamList)) {
+			var stubItem, upstreamItem any
+			if i < len(stubList) {
+				stubItem = stubList[i]
+			}
+
+			if i < len(upstreamList) {
+				upstreamItem = upstreamList[i]
+			}
+
+			fields = compareValue(fields, append(keys, strconv.Itoa(i)), stubItem, upstreamItem, ignore)
+		}
+
+		return fields
+	}
+
+	if reflect.DeepEqual(stub, upstream) {
+		return fields
+	}
+
+	return append(fields, FieldDiff{Path: strings.Join(keys, "."), Stub: stub, Upstream: upstream})
+}
@@ -0,0 +1,87 @@
+package drift_test
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/bavix/gripmock/v3/internal/infra/drift"
+)
+
+func ok(messages ...any) drift.Response {
+	return drift.Response{Code: codes.OK, Messages: messages}
+}
+
+func TestCompareFields(t *testing.T) {
+	t.Parallel()
+
+	stub := map[string]any{
+		"id":     "1",
+		"status": "PAID",
+		"meta":   map[string]any{"updatedAt": "2026-10-17T10:00:00Z"},
+		"items":  []any{map[string]any{"id": "a", "sku": "X"}},
+	}
+	upstream := map[string]any{
+		"id":     "1",
+		"status": "SHIPPED",
+		"meta":   map[string]any{"updatedAt": "2026-10-17T11:00:00Z"},
+		"items":  []any{map[string]any{"id": "b", "sku": "X"}, map[string]any{"id": "c", "sku": "Y"}},
+		"total":  "10",
+	}
+
+	got := drift.Compare(ok(stub), ok(upstream), []string{"meta", "items.*.id"})
+	require.NotNil(t, got)
+	require.Equal(t, []drift.FieldDiff{
+		{Path: "items.1", Upstream: map[string]any{"id": "c", "sku": "Y"}},
+		{Path: "status", Stub: "PAID", Upstream: "SHIPPED"},
+		{Path: "total", Upstream: "10"},
+	}, got.Fields)
+
+	require.Nil(t, drift.Compare(ok(stub), ok(stub), nil))
+	require.Nil(t, drift.Compare(
+		drift.Response{Code: codes.NotFound},
+		drift.Response{Code: codes.NotFound},
+		nil,
+	), "the status messages are not compared")
+}
+
+func TestCompareStreamsAndCodes(t *testing.T) {
+	t.Parallel()
+
+	got := drift.Compare(
+		ok(map[string]any{"n": 1.0}, map[string]any{"n": 2.0}),
+		ok(map[string]any{"n": 1.0}),
+		nil,
+	)
+	require.Equal(t, []drift.FieldDiff{{Path: "1", Stub: map[string]any{"n": 2.0}}}, got.Fields)
+
+	got = drift.Compare(ok(map[string]any{}), drift.Response{Code: codes.Unavailable}, nil)
+	require.Equal(t, codes.OK, got.StubCode)
+	require.Equal(t, codes.Unavailable, got.UpstreamCode)
+	require.Empty(t, got.Fields)
+}
+
+func TestStoreReports(t *testing.T) {
+	t.Parallel()
+
+	store := drift.NewStore()
+	orders, payments := uuid.New(), uuid.New()
+
+	store.Record(payments, "shop.Payments", "Charge", nil)
+	store.Record(orders, "shop.Orders", "Get", &drift.Drift{Fields: []drift.FieldDiff{{Path: "status"}}})
+	store.Record(orders, "shop.Orders", "Get", nil)
+
+	reports := store.Reports()
+	require.Len(t, reports, 2)
+	require.Equal(t, orders, reports[0].StubID)
+	require.Equal(t, 2, reports[0].Checks)
+	require.Equal(t, 1, reports[0].Drifts)
+	require.NotNil(t, reports[0].Last)
+	require.False(t, reports[0].Last.At.IsZero())
+	require.Nil(t, reports[1].Last)
+
+	store.Clear()
+	require.Empty(t, store.Reports())
+}
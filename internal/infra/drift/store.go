@@ -0,0 +1,135 @@
+// Package drift records where the responses of stubs and of the real
+// upstream they stand in for disagree.
+package drift
+
+import (
+	"cmp"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+)
+
+// maxFieldDiffs bounds the field differences kept for one drift.
+const maxFieldDiffs = 64
+
+// FieldDiff is a response field on which the stub and the upstream disagree.
+// A side that lacks the field holds nil.
+type FieldDiff struct {
+	Path     string
+	Stub     any
+	Upstream any
+}
+
+// Drift is one call whose stub response differed from the upstream's.
+type Drift struct {
+	At           time.Time
+	StubCode     codes.Code
+	UpstreamCode codes.Code
+	// Fields are compared only when both sides answered OK.
+	Fields []FieldDiff
+	// Truncated reports that Fields holds only the first differences.
+	Truncated bool
+}
+
+// Report sums up the comparisons of one stub.
+type Report struct {
+	StubID    uuid.UUID
+	Service   string
+	Method    string
+	Checks    int
+	Drifts    int
+	CheckedAt time.Time
+	// Last is the latest drift, nil when every comparison matched.
+	Last *Drift
+}
+
+// Store keeps one report per stub. It is safe for concurrent use.
+type Store struct {
+	mu      sync.Mutex
+	reports map[uuid.UUID]*Report
+}
+
+// NewStore returns an empty store.
+func NewStore() *Store {
+	return &Store{reports: make(map[uuid.UUID]*Report)}
+}
+
+// Record adds a comparison of the stub's response with the upstream's. A nil
+// drift records a match.
+func (s *Store) Record(stubID uuid.UUID, service, method string, drift *Drift) {
+	if s == nil {
+		return
+	}
+
+	now := time.Now()
+
+	if drift != nil {
+		if drift.At.IsZero() {
+			drift.At = now
+		}
+
+		if len(drift.Fields) > maxFieldDiffs {
+			drift.Fields = drift.Fields[:maxFieldDiffs]
+			drift.Truncated = true
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	report, ok := s.reports[stubID]
+	if !ok {
+		report = &Report{StubID: stubID}
+		s.reports[stubID] = report
+	}
+
+	report.Service = service
+	report.Method = method
+	report.Checks++
+	report.CheckedAt = now
+
+	if drift != nil {
+		report.Drifts++
+		report.Last = drift
+	}
+}
+
+// Reports returns a copy of every report, ordered by service, method and stub.
+func (s *Store) Reports() []Report {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+
+	reports := make([]Report, 0, len(s.reports))
+	for _, report := range s.reports {
+		reports = append(reports, *report)
+	}
+
+	s.mu.Unlock()
+
+	slices.SortFunc(reports, func(a, b Report) int {
+		return cmp.Or(
+			cmp.Compare(a.Service, b.Service),
+			cmp.Compare(a.Method, b.Method),
+			cmp.Compare(a.StubID.String(), b.StubID.String()),
+		)
+	})
+
+	return reports
+}
+
+// Clear forgets every report.
+func (s *Store) Clear() {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	clear(s.reports)
+	s.mu.Unlock()
+}
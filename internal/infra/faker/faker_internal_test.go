@@ -248,3 +248,37 @@ func TestGeneratorConcurrentUsage(t *testing.T) {
 
 	require.Equal(t, workers*rounds, count)
 }
+
+func TestSequenceRepeatsFromSeed(t *testing.T) {
+	t.Parallel()
+
+	draw := func(seq *infrafaker.Sequence) []string {
+		return []string{seq.Next().Identity().UUID(), seq.Next().Person().Name(), seq.Next().Identity().UUID()}
+	}
+
+	first := draw(infrafaker.NewSequence(7))
+	require.Equal(t, first, draw(infrafaker.NewSequence(7)))
+	require.NotEqual(t, first[0], first[2])
+	require.NotEqual(t, first, draw(infrafaker.NewSequence(8)))
+
+	require.Equal(t, draw(infrafaker.Derive(7, 1)), draw(infrafaker.Derive(7, 1)))
+	require.NotEqual(t, draw(infrafaker.Derive(7, 1)), draw(infrafaker.Derive(7, 2)))
+}
+
+func TestSessionSeeds(t *testing.T) {
+	t.Parallel()
+
+	sessions := infrafaker.NewSessions()
+	require.Nil(t, sessions.Get("a"))
+	require.Nil(t, sessions.Get(""))
+
+	sessions.Set("a", 11)
+	first := sessions.Get("a").Next().Identity().UUID()
+
+	sessions.Set("a", 11)
+	require.Equal(t, uint64(11), sessions.Get("a").Seed())
+	require.Equal(t, first, sessions.Get("a").Next().Identity().UUID())
+
+	sessions.Forget("a")
+	require.Nil(t, sessions.Get("a"))
+}
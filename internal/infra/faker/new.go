@@ -3,28 +3,16 @@ package faker
 import (
 	"crypto/rand"
 	"encoding/binary"
-	"sync/atomic"
 	"time"
 
 	"github.com/brianvoe/gofakeit/v7"
 )
 
-//nolint:gochecknoglobals
-var seedCounter atomic.Uint64
-
-//nolint:gochecknoinits
-func init() {
-	seed := randomSeed()
-	if seed == 0 {
-		seed = 1
-	}
-
-	seedCounter.Store(seed)
-}
-
+// New returns a generator with a random seed.
+//
 //nolint:ireturn
 func New() Generator {
-	return NewWithSeed(seedCounter.Add(1))
+	return NewWithSeed(randomSeed())
 }
 
 // NewWithSeed returns deterministic generator. Same seed => same sequence.
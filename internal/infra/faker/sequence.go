@@ -0,0 +1,128 @@
+package faker
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// Sequence hands out generators with consecutive seeds starting after a base
+// seed: two sequences with the same seed yield the same generators in the
+// same order.
+type Sequence struct {
+	seed uint64
+	next atomic.Uint64
+}
+
+// NewSequence starts a sequence from seed.
+func NewSequence(seed uint64) *Sequence {
+	s := &Sequence{seed: seed}
+	s.next.Store(seed)
+
+	return s
+}
+
+// Derive starts a sequence for the n-th use of seed, e.g. the n-th match of a
+// stub. Neighbouring n give unrelated sequences.
+func Derive(seed, n uint64) *Sequence {
+	return NewSequence(mix(seed ^ mix(n)))
+}
+
+// Seed is the seed the sequence started from.
+func (s *Sequence) Seed() uint64 {
+	return s.seed
+}
+
+// Next returns the next generator.
+//
+//nolint:ireturn
+func (s *Sequence) Next() Generator {
+	return NewWithSeed(s.next.Add(1))
+}
+
+// mix is the splitmix64 finalizer.
+func mix(x uint64) uint64 {
+	x += 0x9e3779b97f4a7c15
+	x = (x ^ (x >> 30)) * 0xbf58476d1ce4e5b9 //nolint:mnd
+	x = (x ^ (x >> 27)) * 0x94d049bb133111eb //nolint:mnd
+
+	return x ^ (x >> 31) //nolint:mnd
+}
+
+// Sessions keeps the sequences of sessions given a seed of their own.
+type Sessions struct {
+	mu        sync.RWMutex
+	sequences map[string]*Sequence
+}
+
+// NewSessions returns an empty registry.
+func NewSessions() *Sessions {
+	return &Sessions{sequences: make(map[string]*Sequence)}
+}
+
+// Set restarts the sequence of session from seed.
+func (s *Sessions) Set(session string, seed uint64) {
+	s.mu.Lock()
+	s.sequences[session] = NewSequence(seed)
+	s.mu.Unlock()
+}
+
+// Get returns the sequence of session, nil when it has no seed.
+func (s *Sessions) Get(session string) *Sequence {
+	if session == "" {
+		return nil
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.sequences[session]
+}
+
+// Forget drops the seed of session.
+func (s *Sessions) Forget(session string) {
+	s.mu.Lock()
+	delete(s.sequences, session)
+	s.mu.Unlock()
+}
+
+// Seeds holds the faker sequences of one server: its own, drawn by calls
+// without a seed of their own, and those of the sessions given one.
+type Seeds struct {
+	server   atomic.Pointer[Sequence]
+	sessions *Sessions
+}
+
+// NewSeeds starts the server sequence from seed, a random one when zero.
+func NewSeeds(seed uint64) *Seeds {
+	for seed == 0 {
+		seed = randomSeed()
+	}
+
+	s := &Seeds{sessions: NewSessions()}
+	s.Reseed(seed)
+
+	return s
+}
+
+// Reseed restarts the server sequence from seed, so the same order of faker
+// calls yields the same values on every run.
+func (s *Seeds) Reseed(seed uint64) {
+	s.server.Store(NewSequence(seed))
+}
+
+// Seed is the seed the server sequence started from.
+func (s *Seeds) Seed() uint64 {
+	return s.server.Load().Seed()
+}
+
+// Next returns the next generator of the server sequence.
+//
+//nolint:ireturn
+func (s *Seeds) Next() Generator {
+	return s.server.Load().Next()
+}
+
+// Sessions returns the sequences of the sessions given a seed of their own.
+func (s *Seeds) Sessions() *Sessions {
+	return s.sessions
+}
@@ -0,0 +1,200 @@
+// Package faults decides which injected failures a mocked call suffers.
+package faults
+
+import (
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// DefaultErrorMessage is the status message of an injected error without one.
+const DefaultErrorMessage = "injected fault"
+
+// ErrReset is the error of a call rolled for a reset. It is what history
+// records and what a native gRPC client sees: UNAVAILABLE, the status a client
+// reports for a stream refused with RST_STREAM.
+//
+//nolint:gochecknoglobals
+var ErrReset = status.Error(codes.Unavailable, "stream reset by fault injection")
+
+//nolint:gochecknoglobals
+var fallback = sync.OnceValue(func() *Injector { return New(0) })
+
+// Injector rolls fault dice from a single seeded source, so a run that makes
+// the same calls in the same order sees the same faults. It also holds the
+// global profile applied to every stub. A nil *Injector rolls from a randomly
+// seeded source and has no global profile.
+type Injector struct {
+	mu     sync.Mutex
+	seed   uint64
+	rng    *rand.Rand
+	global *stuber.Faults
+}
+
+// New creates an injector seeded with seed; zero picks a random seed.
+func New(seed uint64) *Injector {
+	i := new(Injector)
+	i.Reseed(seed)
+
+	return i
+}
+
+// Seed returns the seed the source was last reset with.
+func (i *Injector) Seed() uint64 {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.seed
+}
+
+// Reseed restarts the source from seed; zero picks a random seed.
+func (i *Injector) Reseed(seed uint64) {
+	if seed == 0 {
+		seed = rand.Uint64() //nolint:gosec
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.seed = seed
+	i.rng = rand.New(rand.NewPCG(seed, seed)) //nolint:gosec
+}
+
+// Global returns the global profile, nil when none is set.
+func (i *Injector) Global() *stuber.Faults {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	return i.global
+}
+
+// SetGlobal replaces the global profile; nil clears it.
+func (i *Injector) SetGlobal(profile *stuber.Faults) {
+	if profile.IsZero() {
+		profile = nil
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.global = profile
+}
+
+// Plan rolls the faults of a call matched to a stub with faults stub. Messages
+// is how many responses the call sends, which bounds where a reset lands; it
+// returns nil when neither the stub nor the global profile injects anything.
+func (i *Injector) Plan(stub *stuber.Faults, messages int) *Plan {
+	if i == nil {
+		if stub.IsZero() {
+			return nil
+		}
+
+		i = fallback()
+	}
+
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	f := merge(i.global, stub)
+	if f.IsZero() {
+		return nil
+	}
+
+	plan := &Plan{ResetAt: -1, drop: f.Drop, injector: i}
+
+	if f.Latency != nil && i.hit(f.Latency.Probability) {
+		plan.Delay = i.sample(f.Latency)
+	}
+
+	if f.Reset > 0 && i.rng.Float64() < f.Reset {
+		plan.ResetAt = i.rng.IntN(max(messages, 1))
+	}
+
+	if f.Error != nil && i.hit(f.Error.Probability) {
+		msg := f.Error.Message
+		if msg == "" {
+			msg = DefaultErrorMessage
+		}
+
+		plan.Err = status.Error(f.Error.Code, msg)
+	}
+
+	return plan
+}
+
+// hit rolls a probability; nil means always. Callers hold mu.
+func (i *Injector) hit(probability *float64) bool {
+	if probability == nil {
+		return true
+	}
+
+	return i.rng.Float64() < *probability
+}
+
+// merge overlays the stub's faults on the global profile field by field.
+func merge(global, stub *stuber.Faults) *stuber.Faults {
+	switch {
+	case stub.IsZero():
+		return global
+	case global.IsZero():
+		return stub
+	}
+
+	out := *global
+
+	if stub.Error != nil {
+		out.Error = stub.Error
+	}
+
+	if stub.Latency != nil {
+		out.Latency = stub.Latency
+	}
+
+	if stub.Reset != 0 {
+		out.Reset = stub.Reset
+	}
+
+	if stub.Drop != 0 {
+		out.Drop = stub.Drop
+	}
+
+	return &out
+}
+
+// Plan is what a single call suffers.
+type Plan struct {
+	// Delay is waited before the first response.
+	Delay time.Duration
+	// Err fails the call after Delay; nil when no error was rolled.
+	Err error
+	// ResetAt is the response index before which the call is reset; -1 when
+	// no reset was rolled.
+	ResetAt int
+
+	drop     float64
+	injector *Injector
+}
+
+// Drop rolls whether the next server stream message is dropped. It is safe
+// on a nil plan.
+func (p *Plan) Drop() bool {
+	if p == nil || p.drop <= 0 {
+		return false
+	}
+
+	p.injector.mu.Lock()
+	defer p.injector.mu.Unlock()
+
+	return p.injector.rng.Float64() < p.drop
+}
+
+// ResetBefore reports whether the call is reset before response index. It is
+// safe on a nil plan.
+func (p *Plan) ResetBefore(index int) bool {
+	return p != nil && p.ResetAt == index
+}
@@ -0,0 +1,150 @@
+package faults
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	"github.com/bavix/gripmock/v3/internal/infra/types"
+)
+
+func rolls(i *Injector, f *stuber.Faults, n int) []string {
+	out := make([]string, 0, n)
+
+	for range n {
+		plan := i.Plan(f, 4)
+		out = append(out, fmt.Sprint(status.Convert(plan.Err).Message(), plan.Delay, plan.ResetAt))
+	}
+
+	return out
+}
+
+func TestPlanIsDeterministicForASeed(t *testing.T) {
+	t.Parallel()
+
+	f := &stuber.Faults{
+		Error:   &stuber.FaultError{Probability: new(0.5), Code: codes.Unavailable},
+		Latency: &stuber.FaultLatency{Min: types.Duration(time.Millisecond), Max: types.Duration(time.Second)},
+		Reset:   0.3,
+	}
+
+	a, b := New(42), New(42)
+	require.Equal(t, rolls(a, f, 50), rolls(b, f, 50))
+
+	a.Reseed(42)
+	require.Equal(t, rolls(a, f, 50), rolls(New(42), f, 50))
+	require.NotEqual(t, rolls(New(1), f, 50), rolls(New(2), f, 50))
+}
+
+func TestPlanMergesStubOverGlobal(t *testing.T) {
+	t.Parallel()
+
+	i := New(7)
+	require.Nil(t, i.Plan(nil, 1))
+
+	i.SetGlobal(&stuber.Faults{
+		Error:   &stuber.FaultError{Code: codes.Internal, Message: "global"},
+		Latency: &stuber.FaultLatency{Min: types.Duration(5 * time.Millisecond)},
+	})
+
+	plan := i.Plan(&stuber.Faults{Error: &stuber.FaultError{Code: codes.ResourceExhausted}}, 1)
+	require.Equal(t, codes.ResourceExhausted, status.Code(plan.Err))
+	require.Equal(t, DefaultErrorMessage, status.Convert(plan.Err).Message())
+	require.Equal(t, 5*time.Millisecond, plan.Delay)
+	require.Equal(t, -1, plan.ResetAt)
+
+	i.SetGlobal(&stuber.Faults{})
+	require.Nil(t, i.Global())
+	require.Nil(t, i.Plan(nil, 1))
+}
+
+func TestPlanNilInjector(t *testing.T) {
+	t.Parallel()
+
+	var i *Injector
+
+	require.Nil(t, i.Plan(nil, 1))
+
+	plan := i.Plan(&stuber.Faults{Reset: 1, Drop: 1}, 3)
+	require.GreaterOrEqual(t, plan.ResetAt, 0)
+	require.Less(t, plan.ResetAt, 3)
+	require.True(t, plan.ResetBefore(plan.ResetAt))
+	require.True(t, plan.Drop())
+
+	var none *Plan
+
+	require.False(t, none.Drop())
+	require.False(t, none.ResetBefore(0))
+}
+
+func TestSampleLatency(t *testing.T) {
+	t.Parallel()
+
+	i := New(3)
+
+	uniform := &stuber.FaultLatency{Min: types.Duration(10 * time.Millisecond), Max: types.Duration(20 * time.Millisecond)}
+	normal := &stuber.FaultLatency{
+		Mean:   types.Duration(50 * time.Millisecond),
+		StdDev: types.Duration(time.Second),
+		Max:    types.Duration(100 * time.Millisecond),
+	}
+	table := &stuber.FaultLatency{Percentiles: map[string]types.Duration{
+		"p50": types.Duration(10 * time.Millisecond),
+		"p99": types.Duration(100 * time.Millisecond),
+	}}
+
+	for range 1000 {
+		d := i.sample(uniform)
+		require.GreaterOrEqual(t, d, 10*time.Millisecond)
+		require.Less(t, d, 20*time.Millisecond)
+
+		d = i.sample(normal)
+		require.GreaterOrEqual(t, d, time.Duration(0))
+		require.LessOrEqual(t, d, 100*time.Millisecond)
+
+		d = i.sample(table)
+		require.GreaterOrEqual(t, d, time.Duration(0))
+		require.LessOrEqual(t, d, 100*time.Millisecond)
+	}
+
+	points := percentiles(table)
+	require.Equal(t, 5*time.Millisecond, interpolate(points, 0, 25))
+	require.Equal(t, 10*time.Millisecond, interpolate(points, 0, 50))
+	require.Equal(t, 100*time.Millisecond, interpolate(points, 0, 99.5))
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+
+	valid := []*stuber.Faults{
+		nil,
+		{Reset: 1, Drop: 0.5},
+		{Error: &stuber.FaultError{Code: codes.Unavailable, Probability: new(0.1)}},
+		{Latency: &stuber.FaultLatency{Percentiles: map[string]types.Duration{"p50": 1, "99.9": 2}}},
+	}
+	for _, f := range valid {
+		require.NoError(t, Validate(f))
+	}
+
+	invalid := []*stuber.Faults{
+		{Reset: 1.5},
+		{Drop: -0.1},
+		{Error: &stuber.FaultError{}},
+		{Error: &stuber.FaultError{Code: 17}},
+		{Latency: &stuber.FaultLatency{Probability: new(2.0)}},
+		{Latency: &stuber.FaultLatency{Min: 2, Max: 1}},
+		{Latency: &stuber.FaultLatency{Min: -1}},
+		{Latency: &stuber.FaultLatency{Distribution: "pareto"}},
+		{Latency: &stuber.FaultLatency{Distribution: stuber.LatencyPercentiles}},
+		{Latency: &stuber.FaultLatency{Percentiles: map[string]types.Duration{"p101": 1}}},
+		{Latency: &stuber.FaultLatency{Percentiles: map[string]types.Duration{"p50": 2, "p99": 1}}},
+	}
+	for _, f := range invalid {
+		require.ErrorIs(t, Validate(f), ErrInvalidFaults)
+	}
+}
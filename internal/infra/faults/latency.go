@@ -0,0 +1,177 @@
+package faults
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc/codes"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// ErrInvalidFaults is returned for a fault section that cannot be applied.
+var ErrInvalidFaults = errors.New("invalid faults")
+
+type percentile struct {
+	at    float64
+	delay time.Duration
+}
+
+// sample draws a latency. Callers hold mu.
+func (i *Injector) sample(l *stuber.FaultLatency) time.Duration {
+	lowest, highest := time.Duration(l.Min), time.Duration(l.Max)
+
+	var d time.Duration
+
+	switch l.Kind() {
+	case stuber.LatencyNormal:
+		d = time.Duration(l.Mean) + time.Duration(i.rng.NormFloat64()*float64(l.StdDev))
+	case stuber.LatencyPercentiles:
+		d = interpolate(percentiles(l), lowest, i.rng.Float64()*100) //nolint:mnd
+	default:
+		d = lowest
+		if highest > lowest {
+			d += time.Duration(i.rng.Int64N(int64(highest - lowest)))
+		}
+	}
+
+	if highest > 0 {
+		d = min(d, highest)
+	}
+
+	return max(d, lowest, 0)
+}
+
+// interpolate reads the latency at percentile u off points sorted by
+// percentile, linearly between neighbours and from lowest at percentile 0.
+func interpolate(points []percentile, lowest time.Duration, u float64) time.Duration {
+	prev := percentile{delay: lowest}
+
+	for _, p := range points {
+		if u <= p.at {
+			span := p.at - prev.at
+			if span <= 0 {
+				return p.delay
+			}
+
+			return prev.delay + time.Duration((u-prev.at)/span*float64(p.delay-prev.delay))
+		}
+
+		prev = p
+	}
+
+	return prev.delay
+}
+
+func percentiles(l *stuber.FaultLatency) []percentile {
+	points := make([]percentile, 0, len(l.Percentiles))
+
+	for key, delay := range l.Percentiles {
+		at, err := parsePercentile(key)
+		if err != nil {
+			continue
+		}
+
+		points = append(points, percentile{at: at, delay: time.Duration(delay)})
+	}
+
+	slices.SortFunc(points, func(a, b percentile) int { return cmp.Compare(a.at, b.at) })
+
+	return points
+}
+
+// parsePercentile accepts "p99.9" and "99.9".
+func parsePercentile(key string) (float64, error) {
+	at, err := strconv.ParseFloat(strings.TrimPrefix(strings.ToLower(key), "p"), 64)
+	if err != nil || at <= 0 || at > 100 {
+		return 0, fmt.Errorf("%w: percentile %q must be within (0, 100]", ErrInvalidFaults, key)
+	}
+
+	return at, nil
+}
+
+// Validate reports why f cannot be applied.
+//
+//nolint:cyclop
+func Validate(f *stuber.Faults) error {
+	if f == nil {
+		return nil
+	}
+
+	if err := probability("reset", &f.Reset); err != nil {
+		return err
+	}
+
+	if err := probability("drop", &f.Drop); err != nil {
+		return err
+	}
+
+	if e := f.Error; e != nil {
+		if err := probability("error.probability", e.Probability); err != nil {
+			return err
+		}
+
+		if e.Code == codes.OK || e.Code > codes.Unauthenticated {
+			return fmt.Errorf("%w: error.code must be a non-OK gRPC code", ErrInvalidFaults)
+		}
+	}
+
+	if f.Latency != nil {
+		return validateLatency(f.Latency)
+	}
+
+	return nil
+}
+
+func validateLatency(l *stuber.FaultLatency) error {
+	if err := probability("latency.probability", l.Probability); err != nil {
+		return err
+	}
+
+	if l.Min < 0 || l.Max < 0 || l.Mean < 0 || l.StdDev < 0 {
+		return fmt.Errorf("%w: latency durations must not be negative", ErrInvalidFaults)
+	}
+
+	if l.Max > 0 && l.Max < l.Min {
+		return fmt.Errorf("%w: latency.max must not be below latency.min", ErrInvalidFaults)
+	}
+
+	switch l.Kind() {
+	case stuber.LatencyUniform, stuber.LatencyNormal:
+		return nil
+	case stuber.LatencyPercentiles:
+		if len(l.Percentiles) == 0 {
+			return fmt.Errorf("%w: latency.percentiles is required for the percentiles distribution", ErrInvalidFaults)
+		}
+
+		for key := range l.Percentiles {
+			if _, err := parsePercentile(key); err != nil {
+				return err
+			}
+		}
+
+		points := percentiles(l)
+		for n := 1; n < len(points); n++ {
+			if points[n].delay < points[n-1].delay {
+				return fmt.Errorf("%w: latency.percentiles must not decrease", ErrInvalidFaults)
+			}
+		}
+
+		return nil
+	default:
+		return fmt.Errorf("%w: unknown latency distribution %q", ErrInvalidFaults, l.Distribution)
+	}
+}
+
+func probability(name string, p *float64) error {
+	if p != nil && (*p < 0 || *p > 1) {
+		return fmt.Errorf("%w: %s must be within [0, 1]", ErrInvalidFaults, name)
+	}
+
+	return nil
+}
@@ -0,0 +1,334 @@
+// Package jsonpatch applies JSON patches (RFC 6902) and merge patches
+// (RFC 7396) to documents decoded into maps, lists and scalars.
+package jsonpatch
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+)
+
+var (
+	// ErrInvalidPatch is returned for an operation that is malformed or does
+	// not apply to the document.
+	ErrInvalidPatch = errors.New("invalid JSON patch")
+	// ErrTestFailed is returned when a test operation does not hold.
+	ErrTestFailed = errors.New("JSON patch test failed")
+)
+
+// Operation is one JSON patch operation: add, remove, replace, move, copy or
+// test. Path and From are JSON pointers; "-" as the last token of an add
+// path appends to a list.
+type Operation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path"`
+	From  string `json:"from,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// Validate checks that every operation is known and carries well-formed
+// pointers, without a document to apply them to.
+func Validate(ops []Operation) error {
+	for i, op := range ops {
+		if err := op.validate(); err != nil {
+			return errors.Wrapf(err, "operation %d", i)
+		}
+	}
+
+	return nil
+}
+
+// Apply applies ops to doc in order and returns the patched document. doc
+// may be modified in place; values taken from ops are copied.
+func Apply(doc any, ops []Operation) (any, error) {
+	for i, op := range ops {
+		var err error
+
+		doc, err = op.apply(doc)
+		if err != nil {
+			return nil, errors.Wrapf(err, "operation %d (%s %s)", i, op.Op, op.Path)
+		}
+	}
+
+	return doc, nil
+}
+
+// Merge applies a merge patch to target and returns the result: objects are
+// merged key by key, a null member removes the key, any other value replaces
+// the target. target may be modified in place; values taken from patch are
+// copied.
+func Merge(target, patch any) any {
+	patchMap, ok := patch.(map[string]any)
+	if !ok {
+		return clone(patch)
+	}
+
+	targetMap, ok := target.(map[string]any)
+	if !ok {
+		targetMap = make(map[string]any, len(patchMap))
+	}
+
+	for key, value := range patchMap {
+		if value == nil {
+			delete(targetMap, key)
+
+			continue
+		}
+
+		targetMap[key] = Merge(targetMap[key], value)
+	}
+
+	return targetMap
+}
+
+func (op Operation) validate() error {
+	if _, err := parsePointer(op.Path); err != nil {
+		return err
+	}
+
+	switch op.Op {
+	case "add", "remove", "replace", "test":
+		return nil
+	case "move", "copy":
+		_, err := parsePointer(op.From)
+
+		return err
+	default:
+		return errors.Wrapf(ErrInvalidPatch, "unknown op %q", op.Op)
+	}
+}
+
+func (op Operation) apply(doc any) (any, error) {
+	if err := op.validate(); err != nil {
+		return nil, err
+	}
+
+	path, _ := parsePointer(op.Path)
+
+	switch op.Op {
+	case "add":
+		return edit(doc, path, clone(op.Value), addMember)
+	case "remove":
+		return edit(doc, path, nil, removeMember)
+	case "replace":
+		return edit(doc, path, clone(op.Value), replaceMember)
+	case "test":
+		value, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+
+		if !equal(value, op.Value) {
+			return nil, ErrTestFailed
+		}
+
+		return doc, nil
+	}
+
+	from, _ := parsePointer(op.From)
+
+	value, err := get(doc, from)
+	if err != nil {
+		return nil, err
+	}
+
+	if op.Op == "move" {
+		if len(path) > len(from) && slices.Equal(path[:len(from)], from) {
+			return nil, errors.Wrap(ErrInvalidPatch, "cannot move a value into itself")
+		}
+
+		if doc, err = edit(doc, from, nil, removeMember); err != nil {
+			return nil, err
+		}
+	} else {
+		value = clone(value)
+	}
+
+	return edit(doc, path, value, addMember)
+}
+
+// member edits the member key of container and returns the container.
+type member func(container any, key string, value any) (any, error)
+
+// edit applies change to the container that the last token of path names a
+// member of, and returns the document with that container in place.
+func edit(doc any, path []string, value any, change member) (any, error) {
+	// At the root add and replace swap the document, remove clears it.
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	if len(path) == 1 {
+		return change(doc, path[0], value)
+	}
+
+	child, err := get(doc, path[:1])
+	if err != nil {
+		return nil, err
+	}
+
+	child, err = edit(child, path[1:], value, change)
+	if err != nil {
+		return nil, err
+	}
+
+	return replaceMember(doc, path[0], child)
+}
+
+func addMember(container any, key string, value any) (any, error) {
+	switch node := container.(type) {
+	case map[string]any:
+		node[key] = value
+
+		return node, nil
+	case []any:
+		if key == "-" {
+			return append(node, value), nil
+		}
+
+		i, err := index(key, len(node)+1)
+		if err != nil {
+			return nil, err
+		}
+
+		node = append(node, nil)
+		copy(node[i+1:], node[i:])
+		node[i] = value
+
+		return node, nil
+	default:
+		return nil, errors.Wrapf(ErrInvalidPatch, "cannot add %q to a scalar", key)
+	}
+}
+
+func removeMember(container any, key string, _ any) (any, error) {
+	switch node := container.(type) {
+	case map[string]any:
+		if _, ok := node[key]; !ok {
+			return nil, errors.Wrapf(ErrInvalidPatch, "missing member %q", key)
+		}
+
+		delete(node, key)
+
+		return node, nil
+	case []any:
+		i, err := index(key, len(node))
+		if err != nil {
+			return nil, err
+		}
+
+		return append(node[:i], node[i+1:]...), nil
+	default:
+		return nil, errors.Wrapf(ErrInvalidPatch, "cannot remove %q from a scalar", key)
+	}
+}
+
+func replaceMember(container any, key string, value any) (any, error) {
+	switch node := container.(type) {
+	case map[string]any:
+		if _, ok := node[key]; !ok {
+			return nil, errors.Wrapf(ErrInvalidPatch, "missing member %q", key)
+		}
+
+		node[key] = value
+
+		return node, nil
+	case []any:
+		i, err := index(key, len(node))
+		if err != nil {
+			return nil, err
+		}
+
+		node[i] = value
+
+		return node, nil
+	default:
+		return nil, errors.Wrapf(ErrInvalidPatch, "cannot replace %q in a scalar", key)
+	}
+}
+
+func get(doc any, path []string) (any, error) {
+	for _, key := range path {
+		switch node := doc.(type) {
+		case map[string]any:
+			value, ok := node[key]
+			if !ok {
+				return nil, errors.Wrapf(ErrInvalidPatch, "missing member %q", key)
+			}
+
+			doc = value
+		case []any:
+			i, err := index(key, len(node))
+			if err != nil {
+				return nil, err
+			}
+
+			doc = node[i]
+		default:
+			return nil, errors.Wrapf(ErrInvalidPatch, "cannot read %q from a scalar", key)
+		}
+	}
+
+	return doc, nil
+}
+
+// index parses a list index below limit.
+func index(key string, limit int) (int, error) {
+	i, err := strconv.Atoi(key)
+	if err != nil || i < 0 || i >= limit || (len(key) > 1 && key[0] == '0') {
+		return 0, errors.Wrapf(ErrInvalidPatch, "bad list index %q", key)
+	}
+
+	return i, nil
+}
+
+// parsePointer splits a JSON pointer into its unescaped tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Wrapf(ErrInvalidPatch, "pointer %q does not start with /", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(token, "~1", "/"), "~0", "~")
+	}
+
+	return tokens, nil
+}
+
+// equal compares two values by their JSON encoding, so numbers decoded as
+// float64 and as json.Number compare equal.
+func equal(a, b any) bool {
+	encodedA, errA := json.Marshal(a)
+	encodedB, errB := json.Marshal(b)
+
+	return errA == nil && errB == nil && string(encodedA) == string(encodedB)
+}
+
+func clone(value any) any {
+	switch node := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(node))
+		for key, item := range node {
+			out[key] = clone(item)
+		}
+
+		return out
+	case []any:
+		out := make([]any, len(node))
+		for i, item := range node {
+			out[i] = clone(item)
+		}
+
+		return out
+	default:
+		return value
+	}
+}
@@ -0,0 +1,91 @@
+package jsonpatch_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/jsonpatch"
+)
+
+func decode(t *testing.T, raw string) any {
+	t.Helper()
+
+	var out any
+	require.NoError(t, json.Unmarshal([]byte(raw), &out))
+
+	return out
+}
+
+func TestApply(t *testing.T) {
+	t.Parallel()
+
+	value := map[string]any{"sku": "Z"}
+
+	got, err := jsonpatch.Apply(decode(t, `{
+		"status": "PAID",
+		"items": [{"sku": "X"}, {"sku": "Y"}],
+		"meta": {"a/b": 1, "n": 2}
+	}`), []jsonpatch.Operation{
+		{Op: "test", Path: "/status", Value: "PAID"},
+		{Op: "replace", Path: "/status", Value: "CANCELLED"},
+		{Op: "add", Path: "/items/-", Value: value},
+		{Op: "add", Path: "/items/0", Value: map[string]any{"sku": "W"}},
+		{Op: "remove", Path: "/items/1"},
+		{Op: "move", From: "/meta/a~1b", Path: "/moved"},
+		{Op: "copy", From: "/items/2", Path: "/first"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, decode(t, `{
+		"status": "CANCELLED",
+		"items": [{"sku": "W"}, {"sku": "Y"}, {"sku": "Z"}],
+		"meta": {"n": 2},
+		"moved": 1,
+		"first": {"sku": "Z"}
+	}`), got)
+
+	got.(map[string]any)["first"].(map[string]any)["sku"] = "changed" //nolint:forcetypeassert
+	require.Equal(t, "Z", value["sku"], "patch values are copied")
+
+	got, err = jsonpatch.Apply(decode(t, `{"items": [1]}`), []jsonpatch.Operation{{Op: "replace", Path: "", Value: []any{}}})
+	require.NoError(t, err)
+	require.Equal(t, []any{}, got)
+}
+
+func TestApplyErrors(t *testing.T) {
+	t.Parallel()
+
+	for name, op := range map[string]jsonpatch.Operation{
+		"unknown op":      {Op: "merge", Path: "/a"},
+		"bad pointer":     {Op: "remove", Path: "a"},
+		"missing member":  {Op: "replace", Path: "/missing", Value: 1},
+		"index too large": {Op: "add", Path: "/items/5", Value: 1},
+		"leading zero":    {Op: "remove", Path: "/items/01"},
+		"into itself":     {Op: "move", From: "/items", Path: "/items/0"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+
+			_, err := jsonpatch.Apply(decode(t, `{"items": [1, 2]}`), []jsonpatch.Operation{op})
+			require.ErrorIs(t, err, jsonpatch.ErrInvalidPatch)
+		})
+	}
+
+	_, err := jsonpatch.Apply(decode(t, `{"n": 1}`), []jsonpatch.Operation{{Op: "test", Path: "/n", Value: json.Number("2")}})
+	require.ErrorIs(t, err, jsonpatch.ErrTestFailed)
+
+	require.ErrorIs(t, jsonpatch.Validate([]jsonpatch.Operation{{Op: "copy", From: "x", Path: "/y"}}), jsonpatch.ErrInvalidPatch)
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+
+	got := jsonpatch.Merge(
+		decode(t, `{"status": "PAID", "items": [1, 2], "meta": {"a": 1, "b": 2}}`),
+		decode(t, `{"items": [], "meta": {"a": null, "c": 3}, "note": "x"}`),
+	)
+	require.Equal(t, decode(t, `{"status": "PAID", "items": [], "meta": {"b": 2, "c": 3}, "note": "x"}`), got)
+
+	require.Equal(t, map[string]any{"a": 1.0}, jsonpatch.Merge(nil, map[string]any{"a": 1.0}))
+}
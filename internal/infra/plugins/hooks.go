@@ -0,0 +1,98 @@
+package plugins
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/cockroachdb/errors"
+
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+)
+
+// ErrHookPanic is returned when a hook panics.
+var ErrHookPanic = errors.New("hook panicked")
+
+//nolint:gochecknoglobals
+var lifecycleHooks = []string{
+	pkgplugins.HookBeforeMatch,
+	pkgplugins.HookAfterMatch,
+	pkgplugins.HookBeforeResponse,
+	pkgplugins.HookOnMiss,
+	pkgplugins.HookOnStubUpsert,
+}
+
+// Hooks runs the lifecycle hooks registered by plugins. Hooks are resolved
+// once, so build it after plugins are loaded. A nil *Hooks runs nothing.
+type Hooks struct {
+	groups map[string][]pkgplugins.Func
+}
+
+// NewHooks collects the lifecycle hooks of reg; it returns nil when there are
+// none, which keeps the request path free of any hook work.
+func NewHooks(reg pkgplugins.Registry) *Hooks {
+	if reg == nil {
+		return nil
+	}
+
+	groups := make(map[string][]pkgplugins.Func)
+
+	for _, group := range lifecycleHooks {
+		if funcs := reg.Hooks(group); len(funcs) > 0 {
+			groups[group] = funcs
+		}
+	}
+
+	if len(groups) == 0 {
+		return nil
+	}
+
+	return &Hooks{groups: groups}
+}
+
+// Has reports whether any hook is registered for group.
+func (h *Hooks) Has(group string) bool {
+	return h != nil && len(h.groups[group]) > 0
+}
+
+// Run passes event through the hooks of its group in order; each hook sees
+// the changes of the previous one. The first error stops the chain and is
+// returned as is. A hook may return nil to keep the event unchanged.
+func (h *Hooks) Run(ctx context.Context, event *pkgplugins.HookEvent) (*pkgplugins.HookEvent, error) {
+	if !h.Has(event.Hook) {
+		return event, nil
+	}
+
+	group := event.Hook
+
+	for _, fn := range h.groups[group] {
+		result, err := callHook(ctx, fn, event)
+		if err != nil {
+			return event, err
+		}
+
+		if result == nil {
+			continue
+		}
+
+		next, err := pkgplugins.AsHookEvent(result)
+		if err != nil {
+			return event, errors.Wrapf(err, "%s hook result", group)
+		}
+
+		next.Hook = group
+		event = next
+	}
+
+	return event, nil
+}
+
+//nolint:nonamedreturns
+func callHook(ctx context.Context, fn pkgplugins.Func, event *pkgplugins.HookEvent) (result any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%w: %s: %v", ErrHookPanic, event.Hook, r)
+		}
+	}()
+
+	return fn(ctx, event)
+}
@@ -0,0 +1,90 @@
+package plugins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+)
+
+func TestNewHooksWithoutHooks(t *testing.T) {
+	t.Parallel()
+
+	reg := NewRegistry()
+	RegisterBuiltins(reg)
+
+	hooks := NewHooks(reg)
+	require.Nil(t, hooks)
+	require.False(t, hooks.Has(pkgplugins.HookBeforeMatch))
+
+	event := &pkgplugins.HookEvent{Hook: pkgplugins.HookBeforeMatch}
+	got, err := hooks.Run(t.Context(), event)
+	require.NoError(t, err)
+	require.Same(t, event, got)
+}
+
+func TestHooksRunInPluginOrder(t *testing.T) {
+	t.Parallel()
+
+	appendStep := func(step string) pkgplugins.Func {
+		return pkgplugins.HookFunc(func(_ context.Context, event *pkgplugins.HookEvent) error {
+			steps, _ := event.Headers["steps"].(string)
+			event.Headers["steps"] = steps + step
+
+			return nil
+		})
+	}
+
+	reg := NewRegistry()
+	reg.AddPlugin(pkgplugins.PluginInfo{Name: "first"}, []pkgplugins.SpecProvider{pkgplugins.Specs(
+		pkgplugins.FuncSpec{Name: "a", Group: pkgplugins.HookBeforeMatch, Fn: appendStep("a")},
+	)})
+	reg.AddPlugin(pkgplugins.PluginInfo{Name: "second"}, []pkgplugins.SpecProvider{pkgplugins.Specs(
+		pkgplugins.FuncSpec{Name: "b", Group: pkgplugins.HookBeforeMatch, Fn: appendStep("b")},
+		// A process plugin hands the event back in its JSON form.
+		pkgplugins.FuncSpec{Name: "c", Group: pkgplugins.HookBeforeMatch, Fn: func(_ context.Context, args ...any) (any, error) {
+			event, _ := args[0].(*pkgplugins.HookEvent)
+
+			return map[string]any{"headers": map[string]any{"steps": event.Headers["steps"].(string) + "c"}}, nil
+		}},
+		pkgplugins.FuncSpec{Name: "d", Group: pkgplugins.HookBeforeMatch, Fn: func(context.Context, ...any) (any, error) {
+			return nil, nil //nolint:nilnil
+		}},
+	)})
+
+	hooks := NewHooks(reg)
+	require.True(t, hooks.Has(pkgplugins.HookBeforeMatch))
+	require.False(t, hooks.Has(pkgplugins.HookOnMiss))
+
+	got, err := hooks.Run(t.Context(), &pkgplugins.HookEvent{
+		Hook:    pkgplugins.HookBeforeMatch,
+		Headers: map[string]any{},
+	})
+	require.NoError(t, err)
+	require.Equal(t, pkgplugins.HookBeforeMatch, got.Hook)
+	require.Equal(t, "abc", got.Headers["steps"])
+}
+
+func TestHooksStopOnError(t *testing.T) {
+	t.Parallel()
+
+	called := false
+
+	reg := NewRegistry()
+	reg.AddPlugin(pkgplugins.PluginInfo{Name: "p"}, []pkgplugins.SpecProvider{pkgplugins.Specs(
+		pkgplugins.FuncSpec{Name: "boom", Group: pkgplugins.HookOnMiss, Fn: func(context.Context, ...any) (any, error) {
+			panic("boom")
+		}},
+		pkgplugins.FuncSpec{Name: "after", Group: pkgplugins.HookOnMiss, Fn: func(context.Context, ...any) (any, error) {
+			called = true
+
+			return nil, nil //nolint:nilnil
+		}},
+	)})
+
+	_, err := NewHooks(reg).Run(t.Context(), &pkgplugins.HookEvent{Hook: pkgplugins.HookOnMiss})
+	require.ErrorIs(t, err, ErrHookPanic)
+	require.False(t, called)
+}
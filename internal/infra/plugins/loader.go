@@ -2,7 +2,9 @@ package plugins
 
 import (
 	"context"
+	"errors"
 	"os"
+	"os/exec"
 	"path/filepath"
 	"plugin"
 	"strings"
@@ -10,10 +12,12 @@ import (
 	"github.com/rs/zerolog"
 
 	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+	"github.com/bavix/gripmock/v3/pkg/plugins/pluginrpc"
 )
 
 type Loader struct {
-	paths []string
+	paths     []string
+	processes []*pluginrpc.Process
 }
 
 func NewLoader(paths []string) *Loader {
@@ -38,6 +42,12 @@ func (l *Loader) Load(ctx context.Context, reg pkgplugins.Registry) {
 			continue
 		}
 
+		if isProcessPlugin(p, stat) {
+			l.startProcess(ctx, reg, p)
+
+			continue
+		}
+
 		lp, err := plugin.Open(p)
 		if err != nil {
 			if logger != nil {
@@ -87,6 +97,50 @@ func (l *Loader) Load(ctx context.Context, reg pkgplugins.Registry) {
 	}
 }
 
+// Close stops the process plugins started by Load.
+func (l *Loader) Close() error {
+	errs := make([]error, 0, len(l.processes))
+	for _, p := range l.processes {
+		errs = append(errs, p.Close())
+	}
+
+	l.processes = nil
+
+	return errors.Join(errs...)
+}
+
+// isProcessPlugin tells a process plugin from a Go one: anything executable
+// that is not a .so file runs as a subprocess.
+func isProcessPlugin(path string, stat os.FileInfo) bool {
+	return filepath.Ext(path) != ".so" && stat.Mode().Perm()&0o111 != 0
+}
+
+func (l *Loader) startProcess(ctx context.Context, reg pkgplugins.Registry, path string) {
+	logger := zerolog.Ctx(ctx)
+
+	proc, err := pluginrpc.Start(ctx, exec.Command(path)) //nolint:gosec,noctx
+	if err != nil {
+		if logger != nil {
+			logger.Warn().Str("path", path).Err(err).Msg("plugin load skip")
+		}
+
+		return
+	}
+
+	l.processes = append(l.processes, proc)
+
+	info := proc.Info()
+	if info.Name == "" {
+		info.Name = filepath.Base(path)
+	}
+
+	info.Source = path
+	info.Kind = "process"
+	info.Capabilities = []string{"template-funcs"}
+
+	reg.AddPlugin(info, []pkgplugins.SpecProvider{pkgplugins.Specs(proc.Specs()...)})
+}
+
 func (l *Loader) expandPaths() []string {
 	paths := make([]string, 0, len(l.paths))
 	for _, p := range l.paths {
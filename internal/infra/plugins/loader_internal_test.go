@@ -0,0 +1,84 @@
+package plugins
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+	"github.com/bavix/gripmock/v3/pkg/plugins/pluginrpc"
+)
+
+const processPluginEnv = "GRIPMOCK_TEST_PROCESS_PLUGIN"
+
+// TestMain lets the loader start this test binary as a process plugin.
+func TestMain(m *testing.M) {
+	if os.Getenv(processPluginEnv) == "1" {
+		err := pluginrpc.Serve(pkgplugins.PluginInfo{Name: "shout", Version: "v1.0.0"},
+			pkgplugins.FuncSpec{Name: "shout", Fn: strings.ToUpper, Group: "text"},
+		)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+//nolint:paralleltest
+func TestLoaderStartsProcessPlugin(t *testing.T) {
+	// Arrange
+	t.Setenv(processPluginEnv, "1")
+
+	reg := NewRegistry()
+	loader := NewLoader([]string{os.Args[0]})
+
+	// Act
+	loader.Load(t.Context(), reg)
+	t.Cleanup(func() { _ = loader.Close() })
+
+	// Assert
+	var info pkgplugins.PluginInfo
+
+	for _, p := range reg.Plugins(t.Context()) {
+		if p.Name == "shout" {
+			info = p
+		}
+	}
+
+	require.Equal(t, "process", info.Kind)
+	require.Equal(t, os.Args[0], info.Source)
+	require.Equal(t, "v1.0.0", info.Version)
+
+	fn, ok := reg.Funcs()["shout"].(pkgplugins.Func)
+	require.True(t, ok)
+
+	got, err := fn(context.Background(), "hi")
+	require.NoError(t, err)
+	require.Equal(t, "HI", got)
+
+	require.NoError(t, loader.Close())
+}
+
+func TestLoaderSkipsNonExecutable(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	path := t.TempDir() + "/notes.txt"
+	require.NoError(t, os.WriteFile(path, []byte("hello"), 0o600))
+
+	reg := NewRegistry()
+	loader := NewLoader([]string{path})
+
+	// Act
+	loader.Load(t.Context(), reg)
+
+	// Assert
+	require.Empty(t, loader.processes)
+	require.Empty(t, reg.Plugins(t.Context()))
+}
@@ -135,7 +135,7 @@ func (r *Registry) Groups(ctx context.Context) []pkgplugins.PluginWithFuncs {
 	return result
 }
 
-// Hooks returns functions filtered by Group.
+// Hooks returns functions filtered by Group, in plugin load order.
 func (r *Registry) Hooks(group string) []pkgplugins.Func {
 	r.mu.RLock()
 	defer r.mu.RUnlock()
@@ -146,9 +146,10 @@ func (r *Registry) Hooks(group string) []pkgplugins.Func {
 
 	funcs := make([]pkgplugins.Func, 0)
 
-	for _, infoList := range r.pluginFuncs {
-		for _, f := range infoList {
-			if f.Group != group {
+	order, _ := r.sortedPluginOrder()
+	for _, name := range order {
+		for _, f := range r.pluginFuncs[name] {
+			if f.Group != group || f.Deactivated {
 				continue
 			}
 
@@ -13,12 +13,14 @@ import (
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 )
 
-func MessageToMap(message proto.Message) map[string]any {
+// MessageToMap encodes message as a JSON object, resolving the types packed in
+// google.protobuf.Any fields through resolver.
+func MessageToMap(message proto.Message, resolver *protoset.TypeResolver) map[string]any {
 	if message == nil {
 		return nil
 	}
 
-	encoded, err := protoset.GlobalTypeResolver().Marshal(message)
+	encoded, err := resolver.Marshal(message)
 	if err != nil {
 		return nil
 	}
@@ -38,12 +40,12 @@ func MessageToMap(message proto.Message) map[string]any {
 // shape. For well-known types whose JSON encoding is a primitive (e.g. a
 // google.protobuf.Timestamp becomes an RFC3339 string) the returned value is
 // that scalar wrapped in any; for regular messages it is a map[string]any.
-func MessageToAny(message proto.Message) any {
+func MessageToAny(message proto.Message, resolver *protoset.TypeResolver) any {
 	if message == nil {
 		return nil
 	}
 
-	encoded, err := protoset.GlobalTypeResolver().Marshal(message)
+	encoded, err := resolver.Marshal(message)
 	if err != nil {
 		return nil
 	}
@@ -287,7 +289,7 @@ func statusDetailsToMaps(callErr error) []map[string]any {
 			continue
 		}
 
-		mapped := MessageToMap(msg)
+		mapped := MessageToMap(msg, protoset.GlobalTypeResolver())
 		if mapped == nil {
 			continue
 		}
@@ -8,7 +8,15 @@ import (
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
-
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/descriptorpb"
+	"google.golang.org/protobuf/types/dynamicpb"
+	"google.golang.org/protobuf/types/known/anypb"
+
+	"github.com/bavix/gripmock/v3/internal/infra/protoset"
 	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 )
@@ -157,3 +165,44 @@ func TestBuildClientStreamStubKeepsMessageOrder(t *testing.T) {
 	require.Equal(t, map[string]any{"n": 2}, stub.Inputs[1].Equals)
 	require.Equal(t, map[string]any{"total": 3}, stub.Output.Data)
 }
+
+func TestMessageToMapResolvesRuntimeAnyTypes(t *testing.T) {
+	t.Parallel()
+
+	file, err := protodesc.NewFile(&descriptorpb.FileDescriptorProto{
+		Name:    proto.String("runtime/item.proto"),
+		Package: proto.String("runtime"),
+		Syntax:  proto.String("proto3"),
+		MessageType: []*descriptorpb.DescriptorProto{{
+			Name: proto.String("Item"),
+			Field: []*descriptorpb.FieldDescriptorProto{{
+				Name:     proto.String("name"),
+				JsonName: proto.String("name"),
+				Number:   proto.Int32(1),
+				Label:    descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+				Type:     descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(),
+			}},
+		}},
+	}, nil)
+	require.NoError(t, err)
+
+	files := new(protoregistry.Files)
+	require.NoError(t, files.RegisterFile(file))
+
+	item := dynamicpb.NewMessage(file.Messages().ByName("Item"))
+	item.Set(file.Messages().ByName("Item").Fields().ByName("name"), protoreflect.ValueOfString("widget"))
+
+	packed, err := anypb.New(item)
+	require.NoError(t, err)
+
+	require.Equal(t, map[string]any{
+		"@type": "type.googleapis.com/runtime.Item",
+		"name":  "widget",
+	}, proxycapture.MessageToMap(packed, protoset.NewTypeResolver(files)))
+	require.Equal(t, map[string]any{
+		"@type": "type.googleapis.com/runtime.Item",
+		"name":  "widget",
+	}, proxycapture.MessageToAny(packed, protoset.NewTypeResolver(files)))
+	require.Nil(t, proxycapture.MessageToMap(packed, protoset.GlobalTypeResolver()),
+		"the type is only known to the server's descriptors")
+}
@@ -0,0 +1,184 @@
+package proxycapture
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/goccy/go-yaml"
+	"github.com/google/uuid"
+
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+const (
+	captureDirPerm  = 0o750
+	captureFilePerm = 0o644
+)
+
+// ErrCaptureDirRequired is returned when a DirWriter is opened without a directory.
+var ErrCaptureDirRequired = errors.New("capture directory is required")
+
+// stableIDNamespace seeds the name-based IDs of captured stubs.
+//
+//nolint:gochecknoglobals
+var stableIDNamespace = uuid.MustParse("6f1c4f8e-5a43-4c55-9d0e-3b8f2a9c7d10")
+
+// StableID derives the ID of a captured stub from what it matches and what it
+// returns, so the same exchange captured twice (or in another run) gets the
+// same ID. The recorded delay is left out: it differs on every call.
+func StableID(stub *stuber.Stub) uuid.UUID {
+	key := *stub
+	key.ID = uuid.Nil
+	key.Used = false
+	key.Output.Delay = ""
+
+	raw, err := json.Marshal(&key)
+	if err != nil {
+		return uuid.New()
+	}
+
+	return uuid.NewSHA1(stableIDNamespace, raw)
+}
+
+// DirWriter writes captured stubs to a directory in the layout of
+// `gripmock dump`: one file per service method holding a list of stubs, which
+// the stub loader reads as is. A stub identical to one already in its file is
+// not written again; files written by an earlier run are extended, not replaced.
+type DirWriter struct {
+	mu     sync.Mutex
+	dir    string
+	format string
+	files  map[string]*capturedFile
+}
+
+type capturedFile struct {
+	stubs []*stuber.Stub
+	ids   map[uuid.UUID]struct{}
+}
+
+// NewDirWriter opens dir for captured stubs in the given dump format (yaml or
+// json), creating the directory when it does not exist.
+func NewDirWriter(dir, format string) (*DirWriter, error) {
+	if dir == "" {
+		return nil, ErrCaptureDirRequired
+	}
+
+	if err := stuber.ValidateDumpFormat(format); err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(dir, captureDirPerm); err != nil {
+		return nil, errors.Wrap(err, "failed to create capture directory")
+	}
+
+	return &DirWriter{dir: dir, format: format, files: make(map[string]*capturedFile)}, nil
+}
+
+// Write gives the stub its stable ID and adds it to the file of its method.
+// It reports false when the file already held the same stub.
+func (w *DirWriter) Write(stub *stuber.Stub) (bool, error) {
+	stub.ID = StableID(stub)
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	key := stuber.DumpFileKey(stub)
+
+	file, err := w.file(key)
+	if err != nil {
+		return false, err
+	}
+
+	if _, ok := file.ids[stub.ID]; ok {
+		return false, nil
+	}
+
+	stubs := append(file.stubs[:len(file.stubs):len(file.stubs)], stub)
+	if err := w.flush(key, stubs); err != nil {
+		return false, err
+	}
+
+	file.stubs = stubs
+	file.ids[stub.ID] = struct{}{}
+
+	return true, nil
+}
+
+// file returns the stubs already in the file of key, reading the file the
+// first time the key is seen.
+func (w *DirWriter) file(key string) (*capturedFile, error) {
+	if file, ok := w.files[key]; ok {
+		return file, nil
+	}
+
+	stubs, err := w.read(w.path(key))
+	if err != nil {
+		return nil, err
+	}
+
+	file := &capturedFile{stubs: stubs, ids: make(map[uuid.UUID]struct{}, len(stubs))}
+	for _, stub := range stubs {
+		file.ids[stub.ID] = struct{}{}
+	}
+
+	w.files[key] = file
+
+	return file, nil
+}
+
+func (w *DirWriter) read(path string) ([]*stuber.Stub, error) {
+	raw, err := os.ReadFile(path) //nolint:gosec
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read captured stubs %s", path)
+	}
+
+	if len(bytes.TrimSpace(raw)) == 0 {
+		return nil, nil
+	}
+
+	raw, err = yaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode captured stubs %s", path)
+	}
+
+	var stubs []*stuber.Stub
+	if err := jsondecoder.UnmarshalSlice(raw, &stubs); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode captured stubs %s", path)
+	}
+
+	for _, stub := range stubs {
+		if stub.Source == "" {
+			stub.Source = stuber.SourceProxy
+		}
+
+		if stub.ID == uuid.Nil {
+			stub.ID = StableID(stub)
+		}
+	}
+
+	return stubs, nil
+}
+
+// flush replaces the file of key atomically, so the stub loader never reads a
+// half-written file.
+func (w *DirWriter) flush(key string, stubs []*stuber.Stub) error {
+	err := stuber.WriteFileAtomic(w.path(key), captureFilePerm, func(out io.Writer) error {
+		return stuber.WriteDump(out, stubs, w.format)
+	})
+
+	return errors.Wrapf(err, "failed to write captured stubs %s", w.path(key))
+}
+
+func (w *DirWriter) path(key string) string {
+	return filepath.Join(w.dir, key+"."+w.format)
+}
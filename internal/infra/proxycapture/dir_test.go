@@ -0,0 +1,105 @@
+package proxycapture_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/goccy/go-yaml"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+	"github.com/bavix/gripmock/v3/internal/infra/types"
+)
+
+func capturedOrder(id string) *stuber.Stub {
+	return proxycapture.BuildUnaryStub(
+		"shop.Orders", "Get", "",
+		map[string]any{"id": id}, nil,
+		map[string]any{"id": id, "status": "paid"},
+		proxycapture.ResponseMetadata{}, nil,
+	)
+}
+
+func readCaptured(t *testing.T, path string) []*stuber.Stub {
+	t.Helper()
+
+	raw, err := os.ReadFile(path) //nolint:gosec
+	require.NoError(t, err)
+
+	raw, err = yaml.YAMLToJSON(raw)
+	require.NoError(t, err)
+
+	var stubs []*stuber.Stub
+	require.NoError(t, jsondecoder.UnmarshalSlice(raw, &stubs))
+
+	return stubs
+}
+
+func TestDirWriterDeduplicatesExchanges(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writer, err := proxycapture.NewDirWriter(dir, stuber.DumpFormatYAML)
+	require.NoError(t, err)
+
+	first := capturedOrder("1")
+	written, err := writer.Write(first)
+	require.NoError(t, err)
+	require.True(t, written)
+
+	again := capturedOrder("1")
+	again.Output.Delay = types.Delay("15ms")
+	written, err = writer.Write(again)
+	require.NoError(t, err)
+	require.False(t, written, "the recorded delay is not part of the exchange")
+	require.Equal(t, first.ID, again.ID)
+
+	written, err = writer.Write(capturedOrder("2"))
+	require.NoError(t, err)
+	require.True(t, written)
+
+	stubs := readCaptured(t, filepath.Join(dir, "shop_Orders_Get.yaml"))
+	require.Len(t, stubs, 2)
+	require.Equal(t, first.ID, stubs[0].ID)
+	require.Equal(t, map[string]any{"id": "1"}, stubs[0].Input.Equals)
+}
+
+func TestDirWriterExtendsEarlierRun(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	writer, err := proxycapture.NewDirWriter(dir, stuber.DumpFormatJSON)
+	require.NoError(t, err)
+
+	_, err = writer.Write(capturedOrder("1"))
+	require.NoError(t, err)
+
+	// A new run sees the file left behind and keeps its stubs.
+	writer, err = proxycapture.NewDirWriter(dir, stuber.DumpFormatJSON)
+	require.NoError(t, err)
+
+	written, err := writer.Write(capturedOrder("1"))
+	require.NoError(t, err)
+	require.False(t, written)
+
+	written, err = writer.Write(capturedOrder("2"))
+	require.NoError(t, err)
+	require.True(t, written)
+
+	require.Len(t, readCaptured(t, filepath.Join(dir, "shop_Orders_Get.json")), 2)
+}
+
+func TestNewDirWriterErrors(t *testing.T) {
+	t.Parallel()
+
+	_, err := proxycapture.NewDirWriter("", stuber.DumpFormatYAML)
+	require.ErrorIs(t, err, proxycapture.ErrCaptureDirRequired)
+
+	_, err = proxycapture.NewDirWriter(t.TempDir(), "xml")
+	require.ErrorIs(t, err, stuber.ErrUnknownDumpFormat)
+}
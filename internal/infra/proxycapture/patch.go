@@ -0,0 +1,97 @@
+package proxycapture
+
+import (
+	"maps"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+
+	"github.com/bavix/gripmock/v3/internal/infra/jsonpatch"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// PatchUpstream builds the response of a stub that forwards to the upstream.
+// The upstream call is converted the way BuildUnaryStub captures it, then the
+// stub's headers and trailers are laid over the upstream's and its code, error
+// and details, when set, replace the upstream status. When the result is OK
+// the stub's patch and merge patch are applied to the data, starting from an
+// empty message if the upstream failed. The stub's other fields are kept;
+// stub.Upstream.Merge is expected to be rendered already.
+func PatchUpstream(stub stuber.Output, response any, responseMeta ResponseMetadata, callErr error) (stuber.Output, error) {
+	output := stub
+	output.Upstream = nil
+	output.Data = response
+	output.Headers = overlay(responseMeta.Headers, stub.Headers)
+	output.Trailers = overlay(responseMeta.Trailers, stub.Trailers)
+	output.Code, output.Error, output.Details = nil, "", nil
+
+	applyStatusError(&output, callErr, true)
+
+	if stub.Code != nil {
+		output.Code = stub.Code
+	}
+
+	if stub.Error != "" {
+		output.Error = stub.Error
+	}
+
+	if len(stub.Details) > 0 {
+		output.Details = stub.Details
+	}
+
+	if !succeeds(output) {
+		output.Data = nil
+
+		return output, nil
+	}
+
+	output.Error, output.Details = "", nil
+
+	if output.Data == nil {
+		output.Data = map[string]any{}
+	}
+
+	if stub.Upstream == nil {
+		return output, nil
+	}
+
+	data, err := jsonpatch.Apply(output.Data, stub.Upstream.Patch)
+	if err != nil {
+		return stuber.Output{}, err //nolint:wrapcheck
+	}
+
+	if len(stub.Upstream.Merge) > 0 {
+		data = jsonpatch.Merge(data, stub.Upstream.Merge)
+	}
+
+	output.Data = data
+
+	return output, nil
+}
+
+// succeeds reports whether output answers with an OK status: no error, or an
+// explicit OK code.
+func succeeds(output stuber.Output) bool {
+	if output.Code != nil {
+		return *output.Code == codes.OK
+	}
+
+	return output.Error == ""
+}
+
+// overlay lays over on top of base. Metadata keys are case-insensitive and
+// arrive lowercased from the upstream, so over's keys are lowercased too.
+func overlay(base, over map[string]string) map[string]string {
+	if len(over) == 0 {
+		return base
+	}
+
+	out := make(map[string]string, len(base)+len(over))
+	maps.Copy(out, base)
+
+	for key, value := range over {
+		out[strings.ToLower(key)] = value
+	}
+
+	return out
+}
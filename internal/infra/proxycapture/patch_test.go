@@ -0,0 +1,80 @@
+package proxycapture_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/bavix/gripmock/v3/internal/infra/jsonpatch"
+	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func TestPatchUpstreamEditsTheResponse(t *testing.T) {
+	t.Parallel()
+
+	stub := stuber.Output{
+		Headers: map[string]string{"X-Mock": "yes"},
+		Upstream: &stuber.UpstreamPatch{
+			Patch: []jsonpatch.Operation{{Op: "replace", Path: "/status", Value: "PAID"}},
+			Merge: map[string]any{"total": nil, "note": "patched"},
+		},
+	}
+
+	output, err := proxycapture.PatchUpstream(stub,
+		map[string]any{"id": "1", "status": "NEW", "total": "10"},
+		proxycapture.ResponseMetadata{
+			Headers:  map[string]string{"x-mock": "no", "x-upstream": "1"},
+			Trailers: map[string]string{"x-trace": "t"},
+		},
+		nil,
+	)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"id": "1", "status": "PAID", "note": "patched"}, output.Data)
+	require.Equal(t, map[string]string{"x-mock": "yes", "x-upstream": "1"}, output.Headers)
+	require.Equal(t, map[string]string{"x-trace": "t"}, output.Trailers)
+	require.Nil(t, output.Code)
+	require.Nil(t, output.Upstream)
+}
+
+func TestPatchUpstreamRewritesTheStatus(t *testing.T) {
+	t.Parallel()
+
+	ok, unavailable := codes.OK, codes.Unavailable
+	callErr := status.Error(codes.NotFound, "no such order")
+
+	// The upstream status passes through when the stub sets none.
+	output, err := proxycapture.PatchUpstream(stuber.Output{Upstream: &stuber.UpstreamPatch{}}, nil,
+		proxycapture.ResponseMetadata{}, callErr)
+	require.NoError(t, err)
+	require.Equal(t, codes.NotFound, *output.Code)
+	require.Equal(t, "no such order", output.Error)
+	require.Nil(t, output.Data)
+
+	// A failure turned into OK is patched from an empty message.
+	output, err = proxycapture.PatchUpstream(stuber.Output{
+		Code:     &ok,
+		Upstream: &stuber.UpstreamPatch{Merge: map[string]any{"id": "1"}},
+	}, nil, proxycapture.ResponseMetadata{}, callErr)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"id": "1"}, output.Data)
+	require.Empty(t, output.Error)
+
+	// A success turned into a failure drops the data.
+	output, err = proxycapture.PatchUpstream(stuber.Output{
+		Code:     &unavailable,
+		Error:    "maintenance",
+		Upstream: &stuber.UpstreamPatch{Merge: map[string]any{"id": "1"}},
+	}, map[string]any{"id": "2"}, proxycapture.ResponseMetadata{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, codes.Unavailable, *output.Code)
+	require.Equal(t, "maintenance", output.Error)
+	require.Nil(t, output.Data)
+
+	_, err = proxycapture.PatchUpstream(stuber.Output{Upstream: &stuber.UpstreamPatch{
+		Patch: []jsonpatch.Operation{{Op: "test", Path: "/id", Value: "1"}},
+	}}, map[string]any{"id": "2"}, proxycapture.ResponseMetadata{}, nil)
+	require.ErrorIs(t, err, jsonpatch.ErrTestFailed)
+}
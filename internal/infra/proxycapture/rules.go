@@ -0,0 +1,348 @@
+package proxycapture
+
+import (
+	"os"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/cockroachdb/errors"
+	"github.com/goccy/go-yaml"
+
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// ErrInvalidCaptureRule is returned for a capture rule that cannot be applied.
+var ErrInvalidCaptureRule = errors.New("invalid capture rule")
+
+// Rule rewrites the stubs captured for a service method before they are
+// stored, so values that change on every call (timestamps, random IDs,
+// idempotency keys) do not pin the stub to the one request it was captured
+// from. Service and Method select the calls; empty matches every one.
+//
+// Field paths are dot-separated keys into the request (Drop, Matches, Glob) or
+// the response (Template), e.g. "meta.requestId".
+type Rule struct {
+	Service string `json:"service,omitempty"`
+	Method  string `json:"method,omitempty"`
+
+	// Drop removes request fields from the input matcher.
+	Drop []string `json:"drop,omitempty"`
+	// Matches and Glob replace the captured value of a request field with a
+	// regular expression or a glob pattern.
+	Matches map[string]string `json:"matches,omitempty"`
+	Glob    map[string]string `json:"glob,omitempty"`
+	// Contains matches the captured request as a subset instead of exactly.
+	Contains bool `json:"contains,omitempty"`
+	// DropHeaders removes request headers from the header matcher.
+	DropHeaders []string `json:"dropHeaders,omitempty"`
+	// Template replaces response fields with templates evaluated on replay,
+	// e.g. {"requestId": "{{.Request.requestId}}"} to echo the request ID.
+	Template map[string]string `json:"template,omitempty"`
+}
+
+// Rules is an ordered list of capture rules; every rule selecting a call is
+// applied, in order.
+type Rules []Rule
+
+// LoadRules reads capture rules from a YAML or JSON file holding a list of rules.
+func LoadRules(file string) (Rules, error) {
+	raw, err := os.ReadFile(file) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read capture rules %s", file)
+	}
+
+	raw, err = yaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode capture rules %s", file)
+	}
+
+	var rules Rules
+	if err := jsondecoder.UnmarshalSlice(raw, &rules); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode capture rules %s", file)
+	}
+
+	if err := rules.Validate(); err != nil {
+		return nil, errors.Wrapf(err, "capture rules %s", file)
+	}
+
+	return rules, nil
+}
+
+// Validate checks that every pattern of every rule compiles.
+func (r Rules) Validate() error {
+	for i, rule := range r {
+		for field, pattern := range rule.Matches {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return errors.Wrapf(ErrInvalidCaptureRule, "rule %d: matches %s: %v", i, field, err)
+			}
+		}
+
+		for field, pattern := range rule.Glob {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return errors.Wrapf(ErrInvalidCaptureRule, "rule %d: glob %s: %v", i, field, err)
+			}
+		}
+	}
+
+	return nil
+}
+
+// Apply rewrites the stub with every rule selecting its service and method.
+// The request and response values are copied before they are changed: the
+// captured maps are shared with the call history.
+func (r Rules) Apply(stub *stuber.Stub) {
+	applied := false
+
+	for _, rule := range r {
+		if !rule.selects(stub) {
+			continue
+		}
+
+		if !applied {
+			detachStub(stub)
+
+			applied = true
+		}
+
+		rule.apply(stub)
+	}
+}
+
+func (rule Rule) selects(stub *stuber.Stub) bool {
+	return (rule.Service == "" || rule.Service == stub.Service) &&
+		(rule.Method == "" || rule.Method == stub.Method)
+}
+
+func (rule Rule) apply(stub *stuber.Stub) {
+	rule.applyInput(&stub.Input)
+
+	for i := range stub.Inputs {
+		rule.applyInput(&stub.Inputs[i])
+	}
+
+	for _, header := range rule.DropHeaders {
+		for key := range stub.Headers.Equals {
+			if strings.EqualFold(key, header) {
+				delete(stub.Headers.Equals, key)
+			}
+		}
+	}
+
+	if len(stub.Headers.Equals) == 0 {
+		stub.Headers.Equals = nil
+	}
+
+	rule.applyTemplate(&stub.Output)
+}
+
+func (rule Rule) applyInput(input *stuber.InputData) {
+	if rule.Contains && len(input.Equals) > 0 {
+		input.Contains = mergeFields(input.Contains, input.Equals)
+		input.Equals = nil
+	}
+
+	for _, field := range rule.Drop {
+		releaseField(input, splitFieldPath(field))
+	}
+
+	for field, pattern := range rule.Matches {
+		if keys := splitFieldPath(field); releaseField(input, keys) {
+			input.Matches = setField(input.Matches, keys, pattern)
+		}
+	}
+
+	for field, pattern := range rule.Glob {
+		if keys := splitFieldPath(field); releaseField(input, keys) {
+			input.Glob = setField(input.Glob, keys, pattern)
+		}
+	}
+
+	if len(input.Equals) == 0 {
+		input.Equals = nil
+	}
+
+	if len(input.Contains) == 0 {
+		input.Contains = nil
+	}
+}
+
+// releaseField removes a request field from the exact matchers and reports
+// whether it was there. A top-level field can simply go: equals ignores fields
+// it does not name. A nested one sits inside a value equals compares as a
+// whole, so that value moves to contains first.
+func releaseField(input *stuber.InputData, keys []string) bool {
+	if len(keys) == 0 {
+		return false
+	}
+
+	if len(keys) > 1 {
+		if value, ok := input.Equals[keys[0]]; ok {
+			input.Contains = mergeFields(input.Contains, map[string]any{keys[0]: value})
+			delete(input.Equals, keys[0])
+		}
+	}
+
+	removedEquals := deleteField(input.Equals, keys)
+	removedContains := deleteField(input.Contains, keys)
+
+	return removedEquals || removedContains
+}
+
+func (rule Rule) applyTemplate(output *stuber.Output) {
+	if len(rule.Template) == 0 {
+		return
+	}
+
+	if data, ok := output.Data.(map[string]any); ok {
+		for field, template := range rule.Template {
+			data = setField(data, splitFieldPath(field), template)
+		}
+
+		output.Data = data
+	}
+
+	for i, message := range output.Stream {
+		data, ok := message.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		for field, template := range rule.Template {
+			data = setField(data, splitFieldPath(field), template)
+		}
+
+		output.Stream[i] = data
+	}
+}
+
+// detachStub gives the stub its own copy of every value the rules may change.
+func detachStub(stub *stuber.Stub) {
+	stub.Headers.Equals = cloneFields(stub.Headers.Equals)
+	detachInput(&stub.Input)
+
+	inputs := make([]stuber.InputData, len(stub.Inputs))
+	for i, input := range stub.Inputs {
+		detachInput(&input)
+		inputs[i] = input
+	}
+
+	if stub.Inputs != nil {
+		stub.Inputs = inputs
+	}
+
+	stub.Output.Data = cloneValue(stub.Output.Data)
+
+	if stub.Output.Stream != nil {
+		stream, _ := cloneValue(stub.Output.Stream).([]any)
+		stub.Output.Stream = stream
+	}
+}
+
+func detachInput(input *stuber.InputData) {
+	input.Equals = cloneFields(input.Equals)
+	input.Contains = cloneFields(input.Contains)
+	input.Matches = cloneFields(input.Matches)
+	input.Glob = cloneFields(input.Glob)
+}
+
+func cloneFields(fields map[string]any) map[string]any {
+	if fields == nil {
+		return nil
+	}
+
+	cloned, _ := cloneValue(fields).(map[string]any)
+
+	return cloned
+}
+
+func cloneValue(value any) any {
+	switch typed := value.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(typed))
+		for key, nested := range typed {
+			out[key] = cloneValue(nested)
+		}
+
+		return out
+	case []any:
+		out := make([]any, len(typed))
+		for i, nested := range typed {
+			out[i] = cloneValue(nested)
+		}
+
+		return out
+	default:
+		return value
+	}
+}
+
+func splitFieldPath(field string) []string {
+	if field == "" {
+		return nil
+	}
+
+	return strings.Split(field, ".")
+}
+
+// mergeFields adds the fields of src to dst, merging nested objects.
+func mergeFields(dst, src map[string]any) map[string]any {
+	if dst == nil {
+		dst = make(map[string]any, len(src))
+	}
+
+	for key, value := range src {
+		nested, isMap := value.(map[string]any)
+		existing, hasMap := dst[key].(map[string]any)
+
+		if isMap && hasMap {
+			dst[key] = mergeFields(existing, nested)
+		} else {
+			dst[key] = value
+		}
+	}
+
+	return dst
+}
+
+func deleteField(fields map[string]any, keys []string) bool {
+	for len(keys) > 1 {
+		nested, ok := fields[keys[0]].(map[string]any)
+		if !ok {
+			return false
+		}
+
+		fields, keys = nested, keys[1:]
+	}
+
+	if _, ok := fields[keys[0]]; !ok {
+		return false
+	}
+
+	delete(fields, keys[0])
+
+	return true
+}
+
+// setField stores value at keys, creating the objects on the way.
+func setField(fields map[string]any, keys []string, value any) map[string]any {
+	if len(keys) == 0 {
+		return fields
+	}
+
+	if fields == nil {
+		fields = make(map[string]any)
+	}
+
+	if len(keys) == 1 {
+		fields[keys[0]] = value
+
+		return fields
+	}
+
+	nested, _ := fields[keys[0]].(map[string]any)
+	fields[keys[0]] = setField(nested, keys[1:], value)
+
+	return fields
+}
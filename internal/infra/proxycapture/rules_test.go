@@ -0,0 +1,119 @@
+package proxycapture_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func capturedPayment(request map[string]any) *stuber.Stub {
+	return proxycapture.BuildUnaryStub(
+		"shop.Payments", "Charge", "",
+		request,
+		map[string]any{"x-idempotency-key": "k-1", "x-tenant": "acme"},
+		map[string]any{"requestId": "r-1", "status": "ok"},
+		proxycapture.ResponseMetadata{}, nil,
+	)
+}
+
+func TestRulesReplayWithOtherVolatileValues(t *testing.T) {
+	t.Parallel()
+
+	request := map[string]any{
+		"amount":    "10",
+		"requestId": "r-1",
+		"meta":      map[string]any{"ts": "2026-10-17T10:00:00Z", "channel": "web"},
+		"card":      "4111-0000",
+	}
+	stub := capturedPayment(request)
+
+	proxycapture.Rules{
+		{Service: "shop.Orders", Drop: []string{"amount"}},
+		{
+			Service:     "shop.Payments",
+			Drop:        []string{"meta.ts"},
+			Matches:     map[string]string{"requestId": "^r-\\d+$"},
+			Glob:        map[string]string{"card": "4111-*"},
+			DropHeaders: []string{"X-Idempotency-Key"},
+			Template:    map[string]string{"requestId": "{{.Request.requestId}}"},
+		},
+	}.Apply(stub)
+
+	require.Equal(t, map[string]any{"amount": "10"}, stub.Input.Equals)
+	require.Equal(t, map[string]any{"meta": map[string]any{"channel": "web"}}, stub.Input.Contains)
+	require.Equal(t, map[string]any{"requestId": "^r-\\d+$"}, stub.Input.Matches)
+	require.Equal(t, map[string]any{"card": "4111-*"}, stub.Input.Glob)
+	require.Equal(t, map[string]any{"x-tenant": "acme"}, stub.Headers.Equals)
+	require.Equal(t, "{{.Request.requestId}}", stub.Output.Data.(map[string]any)["requestId"]) //nolint:forcetypeassert
+
+	require.Contains(t, request, "requestId", "the captured request is shared with history")
+	require.Contains(t, request["meta"], "ts")
+
+	budgerigar := stuber.NewBudgerigar()
+	budgerigar.PutMany(stub)
+
+	result, err := budgerigar.FindByQuery(stuber.Query{
+		Service: "shop.Payments",
+		Method:  "Charge",
+		Headers: map[string]any{"x-idempotency-key": "k-2", "x-tenant": "acme"},
+		Input: []map[string]any{{
+			"amount":    "10",
+			"requestId": "r-2",
+			"meta":      map[string]any{"ts": "2026-10-17T11:00:00Z", "channel": "web"},
+			"card":      "4111-9999",
+		}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Found())
+}
+
+func TestRulesContainsStreamInputs(t *testing.T) {
+	t.Parallel()
+
+	stub := proxycapture.BuildClientStreamStub(
+		"chat.Room", "Send", "",
+		[]map[string]any{{"text": "hi", "sentAt": "1"}, {"text": "bye", "sentAt": "2"}},
+		nil, map[string]any{"ok": true}, proxycapture.ResponseMetadata{}, nil,
+	)
+
+	proxycapture.Rules{{Method: "Send", Contains: true, Drop: []string{"sentAt"}}}.Apply(stub)
+
+	require.Len(t, stub.Inputs, 2)
+
+	for _, input := range stub.Inputs {
+		require.Nil(t, input.Equals)
+		require.NotContains(t, input.Contains, "sentAt")
+		require.Contains(t, input.Contains, "text")
+	}
+}
+
+func TestLoadRules(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+
+	valid := filepath.Join(dir, "rules.yaml")
+	require.NoError(t, os.WriteFile(valid, []byte(`
+- service: shop.Payments
+  drop: [meta.ts]
+  matches:
+    requestId: "^r-\\d+$"
+`), 0o600))
+
+	rules, err := proxycapture.LoadRules(valid)
+	require.NoError(t, err)
+	require.Len(t, rules, 1)
+	require.Equal(t, []string{"meta.ts"}, rules[0].Drop)
+	require.Equal(t, `^r-\d+$`, rules[0].Matches["requestId"])
+
+	invalid := filepath.Join(dir, "invalid.json")
+	require.NoError(t, os.WriteFile(invalid, []byte(`[{"matches":{"id":"("}}]`), 0o600))
+
+	_, err = proxycapture.LoadRules(invalid)
+	require.ErrorIs(t, err, proxycapture.ErrInvalidCaptureRule)
+}
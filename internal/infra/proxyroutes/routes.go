@@ -3,6 +3,9 @@ package proxyroutes
 import (
 	"context"
 	"log"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
 
 	"github.com/cockroachdb/errors"
 	"google.golang.org/grpc"
@@ -11,6 +14,7 @@ import (
 
 	protosetdom "github.com/bavix/gripmock/v3/internal/domain/protoset"
 	grpcclient "github.com/bavix/gripmock/v3/internal/infra/grpcclient"
+	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
 )
 
 var errRemoteClientNil = errors.New("remote client is not configured")
@@ -25,18 +29,31 @@ const (
 	ModeProxy Mode = iota + 1
 	ModeReplay
 	ModeCapture
+	// ModeShadow answers from stubs like ModeReplay and also sends every call
+	// a stub answered to the upstream, to compare the two responses.
+	ModeShadow
 )
 
 type Route struct {
 	Source *protosetdom.Source
 	Conn   *grpc.ClientConn
 	Mode   Mode
+	// Capture receives the stubs recorded by a capture route whose source
+	// sets captureDir; nil otherwise.
+	Capture *proxycapture.DirWriter
+	// CaptureRules normalize the stubs recorded by a capture route.
+	CaptureRules proxycapture.Rules
 }
 
 type Registry struct {
 	routes []*Route
 	index  map[string]*Route
 	files  []*descriptorpb.FileDescriptorSet
+
+	// rulesMu serializes rule loads; writers is shared with rule upstreams.
+	rulesMu sync.Mutex
+	rules   atomic.Pointer[ruleSet]
+	writers map[string]*proxycapture.DirWriter
 }
 
 // ProxyDescriptorBinding maps a proxy URL to its local descriptor sets.
@@ -87,7 +104,12 @@ func New(
 		}
 	}
 
-	return &Registry{routes: routes, index: index, files: files}, nil
+	writers := make(map[string]*proxycapture.DirWriter)
+	if err := attachCapture(routes, writers); err != nil {
+		return nil, err
+	}
+
+	return &Registry{routes: routes, index: index, files: files, writers: writers}, nil
 }
 
 // NewWithPerProxyDescriptors creates a registry with per-proxy descriptor bindings.
@@ -136,7 +158,12 @@ func NewWithPerProxyDescriptors(
 		}
 	}
 
-	return &Registry{routes: routes, index: index, files: files}, nil
+	writers := make(map[string]*proxycapture.DirWriter)
+	if err := attachCapture(routes, writers); err != nil {
+		return nil, err
+	}
+
+	return &Registry{routes: routes, index: index, files: files, writers: writers}, nil
 }
 
 func parseProxySources(paths []string) ([]*protosetdom.Source, error) {
@@ -169,6 +196,15 @@ func buildRoute(
 		return nil, nil, nil, err
 	}
 
+	route, err := dialRoute(source)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return route, fds, serviceMethods, nil
+}
+
+func dialRoute(source *protosetdom.Source) (*Route, error) {
 	conn, err := grpc.NewClient("passthrough:///"+source.ReflectAddress, grpcclient.DialOptions(
 		source.ReflectTimeout,
 		source.ReflectTLS,
@@ -177,14 +213,56 @@ func buildRoute(
 		source.ReflectInsecure,
 	)...)
 	if err != nil {
-		return nil, nil, nil, errors.Wrapf(err, "failed to connect proxy upstream: %s", source.ReflectAddress)
+		return nil, errors.Wrapf(err, "failed to connect proxy upstream: %s", source.ReflectAddress)
 	}
 
 	return &Route{
 		Mode:   mapMode(source.ProxyMode),
 		Source: source,
 		Conn:   conn,
-	}, fds, serviceMethods, nil
+	}, nil
+}
+
+// attachCapture opens the capture directories and loads the capture rules of
+// capture routes. Routes naming the same directory and format share one
+// writer, so a method captured through either of them lands in a single file.
+func attachCapture(routes []*Route, writers map[string]*proxycapture.DirWriter) error {
+	for _, route := range routes {
+		if route.Mode != ModeCapture {
+			continue
+		}
+
+		if route.Source.CaptureRules != "" {
+			rules, err := proxycapture.LoadRules(route.Source.CaptureRules)
+			if err != nil {
+				return errors.Wrapf(err, "failed to load capture rules: %s", route.Source.Raw)
+			}
+
+			route.CaptureRules = rules
+		}
+
+		if route.Source.CaptureDir == "" {
+			continue
+		}
+
+		key := filepath.Clean(route.Source.CaptureDir) + "\x00" + route.Source.CaptureFormat
+
+		writer, ok := writers[key]
+		if !ok {
+			var err error
+
+			writer, err = proxycapture.NewDirWriter(route.Source.CaptureDir, route.Source.CaptureFormat)
+			if err != nil {
+				return errors.Wrapf(err, "failed to open capture directory: %s", route.Source.Raw)
+			}
+
+			writers[key] = writer
+		}
+
+		route.Capture = writer
+	}
+
+	return nil
 }
 
 func bindServices(
@@ -230,16 +308,22 @@ func resolveServiceMethods(
 	return fds, collectServiceMethods(fds), nil
 }
 
-func (r *Registry) RouteByMethod(fullMethod string) *Route {
+// RouteByMethod returns the route serving fullMethod, nil when the call is
+// answered by stubs alone. Routing rules, when loaded, are evaluated against
+// the method and the request metadata md (nil for none) before the route the
+// method's service is bound to.
+func (r *Registry) RouteByMethod(fullMethod string, md metadata.MD) *Route {
 	if r == nil {
 		return nil
 	}
 
-	if route, ok := r.index[fullMethod]; ok {
-		return route
+	route := r.index[fullMethod]
+
+	if set := r.rules.Load(); set != nil {
+		return set.route(fullMethod, md, route)
 	}
 
-	return nil
+	return route
 }
 
 func (r *Registry) Routes() []*Route {
@@ -263,7 +347,21 @@ func (r *Registry) Close() {
 		return
 	}
 
-	for _, route := range r.routes {
+	routes := r.routes
+
+	r.rulesMu.Lock()
+	if set := r.rules.Swap(nil); set != nil {
+		for _, route := range set.upstreams {
+			routes = append(routes, route)
+		}
+	}
+	r.rulesMu.Unlock()
+
+	closeRoutes(routes)
+}
+
+func closeRoutes(routes []*Route) {
+	for _, route := range routes {
 		if route == nil || route.Conn == nil {
 			continue
 		}
@@ -315,6 +413,8 @@ func mapMode(mode string) Mode {
 		return ModeCapture
 	case "replay":
 		return ModeReplay
+	case "shadow":
+		return ModeShadow
 	default:
 		return ModeProxy
 	}
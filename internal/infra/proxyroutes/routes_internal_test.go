@@ -2,6 +2,7 @@ package proxyroutes
 
 import (
 	"context"
+	"net/url"
 	"testing"
 	"time"
 
@@ -40,8 +41,8 @@ func TestRegistryRouteByMethodNoFallback(t *testing.T) {
 		},
 	}
 
-	require.Same(t, route, r.RouteByMethod("/svc/Method"))
-	require.Nil(t, r.RouteByMethod("/svc/Unknown"))
+	require.Same(t, route, r.RouteByMethod("/svc/Method", nil))
+	require.Nil(t, r.RouteByMethod("/svc/Unknown", nil))
 }
 
 func TestNewFirstSourceWinsPerService(t *testing.T) {
@@ -70,11 +71,11 @@ func TestNewFirstSourceWinsPerService(t *testing.T) {
 	require.NoError(t, err)
 	t.Cleanup(r.Close)
 
-	require.Equal(t, ModeProxy, r.RouteByMethod("/greeter/Ping").Mode)
-	require.Equal(t, ModeProxy, r.RouteByMethod("/greeter1/Ping").Mode)
-	require.Equal(t, ModeReplay, r.RouteByMethod("/greeter2/Ping").Mode)
-	require.Equal(t, ModeCapture, r.RouteByMethod("/greeter3/Ping").Mode)
-	require.Nil(t, r.RouteByMethod("/unknown/Method"))
+	require.Equal(t, ModeProxy, r.RouteByMethod("/greeter/Ping", nil).Mode)
+	require.Equal(t, ModeProxy, r.RouteByMethod("/greeter1/Ping", nil).Mode)
+	require.Equal(t, ModeReplay, r.RouteByMethod("/greeter2/Ping", nil).Mode)
+	require.Equal(t, ModeCapture, r.RouteByMethod("/greeter3/Ping", nil).Mode)
+	require.Nil(t, r.RouteByMethod("/unknown/Method", nil))
 }
 
 func TestNewSkipsReflectionWhenLocalDescriptorsPresent(t *testing.T) {
@@ -98,8 +99,8 @@ func TestNewSkipsReflectionWhenLocalDescriptorsPresent(t *testing.T) {
 	t.Cleanup(r.Close)
 
 	require.Equal(t, 0, client.calls)
-	require.Equal(t, ModeCapture, r.RouteByMethod("/orders.OrderService/Create").Mode)
-	require.Equal(t, ModeCapture, r.RouteByMethod("/orders.OrderService/Get").Mode)
+	require.Equal(t, ModeCapture, r.RouteByMethod("/orders.OrderService/Create", nil).Mode)
+	require.Equal(t, ModeCapture, r.RouteByMethod("/orders.OrderService/Get", nil).Mode)
 }
 
 func TestNewUsesReflectionWhenLocalDescriptorsAbsent(t *testing.T) {
@@ -121,7 +122,7 @@ func TestNewUsesReflectionWhenLocalDescriptorsAbsent(t *testing.T) {
 	t.Cleanup(r.Close)
 
 	require.Equal(t, 1, client.calls)
-	require.Equal(t, ModeCapture, r.RouteByMethod("/orders.OrderService/Create").Mode)
+	require.Equal(t, ModeCapture, r.RouteByMethod("/orders.OrderService/Create", nil).Mode)
 }
 
 func TestNewStoresReflectionDescriptors(t *testing.T) {
@@ -230,11 +231,11 @@ func TestMultiProxyWithLocalDescriptorsResolvesPerSource(t *testing.T) {
 
 	require.Equal(t, 2, client.calls)
 
-	greeterRoute := r.RouteByMethod("/greeter/SayHello")
+	greeterRoute := r.RouteByMethod("/greeter/SayHello", nil)
 	require.NotNil(t, greeterRoute)
 	require.Equal(t, "upstream1:4111", greeterRoute.Source.ReflectAddress)
 
-	ordersRoute := r.RouteByMethod("/orders/CreateOrder")
+	ordersRoute := r.RouteByMethod("/orders/CreateOrder", nil)
 	require.NotNil(t, ordersRoute)
 	require.Equal(t, "upstream2:4222", ordersRoute.Source.ReflectAddress)
 }
@@ -265,11 +266,11 @@ func TestMultiProxyWithoutLocalDescriptorsUsesReflection(t *testing.T) {
 
 	require.Equal(t, 2, client.calls)
 
-	greeterRoute := r.RouteByMethod("/greeter/SayHello")
+	greeterRoute := r.RouteByMethod("/greeter/SayHello", nil)
 	require.NotNil(t, greeterRoute)
 	require.Equal(t, "upstream1:4111", greeterRoute.Source.ReflectAddress)
 
-	ordersRoute := r.RouteByMethod("/orders/CreateOrder")
+	ordersRoute := r.RouteByMethod("/orders/CreateOrder", nil)
 	require.NotNil(t, ordersRoute)
 	require.Equal(t, "upstream2:4222", ordersRoute.Source.ReflectAddress)
 }
@@ -302,10 +303,10 @@ func TestLocalDescriptorsPriorityOverReflection(t *testing.T) {
 
 	require.Equal(t, 0, client.calls)
 
-	route := r.RouteByMethod("/greeter/SayHello")
+	route := r.RouteByMethod("/greeter/SayHello", nil)
 	require.NotNil(t, route)
 
-	route = r.RouteByMethod("/greeter/ShouldNotAppear")
+	route = r.RouteByMethod("/greeter/ShouldNotAppear", nil)
 	require.Nil(t, route)
 }
 
@@ -329,7 +330,7 @@ func TestEmptyLocalDescriptorsFallbackToReflection(t *testing.T) {
 
 	require.Equal(t, 1, client.calls)
 
-	route := r.RouteByMethod("/greeter/SayHello")
+	route := r.RouteByMethod("/greeter/SayHello", nil)
 	require.NotNil(t, route)
 }
 
@@ -359,15 +360,45 @@ func TestMultiProxyDifferentModes(t *testing.T) {
 
 	require.Equal(t, 2, client.calls)
 
-	greeterRoute := r.RouteByMethod("/greeter/SayHello")
+	greeterRoute := r.RouteByMethod("/greeter/SayHello", nil)
 	require.NotNil(t, greeterRoute)
 	require.Equal(t, ModeProxy, greeterRoute.Mode)
 
-	ordersRoute := r.RouteByMethod("/orders/CreateOrder")
+	ordersRoute := r.RouteByMethod("/orders/CreateOrder", nil)
 	require.NotNil(t, ordersRoute)
 	require.Equal(t, ModeCapture, ordersRoute.Mode)
 }
 
+func TestNewSharesCaptureWriters(t *testing.T) {
+	t.Parallel()
+
+	client := &fakeRemoteClient{sets: map[string]*descriptorpb.FileDescriptorSet{
+		"upstream1:4111": buildDescriptorSet(map[string][]string{"greeter": {"SayHello"}}),
+		"upstream2:4222": buildDescriptorSet(map[string][]string{"orders": {"CreateOrder"}}),
+		"upstream3:4333": buildDescriptorSet(map[string][]string{"billing": {"Charge"}}),
+	}}
+
+	dir := url.QueryEscape(t.TempDir())
+
+	r, err := New(
+		t.Context(),
+		[]string{
+			"grpc+capture://upstream1:4111?captureDir=" + dir,
+			"grpc+capture://upstream2:4222?captureDir=" + dir,
+			"grpc+replay://upstream3:4333?captureDir=" + dir,
+		},
+		client,
+		nil,
+	)
+	require.NoError(t, err)
+	t.Cleanup(r.Close)
+
+	greeter := r.RouteByMethod("/greeter/SayHello", nil)
+	require.NotNil(t, greeter.Capture)
+	require.Same(t, greeter.Capture, r.RouteByMethod("/orders/CreateOrder", nil).Capture)
+	require.Nil(t, r.RouteByMethod("/billing/Charge", nil).Capture, "only capture routes write stubs")
+}
+
 func TestNewWithPerProxyDescriptors_EmptyBindings(t *testing.T) {
 	t.Parallel()
 
@@ -497,17 +528,17 @@ func TestNewWithPerProxyDescriptors_IsolatedDescriptors(t *testing.T) {
 	require.Equal(t, 0, client.calls)
 
 	// Each service should route to its correct proxy
-	greeterRoute := r.RouteByMethod("/greeter/SayHello")
+	greeterRoute := r.RouteByMethod("/greeter/SayHello", nil)
 	require.NotNil(t, greeterRoute)
 	require.Equal(t, "upstream1:4111", greeterRoute.Source.ReflectAddress)
 
-	ordersRoute := r.RouteByMethod("/orders/CreateOrder")
+	ordersRoute := r.RouteByMethod("/orders/CreateOrder", nil)
 	require.NotNil(t, ordersRoute)
 	require.Equal(t, "upstream2:4222", ordersRoute.Source.ReflectAddress)
 
 	// Services should not cross-contaminate
-	require.Nil(t, r.RouteByMethod("/greeter/CreateOrder"))
-	require.Nil(t, r.RouteByMethod("/orders/SayHello"))
+	require.Nil(t, r.RouteByMethod("/greeter/CreateOrder", nil))
+	require.Nil(t, r.RouteByMethod("/orders/SayHello", nil))
 }
 
 func TestNewWithPerProxyDescriptors_AllThreeModes(t *testing.T) {
@@ -536,15 +567,15 @@ func TestNewWithPerProxyDescriptors_AllThreeModes(t *testing.T) {
 	require.NoError(t, err)
 	t.Cleanup(r.Close)
 
-	greeterRoute := r.RouteByMethod("/greeter/SayHello")
+	greeterRoute := r.RouteByMethod("/greeter/SayHello", nil)
 	require.NotNil(t, greeterRoute)
 	require.Equal(t, ModeProxy, greeterRoute.Mode)
 
-	ordersRoute := r.RouteByMethod("/orders/CreateOrder")
+	ordersRoute := r.RouteByMethod("/orders/CreateOrder", nil)
 	require.NotNil(t, ordersRoute)
 	require.Equal(t, ModeCapture, ordersRoute.Mode)
 
-	paymentsRoute := r.RouteByMethod("/payments/ProcessPayment")
+	paymentsRoute := r.RouteByMethod("/payments/ProcessPayment", nil)
 	require.NotNil(t, paymentsRoute)
 	require.Equal(t, ModeReplay, paymentsRoute.Mode)
 }
@@ -572,7 +603,7 @@ func TestNewWithPerProxyDescriptors_EmptyDescriptorsUsesReflection(t *testing.T)
 
 	require.Equal(t, 1, client.calls, "should have used reflection")
 
-	route := r.RouteByMethod("/greeter/SayHello")
+	route := r.RouteByMethod("/greeter/SayHello", nil)
 	require.NotNil(t, route)
 }
 
@@ -599,7 +630,7 @@ func TestNewWithPerProxyDescriptors_NilDescriptorsUsesReflection(t *testing.T) {
 
 	require.Equal(t, 1, client.calls, "should have used reflection")
 
-	route := r.RouteByMethod("/greeter/SayHello")
+	route := r.RouteByMethod("/greeter/SayHello", nil)
 	require.NotNil(t, route)
 }
 
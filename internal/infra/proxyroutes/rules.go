@@ -0,0 +1,452 @@
+package proxyroutes
+
+import (
+	"net/url"
+	"os"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/goccy/go-yaml"
+	"google.golang.org/grpc/metadata"
+
+	protosetdom "github.com/bavix/gripmock/v3/internal/domain/protoset"
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/proxycapture"
+)
+
+var (
+	// ErrInvalidRoutingRule is returned for a routing rule that cannot be applied.
+	ErrInvalidRoutingRule = errors.New("invalid routing rule")
+	// ErrNoRoutingRules is returned when rules are reloaded before any were loaded.
+	ErrNoRoutingRules = errors.New("no routing rules file is configured")
+)
+
+// ruleModeStub is the rule mode that answers a call from stubs alone.
+const ruleModeStub = "stub"
+
+// String returns the URL scheme suffix of the mode: proxy, replay, capture or
+// shadow.
+func (m Mode) String() string {
+	switch m {
+	case ModeProxy:
+		return "proxy"
+	case ModeReplay:
+		return "replay"
+	case ModeCapture:
+		return "capture"
+	case ModeShadow:
+		return "shadow"
+	default:
+		return ""
+	}
+}
+
+// RoutingRule overrides how the calls it selects are served. Service and
+// Method are glob patterns (Go path.Match) on the full service name and the
+// method name; empty matches every one. Headers holds glob patterns on
+// request metadata values, all of which must match.
+//
+// Mode is proxy, replay, capture, shadow or stub (served by stubs alone,
+// never forwarded). Upstream is a proxy source URL (grpc+proxy://host:port?...)
+// the calls are sent to instead of the upstream their service is bound to;
+// its scheme gives the mode unless Mode is set. A rule without Upstream only
+// changes the mode of methods bound to a proxy source.
+type RoutingRule struct {
+	Service  string            `json:"service,omitempty"`
+	Method   string            `json:"method,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Mode     string            `json:"mode,omitempty"`
+	Upstream string            `json:"upstream,omitempty"`
+}
+
+// RoutingRules is the rule layer currently applied by a registry.
+type RoutingRules struct {
+	File     string
+	LoadedAt time.Time
+	// Rules are in evaluation order; upstream URLs have their bearer token
+	// removed.
+	Rules []RoutingRule
+}
+
+// Binding describes the methods a proxy source serves without rules.
+type Binding struct {
+	Address string
+	TLS     bool
+	Mode    Mode
+	Methods []string
+}
+
+type ruleSet struct {
+	file      string
+	loadedAt  time.Time
+	rules     []compiledRule
+	upstreams map[string]*Route
+	// captures holds the capture variant, with its writer, of the routes a
+	// capture rule switches from another mode.
+	captures map[*Route]*Route
+}
+
+type compiledRule struct {
+	RoutingRule
+
+	mode     Mode
+	stub     bool
+	upstream *Route
+}
+
+// LoadRules reads routing rules from a YAML or JSON file holding a list of
+// rules and applies them to every call routed from then on. The rules in
+// place are kept when the file is invalid. Upstream connections are reused
+// across loads for the same URL.
+func (r *Registry) LoadRules(file string) error {
+	rules, err := readRoutingRules(file)
+	if err != nil {
+		return err
+	}
+
+	r.rulesMu.Lock()
+	defer r.rulesMu.Unlock()
+
+	previous := r.rules.Load()
+
+	set, err := r.compileRules(file, rules, previous)
+	if err != nil {
+		return err
+	}
+
+	r.rules.Store(set)
+
+	if previous != nil {
+		var removed []*Route
+
+		for raw, route := range previous.upstreams {
+			if _, ok := set.upstreams[raw]; !ok {
+				removed = append(removed, route)
+			}
+		}
+
+		closeRoutes(removed)
+	}
+
+	return nil
+}
+
+// ReloadRules reads the routing rules file loaded last again.
+func (r *Registry) ReloadRules() error {
+	set := r.rules.Load()
+	if set == nil {
+		return ErrNoRoutingRules
+	}
+
+	return r.LoadRules(set.file)
+}
+
+// RoutingRules returns the rules in place, if any.
+func (r *Registry) RoutingRules() (RoutingRules, bool) {
+	if r == nil {
+		return RoutingRules{}, false
+	}
+
+	set := r.rules.Load()
+	if set == nil {
+		return RoutingRules{}, false
+	}
+
+	rules := make([]RoutingRule, len(set.rules))
+	for i, rule := range set.rules {
+		rules[i] = rule.RoutingRule
+		rules[i].Upstream = redactUpstream(rule.Upstream)
+	}
+
+	return RoutingRules{File: set.file, LoadedAt: set.loadedAt, Rules: rules}, true
+}
+
+// Bindings returns the proxy sources and the methods bound to them, in
+// source order.
+func (r *Registry) Bindings() []Binding {
+	if r == nil {
+		return nil
+	}
+
+	methods := make(map[*Route][]string, len(r.routes))
+	for method, route := range r.index {
+		methods[route] = append(methods[route], method)
+	}
+
+	bindings := make([]Binding, 0, len(r.routes))
+
+	for _, route := range r.routes {
+		slices.Sort(methods[route])
+
+		bindings = append(bindings, Binding{
+			Address: route.Source.ReflectAddress,
+			TLS:     route.Source.ReflectTLS,
+			Mode:    route.Mode,
+			Methods: methods[route],
+		})
+	}
+
+	return bindings
+}
+
+func readRoutingRules(file string) ([]RoutingRule, error) {
+	raw, err := os.ReadFile(file) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read routing rules %s", file)
+	}
+
+	raw, err = yaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode routing rules %s", file)
+	}
+
+	var rules []RoutingRule
+	if err := jsondecoder.UnmarshalSlice(raw, &rules); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode routing rules %s", file)
+	}
+
+	return rules, nil
+}
+
+// compileRules validates the rules and connects their upstreams, reusing the
+// connections of previous. Connections it opens are closed again on error.
+func (r *Registry) compileRules(file string, rules []RoutingRule, previous *ruleSet) (*ruleSet, error) {
+	set := &ruleSet{
+		file:      file,
+		loadedAt:  time.Now(),
+		rules:     make([]compiledRule, 0, len(rules)),
+		upstreams: make(map[string]*Route),
+		captures:  make(map[*Route]*Route),
+	}
+
+	var dialed []*Route
+
+	for i, rule := range rules {
+		compiled, err := compileRule(rule)
+		if err == nil && rule.Upstream != "" {
+			compiled.upstream, err = r.upstream(rule.Upstream, previous, set, &dialed)
+		}
+
+		if err != nil {
+			closeRoutes(dialed)
+
+			return nil, errors.Wrapf(err, "routing rules %s: rule %d", file, i)
+		}
+
+		set.rules = append(set.rules, compiled)
+	}
+
+	if err := r.compileCaptures(set); err != nil {
+		closeRoutes(dialed)
+
+		return nil, errors.Wrapf(err, "routing rules %s", file)
+	}
+
+	return set, nil
+}
+
+// compileCaptures prepares the capture variant of every route a capture rule
+// may switch to capture: its upstream, or every bound route for a rule
+// without one.
+func (r *Registry) compileCaptures(set *ruleSet) error {
+	for _, rule := range set.rules {
+		if rule.mode != ModeCapture {
+			continue
+		}
+
+		routes := r.routes
+		if rule.upstream != nil {
+			routes = []*Route{rule.upstream}
+		}
+
+		for _, route := range routes {
+			if _, ok := set.captures[route]; ok || route.Mode == ModeCapture {
+				continue
+			}
+
+			capture := *route
+			capture.Mode = ModeCapture
+
+			if err := attachCapture([]*Route{&capture}, r.captureWriters()); err != nil {
+				return err
+			}
+
+			set.captures[route] = &capture
+		}
+	}
+
+	return nil
+}
+
+// captureWriters returns the writers shared by the registry's capture routes.
+func (r *Registry) captureWriters() map[string]*proxycapture.DirWriter {
+	if r.writers == nil {
+		r.writers = make(map[string]*proxycapture.DirWriter)
+	}
+
+	return r.writers
+}
+
+func compileRule(rule RoutingRule) (compiledRule, error) {
+	compiled := compiledRule{RoutingRule: rule}
+
+	switch rule.Mode {
+	case "":
+		if rule.Upstream == "" {
+			return compiled, errors.Wrap(ErrInvalidRoutingRule, "mode or upstream is required")
+		}
+	case ruleModeStub:
+		if rule.Upstream != "" {
+			return compiled, errors.Wrap(ErrInvalidRoutingRule, "a stub rule has no upstream")
+		}
+
+		compiled.stub = true
+	case "proxy", "replay", "capture", "shadow":
+		compiled.mode = mapMode(rule.Mode)
+	default:
+		return compiled, errors.Wrapf(ErrInvalidRoutingRule, "unknown mode %q", rule.Mode)
+	}
+
+	patterns := []string{rule.Service, rule.Method}
+	for _, pattern := range rule.Headers {
+		patterns = append(patterns, pattern)
+	}
+
+	for _, pattern := range patterns {
+		if _, err := path.Match(pattern, ""); err != nil {
+			return compiled, errors.Wrapf(ErrInvalidRoutingRule, "pattern %q: %v", pattern, err)
+		}
+	}
+
+	return compiled, nil
+}
+
+// upstream returns the route of a rule upstream URL: the one already in set,
+// the one of previous, or a new connection appended to dialed.
+func (r *Registry) upstream(raw string, previous, set *ruleSet, dialed *[]*Route) (*Route, error) {
+	if route, ok := set.upstreams[raw]; ok {
+		return route, nil
+	}
+
+	var route *Route
+	if previous != nil {
+		route = previous.upstreams[raw]
+	}
+
+	if route == nil {
+		source, err := protosetdom.ParseSource(raw)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidRoutingRule, "upstream %s: %v", redactUpstream(raw), err)
+		}
+
+		if source.ProxyMode == "" {
+			return nil, errors.Wrapf(ErrInvalidRoutingRule, "upstream %s is not a proxy source", redactUpstream(raw))
+		}
+
+		route, err = dialRoute(source)
+		if err != nil {
+			return nil, err
+		}
+
+		*dialed = append(*dialed, route)
+
+		if err := attachCapture([]*Route{route}, r.captureWriters()); err != nil {
+			return nil, err
+		}
+	}
+
+	set.upstreams[raw] = route
+
+	return route, nil
+}
+
+// route returns the route of the first rule selecting the call, bound when
+// none does.
+func (s *ruleSet) route(fullMethod string, md metadata.MD, bound *Route) *Route {
+	service, method := splitFullMethod(fullMethod)
+
+	for _, rule := range s.rules {
+		if !rule.selects(service, method, md) {
+			continue
+		}
+
+		if rule.stub {
+			return nil
+		}
+
+		route := bound
+		if rule.upstream != nil {
+			route = rule.upstream
+		}
+
+		if route == nil || rule.mode == 0 || rule.mode == route.Mode {
+			return route
+		}
+
+		if capture, ok := s.captures[route]; ok && rule.mode == ModeCapture {
+			return capture
+		}
+
+		routed := *route
+		routed.Mode = rule.mode
+
+		return &routed
+	}
+
+	return bound
+}
+
+func (rule compiledRule) selects(service, method string, md metadata.MD) bool {
+	if !globMatch(rule.Service, service) || !globMatch(rule.Method, method) {
+		return false
+	}
+
+	for key, pattern := range rule.Headers {
+		if !slices.ContainsFunc(md.Get(key), func(value string) bool {
+			matched, _ := path.Match(pattern, value)
+
+			return matched
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func globMatch(pattern, value string) bool {
+	if pattern == "" {
+		return true
+	}
+
+	matched, _ := path.Match(pattern, value)
+
+	return matched
+}
+
+func splitFullMethod(fullMethod string) (string, string) {
+	service, method, _ := strings.Cut(strings.TrimPrefix(fullMethod, "/"), "/")
+
+	return service, method
+}
+
+// redactUpstream removes the bearer token from an upstream URL.
+func redactUpstream(raw string) string {
+	parsed, err := url.Parse(raw)
+	if err != nil {
+		return raw
+	}
+
+	query := parsed.Query()
+	if !query.Has("bearer") {
+		return raw
+	}
+
+	query.Set("bearer", "REDACTED")
+	parsed.RawQuery = query.Encode()
+
+	return parsed.String()
+}
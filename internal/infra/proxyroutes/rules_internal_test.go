@@ -0,0 +1,152 @@
+package proxyroutes
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+
+	protosetdom "github.com/bavix/gripmock/v3/internal/domain/protoset"
+)
+
+func writeRules(t *testing.T, file, content string) {
+	t.Helper()
+
+	require.NoError(t, os.WriteFile(file, []byte(content), 0o600))
+}
+
+func boundRegistry() (*Registry, *Route) {
+	route := &Route{Mode: ModeProxy, Source: &protosetdom.Source{ReflectAddress: "prod:50051"}}
+
+	return &Registry{
+		routes: []*Route{route},
+		index: map[string]*Route{
+			"/auth.Auth/Login":      route,
+			"/shop.Orders/Get":      route,
+			"/shop.Orders/List":     route,
+			"/shop.Payments/Charge": route,
+			"/shop.Payments/Refund": route,
+		},
+	}, route
+}
+
+func TestRoutingRulesOverrideBoundRoutes(t *testing.T) {
+	t.Parallel()
+
+	r, bound := boundRegistry()
+	t.Cleanup(r.Close)
+
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	writeRules(t, file, `
+- headers: {x-env: staging}
+  upstream: grpc+replay://staging:50051?bearer=secret
+- service: shop.Orders
+  method: List
+  mode: capture
+- service: shop.Payments
+  mode: stub
+`)
+	require.NoError(t, r.LoadRules(file))
+
+	require.Nil(t, r.RouteByMethod("/shop.Payments/Charge", nil))
+	require.Same(t, bound, r.RouteByMethod("/shop.Orders/Get", nil))
+
+	list := r.RouteByMethod("/shop.Orders/List", nil)
+	require.Equal(t, ModeCapture, list.Mode)
+	require.Same(t, bound.Conn, list.Conn)
+	require.Equal(t, ModeProxy, bound.Mode, "the bound route is not changed")
+
+	staging := r.RouteByMethod("/shop.Payments/Refund", metadata.Pairs("X-Env", "staging"))
+	require.NotNil(t, staging)
+	require.Equal(t, ModeReplay, staging.Mode)
+	require.Equal(t, "staging:50051", staging.Source.ReflectAddress)
+
+	rules, ok := r.RoutingRules()
+	require.True(t, ok)
+	require.Equal(t, file, rules.File)
+	require.Len(t, rules.Rules, 3)
+	require.NotContains(t, rules.Rules[0].Upstream, "secret")
+
+	require.Equal(t, []Binding{{
+		Address: "prod:50051",
+		Mode:    ModeProxy,
+		Methods: []string{
+			"/auth.Auth/Login",
+			"/shop.Orders/Get",
+			"/shop.Orders/List",
+			"/shop.Payments/Charge",
+			"/shop.Payments/Refund",
+		},
+	}}, r.Bindings())
+}
+
+func TestReloadRules(t *testing.T) {
+	t.Parallel()
+
+	r, bound := boundRegistry()
+	t.Cleanup(r.Close)
+
+	require.ErrorIs(t, r.ReloadRules(), ErrNoRoutingRules)
+
+	file := filepath.Join(t.TempDir(), "rules.json")
+	writeRules(t, file, `[{"service":"auth.*","upstream":"grpc+proxy://auth:50051"}]`)
+	require.NoError(t, r.LoadRules(file))
+
+	auth := r.RouteByMethod("/auth.Auth/Login", nil)
+	require.NotSame(t, bound, auth)
+
+	writeRules(t, file, `[{"service":"auth.*","mode":"mirror"}]`)
+	require.ErrorIs(t, r.ReloadRules(), ErrInvalidRoutingRule)
+	require.Same(t, auth, r.RouteByMethod("/auth.Auth/Login", nil), "an invalid file keeps the rules in place")
+
+	writeRules(t, file, `[
+		{"service":"auth.*","mode":"capture","upstream":"grpc+proxy://auth:50051"},
+		{"method":"Get","mode":"replay"}
+	]`)
+	require.NoError(t, r.ReloadRules())
+
+	reloaded := r.RouteByMethod("/auth.Auth/Login", nil)
+	require.Equal(t, ModeCapture, reloaded.Mode)
+	require.Same(t, auth.Conn, reloaded.Conn, "the upstream connection is reused")
+	require.Equal(t, ModeReplay, r.RouteByMethod("/shop.Orders/Get", nil).Mode)
+}
+
+func TestCaptureRuleWritesToTheSourceCaptureDir(t *testing.T) {
+	t.Parallel()
+
+	r, bound := boundRegistry()
+	t.Cleanup(r.Close)
+
+	bound.Mode = ModeReplay
+	bound.Source.CaptureDir = t.TempDir()
+	bound.Source.CaptureFormat = "yaml"
+
+	file := filepath.Join(t.TempDir(), "rules.yaml")
+	writeRules(t, file, `
+- service: shop.Orders
+  mode: capture
+`)
+	require.NoError(t, r.LoadRules(file))
+
+	orders := r.RouteByMethod("/shop.Orders/List", nil)
+	require.Equal(t, ModeCapture, orders.Mode)
+	require.NotNil(t, orders.Capture)
+	require.Same(t, orders, r.RouteByMethod("/shop.Orders/Get", nil), "the capture variant is built once")
+	require.Nil(t, r.RouteByMethod("/shop.Payments/Charge", nil).Capture, "only capture routes write stubs")
+}
+
+func TestCompileRuleErrors(t *testing.T) {
+	t.Parallel()
+
+	for _, rule := range []RoutingRule{
+		{Service: "shop.*"},
+		{Mode: "stub", Upstream: "grpc+proxy://auth:50051"},
+		{Method: "[", Mode: "proxy"},
+		{Headers: map[string]string{"x-env": "["}, Mode: "proxy"},
+	} {
+		_, err := compileRule(rule)
+		require.ErrorIs(t, err, ErrInvalidRoutingRule)
+	}
+}
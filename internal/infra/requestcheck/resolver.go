@@ -0,0 +1,77 @@
+package requestcheck
+
+import (
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// extensionResolver finds the predefined rules declared in the loaded
+// descriptors, which protovalidate reads as extensions of its rule messages.
+type extensionResolver struct {
+	files []Files
+}
+
+//nolint:ireturn
+func (r *extensionResolver) FindExtensionByName(field protoreflect.FullName) (protoreflect.ExtensionType, error) {
+	if xt := r.find(func(xd protoreflect.ExtensionDescriptor) bool { return xd.FullName() == field }); xt != nil {
+		return xt, nil
+	}
+
+	return protoregistry.GlobalTypes.FindExtensionByName(field) //nolint:wrapcheck
+}
+
+//nolint:ireturn
+func (r *extensionResolver) FindExtensionByNumber(
+	message protoreflect.FullName,
+	field protoreflect.FieldNumber,
+) (protoreflect.ExtensionType, error) {
+	if xt := r.find(func(xd protoreflect.ExtensionDescriptor) bool {
+		return xd.Number() == field && xd.ContainingMessage().FullName() == message
+	}); xt != nil {
+		return xt, nil
+	}
+
+	return protoregistry.GlobalTypes.FindExtensionByNumber(message, field) //nolint:wrapcheck
+}
+
+//nolint:ireturn
+func (r *extensionResolver) find(match func(protoreflect.ExtensionDescriptor) bool) protoreflect.ExtensionType {
+	var found protoreflect.ExtensionDescriptor
+
+	for _, files := range r.files {
+		files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+			found = findExtension(file.Extensions(), file.Messages(), match)
+
+			return found == nil
+		})
+
+		if found != nil {
+			return dynamicpb.NewExtensionType(found)
+		}
+	}
+
+	return nil
+}
+
+// findExtension searches extensions, then the extensions nested in messages.
+func findExtension(
+	extensions protoreflect.ExtensionDescriptors,
+	messages protoreflect.MessageDescriptors,
+	match func(protoreflect.ExtensionDescriptor) bool,
+) protoreflect.ExtensionDescriptor {
+	for i := range extensions.Len() {
+		if xd := extensions.Get(i); match(xd) {
+			return xd
+		}
+	}
+
+	for i := range messages.Len() {
+		msg := messages.Get(i)
+		if xd := findExtension(msg.Extensions(), msg.Messages(), match); xd != nil {
+			return xd
+		}
+	}
+
+	return nil
+}
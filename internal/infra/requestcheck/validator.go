@@ -0,0 +1,146 @@
+// Package requestcheck validates incoming requests against the protovalidate
+// (buf.validate) rules declared in their descriptors, using the reference
+// protovalidate-go implementation.
+package requestcheck
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"buf.build/go/protovalidate"
+	"github.com/cockroachdb/errors"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ErrInvalidRules reports rules that cannot be evaluated, such as string rules
+// on an integer field or a CEL expression that does not parse.
+var ErrInvalidRules = errors.New("invalid validation rules")
+
+// Files is a set of descriptors the validated messages come from.
+type Files interface {
+	RangeFiles(f func(protoreflect.FileDescriptor) bool)
+}
+
+// Violation is one rule a request breaks.
+type Violation struct {
+	// Field is the path of the offending value, e.g. items[0].sku; empty for
+	// message-level rules.
+	Field string `json:"field,omitempty"`
+	// Rule is the rule id, e.g. string.min_len or required.
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (v Violation) String() string {
+	if v.Field == "" {
+		return v.Message
+	}
+
+	return v.Field + ": " + v.Message
+}
+
+// Error rejects a request that breaks its validation rules.
+type Error struct {
+	Violations []Violation
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		parts[i] = violation.String()
+	}
+
+	return "validation error: " + strings.Join(parts, "; ")
+}
+
+// GRPCStatus is the INVALID_ARGUMENT status a real service answers with: the
+// violations travel as google.rpc.BadRequest field violations.
+func (e *Error) GRPCStatus() *status.Status {
+	st := status.New(codes.InvalidArgument, e.Error())
+
+	details := &errdetails.BadRequest{FieldViolations: make([]*errdetails.BadRequest_FieldViolation, len(e.Violations))}
+	for i, violation := range e.Violations {
+		details.FieldViolations[i] = &errdetails.BadRequest_FieldViolation{
+			Field:       violation.Field,
+			Description: violation.Message,
+			Reason:      violation.Rule,
+		}
+	}
+
+	detailed, err := st.WithDetails(details)
+	if err != nil {
+		return st
+	}
+
+	return detailed
+}
+
+// Validator checks messages against their buf.validate rules. The rules of a
+// message type are compiled once, on first use. A nil *Validator accepts
+// everything.
+type Validator struct {
+	validator protovalidate.Validator
+	now       func() time.Time
+}
+
+// New creates a validator. Predefined rules are extensions of the buf.validate
+// rule messages; they are resolved from files, searched in order, then from
+// the types linked into the binary.
+func New(files ...Files) (*Validator, error) {
+	validator, err := protovalidate.New(protovalidate.WithExtensionTypeResolver(&extensionResolver{files: files}))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create protovalidate validator")
+	}
+
+	return &Validator{validator: validator, now: time.Now}, nil
+}
+
+// Validate checks msg. It returns an *Error listing the violations, or an
+// error wrapping ErrInvalidRules when the rules themselves are broken.
+func (v *Validator) Validate(msg proto.Message) error {
+	if v == nil || msg == nil {
+		return nil
+	}
+
+	err := v.validator.Validate(msg, protovalidate.WithNowFunc(func() *timestamppb.Timestamp {
+		return timestamppb.New(v.now())
+	}))
+	if err == nil {
+		return nil
+	}
+
+	var validationErr *protovalidate.ValidationError
+	if errors.As(err, &validationErr) {
+		return fromValidationError(validationErr)
+	}
+
+	var (
+		compilationErr *protovalidate.CompilationError
+		runtimeErr     *protovalidate.RuntimeError
+	)
+
+	if errors.As(err, &compilationErr) || errors.As(err, &runtimeErr) {
+		return fmt.Errorf("%w: %w", ErrInvalidRules, err)
+	}
+
+	return errors.Wrap(err, "failed to validate request")
+}
+
+func fromValidationError(err *protovalidate.ValidationError) *Error {
+	violations := make([]Violation, len(err.Violations))
+	for i, violation := range err.Violations {
+		violations[i] = Violation{
+			Field:   protovalidate.FieldPathString(violation.Proto.GetField()),
+			Rule:    violation.Proto.GetRuleId(),
+			Message: violation.Proto.GetMessage(),
+		}
+	}
+
+	return &Error{Violations: violations}
+}
@@ -0,0 +1,228 @@
+package requestcheck
+
+import (
+	"testing"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+
+	"github.com/bavix/gripmock/v3/internal/pbs"
+)
+
+const shopProto = `
+syntax = "proto3";
+package shop;
+import "buf/validate/validate.proto";
+import "google/protobuf/timestamp.proto";
+enum Status { STATUS_UNSPECIFIED = 0; PAID = 1; }
+message Item {
+  string sku = 1 [(buf.validate.field).string.min_len = 3];
+  int32 qty = 2 [(buf.validate.field).int32 = {gt: 0, lte: 100}];
+}
+message Order {
+  option (buf.validate.message).cel = {
+    id: "order.window", message: "ends_at must be after starts_at",
+    expression: "!has(this.ends_at) || this.ends_at > this.starts_at"
+  };
+  string email = 1 [(buf.validate.field).string.email = true];
+  repeated Item items = 2 [(buf.validate.field).repeated.min_items = 1];
+  map<string, int32> tags = 3 [(buf.validate.field).map.values.int32.gte = 0];
+  Status status = 4 [(buf.validate.field).enum.defined_only = true];
+  google.protobuf.Timestamp starts_at = 5;
+  google.protobuf.Timestamp ends_at = 6 [(buf.validate.field).timestamp.lt_now = true];
+  optional string note = 7 [(buf.validate.field).string.max_len = 5];
+  string id = 8 [(buf.validate.field).required = true];
+  oneof payment {
+    option (buf.validate.oneof).required = true;
+    string card = 9;
+    string cash = 10;
+  }
+  repeated string codes = 11 [(buf.validate.field).repeated = {unique: true, items: {string: {pattern: "^[A-Z]+$"}}}];
+}
+message Broken { int32 n = 1 [(buf.validate.field).string.min_len = 1]; }
+`
+
+func shopMessage(t *testing.T, name protoreflect.Name) protoreflect.MessageDescriptor {
+	t.Helper()
+
+	resolver, err := pbs.NewResolver()
+	require.NoError(t, err)
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.CompositeResolver{
+			&protocompile.SourceResolver{
+				Accessor: protocompile.SourceAccessorFromMap(map[string]string{"shop.proto": shopProto}),
+			},
+			resolver,
+		},
+	}
+
+	compiled, err := compiler.Compile(t.Context(), "shop.proto")
+	require.NoError(t, err)
+
+	return compiled[0].Messages().ByName(name)
+}
+
+func order(t *testing.T, desc protoreflect.MessageDescriptor, body string) *dynamicpb.Message {
+	t.Helper()
+
+	msg := dynamicpb.NewMessage(desc)
+	require.NoError(t, protojson.Unmarshal([]byte(body), msg))
+
+	return msg
+}
+
+func TestValidateAcceptsValidRequest(t *testing.T) {
+	t.Parallel()
+
+	v, err := New()
+	require.NoError(t, err)
+
+	desc := shopMessage(t, "Order")
+
+	require.NoError(t, v.Validate(order(t, desc, `{
+		"email": "bob@example.com",
+		"items": [{"sku": "abc", "qty": 2}],
+		"tags": {"x": 1},
+		"status": "PAID",
+		"startsAt": "2020-01-01T00:00:00Z",
+		"endsAt": "2020-01-02T00:00:00Z",
+		"id": "1",
+		"card": "visa",
+		"codes": ["A", "B"]
+	}`)))
+
+	var none *Validator
+
+	require.NoError(t, none.Validate(order(t, desc, `{}`)))
+}
+
+func TestValidateReportsViolations(t *testing.T) {
+	t.Parallel()
+
+	v, err := New()
+	require.NoError(t, err)
+
+	v.now = func() time.Time { return time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC) }
+
+	err = v.Validate(order(t, shopMessage(t, "Order"), `{
+		"email": "not an email",
+		"items": [{"sku": "ab", "qty": 0}],
+		"tags": {"a": -1},
+		"status": 7,
+		"startsAt": "2022-01-02T00:00:00Z",
+		"endsAt": "2022-01-01T00:00:00Z",
+		"note": "too long",
+		"codes": ["A", "A", "b"]
+	}`))
+
+	var checkErr *Error
+	require.ErrorAs(t, err, &checkErr)
+
+	type got struct{ Field, Rule string }
+
+	violations := make([]got, len(checkErr.Violations))
+	for i, violation := range checkErr.Violations {
+		violations[i] = got{violation.Field, violation.Rule}
+	}
+
+	require.Equal(t, []got{
+		{"", "order.window"},
+		{"payment", "required"},
+		{"email", "string.email"},
+		{"items[0].sku", "string.min_len"},
+		{"items[0].qty", "int32.gt_lte"},
+		{`tags["a"]`, "int32.gte"},
+		{"status", "enum.defined_only"},
+		{"ends_at", "timestamp.lt_now"},
+		{"note", "string.max_len"},
+		{"id", "required"},
+		{"codes", "repeated.unique"},
+		{"codes[2]", "string.pattern"},
+	}, violations)
+	require.Equal(t, "must be at least 3 characters", checkErr.Violations[3].Message)
+
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Len(t, badRequest.GetFieldViolations(), len(violations))
+	require.Equal(t, "items[0].sku", badRequest.GetFieldViolations()[3].GetField())
+	require.Equal(t, "string.min_len", badRequest.GetFieldViolations()[3].GetReason())
+}
+
+func TestValidateRejectsBrokenRules(t *testing.T) {
+	t.Parallel()
+
+	v, err := New()
+	require.NoError(t, err)
+
+	msg := dynamicpb.NewMessage(shopMessage(t, "Broken"))
+
+	require.ErrorIs(t, v.Validate(msg), ErrInvalidRules)
+	require.ErrorIs(t, v.Validate(msg), ErrInvalidRules)
+}
+
+const tagProto = `
+syntax = "proto2";
+package tags;
+import "buf/validate/validate.proto";
+extend buf.validate.StringRules {
+  optional bool no_space = 1001 [(buf.validate.predefined).cel = {
+    id: "string.no_space", message: "must not contain spaces",
+    expression: "!rule || !this.contains(' ')"
+  }];
+}
+message Tag { optional string name = 1 [(buf.validate.field).string.(no_space) = true]; }
+`
+
+type fileList []protoreflect.FileDescriptor
+
+func (l fileList) RangeFiles(f func(protoreflect.FileDescriptor) bool) {
+	for _, file := range l {
+		if !f(file) {
+			return
+		}
+	}
+}
+
+func TestValidateResolvesPredefinedRulesFromFiles(t *testing.T) {
+	t.Parallel()
+
+	resolver, err := pbs.NewResolver()
+	require.NoError(t, err)
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.CompositeResolver{
+			&protocompile.SourceResolver{
+				Accessor: protocompile.SourceAccessorFromMap(map[string]string{"tags.proto": tagProto}),
+			},
+			resolver,
+		},
+	}
+
+	compiled, err := compiler.Compile(t.Context(), "tags.proto")
+	require.NoError(t, err)
+
+	msg := order(t, compiled[0].Messages().ByName("Tag"), `{"name": "two words"}`)
+
+	v, err := New(fileList{compiled[0]})
+	require.NoError(t, err)
+
+	var checkErr *Error
+	require.ErrorAs(t, v.Validate(msg), &checkErr)
+	require.Equal(t, []Violation{{Field: "name", Rule: "string.no_space", Message: "must not contain spaces"}}, checkErr.Violations)
+
+	unresolved, err := New()
+	require.NoError(t, err)
+	require.ErrorIs(t, unresolved.Validate(msg), ErrInvalidRules)
+}
@@ -0,0 +1,242 @@
+package script
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/goccy/go-json"
+	"go.starlark.net/starlark"
+)
+
+// maxDepth bounds nesting on conversion; Starlark lists and dicts may contain
+// themselves.
+const maxDepth = 256
+
+var (
+	// ErrUnsupportedValue is returned for values without a JSON-like form.
+	ErrUnsupportedValue = errors.New("unsupported value")
+	// ErrTooDeep is returned when a value nests deeper than maxDepth.
+	ErrTooDeep = errors.New("value nested too deep")
+)
+
+// ToValue converts a decoded JSON-like Go value into a Starlark value.
+// Integral numbers become ints, so request fields index and repeat as expected.
+func ToValue(v any) (starlark.Value, error) {
+	return toValue(v, 0)
+}
+
+//nolint:cyclop,funlen
+func toValue(v any, depth int) (starlark.Value, error) {
+	if depth > maxDepth {
+		return nil, ErrTooDeep
+	}
+
+	switch x := v.(type) {
+	case nil:
+		return starlark.None, nil
+	case bool:
+		return starlark.Bool(x), nil
+	case string:
+		return starlark.String(x), nil
+	case int:
+		return starlark.MakeInt(x), nil
+	case int32:
+		return starlark.MakeInt64(int64(x)), nil
+	case int64:
+		return starlark.MakeInt64(x), nil
+	case uint32:
+		return starlark.MakeUint64(uint64(x)), nil
+	case uint64:
+		return starlark.MakeUint64(x), nil
+	case float32:
+		return number(float64(x)), nil
+	case float64:
+		return number(x), nil
+	case json.Number:
+		if n, err := strconv.ParseInt(string(x), 10, 64); err == nil {
+			return starlark.MakeInt64(n), nil
+		}
+
+		f, err := x.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrUnsupportedValue, err)
+		}
+
+		return starlark.Float(f), nil
+	case time.Time:
+		return starlark.String(x.Format(time.RFC3339Nano)), nil
+	case map[string]any:
+		dict := starlark.NewDict(len(x))
+
+		for key, item := range x {
+			value, err := toValue(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			_ = dict.SetKey(starlark.String(key), value)
+		}
+
+		return dict, nil
+	case map[string]string:
+		dict := starlark.NewDict(len(x))
+		for key, item := range x {
+			_ = dict.SetKey(starlark.String(key), starlark.String(item))
+		}
+
+		return dict, nil
+	case []any:
+		items := make([]starlark.Value, len(x))
+
+		for i, item := range x {
+			value, err := toValue(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			items[i] = value
+		}
+
+		return starlark.NewList(items), nil
+	case []map[string]any:
+		items := make([]any, len(x))
+		for i, item := range x {
+			items[i] = item
+		}
+
+		return toValue(items, depth)
+	case []string:
+		items := make([]starlark.Value, len(x))
+		for i, item := range x {
+			items[i] = starlark.String(item)
+		}
+
+		return starlark.NewList(items), nil
+	default:
+		return viaJSON(v, depth)
+	}
+}
+
+func number(f float64) starlark.Value {
+	if f == math.Trunc(f) && math.Abs(f) < 1<<53 {
+		return starlark.MakeInt64(int64(f))
+	}
+
+	return starlark.Float(f)
+}
+
+func viaJSON(v any, depth int) (starlark.Value, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedValue, v)
+	}
+
+	var decoded any
+	if err := json.Unmarshal(raw, &decoded); err != nil {
+		return nil, fmt.Errorf("%w: %T", ErrUnsupportedValue, v)
+	}
+
+	return toValue(decoded, depth)
+}
+
+// FromValue converts a Starlark value back into a JSON-like Go value.
+func FromValue(v starlark.Value) (any, error) {
+	return fromValue(v, 0)
+}
+
+//nolint:cyclop
+func fromValue(v starlark.Value, depth int) (any, error) {
+	if depth > maxDepth {
+		return nil, ErrTooDeep
+	}
+
+	switch x := v.(type) {
+	case starlark.NoneType:
+		return nil, nil //nolint:nilnil
+	case starlark.Bool:
+		return bool(x), nil
+	case starlark.String:
+		return string(x), nil
+	case starlark.Int:
+		if n, ok := x.Int64(); ok {
+			return n, nil
+		}
+
+		if n, ok := x.Uint64(); ok {
+			return n, nil
+		}
+
+		return float64(x.Float()), nil
+	case starlark.Float:
+		return float64(x), nil
+	case *starlark.Dict:
+		out := make(map[string]any, x.Len())
+
+		for _, item := range x.Items() {
+			key, ok := starlark.AsString(item[0])
+			if !ok {
+				return nil, fmt.Errorf("%w: dict key %s is not a string", ErrUnsupportedValue, item[0])
+			}
+
+			value, err := fromValue(item[1], depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			out[key] = value
+		}
+
+		return out, nil
+	case starlark.Indexable:
+		return fromSequence(x, depth)
+	case *starlark.Set:
+		items := make([]any, 0, x.Len())
+		for _, item := range setItems(x) {
+			value, err := fromValue(item, depth+1)
+			if err != nil {
+				return nil, err
+			}
+
+			items = append(items, value)
+		}
+
+		return items, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedValue, v.Type())
+	}
+}
+
+// fromSequence converts lists, tuples and ranges.
+func fromSequence(seq starlark.Indexable, depth int) (any, error) {
+	out := make([]any, seq.Len())
+
+	for i := range out {
+		value, err := fromValue(seq.Index(i), depth+1)
+		if err != nil {
+			return nil, err
+		}
+
+		out[i] = value
+	}
+
+	return out, nil
+}
+
+func setItems(set *starlark.Set) []starlark.Value {
+	iter := set.Iterate()
+	defer iter.Done()
+
+	var (
+		items []starlark.Value
+		item  starlark.Value
+	)
+
+	for iter.Next(&item) {
+		items = append(items, item)
+	}
+
+	return items
+}
@@ -0,0 +1,314 @@
+// Package script runs stub output scripts: Starlark programs that compute a
+// response from the same data templates see.
+package script
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	lru "github.com/hashicorp/golang-lru/v2"
+	"go.starlark.net/lib/json"
+	"go.starlark.net/lib/math"
+	"go.starlark.net/starlark"
+	"go.starlark.net/syntax"
+	"google.golang.org/grpc/codes"
+
+	"github.com/bavix/gripmock/v3/internal/infra/template"
+)
+
+// ResponseVar is the global a script assigns its response to.
+const ResponseVar = "response"
+
+const (
+	// DefaultMaxSteps bounds the work of a single script run.
+	DefaultMaxSteps = 1_000_000
+	// DefaultTimeout bounds the wall time of a single script run.
+	DefaultTimeout = time.Second
+
+	programCacheSize = 256
+	filename         = "output.script"
+)
+
+var (
+	// ErrLimitExceeded is returned when a script runs out of steps or time.
+	ErrLimitExceeded = errors.New("script limit exceeded")
+	// ErrNoResponse is returned when a script does not assign a response dict.
+	ErrNoResponse = errors.New("script must assign a dict to " + ResponseVar)
+	// ErrInvalidResponse is returned when the response has an unexpected shape.
+	ErrInvalidResponse = errors.New("invalid script response")
+)
+
+//nolint:gochecknoglobals
+var (
+	fileOptions = &syntax.FileOptions{
+		Set:             true,
+		While:           true,
+		TopLevelControl: true,
+		GlobalReassign:  true,
+	}
+
+	// predeclared lists the names bound for every run, so compilation can
+	// tell them from undefined names without any request data.
+	predeclared = map[string]struct{}{
+		"request": {}, "requests": {}, "headers": {}, "state": {}, "messageIndex": {},
+		"requestTime": {}, "attemptNumber": {}, "maxAttempts": {}, "stubId": {},
+		"json": {}, "math": {},
+	}
+
+	fallback = sync.OnceValue(func() *Runner { return New(Limits{}) })
+)
+
+// Limits bound a script run. Zero values fall back to the defaults.
+type Limits struct {
+	MaxSteps uint64
+	Timeout  time.Duration
+}
+
+// Result is what a script answered with. Fields the script left out are nil
+// (HasData false for data) and keep the stub's values.
+type Result struct {
+	Data     any
+	HasData  bool
+	Headers  map[string]string
+	Trailers map[string]string
+	Error    *string
+	Code     *codes.Code
+}
+
+// Runner compiles and runs scripts under limits. Compiled programs are cached
+// by source. A nil *Runner runs with the default limits.
+type Runner struct {
+	limits Limits
+	cache  *lru.Cache[string, *starlark.Program]
+}
+
+// New creates a runner with the given limits.
+func New(limits Limits) *Runner {
+	if limits.MaxSteps == 0 {
+		limits.MaxSteps = DefaultMaxSteps
+	}
+
+	if limits.Timeout <= 0 {
+		limits.Timeout = DefaultTimeout
+	}
+
+	cache, _ := lru.New[string, *starlark.Program](programCacheSize)
+
+	return &Runner{limits: limits, cache: cache}
+}
+
+// Compile checks that src is a valid script.
+func Compile(src string) error {
+	_, err := compile(src)
+
+	return err
+}
+
+// Run executes src with data bound to globals named after the template data
+// fields and returns the response it assigned.
+func (r *Runner) Run(ctx context.Context, src string, data template.Data) (*Result, error) {
+	if r == nil {
+		r = fallback()
+	}
+
+	prog, err := r.program(src)
+	if err != nil {
+		return nil, err
+	}
+
+	env, err := bindings(data)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.limits.Timeout)
+	defer cancel()
+
+	thread := &starlark.Thread{Name: filename, Print: func(*starlark.Thread, string) {}}
+	thread.SetMaxExecutionSteps(r.limits.MaxSteps)
+
+	stop := context.AfterFunc(ctx, func() { thread.Cancel(context.Cause(ctx).Error()) })
+	defer stop()
+
+	globals, err := prog.Init(thread, env)
+	if err != nil {
+		if ctx.Err() != nil || thread.ExecutionSteps() >= r.limits.MaxSteps {
+			return nil, fmt.Errorf("%w: %w", ErrLimitExceeded, err)
+		}
+
+		return nil, errors.Wrap(err, "script failed")
+	}
+
+	return result(globals[ResponseVar])
+}
+
+func (r *Runner) program(src string) (*starlark.Program, error) {
+	if prog, ok := r.cache.Get(src); ok {
+		return prog, nil
+	}
+
+	prog, err := compile(src)
+	if err != nil {
+		return nil, err
+	}
+
+	r.cache.Add(src, prog)
+
+	return prog, nil
+}
+
+func compile(src string) (*starlark.Program, error) {
+	_, prog, err := starlark.SourceProgramOptions(fileOptions, filename, src, func(name string) bool {
+		_, ok := predeclared[name]
+
+		return ok
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "compile script")
+	}
+
+	return prog, nil
+}
+
+func bindings(data template.Data) (starlark.StringDict, error) {
+	env := starlark.StringDict{
+		"messageIndex":  starlark.MakeInt(data.MessageIndex),
+		"requestTime":   starlark.String(data.RequestTime.Format(time.RFC3339Nano)),
+		"attemptNumber": starlark.MakeInt(data.AttemptNumber),
+		"maxAttempts":   starlark.MakeInt(data.MaxAttempts),
+		"stubId":        starlark.String(data.StubID),
+		"json":          json.Module,
+		"math":          math.Module,
+	}
+
+	for name, value := range map[string]any{
+		"request":  data.Request,
+		"requests": data.Requests,
+		"headers":  data.Headers,
+		"state":    data.State,
+	} {
+		v, err := ToValue(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "bind %s", name)
+		}
+
+		env[name] = v
+	}
+
+	return env, nil
+}
+
+func result(v starlark.Value) (*Result, error) {
+	dict, ok := v.(*starlark.Dict)
+	if !ok {
+		return nil, ErrNoResponse
+	}
+
+	res := new(Result)
+
+	for _, item := range dict.Items() {
+		key, ok := starlark.AsString(item[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: key %s is not a string", ErrInvalidResponse, item[0])
+		}
+
+		if err := res.set(key, item[1]); err != nil {
+			return nil, err
+		}
+	}
+
+	return res, nil
+}
+
+//nolint:cyclop
+func (res *Result) set(key string, v starlark.Value) error {
+	switch key {
+	case "data":
+		data, err := FromValue(v)
+		if err != nil {
+			return fmt.Errorf("%w: data: %w", ErrInvalidResponse, err)
+		}
+
+		res.Data, res.HasData = data, true
+	case "headers", "trailers":
+		md, err := stringMap(v)
+		if err != nil {
+			return fmt.Errorf("%w: %s: %w", ErrInvalidResponse, key, err)
+		}
+
+		if key == "headers" {
+			res.Headers = md
+		} else {
+			res.Trailers = md
+		}
+	case "error":
+		msg, ok := starlark.AsString(v)
+		if !ok {
+			return fmt.Errorf("%w: error must be a string, got %s", ErrInvalidResponse, v.Type())
+		}
+
+		res.Error = &msg
+	case "code":
+		code, err := statusCode(v)
+		if err != nil {
+			return err
+		}
+
+		res.Code = &code
+	default:
+		return fmt.Errorf("%w: unknown key %q", ErrInvalidResponse, key)
+	}
+
+	return nil
+}
+
+func stringMap(v starlark.Value) (map[string]string, error) {
+	dict, ok := v.(*starlark.Dict)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected dict, got %s", ErrUnsupportedValue, v.Type())
+	}
+
+	out := make(map[string]string, dict.Len())
+
+	for _, item := range dict.Items() {
+		key, ok := starlark.AsString(item[0])
+		if !ok {
+			return nil, fmt.Errorf("%w: key %s is not a string", ErrUnsupportedValue, item[0])
+		}
+
+		if s, ok := starlark.AsString(item[1]); ok {
+			out[key] = s
+		} else {
+			out[key] = item[1].String()
+		}
+	}
+
+	return out, nil
+}
+
+// statusCode accepts a numeric gRPC code or its name, e.g. 5 or "NOT_FOUND".
+func statusCode(v starlark.Value) (codes.Code, error) {
+	var code codes.Code
+
+	switch c := v.(type) {
+	case starlark.Int:
+		n, ok := c.Int64()
+		if !ok || n < 0 || n > int64(codes.Unauthenticated) {
+			return 0, fmt.Errorf("%w: code %s is out of range", ErrInvalidResponse, c)
+		}
+
+		code = codes.Code(n)
+	case starlark.String:
+		if err := code.UnmarshalJSON([]byte(`"` + strings.ToUpper(string(c)) + `"`)); err != nil {
+			return 0, fmt.Errorf("%w: code %s", ErrInvalidResponse, c)
+		}
+	default:
+		return 0, fmt.Errorf("%w: code must be an int or a name, got %s", ErrInvalidResponse, v.Type())
+	}
+
+	return code, nil
+}
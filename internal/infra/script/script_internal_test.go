@@ -0,0 +1,126 @@
+package script
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+
+	"github.com/bavix/gripmock/v3/internal/infra/template"
+)
+
+func TestRunComputesResponse(t *testing.T) {
+	t.Parallel()
+
+	src := `
+total = 0
+for item in request["items"]:
+    total += item["price"] * item["qty"]
+
+response = {
+    "data": {"total": total, "attempt": attemptNumber, "seen": state.get("seen", False)},
+    "headers": {"x-user": headers["x-user"], "x-count": len(requests)},
+}
+`
+	res, err := New(Limits{}).Run(t.Context(), src, template.Data{
+		Request: map[string]any{"items": []any{
+			map[string]any{"price": 2.5, "qty": float64(2)},
+			map[string]any{"price": float64(1), "qty": float64(3)},
+		}},
+		Headers:       map[string]any{"x-user": "alice"},
+		Requests:      []any{map[string]any{}, map[string]any{}},
+		State:         map[string]any{"seen": true},
+		AttemptNumber: 2,
+	})
+	require.NoError(t, err)
+	require.True(t, res.HasData)
+	require.Equal(t, map[string]any{"total": 8.0, "attempt": int64(2), "seen": true}, res.Data)
+	require.Equal(t, map[string]string{"x-user": "alice", "x-count": "2"}, res.Headers)
+	require.Nil(t, res.Trailers)
+	require.Nil(t, res.Code)
+	require.Nil(t, res.Error)
+}
+
+func TestRunStatus(t *testing.T) {
+	t.Parallel()
+
+	runner := New(Limits{})
+
+	res, err := runner.Run(t.Context(), `response = {"code": "not_found", "error": "no user"}`, template.Data{})
+	require.NoError(t, err)
+	require.Equal(t, codes.NotFound, *res.Code)
+	require.Equal(t, "no user", *res.Error)
+	require.False(t, res.HasData)
+
+	res, err = runner.Run(t.Context(), `response = {"code": 14}`, template.Data{})
+	require.NoError(t, err)
+	require.Equal(t, codes.Unavailable, *res.Code)
+
+	for _, src := range []string{
+		`response = {"code": 99}`,
+		`response = {"code": "NOPE"}`,
+		`response = {"body": 1}`,
+		`response = {"data": lambda: 1}`,
+		`response = {"headers": [1]}`,
+		`response = 1`,
+		`x = 1`,
+	} {
+		_, err := runner.Run(t.Context(), src, template.Data{})
+		require.Error(t, err, src)
+	}
+}
+
+func TestRunLimits(t *testing.T) {
+	t.Parallel()
+
+	loop := "while True:\n    pass\n"
+
+	_, err := New(Limits{MaxSteps: 1000, Timeout: time.Minute}).Run(t.Context(), loop, template.Data{})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+
+	start := time.Now()
+	_, err = New(Limits{MaxSteps: 1 << 62, Timeout: 50 * time.Millisecond}).Run(t.Context(), loop, template.Data{})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+	require.Less(t, time.Since(start), 5*time.Second)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	cancel()
+
+	_, err = New(Limits{}).Run(ctx, loop, template.Data{})
+	require.ErrorIs(t, err, ErrLimitExceeded)
+}
+
+func TestCompile(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, Compile(`response = {"data": json.decode(json.encode(request))}`))
+	require.Error(t, Compile(`response = {`))
+	require.Error(t, Compile(`response = {"data": undefined_name}`))
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	in := map[string]any{
+		"int":    float64(3),
+		"float":  1.5,
+		"str":    "x",
+		"list":   []any{true, nil},
+		"nested": map[string]any{"when": time.Unix(0, 0).UTC()},
+	}
+
+	v, err := ToValue(in)
+	require.NoError(t, err)
+
+	out, err := FromValue(v)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{
+		"int":    int64(3),
+		"float":  1.5,
+		"str":    "x",
+		"list":   []any{true, nil},
+		"nested": map[string]any{"when": "1970-01-01T00:00:00Z"},
+	}, out)
+}
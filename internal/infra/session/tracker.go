@@ -1,6 +1,7 @@
 package session
 
 import (
+	"maps"
 	"slices"
 	"sync"
 	"time"
@@ -67,6 +68,28 @@ func (t *Tracker) IDs() []string {
 	return ids
 }
 
+// LastSeen returns a copy of when each tracked session was last seen.
+func (t *Tracker) LastSeen() map[string]time.Time {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	return maps.Clone(t.lastSeen)
+}
+
+// Replace forgets every tracked session and tracks the given ones instead.
+func (t *Tracker) Replace(lastSeen map[string]time.Time) {
+	next := make(map[string]time.Time, len(lastSeen))
+	for sessionID, at := range lastSeen {
+		if sessionID != "" {
+			next[sessionID] = at
+		}
+	}
+
+	t.mu.Lock()
+	t.lastSeen = next
+	t.mu.Unlock()
+}
+
 func (t *Tracker) Expired(now time.Time, ttl time.Duration) []string {
 	t.mu.RLock()
 	defer t.mu.RUnlock()
@@ -99,6 +122,16 @@ func IDs() []string {
 	return defaultTracker.IDs()
 }
 
+// LastSeen returns when each session of the default tracker was last seen.
+func LastSeen() map[string]time.Time {
+	return defaultTracker.LastSeen()
+}
+
+// Replace swaps the sessions of the default tracker for the given ones.
+func Replace(lastSeen map[string]time.Time) {
+	defaultTracker.Replace(lastSeen)
+}
+
 func Expired(now time.Time, ttl time.Duration) []string {
 	return defaultTracker.Expired(now, ttl)
 }
@@ -70,3 +70,21 @@ func TestTrackerIDs(t *testing.T) {
 	tracker.ForgetIfExpired("A", time.Now(), 0)
 	require.Equal(t, []string{"Z"}, tracker.IDs())
 }
+
+func TestTrackerReplace(t *testing.T) {
+	t.Parallel()
+
+	tracker := session.NewTracker()
+	base := time.Unix(1_000_000, 0)
+
+	tracker.Touch("old", base)
+
+	tracker.Replace(map[string]time.Time{"a": base, "": base})
+	require.Equal(t, []string{"a"}, tracker.IDs())
+
+	lastSeen := tracker.LastSeen()
+	require.Equal(t, map[string]time.Time{"a": base}, lastSeen)
+
+	lastSeen["b"] = base
+	require.Equal(t, []string{"a"}, tracker.IDs(), "LastSeen must return a copy")
+}
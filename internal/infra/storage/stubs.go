@@ -14,6 +14,7 @@ import (
 	"github.com/rs/zerolog"
 
 	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+	"github.com/bavix/gripmock/v3/internal/infra/stubcheck"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/watcher"
 	"github.com/bavix/gripmock/v3/internal/infra/yaml2json"
@@ -29,6 +30,7 @@ type Extender struct {
 	muUniqueIDs  sync.Mutex
 	uniqueIDs    map[uuid.UUID]struct{}
 	loaded       atomic.Bool
+	checker      atomic.Pointer[stubcheck.Checker]
 }
 
 func NewStub(
@@ -62,6 +64,31 @@ func (s *Extender) SignalLoaded() {
 	close(s.ch)
 }
 
+// EnableChecks validates the stubs loaded so far against their descriptors,
+// and every file reloaded afterwards. Call it once the descriptors are
+// registered; in strict mode stubs that fail are unloaded.
+func (s *Extender) EnableChecks(ctx context.Context, checker *stubcheck.Checker) {
+	s.checker.Store(checker)
+
+	s.muMapIDs.Lock()
+	defer s.muMapIDs.Unlock()
+
+	for filePath, ids := range s.mapIDsByFile {
+		rejected := make(uuid.UUIDs, 0)
+
+		for _, id := range ids {
+			if stub := s.storage.FindByID(id); stub != nil && !s.accept(ctx, filePath, checker, stub) {
+				rejected = append(rejected, id)
+			}
+		}
+
+		if len(rejected) > 0 {
+			s.storage.DeleteByID(rejected...)
+			s.mapIDsByFile[filePath] = withoutIDs(ids, rejected)
+		}
+	}
+}
+
 // ReadFromPathSync loads stubs from the given path synchronously.
 func (s *Extender) ReadFromPathSync(ctx context.Context, pathDir string) {
 	if pathDir == "" {
@@ -177,6 +204,12 @@ func (s *Extender) readByFile(ctx context.Context, rawPath string) {
 		return
 	}
 
+	if checker := s.checker.Load(); checker != nil {
+		stubs = slices.DeleteFunc(stubs, func(stub *stuber.Stub) bool {
+			return !s.accept(ctx, filePath, checker, stub)
+		})
+	}
+
 	s.checkUniqIDs(ctx, filePath, stubs)
 
 	s.muMapIDs.Lock()
@@ -192,6 +225,20 @@ func (s *Extender) readByFile(ctx context.Context, rawPath string) {
 	s.handleExistingFileUpdate(filePath, stubs, existingIDs)
 }
 
+// accept validates stub against its descriptors, logging a rejection.
+func (s *Extender) accept(ctx context.Context, filePath string, checker *stubcheck.Checker, stub *stuber.Stub) bool {
+	if err := checker.Validate(stub); err != nil {
+		zerolog.Ctx(ctx).
+			Err(err).
+			Str("file", filePath).
+			Msg("stub rejected")
+
+		return false
+	}
+
+	return true
+}
+
 func (s *Extender) handleFileReadError(ctx context.Context, filePath string, err error) {
 	zerolog.Ctx(ctx).
 		Err(err).
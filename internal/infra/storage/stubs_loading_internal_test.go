@@ -6,10 +6,13 @@ import (
 	"path/filepath"
 	"testing"
 
+	"github.com/bufbuild/protocompile"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoregistry"
 
+	"github.com/bavix/gripmock/v3/internal/infra/stubcheck"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 	"github.com/bavix/gripmock/v3/internal/infra/yaml2json"
 	"github.com/bavix/gripmock/v3/pkg/plugintest"
@@ -199,3 +202,91 @@ func TestLoaderTracksAFileByOneKeyHoweverItIsSpelled(t *testing.T) {
 	require.Len(t, budgerigar.All(), 1,
 		"the loader and the watcher reach the same file by different routes")
 }
+
+func TestLoaderUnloadsStubsFailingStrictChecks(t *testing.T) {
+	t.Parallel()
+
+	compiler := protocompile.Compiler{
+		Resolver: &protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{"svc.proto": `
+syntax = "proto3";
+package svc;
+message Req { string id = 1; }
+message Resp { bool ok = 1; }
+service Service { rpc Method(Req) returns (Resp); rpc Other(Req) returns (Resp); }
+`}),
+		},
+	}
+
+	compiled, err := compiler.Compile(t.Context(), "svc.proto")
+	require.NoError(t, err)
+
+	files := new(protoregistry.Files)
+	require.NoError(t, files.RegisterFile(compiled[0]))
+
+	dir := t.TempDir()
+	writeStubFile(t, dir, "one.json", jsonStub)
+	path := writeStubFile(t, dir, "two.json", `{
+	"service": "svc.Service",
+	"method": "Other",
+	"input": {"equals": {"idd": "2"}},
+	"output": {"data": {"ok": true}}
+}`)
+
+	loader, budgerigar := newLoader(t)
+	loader.readFromPath(t.Context(), dir)
+	require.Len(t, budgerigar.All(), 2, "stubs load before descriptors are known")
+
+	loader.EnableChecks(t.Context(), stubcheck.New(nil, true, files))
+	require.Len(t, budgerigar.All(), 1)
+	require.Equal(t, "Method", budgerigar.All()[0].Method)
+
+	writeStubFile(t, dir, "two.json", `{
+	"service": "svc.Service",
+	"method": "Other",
+	"input": {"equals": {"id": "2"}},
+	"output": {"data": {"ok": "yes"}}
+}`)
+	loader.readByFile(t.Context(), path)
+	require.Len(t, budgerigar.All(), 1, "a reload is checked too")
+
+	writeStubFile(t, dir, "two.json", `{
+	"service": "svc.Service",
+	"method": "Other",
+	"input": {"equals": {"id": "2"}},
+	"output": {"data": {"ok": true}}
+}`)
+	loader.readByFile(t.Context(), path)
+	require.Len(t, budgerigar.All(), 2)
+}
+
+func TestLoaderUnloadsStubsWithInvalidExpressions(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	writeStubFile(t, dir, "one.json", jsonStub)
+	writeStubFile(t, dir, "cel.yaml", `
+service: unknown.Service
+method: Method
+input:
+  cel: request.amount >
+output:
+  data: {ok: true}
+`)
+	writeStubFile(t, dir, "where.yaml", `
+service: unknown.Service
+method: Method
+input:
+  where:
+    amount: {gt: soon}
+output:
+  data: {ok: true}
+`)
+
+	loader, budgerigar := newLoader(t)
+	loader.readFromPath(t.Context(), dir)
+	require.Len(t, budgerigar.All(), 3)
+
+	loader.EnableChecks(t.Context(), stubcheck.New(nil, true))
+	require.Len(t, budgerigar.All(), 1, "expressions are checked without descriptors")
+}
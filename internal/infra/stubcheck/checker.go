@@ -0,0 +1,178 @@
+// Package stubcheck validates stub payloads against the protobuf descriptors
+// of the method they mock.
+package stubcheck
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// Files is a set of descriptors the checker resolves methods from.
+type Files interface {
+	RangeFiles(f func(protoreflect.FileDescriptor) bool)
+}
+
+// Issue is one place where a stub disagrees with its descriptors.
+type Issue struct {
+	// Path is the JSON path of the offending value, e.g. $.input.equals.user.id.
+	Path    string `json:"path"`
+	Message string `json:"message"`
+}
+
+func (i Issue) String() string {
+	return i.Path + ": " + i.Message
+}
+
+// Error rejects a stub that disagrees with its descriptors.
+type Error struct {
+	Service string
+	Method  string
+	Issues  []Issue
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Issues))
+	for n, issue := range e.Issues {
+		parts[n] = issue.String()
+	}
+
+	return fmt.Sprintf("stub for %s/%s does not match its descriptors: %s", e.Service, e.Method, strings.Join(parts, "; "))
+}
+
+// Checker validates stubs against the descriptors of the methods they mock.
+// In lenient mode it only logs what it finds; in strict mode the stub is
+// rejected. CEL expressions and field conditions are always checked; the
+// payloads of stubs for methods it cannot resolve pass unchecked, since their
+// descriptors may be registered later. A nil *Checker accepts everything.
+type Checker struct {
+	logger *zerolog.Logger
+	strict bool
+	files  []Files
+}
+
+// New creates a checker resolving methods from files, searched in order.
+func New(logger *zerolog.Logger, strict bool, files ...Files) *Checker {
+	if logger == nil {
+		logger = new(zerolog.Nop())
+	}
+
+	return &Checker{logger: logger, strict: strict, files: files}
+}
+
+// Strict reports whether the checker rejects stubs instead of logging.
+func (c *Checker) Strict() bool {
+	return c != nil && c.strict
+}
+
+// Validate checks stub. It returns an *Error in strict mode and logs a warning
+// in lenient mode.
+func (c *Checker) Validate(stub *stuber.Stub) error {
+	issues := c.Check(stub)
+	if len(issues) == 0 {
+		return nil
+	}
+
+	err := &Error{Service: stub.Service, Method: stub.Method, Issues: issues}
+	if c.strict {
+		return err
+	}
+
+	c.logger.Warn().
+		Str("service", stub.Service).
+		Str("method", stub.Method).
+		Str("id", stub.ID.String()).
+		Msg(err.Error())
+
+	return nil
+}
+
+// Check returns where stub disagrees with its method's descriptors, along with
+// its invalid CEL expressions and field conditions.
+func (c *Checker) Check(stub *stuber.Stub) []Issue {
+	if c == nil {
+		return nil
+	}
+
+	issues := append(CheckCEL(stub), CheckWhere(stub)...)
+
+	if method := c.method(stub.Service, stub.Method); method != nil {
+		issues = append(issues, Check(stub, method)...)
+	}
+
+	return issues
+}
+
+// method finds a method by service full name, or by bare service name for
+// stubs that leave the package out.
+//
+//nolint:ireturn
+func (c *Checker) method(service, method string) protoreflect.MethodDescriptor {
+	var found protoreflect.MethodDescriptor
+
+	bare := !strings.Contains(service, ".")
+
+	for _, files := range c.files {
+		files.RangeFiles(func(file protoreflect.FileDescriptor) bool {
+			services := file.Services()
+			for i := range services.Len() {
+				sd := services.Get(i)
+				if string(sd.FullName()) != service && (!bare || string(sd.Name()) != service) {
+					continue
+				}
+
+				if md := sd.Methods().ByName(protoreflect.Name(method)); md != nil {
+					found = md
+
+					return false
+				}
+			}
+
+			return true
+		})
+
+		if found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+// Check returns where stub disagrees with method: unknown fields and values
+// of the wrong type in input matchers, output data and stream messages.
+// Regular expression and glob matchers are checked for field names only, and
+// strings carrying a template are not type-checked.
+func Check(stub *stuber.Stub, method protoreflect.MethodDescriptor) []Issue {
+	w := &walker{}
+
+	w.input("$.input", method.Input(), stub.Input)
+
+	for i, input := range stub.Inputs {
+		w.input(fmt.Sprintf("$.inputs[%d]", i), method.Input(), input)
+	}
+
+	if stub.Output.Data != nil {
+		w.output("$.output.data", method.Output(), stub.Output.Data)
+	}
+
+	for i, msg := range stub.Output.Stream {
+		w.output(fmt.Sprintf("$.output.stream[%d]", i), method.Output(), msg)
+	}
+
+	if upstream := stub.Output.Upstream; upstream != nil {
+		if method.IsStreamingClient() || method.IsStreamingServer() {
+			w.report("$.output.upstream", "upstream patching is supported for unary methods only")
+		}
+
+		if upstream.Merge != nil {
+			w.output("$.output.upstream.merge", method.Output(), upstream.Merge)
+		}
+	}
+
+	return w.issues
+}
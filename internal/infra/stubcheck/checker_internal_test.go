@@ -0,0 +1,210 @@
+package stubcheck
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/bufbuild/protocompile"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/reflect/protoregistry"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+const shopProto = `
+syntax = "proto3";
+package shop;
+import "google/protobuf/timestamp.proto";
+import "google/protobuf/wrappers.proto";
+enum Status { STATUS_UNSPECIFIED = 0; PAID = 1; }
+message Item { string sku = 1; int64 qty = 2; }
+message Order {
+  string order_id = 1;
+  int64 total = 2;
+  Status status = 3;
+  repeated Item items = 4;
+  map<int32, string> notes = 5;
+  google.protobuf.Timestamp created_at = 6;
+  google.protobuf.Int32Value discount = 7;
+  bool paid = 8;
+  double ratio = 9;
+}
+service Orders {
+  rpc Get(Order) returns (Order);
+  rpc Watch(Order) returns (stream Order);
+}
+`
+
+func shopFiles(t *testing.T) *protoregistry.Files {
+	t.Helper()
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{
+			Accessor: protocompile.SourceAccessorFromMap(map[string]string{"shop.proto": shopProto}),
+		}),
+	}
+
+	compiled, err := compiler.Compile(t.Context(), "shop.proto")
+	require.NoError(t, err)
+
+	files := new(protoregistry.Files)
+	require.NoError(t, files.RegisterFile(compiled[0]))
+
+	return files
+}
+
+func paths(issues []Issue) []string {
+	out := make([]string, len(issues))
+	for i, issue := range issues {
+		out[i] = issue.Path
+	}
+
+	return out
+}
+
+func TestCheckAcceptsMatchingStub(t *testing.T) {
+	t.Parallel()
+
+	checker := New(nil, true, shopFiles(t))
+
+	stub := &stuber.Stub{
+		Service: "shop.Orders",
+		Method:  "Get",
+		Input: stuber.InputData{
+			Equals:   map[string]any{"orderId": "1", "total": json.Number("10"), "status": "PAID"},
+			Contains: map[string]any{"items": []any{map[string]any{"sku": "a", "qty": "2"}}},
+			Matches:  map[string]any{"order_id": "^[0-9]+$", "total": "^1"},
+		},
+		Output: stuber.Output{
+			Data: map[string]any{
+				"order_id":   "{{ .Request.orderId }}",
+				"total":      "{{ add 1 2 }}",
+				"status":     json.Number("1"),
+				"notes":      map[string]any{"1": "first"},
+				"created_at": "2026-01-02T03:04:05Z",
+				"discount":   json.Number("5"),
+				"paid":       true,
+				"ratio":      "NaN",
+			},
+		},
+	}
+
+	require.Empty(t, checker.Check(stub))
+	require.NoError(t, checker.Validate(stub))
+}
+
+func TestCheckReportsPaths(t *testing.T) {
+	t.Parallel()
+
+	checker := New(nil, true, shopFiles(t))
+
+	stub := &stuber.Stub{
+		Service: "Orders",
+		Method:  "Get",
+		Input: stuber.InputData{
+			Equals: map[string]any{"ordr_id": "1", "total": "ten"},
+			AnyOf:  []stuber.AnyOfElement{{Glob: map[string]any{"items": map[string]any{"skuu": "a*"}}}},
+		},
+		Output: stuber.Output{
+			Stream: []any{
+				map[string]any{stuber.GripMockKey: map[string]any{"delay": "1s"}, "status": "REFUNDED"},
+				map[string]any{"items": []any{map[string]any{"qty": 1.5}}, "notes": map[string]any{"x": "y"}},
+				map[string]any{"created_at": "yesterday", "discount": "big", "paid": "yes"},
+			},
+		},
+	}
+
+	issues := checker.Check(stub)
+	require.Equal(t, []string{
+		"$.input.equals.ordr_id",
+		"$.input.equals.total",
+		"$.input.anyOf[0].glob.items.skuu",
+		"$.output.stream[0].status",
+		"$.output.stream[1].items[0].qty",
+		"$.output.stream[1].notes.x",
+		"$.output.stream[2].created_at",
+		"$.output.stream[2].discount",
+		"$.output.stream[2].paid",
+	}, paths(issues))
+	require.Contains(t, issues[0].Message, `unknown field "ordr_id" in shop.Order`)
+	require.Contains(t, issues[3].Message, `unknown value "REFUNDED" for enum shop.Status`)
+
+	err := checker.Validate(stub)
+
+	var checkErr *Error
+	require.ErrorAs(t, err, &checkErr)
+	require.Len(t, checkErr.Issues, len(issues))
+	require.Contains(t, err.Error(), "$.input.equals.ordr_id")
+}
+
+func TestCheckUpstreamPatch(t *testing.T) {
+	t.Parallel()
+
+	checker := New(nil, true, shopFiles(t))
+
+	stub := &stuber.Stub{
+		Service: "shop.Orders",
+		Method:  "Get",
+		Output: stuber.Output{Upstream: &stuber.UpstreamPatch{
+			Merge: map[string]any{"status": "PAID", "totl": json.Number("1")},
+		}},
+	}
+
+	require.Equal(t, []string{"$.output.upstream.merge.totl"}, paths(checker.Check(stub)))
+
+	stub.Method = "Watch"
+	stub.Output.Upstream.Merge = nil
+
+	issues := checker.Check(stub)
+	require.Equal(t, []string{"$.output.upstream"}, paths(issues))
+	require.Contains(t, issues[0].Message, "unary methods only")
+}
+
+func TestCheckerModes(t *testing.T) {
+	t.Parallel()
+
+	files := shopFiles(t)
+	stub := &stuber.Stub{
+		Service: "shop.Orders",
+		Method:  "Get",
+		Output:  stuber.Output{Data: map[string]any{"unknown": 1}},
+	}
+
+	require.NoError(t, New(nil, false, files).Validate(stub))
+	require.Error(t, New(nil, true, files).Validate(stub))
+
+	stub.Method = "Missing"
+	require.NoError(t, New(nil, true, files).Validate(stub))
+
+	var checker *Checker
+
+	require.NoError(t, checker.Validate(stub))
+	require.False(t, checker.Strict())
+}
+
+func TestCheckReportsInvalidExpressions(t *testing.T) {
+	t.Parallel()
+
+	stub := &stuber.Stub{
+		Service: "shop.Missing",
+		Method:  "Get",
+		Input: stuber.InputData{
+			CEL:   "request.total >",
+			Where: map[string]any{"total": map[string]any{"gt": "soon"}, "ratio": 1},
+			AnyOf: []stuber.AnyOfElement{{Where: map[string]any{"total": map[string]any{"between": 1}}}},
+		},
+		Headers: stuber.InputHeader{CEL: "headers['x-n'] + 'x'"},
+		Inputs:  []stuber.InputData{{CEL: "request.total > 1"}, {CEL: "1 + 2"}},
+	}
+
+	issues := New(nil, true, shopFiles(t)).Check(stub)
+	require.Equal(t, []string{
+		"$.input.cel",
+		"$.headers.cel",
+		"$.inputs[1].cel",
+		"$.input.where.ratio",
+		"$.input.where.total",
+		"$.input.anyOf[0].where.total",
+	}, paths(issues))
+	require.Contains(t, issues[2].Message, "must evaluate to a bool")
+}
@@ -0,0 +1,77 @@
+package stubcheck
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+
+	"github.com/cockroachdb/errors"
+
+	"github.com/bavix/gripmock/v3/internal/infra/celmatch"
+	"github.com/bavix/gripmock/v3/internal/infra/deeply"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// CheckCEL returns the CEL expressions of stub's matchers that do not compile.
+// Such an expression never matches, so the stub is dead.
+func CheckCEL(stub *stuber.Stub) []Issue {
+	w := &walker{}
+
+	w.cel("$.input.cel", stub.Input.CEL)
+	w.cel("$.headers.cel", stub.Headers.CEL)
+
+	for i, input := range stub.Inputs {
+		w.cel(fmt.Sprintf("$.inputs[%d].cel", i), input.CEL)
+	}
+
+	return w.issues
+}
+
+// CheckWhere returns the field conditions of stub's matchers with operands
+// they cannot use.
+func CheckWhere(stub *stuber.Stub) []Issue {
+	w := &walker{}
+
+	w.conditions("$.input", stub.Input)
+
+	for i, input := range stub.Inputs {
+		w.conditions(fmt.Sprintf("$.inputs[%d]", i), input)
+	}
+
+	return w.issues
+}
+
+func (w *walker) conditions(path string, in stuber.InputData) {
+	w.where(path+".where", in.Where)
+
+	for i, alt := range in.AnyOf {
+		w.where(fmt.Sprintf("%s.anyOf[%d].where", path, i), alt.Where)
+	}
+}
+
+func (w *walker) cel(path, expression string) {
+	if expression == "" {
+		return
+	}
+
+	if _, err := celmatch.Compile(expression); err != nil {
+		w.report(path, "%s", err)
+	}
+}
+
+func (w *walker) where(path string, where map[string]any) {
+	for _, key := range slices.Sorted(maps.Keys(where)) {
+		condition, ok := where[key].(map[string]any)
+
+		var err error
+		if !ok {
+			err = errors.Wrapf(deeply.ErrInvalidCondition, "want a condition, got %v", where[key])
+		} else {
+			err = deeply.CheckCondition(condition)
+		}
+
+		if err != nil {
+			w.report(path+"."+key, "%s", err)
+		}
+	}
+}
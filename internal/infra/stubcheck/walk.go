@@ -0,0 +1,413 @@
+package stubcheck
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+//nolint:gochecknoglobals
+var identifier = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+type walker struct {
+	issues []Issue
+}
+
+func (w *walker) report(path, format string, args ...any) {
+	w.issues = append(w.issues, Issue{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (w *walker) input(path string, desc protoreflect.MessageDescriptor, in stuber.InputData) {
+	w.matchers(path, desc, in.Equals, in.Contains, in.Matches, in.Glob)
+
+	for i, alt := range in.AnyOf {
+		w.matchers(fmt.Sprintf("%s.anyOf[%d]", path, i), desc, alt.Equals, alt.Contains, alt.Matches, alt.Glob)
+	}
+}
+
+func (w *walker) matchers(path string, desc protoreflect.MessageDescriptor, equals, contains, matches, glob map[string]any) {
+	if equals != nil {
+		w.message(path+".equals", desc, equals, true)
+	}
+
+	if contains != nil {
+		w.message(path+".contains", desc, contains, true)
+	}
+
+	if matches != nil {
+		w.message(path+".matches", desc, matches, false)
+	}
+
+	if glob != nil {
+		w.message(path+".glob", desc, glob, false)
+	}
+}
+
+// output checks a response message, skipping the reserved per-message key of
+// stream elements.
+func (w *walker) output(path string, desc protoreflect.MessageDescriptor, value any) {
+	if m, ok := value.(map[string]any); ok {
+		if _, reserved := m[stuber.GripMockKey]; reserved {
+			m = maps.Clone(m)
+			delete(m, stuber.GripMockKey)
+		}
+
+		value = m
+	}
+
+	w.message(path, desc, value, true)
+}
+
+// message checks value against desc. With typed unset only field names are
+// checked: the values are patterns, not data.
+func (w *walker) message(path string, desc protoreflect.MessageDescriptor, value any, typed bool) {
+	if value == nil || isTemplate(value) {
+		return
+	}
+
+	if done := w.wellKnown(path, desc, value, typed); done {
+		return
+	}
+
+	m, ok := value.(map[string]any)
+	if !ok {
+		if typed {
+			w.report(path, "expected object %s, got %s", desc.FullName(), kindOf(value))
+		}
+
+		return
+	}
+
+	for _, key := range slices.Sorted(maps.Keys(m)) {
+		fd := lookup(desc, key)
+		if fd == nil {
+			w.report(child(path, key), "unknown field %q in %s", key, desc.FullName())
+
+			continue
+		}
+
+		w.field(child(path, key), fd, m[key], typed)
+	}
+}
+
+func (w *walker) field(path string, fd protoreflect.FieldDescriptor, value any, typed bool) {
+	if value == nil {
+		return
+	}
+
+	switch {
+	case fd.IsList():
+		list, ok := value.([]any)
+		if !ok {
+			if !typed {
+				// A pattern may stand for every element.
+				w.single(path, fd, value, false)
+			} else if !isTemplate(value) {
+				w.report(path, "expected array, got %s", kindOf(value))
+			}
+
+			return
+		}
+
+		for i, elem := range list {
+			w.single(fmt.Sprintf("%s[%d]", path, i), fd, elem, typed)
+		}
+	case fd.IsMap():
+		m, ok := value.(map[string]any)
+		if !ok {
+			if typed && !isTemplate(value) {
+				w.report(path, "expected object, got %s", kindOf(value))
+			}
+
+			return
+		}
+
+		for _, key := range slices.Sorted(maps.Keys(m)) {
+			if typed {
+				if problem := scalar(fd.MapKey(), key); problem != "" {
+					w.report(child(path, key), "map key: %s", problem)
+				}
+			}
+
+			w.single(child(path, key), fd.MapValue(), m[key], typed)
+		}
+	default:
+		w.single(path, fd, value, typed)
+	}
+}
+
+func (w *walker) single(path string, fd protoreflect.FieldDescriptor, value any, typed bool) {
+	if fd.Message() != nil {
+		w.message(path, fd.Message(), value, typed)
+
+		return
+	}
+
+	if !typed || value == nil || isTemplate(value) {
+		return
+	}
+
+	if problem := scalar(fd, value); problem != "" {
+		w.report(path, "%s", problem)
+	}
+}
+
+// wellKnown handles the well-known types protojson encodes specially. It
+// returns true when value needs no further checks.
+func (w *walker) wellKnown(path string, desc protoreflect.MessageDescriptor, value any, typed bool) bool {
+	if desc.ParentFile().Package() != "google.protobuf" {
+		return false
+	}
+
+	if _, isMap := value.(map[string]any); isMap && desc.Name() != "Struct" && desc.Name() != "Any" {
+		return false
+	}
+
+	str, isString := value.(string)
+
+	switch desc.Name() {
+	case "Struct", "Value", "ListValue", "Any":
+		return true
+	case "Timestamp":
+		if typed && (!isString || !validTimestamp(str)) {
+			w.report(path, "expected RFC 3339 timestamp, got %s", kindOf(value))
+		}
+
+		return true
+	case "Duration":
+		if typed && (!isString || !validDuration(str)) {
+			w.report(path, "expected duration such as \"1.5s\", got %s", kindOf(value))
+		}
+
+		return true
+	case "FieldMask":
+		if typed && !isString {
+			w.report(path, "expected field mask string, got %s", kindOf(value))
+		}
+
+		return true
+	case "DoubleValue", "FloatValue", "Int64Value", "UInt64Value", "Int32Value", "UInt32Value",
+		"BoolValue", "StringValue", "BytesValue":
+		w.single(path, desc.Fields().ByName("value"), value, typed)
+
+		return true
+	default:
+		return false
+	}
+}
+
+//nolint:ireturn
+func lookup(desc protoreflect.MessageDescriptor, key string) protoreflect.FieldDescriptor {
+	fields := desc.Fields()
+
+	if fd := fields.ByName(protoreflect.Name(key)); fd != nil {
+		return fd
+	}
+
+	if fd := fields.ByJSONName(key); fd != nil {
+		return fd
+	}
+
+	return fields.ByTextName(key)
+}
+
+// scalar reports why value cannot be decoded into fd, empty when it can.
+//
+//nolint:cyclop
+func scalar(fd protoreflect.FieldDescriptor, value any) string {
+	switch fd.Kind() {
+	case protoreflect.BoolKind:
+		if b, ok := value.(string); ok && (b == "true" || b == "false") {
+			return ""
+		}
+
+		if _, ok := value.(bool); !ok {
+			return "expected bool, got " + kindOf(value)
+		}
+	case protoreflect.StringKind, protoreflect.BytesKind:
+		if _, ok := value.(string); !ok {
+			return fmt.Sprintf("expected %s, got %s", fd.Kind(), kindOf(value))
+		}
+	case protoreflect.EnumKind:
+		return enum(fd.Enum(), value)
+	case protoreflect.FloatKind, protoreflect.DoubleKind:
+		if !isFloat(value) {
+			return fmt.Sprintf("expected %s, got %s", fd.Kind(), kindOf(value))
+		}
+	case protoreflect.Int32Kind, protoreflect.Sint32Kind, protoreflect.Sfixed32Kind:
+		return integer(fd.Kind(), value, math.MinInt32, math.MaxInt32)
+	case protoreflect.Int64Kind, protoreflect.Sint64Kind, protoreflect.Sfixed64Kind:
+		return integer(fd.Kind(), value, math.MinInt64, math.MaxInt64)
+	case protoreflect.Uint32Kind, protoreflect.Fixed32Kind:
+		return unsigned(fd.Kind(), value, math.MaxUint32)
+	case protoreflect.Uint64Kind, protoreflect.Fixed64Kind:
+		return unsigned(fd.Kind(), value, math.MaxUint64)
+	case protoreflect.MessageKind, protoreflect.GroupKind:
+	}
+
+	return ""
+}
+
+func enum(desc protoreflect.EnumDescriptor, value any) string {
+	if name, ok := value.(string); ok {
+		if desc.Values().ByName(protoreflect.Name(name)) == nil {
+			return fmt.Sprintf("unknown value %q for enum %s", name, desc.FullName())
+		}
+
+		return ""
+	}
+
+	if n, ok := number(value); ok {
+		if _, err := strconv.ParseInt(n, 10, 32); err == nil {
+			return ""
+		}
+	}
+
+	return fmt.Sprintf("expected %s value name or number, got %s", desc.FullName(), kindOf(value))
+}
+
+func integer(kind protoreflect.Kind, value any, lowest, highest int64) string {
+	n, ok := number(value)
+	if !ok {
+		return fmt.Sprintf("expected %s, got %s", kind, kindOf(value))
+	}
+
+	v, err := strconv.ParseInt(n, 10, 64)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(n, 64)
+		if ferr != nil || f != math.Trunc(f) || f < float64(lowest) || f > float64(highest) {
+			return fmt.Sprintf("expected %s, got %s", kind, n)
+		}
+
+		return ""
+	}
+
+	if v < lowest || v > highest {
+		return fmt.Sprintf("%s out of range for %s", n, kind)
+	}
+
+	return ""
+}
+
+func unsigned(kind protoreflect.Kind, value any, highest uint64) string {
+	n, ok := number(value)
+	if !ok {
+		return fmt.Sprintf("expected %s, got %s", kind, kindOf(value))
+	}
+
+	v, err := strconv.ParseUint(n, 10, 64)
+	if err != nil {
+		f, ferr := strconv.ParseFloat(n, 64)
+		if ferr != nil || f != math.Trunc(f) || f < 0 || f > float64(highest) {
+			return fmt.Sprintf("expected %s, got %s", kind, n)
+		}
+
+		return ""
+	}
+
+	if v > highest {
+		return fmt.Sprintf("%s out of range for %s", n, kind)
+	}
+
+	return ""
+}
+
+// number returns the textual form of a JSON number, or of a string holding
+// one, which protojson accepts for every numeric kind.
+func number(value any) (string, bool) {
+	switch v := value.(type) {
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int:
+		return strconv.Itoa(v), true
+	case int64:
+		return strconv.FormatInt(v, 10), true
+	case uint64:
+		return strconv.FormatUint(v, 10), true
+	case string:
+		if _, err := strconv.ParseFloat(v, 64); err == nil {
+			return v, true
+		}
+
+		return "", false
+	default:
+		return "", false
+	}
+}
+
+func isFloat(value any) bool {
+	switch v := value.(type) {
+	case string:
+		if v == "NaN" || v == "Infinity" || v == "-Infinity" {
+			return true
+		}
+	case json.Number, float64, int, int64, uint64:
+		return true
+	}
+
+	_, ok := number(value)
+
+	return ok
+}
+
+func validTimestamp(s string) bool {
+	_, err := time.Parse(time.RFC3339Nano, s)
+
+	return err == nil
+}
+
+func validDuration(s string) bool {
+	seconds, ok := strings.CutSuffix(s, "s")
+	if !ok {
+		return false
+	}
+
+	_, err := strconv.ParseFloat(seconds, 64)
+
+	return err == nil
+}
+
+func isTemplate(value any) bool {
+	s, ok := value.(string)
+
+	return ok && strings.Contains(s, "{{")
+}
+
+func kindOf(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case string:
+		return strconv.Quote(v)
+	case bool:
+		return "bool"
+	case json.Number, float64, int, int64, uint64:
+		return "number"
+	case map[string]any:
+		return "object"
+	case []any:
+		return "array"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+func child(path, key string) string {
+	if identifier.MatchString(key) {
+		return path + "." + key
+	}
+
+	return path + "[" + strconv.Quote(key) + "]"
+}
@@ -168,6 +168,35 @@ func WriteDump(writer io.Writer, stubs []*Stub, format string) error {
 	return encoder.Close()
 }
 
+// WriteFileAtomic replaces path with what write produces, through a temporary
+// file in the same directory, so readers see either the old or the new
+// content and never a half-written file.
+func WriteFileAtomic(path string, perm os.FileMode, write func(io.Writer) error) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+"-*")
+	if err != nil {
+		return err
+	}
+
+	err = write(tmp)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+
+	if err == nil {
+		err = os.Chmod(tmp.Name(), perm)
+	}
+
+	if err == nil {
+		err = os.Rename(tmp.Name(), path)
+	}
+
+	if err != nil {
+		_ = os.Remove(tmp.Name())
+	}
+
+	return err
+}
+
 func dumpRecordOf(stub *Stub) map[string]any {
 	record := map[string]any{
 		"service": stub.Service,
@@ -182,6 +211,10 @@ func dumpRecordOf(stub *Stub) map[string]any {
 		record["effects"] = stub.Effects
 	}
 
+	if !stub.Faults.IsZero() {
+		record["faults"] = pruneNulls(structToMap(stub.Faults))
+	}
+
 	if stub.Source != "" {
 		record["_meta"] = map[string]any{"source": stub.Source}
 	}
@@ -217,6 +250,26 @@ func addDumpScalars(record map[string]any, stub *Stub) {
 	if stub.Options.Times != 0 {
 		record["options"] = map[string]any{"times": stub.Options.Times}
 	}
+
+	if stub.Seed != nil {
+		record["seed"] = *stub.Seed
+	}
+
+	addDumpScenario(record, stub)
+}
+
+func addDumpScenario(record map[string]any, stub *Stub) {
+	if stub.Scenario != "" {
+		record["scenario"] = stub.Scenario
+	}
+
+	if stub.RequiredState != "" {
+		record["requiredState"] = stub.RequiredState
+	}
+
+	if stub.NewState != "" {
+		record["newState"] = stub.NewState
+	}
 }
 
 func addDumpMatchers(record map[string]any, stub *Stub) {
@@ -3,6 +3,8 @@ package stuber
 import (
 	"bytes"
 	"encoding/json"
+	"errors"
+	"io"
 	"math"
 	"os"
 	"path/filepath"
@@ -216,3 +218,35 @@ func engineStubJSONFields(t *testing.T) []string {
 
 	return fields
 }
+
+func TestWriteFileAtomicKeepsOldContentOnFailure(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	path := filepath.Join(dir, "stubs.yaml")
+
+	write := func(content string, err error) error {
+		return WriteFileAtomic(path, 0o600, func(w io.Writer) error {
+			_, _ = io.WriteString(w, content)
+
+			return err
+		})
+	}
+
+	require.NoError(t, write("old", nil))
+
+	errBroken := errors.New("broken")
+	require.ErrorIs(t, write("half", errBroken), errBroken)
+
+	raw, err := os.ReadFile(path) //nolint:gosec
+	require.NoError(t, err)
+	require.Equal(t, "old", string(raw))
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "the temporary file is removed")
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	require.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+}
@@ -0,0 +1,108 @@
+package stuber
+
+import (
+	"maps"
+	"slices"
+
+	"github.com/bavix/gripmock/v3/internal/infra/celmatch"
+)
+
+// Mismatch is one matcher entry that a message or a header set failed.
+type Mismatch struct {
+	Field    string `json:"field,omitempty"`
+	Operator string `json:"operator"`
+	Expected any    `json:"expected"`
+	Actual   any    `json:"actual,omitempty"`
+}
+
+// Match reports whether data satisfies the matcher the way a stub's input does.
+func (i InputData) Match(data map[string]any) bool {
+	return matchInput(data, i)
+}
+
+// Explain lists the matcher entries data fails, one per field and operator.
+// It is empty exactly when Match holds.
+func (i InputData) Explain(data map[string]any) []Mismatch {
+	out := explainFields(data, "equals", i.Equals, func(expected map[string]any) bool {
+		return equals(expected, data, i.IgnoreArrayOrder)
+	})
+	out = append(out, explainFields(data, "contains", i.Contains, func(expected map[string]any) bool {
+		return contains(expected, data)
+	})...)
+	out = append(out, explainFields(data, "matches", i.Matches, func(expected map[string]any) bool {
+		return matches(expected, data)
+	})...)
+	out = append(out, explainFields(data, "glob", i.Glob, func(expected map[string]any) bool {
+		return globMatch(expected, data)
+	})...)
+
+	if !celMatch(i.CEL, celmatch.Request, data) {
+		out = append(out, Mismatch{Operator: "cel", Expected: i.CEL})
+	}
+
+	out = append(out, explainFields(data, "where", i.Where, func(expected map[string]any) bool {
+		return whereMatch(expected, data)
+	})...)
+
+	if len(i.AnyOf) > 0 && !matchInput(data, InputData{AnyOf: i.AnyOf}) {
+		out = append(out, Mismatch{Operator: "anyOf", Expected: i.AnyOf})
+	}
+
+	return out
+}
+
+// Match reports whether headers satisfy the matcher the way a stub's headers do.
+func (i InputHeader) Match(headers map[string]any) bool {
+	return matchHeaders(headers, i)
+}
+
+// Explain lists the matcher entries headers fail, one per header and operator.
+// It is empty exactly when Match holds.
+func (i InputHeader) Explain(headers map[string]any) []Mismatch {
+	out := explainFields(headers, "equals", i.Equals, func(expected map[string]any) bool {
+		return equals(expected, headers, false)
+	})
+	out = append(out, explainFields(headers, "contains", i.Contains, func(expected map[string]any) bool {
+		return contains(expected, headers)
+	})...)
+	out = append(out, explainFields(headers, "matches", i.Matches, func(expected map[string]any) bool {
+		return matches(expected, headers)
+	})...)
+	out = append(out, explainFields(headers, "glob", i.Glob, func(expected map[string]any) bool {
+		return globMatch(expected, headers)
+	})...)
+
+	if !celMatch(i.CEL, celmatch.Headers, headers) {
+		out = append(out, Mismatch{Operator: "cel", Expected: i.CEL})
+	}
+
+	if len(i.AnyOf) > 0 && !matchHeaders(headers, InputHeader{AnyOf: i.AnyOf}) {
+		out = append(out, Mismatch{Operator: "anyOf", Expected: i.AnyOf})
+	}
+
+	return out
+}
+
+// explainFields checks every field of one operator on its own, so a failure
+// names the field instead of the whole map.
+func explainFields(
+	actual map[string]any, operator string, expected map[string]any, match func(map[string]any) bool,
+) []Mismatch {
+	var out []Mismatch
+
+	for _, field := range slices.Sorted(maps.Keys(expected)) {
+		value := expected[field]
+		if match(map[string]any{field: value}) {
+			continue
+		}
+
+		mismatch := Mismatch{Field: field, Operator: operator, Expected: value}
+		if got, ok := findValueWithVariations(actual, field); ok {
+			mismatch.Actual = got
+		}
+
+		out = append(out, mismatch)
+	}
+
+	return out
+}
@@ -0,0 +1,66 @@
+package stuber_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func TestInputDataExplainNamesEachField(t *testing.T) {
+	t.Parallel()
+
+	input := stuber.InputData{
+		Equals:   map[string]any{"name": "Bob", "age": 30.0},
+		Contains: map[string]any{"tags": []any{"vip"}},
+		Glob:     map[string]any{"email": "*@acme.io"},
+	}
+
+	data := map[string]any{"name": "Alex", "age": 30.0, "tags": []any{"new"}, "email": "a@acme.io"}
+
+	require.False(t, input.Match(data))
+	require.Equal(t, []stuber.Mismatch{
+		{Field: "name", Operator: "equals", Expected: "Bob", Actual: "Alex"},
+		{Field: "tags", Operator: "contains", Expected: []any{"vip"}, Actual: []any{"new"}},
+	}, input.Explain(data))
+
+	data["name"] = "Bob"
+	data["tags"] = []any{"vip", "new"}
+
+	require.True(t, input.Match(data))
+	require.Empty(t, input.Explain(data))
+}
+
+func TestInputDataExplainMissingFieldAndAnyOf(t *testing.T) {
+	t.Parallel()
+
+	input := stuber.InputData{
+		Matches: map[string]any{"id": "^[0-9]+$"},
+		AnyOf: []stuber.AnyOfElement{
+			{Equals: map[string]any{"kind": "a"}},
+			{Equals: map[string]any{"kind": "b"}},
+		},
+	}
+
+	require.Equal(t, []stuber.Mismatch{
+		{Field: "id", Operator: "matches", Expected: "^[0-9]+$"},
+		{Operator: "anyOf", Expected: input.AnyOf},
+	}, input.Explain(map[string]any{"kind": "c"}))
+}
+
+func TestInputHeaderExplain(t *testing.T) {
+	t.Parallel()
+
+	headers := stuber.InputHeader{
+		Equals:   map[string]any{"x-tenant": "acme"},
+		Contains: map[string]any{"authorization": "Bearer token"},
+	}
+
+	got := map[string]any{"x-tenant": "other", "authorization": "Bearer token"}
+
+	require.False(t, headers.Match(got))
+	require.Equal(t, []stuber.Mismatch{
+		{Field: "x-tenant", Operator: "equals", Expected: "acme", Actual: "other"},
+	}, headers.Explain(got))
+}
@@ -0,0 +1,68 @@
+package stuber
+
+import (
+	"google.golang.org/grpc/codes"
+
+	"github.com/bavix/gripmock/v3/internal/infra/types"
+)
+
+// Latency distributions.
+const (
+	LatencyUniform     = "uniform"
+	LatencyNormal      = "normal"
+	LatencyPercentiles = "percentiles"
+)
+
+// Faults injects failures into matched calls to exercise client retries.
+// Each fault fires independently with its probability; the same section set
+// globally applies to every stub, with the stub's own fields taking priority.
+type Faults struct {
+	Error   *FaultError   `json:"error,omitempty"`
+	Latency *FaultLatency `json:"latency,omitempty"`
+	// Reset is the probability of resetting the call's stream.
+	// For streams it happens before a randomly chosen message.
+	Reset float64 `json:"reset,omitempty"`
+	// Drop is the probability of silently dropping each server or bidirectional
+	// stream message.
+	Drop float64 `json:"drop,omitempty"`
+}
+
+// IsZero reports whether f injects nothing.
+func (f *Faults) IsZero() bool {
+	return f == nil || (f.Error == nil && f.Latency == nil && f.Reset == 0 && f.Drop == 0)
+}
+
+// FaultError fails the call with Code. Probability defaults to 1.
+type FaultError struct {
+	Probability *float64   `json:"probability,omitempty"`
+	Code        codes.Code `json:"code"`
+	Message     string     `json:"message,omitempty"`
+}
+
+// FaultLatency delays the first response by a sampled duration. Probability
+// defaults to 1. Without Distribution it is inferred: percentiles when given,
+// normal when Mean or StdDev is set, uniform otherwise.
+type FaultLatency struct {
+	Probability  *float64       `json:"probability,omitempty"`
+	Distribution string         `json:"distribution,omitempty"`
+	Min          types.Duration `json:"min,omitempty"`
+	Max          types.Duration `json:"max,omitempty"`
+	Mean         types.Duration `json:"mean,omitempty"`
+	StdDev       types.Duration `json:"stddev,omitempty"`
+	// Percentiles maps a percentile such as "p50" or "p99.9" to its latency.
+	Percentiles map[string]types.Duration `json:"percentiles,omitempty"`
+}
+
+// Kind is the distribution the latency is sampled from.
+func (l *FaultLatency) Kind() string {
+	switch {
+	case l.Distribution != "":
+		return l.Distribution
+	case len(l.Percentiles) > 0:
+		return LatencyPercentiles
+	case l.Mean > 0 || l.StdDev > 0:
+		return LatencyNormal
+	default:
+		return LatencyUniform
+	}
+}
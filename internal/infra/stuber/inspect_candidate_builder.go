@@ -1,8 +1,8 @@
 package stuber
 
 const (
-	inspectEventCount        = 6
-	inspectExcludedReasonCap = 4
+	inspectEventCount        = 7
+	inspectExcludedReasonCap = 5
 )
 
 func (s *searcher) collectTraceCandidates(query Query, stubs []*Stub, fallbackToMethod bool) []InspectCandidate {
@@ -74,6 +74,7 @@ func (s *searcher) evalTraceCandidate(
 	used := s.stubCallCount[callCountKey{id: stub.ID, session: query.Session}]
 	times := stub.EffectiveTimes()
 	withinTimes := times <= 0 || used < times
+	inScenario := s.inRequiredState(stub, query.Session)
 	visible := isStubVisibleForSession(stub.Session, query.Session)
 	headersMatched := doesQueryMatchStubHeaders(query, stub)
 	inputMatched := s.fastMatchBody(query, stub)
@@ -82,10 +83,11 @@ func (s *searcher) evalTraceCandidate(
 	routeStage, routePassed, routeReason, reasonCount := evalRoute(query, stub, fallbackToMethod, reasons)
 	reasonCount = appendReasonToBuffer(reasons, reasonCount, !visible, traceReasonSession)
 	reasonCount = appendReasonToBuffer(reasons, reasonCount, !withinTimes, traceReasonTimes)
+	reasonCount = appendReasonToBuffer(reasons, reasonCount, !inScenario, traceReasonScenario)
 	reasonCount = appendReasonToBuffer(reasons, reasonCount, query.ID == nil && !headersMatched, traceReasonHeaders)
 	reasonCount = appendReasonToBuffer(reasons, reasonCount, query.ID == nil && !inputMatched, traceReasonInput)
 
-	buildTraceEvents(events, query, routeStage, routePassed, routeReason, visible, withinTimes, headersMatched, inputMatched)
+	buildTraceEvents(events, query, routeStage, routePassed, routeReason, visible, withinTimes, inScenario, headersMatched, inputMatched)
 
 	return traceEval{
 		used:           used,
@@ -158,6 +160,7 @@ func buildTraceEvents(
 	routeReason string,
 	visible bool,
 	withinTimes bool,
+	inScenario bool,
 	headersMatched bool,
 	inputMatched bool,
 ) {
@@ -168,22 +171,27 @@ func buildTraceEvents(
 		Result: boolResult(withinTimes),
 		Reason: reasonIf(!withinTimes, traceReasonTimes),
 	}
+	events[3] = InspectCandidateEvent{
+		Stage:  traceStageScenario,
+		Result: boolResult(inScenario),
+		Reason: reasonIf(!inScenario, traceReasonScenario),
+	}
 
 	if query.ID != nil {
-		events[3] = InspectCandidateEvent{Stage: traceStageHeaders, Result: traceResultSkipped, Reason: traceReasonIDLookup}
-		events[4] = InspectCandidateEvent{Stage: traceStageInput, Result: traceResultSkipped, Reason: traceReasonIDLookup}
+		events[4] = InspectCandidateEvent{Stage: traceStageHeaders, Result: traceResultSkipped, Reason: traceReasonIDLookup}
+		events[5] = InspectCandidateEvent{Stage: traceStageInput, Result: traceResultSkipped, Reason: traceReasonIDLookup}
 	} else {
-		events[3] = InspectCandidateEvent{
+		events[4] = InspectCandidateEvent{
 			Stage:  traceStageHeaders,
 			Result: boolResult(headersMatched),
 			Reason: reasonIf(!headersMatched, traceReasonHeaders),
 		}
-		events[4] = InspectCandidateEvent{
+		events[5] = InspectCandidateEvent{
 			Stage:  traceStageInput,
 			Result: boolResult(inputMatched),
 			Reason: reasonIf(!inputMatched, traceReasonInput),
 		}
 	}
 
-	events[5] = InspectCandidateEvent{Stage: traceStageSelected, Result: traceResultFailed, Reason: traceReasonNotSelect}
+	events[6] = InspectCandidateEvent{Stage: traceStageSelected, Result: traceResultFailed, Reason: traceReasonNotSelect}
 }
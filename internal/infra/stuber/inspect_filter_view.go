@@ -1,9 +1,10 @@
 package stuber
 
 type regularLookupView struct {
-	serviceMethod   []*Stub
-	sessionFiltered []*Stub
-	timesFiltered   []*Stub
+	serviceMethod    []*Stub
+	sessionFiltered  []*Stub
+	timesFiltered    []*Stub
+	scenarioFiltered []*Stub
 }
 
 func (s *searcher) buildRegularLookupView(query Query, all []*Stub) regularLookupView {
@@ -11,11 +12,13 @@ func (s *searcher) buildRegularLookupView(query Query, all []*Stub) regularLooku
 
 	sessionFiltered := filterBySession(serviceMethod, query.Session)
 	timesFiltered := s.filterExhaustedStubs(sessionFiltered, query.Session)
+	scenarioFiltered := s.filterByScenarioState(timesFiltered, query.Session)
 
 	return regularLookupView{
-		serviceMethod:   serviceMethod,
-		sessionFiltered: sessionFiltered,
-		timesFiltered:   timesFiltered,
+		serviceMethod:    serviceMethod,
+		sessionFiltered:  sessionFiltered,
+		timesFiltered:    timesFiltered,
+		scenarioFiltered: scenarioFiltered,
 	}
 }
 
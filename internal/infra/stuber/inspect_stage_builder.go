@@ -4,6 +4,7 @@ func addIDLookupStages(collector traceCollector, allCount int) {
 	collector.addStage(traceStageID, allCount, 1)
 	collector.addStage(traceStageSession, 1, 1)
 	collector.addStage(traceStageTimes, 1, 1)
+	collector.addStage(traceStageScenario, 1, 1)
 	collector.addStage(traceStageHeaders, 1, 1)
 	collector.addStage(traceStageInput, 1, 1)
 }
@@ -13,14 +14,15 @@ func (s *searcher) addRegularLookupStages(query Query, collector traceCollector,
 	collector.addStage(traceStageServiceMethod, len(all), len(view.serviceMethod))
 	collector.addStage(traceStageSession, len(view.serviceMethod), len(view.sessionFiltered))
 	collector.addStage(traceStageTimes, len(view.sessionFiltered), len(view.timesFiltered))
+	collector.addStage(traceStageScenario, len(view.timesFiltered), len(view.scenarioFiltered))
 
-	headersCount, inputCount := countHeadersAndInputMatches(s, query, view.timesFiltered)
-	collector.addStage(traceStageHeaders, len(view.timesFiltered), headersCount)
-	collector.addStage(traceStageInput, len(view.timesFiltered), inputCount)
+	headersCount, inputCount := countHeadersAndInputMatches(s, query, view.scenarioFiltered)
+	collector.addStage(traceStageHeaders, len(view.scenarioFiltered), headersCount)
+	collector.addStage(traceStageInput, len(view.scenarioFiltered), inputCount)
 
 	if view.hasFallback() {
 		collector.setFallbackToMethod(true)
-		collector.addStage(traceStageFallbackMethod, len(view.timesFiltered), s.countFallbackMethodCandidates(query))
+		collector.addStage(traceStageFallbackMethod, len(view.scenarioFiltered), s.countFallbackMethodCandidates(query))
 	}
 }
 
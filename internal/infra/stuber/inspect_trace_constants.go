@@ -6,6 +6,7 @@ const (
 	traceStageFallbackMethod = "fallback_method"
 	traceStageSession        = "session"
 	traceStageTimes          = "times"
+	traceStageScenario       = "scenario"
 	traceStageHeaders        = "headers"
 	traceStageInput          = "input"
 	traceStageSelected       = "selected"
@@ -25,6 +26,7 @@ const (
 	traceReasonMethod    = "method"
 	traceReasonSession   = "session"
 	traceReasonTimes     = "times"
+	traceReasonScenario  = "scenario"
 	traceReasonHeaders   = "headers"
 	traceReasonInput     = "input"
 )
@@ -140,6 +140,10 @@ func inputHasKind(in InputData, kind string) bool {
 		return len(in.Glob) > 0
 	case "anyOf":
 		return len(in.AnyOf) > 0
+	case "cel":
+		return in.CEL != ""
+	case "where":
+		return len(in.Where) > 0
 	default:
 		return false
 	}
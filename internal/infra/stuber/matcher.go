@@ -1,6 +1,9 @@
 package stuber
 
-import "github.com/bavix/gripmock/v3/internal/infra/deeply"
+import (
+	"github.com/bavix/gripmock/v3/internal/infra/celmatch"
+	"github.com/bavix/gripmock/v3/internal/infra/deeply"
+)
 
 // matchHeaders checks if query headers match stub headers.
 //
@@ -9,7 +12,8 @@ func matchHeaders(queryHeaders map[string]any, stubHeaders InputHeader) bool {
 	if !equals(stubHeaders.Equals, queryHeaders, false) ||
 		!contains(stubHeaders.Contains, queryHeaders) ||
 		!matches(stubHeaders.Matches, queryHeaders) ||
-		!globMatch(stubHeaders.Glob, queryHeaders) {
+		!globMatch(stubHeaders.Glob, queryHeaders) ||
+		!celMatch(stubHeaders.CEL, celmatch.Headers, queryHeaders) {
 		return false
 	}
 
@@ -37,7 +41,9 @@ func matchInput(queryData map[string]any, stubInput InputData) bool {
 	if !equals(stubInput.Equals, queryData, stubInput.IgnoreArrayOrder) ||
 		!contains(stubInput.Contains, queryData) ||
 		!matches(stubInput.Matches, queryData) ||
-		!globMatch(stubInput.Glob, queryData) {
+		!globMatch(stubInput.Glob, queryData) ||
+		!celMatch(stubInput.CEL, celmatch.Request, queryData) ||
+		!whereMatch(stubInput.Where, queryData) {
 		return false
 	}
 
@@ -50,7 +56,8 @@ func matchInput(queryData map[string]any, stubInput InputData) bool {
 		if equals(alt.Equals, queryData, alt.IgnoreArrayOrder) &&
 			contains(alt.Contains, queryData) &&
 			matches(alt.Matches, queryData) &&
-			globMatch(alt.Glob, queryData) {
+			globMatch(alt.Glob, queryData) &&
+			whereMatch(alt.Where, queryData) {
 			return true
 		}
 	}
@@ -67,7 +74,8 @@ func rankHeaders(queryHeaders map[string]any, stubHeaders InputHeader) float64 {
 	base := deeply.RankMatch(stubHeaders.Equals, queryHeaders) +
 		deeply.RankMatch(stubHeaders.Contains, queryHeaders) +
 		deeply.RankMatch(stubHeaders.Matches, queryHeaders) +
-		rankGlob(stubHeaders.Glob, queryHeaders)
+		rankGlob(stubHeaders.Glob, queryHeaders) +
+		rankCEL(stubHeaders.CEL, celmatch.Headers, queryHeaders)
 
 	if len(stubHeaders.AnyOf) == 0 {
 		return base
@@ -95,7 +103,9 @@ func rankInput(queryData map[string]any, stubInput InputData) float64 {
 	base := deeply.RankMatch(stubInput.Equals, queryData) +
 		deeply.RankMatch(stubInput.Contains, queryData) +
 		deeply.RankMatch(stubInput.Matches, queryData) +
-		rankGlob(stubInput.Glob, queryData)
+		rankGlob(stubInput.Glob, queryData) +
+		rankCEL(stubInput.CEL, celmatch.Request, queryData) +
+		rankWhere(stubInput.Where, queryData)
 
 	if len(stubInput.AnyOf) == 0 {
 		return base
@@ -109,7 +119,8 @@ func rankInput(queryData map[string]any, stubInput InputData) float64 {
 		r := deeply.RankMatch(alt.Equals, queryData) +
 			deeply.RankMatch(alt.Contains, queryData) +
 			deeply.RankMatch(alt.Matches, queryData) +
-			rankGlob(alt.Glob, queryData)
+			rankGlob(alt.Glob, queryData) +
+			rankWhere(alt.Where, queryData)
 		if r > best {
 			best = r
 		}
@@ -0,0 +1,124 @@
+package stuber_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func celStub(name string, input stuber.InputData, headers stuber.InputHeader) *stuber.Stub {
+	return &stuber.Stub{
+		Service: "shop.Orders",
+		Method:  "Create",
+		Input:   input,
+		Headers: headers,
+		Output:  stuber.Output{Data: map[string]any{"stub": name}},
+	}
+}
+
+func findCELStub(t *testing.T, b *stuber.Budgerigar, headers map[string]any, input ...map[string]any) string {
+	t.Helper()
+
+	result, err := b.FindByQuery(stuber.Query{Service: "shop.Orders", Method: "Create", Headers: headers, Input: input})
+	if err != nil || result.Found() == nil {
+		return ""
+	}
+
+	data, ok := result.Found().Output.Data.(map[string]any)
+	require.True(t, ok)
+
+	name, _ := data["stub"].(string)
+
+	return name
+}
+
+func TestCELMatchesInput(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(celStub("big", stuber.InputData{
+		CEL: `request.amount > 100 && size(request.items) >= 2 && request.status in ['NEW', 'PAID']`,
+	}, stuber.InputHeader{}))
+
+	order := func(amount string, items int, status string) map[string]any {
+		list := make([]any, items)
+		for i := range list {
+			list[i] = map[string]any{"sku": "x"}
+		}
+
+		return map[string]any{"amount": json.Number(amount), "items": list, "status": status}
+	}
+
+	require.Equal(t, "big", findCELStub(t, b, nil, order("150", 2, "NEW")))
+	require.Empty(t, findCELStub(t, b, nil, order("50", 2, "NEW")))
+	require.Empty(t, findCELStub(t, b, nil, order("150", 1, "NEW")))
+	require.Empty(t, findCELStub(t, b, nil, order("150", 2, "VOID")))
+	require.Empty(t, findCELStub(t, b, nil, map[string]any{"status": "NEW"}))
+}
+
+func TestCELRanksByFieldsRead(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(
+		celStub("amount", stuber.InputData{CEL: `request.amount > 100`}, stuber.InputHeader{}),
+		celStub("amount-currency", stuber.InputData{
+			CEL: `request.amount > 100 && request.currency == 'USD'`,
+		}, stuber.InputHeader{}),
+		celStub("equals", stuber.InputData{Equals: map[string]any{"currency": "USD"}}, stuber.InputHeader{}),
+	)
+
+	require.Equal(t, "amount-currency", findCELStub(t, b, nil, map[string]any{"amount": json.Number("150"), "currency": "USD"}))
+	require.Equal(t, "equals", findCELStub(t, b, nil, map[string]any{"amount": json.Number("50"), "currency": "USD"}))
+	require.Equal(t, "amount", findCELStub(t, b, nil, map[string]any{"amount": json.Number("150"), "currency": "EUR"}))
+}
+
+func TestCELMatchesHeaders(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(
+		celStub("tenant", stuber.InputData{}, stuber.InputHeader{
+			CEL: `headers['x-tenant'] in ['acme', 'globex'] && int(headers['x-version']) >= 2`,
+		}),
+		celStub("fallback", stuber.InputData{}, stuber.InputHeader{}),
+	)
+
+	require.Equal(t, "tenant", findCELStub(t, b, map[string]any{"x-tenant": "acme", "x-version": "2"}, map[string]any{}))
+	require.Equal(t, "fallback", findCELStub(t, b, map[string]any{"x-tenant": "acme", "x-version": "1"}, map[string]any{}))
+	require.Equal(t, "fallback", findCELStub(t, b, nil, map[string]any{}))
+}
+
+func TestCELMatchesStreamInputs(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(&stuber.Stub{
+		Service: "shop.Orders",
+		Method:  "Create",
+		Inputs:  []stuber.InputData{{CEL: `request.qty > 0`}},
+		Output:  stuber.Output{Data: map[string]any{"stub": "stream"}},
+	})
+
+	require.Equal(t, "stream", findCELStub(t, b, nil,
+		map[string]any{"qty": json.Number("1")}, map[string]any{"qty": json.Number("3")}))
+	require.Empty(t, findCELStub(t, b, nil,
+		map[string]any{"qty": json.Number("1")}, map[string]any{"qty": json.Number("0")}))
+}
+
+func TestCELExplain(t *testing.T) {
+	t.Parallel()
+
+	input := stuber.InputData{Equals: map[string]any{"id": "1"}, CEL: `request.amount > 100`}
+	data := map[string]any{"id": "1", "amount": json.Number("5")}
+
+	require.False(t, input.Match(data))
+	require.Equal(t, []stuber.Mismatch{{Operator: "cel", Expected: `request.amount > 100`}}, input.Explain(data))
+
+	data["amount"] = json.Number("500")
+	require.True(t, input.Match(data))
+	require.Empty(t, input.Explain(data))
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"path"
 
+	"github.com/bavix/gripmock/v3/internal/infra/celmatch"
 	"github.com/bavix/gripmock/v3/internal/infra/deeply"
 )
 
@@ -33,6 +34,71 @@ func matches(expected map[string]any, actual any) bool {
 	return deeply.MatchesIgnoreArrayOrder(expected, actual)
 }
 
+// celMatch checks if the CEL expression holds with data bound to variable. An
+// empty expression always holds.
+func celMatch(expression, variable string, data map[string]any) bool {
+	return expression == "" || celmatch.Match(expression, variable, data)
+}
+
+// rankCEL scores a holding CEL expression by the number of fields it reads, so
+// it weighs like the same checks written key by key.
+func rankCEL(expression, variable string, data map[string]any) float64 {
+	if expression == "" || !celmatch.Match(expression, variable, data) {
+		return 0
+	}
+
+	return float64(celmatch.Weight(expression))
+}
+
+// whereMatch checks if every field of the query meets its condition. A field
+// the query lacks is checked as absent.
+func whereMatch(where map[string]any, data map[string]any) bool {
+	for key, condition := range where {
+		cond, ok := condition.(map[string]any)
+		if !ok {
+			return false
+		}
+
+		value, exists := findValueWithVariations(data, key)
+		if !deeply.Satisfies(cond, value, exists) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rankWhere scores the conditions the query meets by the fields they check.
+func rankWhere(where map[string]any, data map[string]any) float64 {
+	var rank float64
+
+	for key, condition := range where {
+		cond, ok := condition.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if value, exists := findValueWithVariations(data, key); deeply.Satisfies(cond, value, exists) {
+			rank += float64(deeply.ConditionWeight(cond))
+		}
+	}
+
+	return rank
+}
+
+// whereFields is how many fields the conditions check.
+func whereFields(where map[string]any) int {
+	n := 0
+
+	for _, condition := range where {
+		if cond, ok := condition.(map[string]any); ok {
+			n += deeply.ConditionWeight(cond)
+		}
+	}
+
+	return n
+}
+
 // globMatch checks if the expected map matches the actual value using glob patterns.
 //
 // It returns true if all glob patterns match, otherwise false.
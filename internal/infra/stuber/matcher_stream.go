@@ -1,19 +1,25 @@
 package stuber
 
-import "github.com/bavix/gripmock/v3/internal/infra/deeply"
+import (
+	"github.com/bavix/gripmock/v3/internal/infra/celmatch"
+	"github.com/bavix/gripmock/v3/internal/infra/deeply"
+)
 
 // streamItemMatches checks if a single query item matches the stub item matchers.
 //
 //nolint:cyclop
 func streamItemMatches(stubItem InputData, queryItem map[string]any) bool {
-	if len(stubItem.Equals) == 0 && len(stubItem.Contains) == 0 && len(stubItem.Matches) == 0 && len(stubItem.Glob) == 0 {
+	if len(stubItem.Equals) == 0 && len(stubItem.Contains) == 0 && len(stubItem.Matches) == 0 &&
+		len(stubItem.Glob) == 0 && stubItem.CEL == "" && len(stubItem.Where) == 0 {
 		return false
 	}
 
 	return (len(stubItem.Equals) == 0 || equals(stubItem.Equals, queryItem, stubItem.IgnoreArrayOrder)) &&
 		(len(stubItem.Contains) == 0 || contains(stubItem.Contains, queryItem)) &&
 		(len(stubItem.Matches) == 0 || matches(stubItem.Matches, queryItem)) &&
-		(len(stubItem.Glob) == 0 || globMatch(stubItem.Glob, queryItem))
+		(len(stubItem.Glob) == 0 || globMatch(stubItem.Glob, queryItem)) &&
+		celMatch(stubItem.CEL, celmatch.Request, queryItem) &&
+		whereMatch(stubItem.Where, queryItem)
 }
 
 // matchStreamElements checks if the query stream matches the stub stream.
@@ -105,7 +111,8 @@ func computeStreamElementRanksBroadcast(
 
 		containsRank := deeply.RankMatch(stubPattern.Contains, queryItem)
 		matchesRank := deeply.RankMatch(stubPattern.Matches, queryItem)
-		elementRank := equalsRank*100.0 + containsRank*0.1 + matchesRank*0.1 //nolint:mnd
+		celRank := rankCEL(stubPattern.CEL, celmatch.Request, queryItem) + rankWhere(stubPattern.Where, queryItem)
+		elementRank := equalsRank*100.0 + containsRank*0.1 + matchesRank*0.1 + celRank*0.1 //nolint:mnd
 		totalRank += elementRank
 
 		if equalsRank > 0.99 { //nolint:mnd
@@ -134,7 +141,8 @@ func computeStreamElementRanks(
 
 		containsRank := deeply.RankMatch(stubItem.Contains, queryItem)
 		matchesRank := deeply.RankMatch(stubItem.Matches, queryItem)
-		elementRank := equalsRank*100.0 + containsRank*0.1 + matchesRank*0.1 //nolint:mnd
+		celRank := rankCEL(stubItem.CEL, celmatch.Request, queryItem) + rankWhere(stubItem.Where, queryItem)
+		elementRank := equalsRank*100.0 + containsRank*0.1 + matchesRank*0.1 + celRank*0.1 //nolint:mnd
 		totalRank += elementRank
 
 		if equalsRank > 0.99 { //nolint:mnd
@@ -150,6 +158,12 @@ func countStreamMatchers(stubStream []InputData) float64 {
 
 	for _, stubItem := range stubStream {
 		total += countNonNil(stubItem.Equals) + countNonNil(stubItem.Contains) + countNonNil(stubItem.Matches)
+
+		if stubItem.CEL != "" {
+			total += celmatch.Weight(stubItem.CEL)
+		}
+
+		total += whereFields(stubItem.Where)
 	}
 
 	return float64(total)
@@ -0,0 +1,97 @@
+package stuber_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func TestWhereMatchesInput(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(celStub("big", stuber.InputData{
+		Where: map[string]any{
+			"amount":     map[string]any{"between": []any{100, 500}},
+			"items":      map[string]any{"len": map[string]any{"gte": 2}, "every": map[string]any{"qty": map[string]any{"gt": 0}}},
+			"coupon":     map[string]any{"exists": false},
+			"created_at": map[string]any{"gte": "2024-01-01T00:00:00Z"},
+		},
+	}, stuber.InputHeader{}))
+
+	order := func(amount string, qty ...string) map[string]any {
+		items := make([]any, len(qty))
+		for i, q := range qty {
+			items[i] = map[string]any{"qty": json.Number(q)}
+		}
+
+		return map[string]any{"amount": json.Number(amount), "items": items, "createdAt": "2024-06-01T10:00:00Z"}
+	}
+
+	require.Equal(t, "big", findCELStub(t, b, nil, order("150", "1", "2")))
+	require.Empty(t, findCELStub(t, b, nil, order("50", "1", "2")))
+	require.Empty(t, findCELStub(t, b, nil, order("150", "1")))
+	require.Empty(t, findCELStub(t, b, nil, order("150", "1", "0")))
+
+	withCoupon := order("150", "1", "2")
+	withCoupon["coupon"] = "SALE"
+	require.Empty(t, findCELStub(t, b, nil, withCoupon))
+}
+
+func TestWhereRanksByFieldsChecked(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(
+		celStub("amount", stuber.InputData{Where: map[string]any{"amount": map[string]any{"gt": 100}}}, stuber.InputHeader{}),
+		celStub("amount-ttl", stuber.InputData{Where: map[string]any{
+			"amount": map[string]any{"gt": 100},
+			"ttl":    map[string]any{"lt": "1m"},
+		}}, stuber.InputHeader{}),
+	)
+
+	require.Equal(t, "amount-ttl", findCELStub(t, b, nil, map[string]any{"amount": json.Number("150"), "ttl": "30s"}))
+	require.Equal(t, "amount", findCELStub(t, b, nil, map[string]any{"amount": json.Number("150"), "ttl": "2m"}))
+}
+
+func TestWhereMatchesEmptyMessage(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(celStub("absent", stuber.InputData{
+		Where: map[string]any{"coupon": map[string]any{"exists": false}},
+	}, stuber.InputHeader{}))
+
+	require.Equal(t, "absent", findCELStub(t, b, nil, map[string]any{}))
+}
+
+func TestWhereInAnyOf(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(celStub("outlier", stuber.InputData{AnyOf: []stuber.AnyOfElement{
+		{Where: map[string]any{"amount": map[string]any{"gt": 100}}},
+		{Where: map[string]any{"amount": map[string]any{"lt": 0}}},
+		{Where: map[string]any{"amount": map[string]any{"exists": false}}},
+	}}, stuber.InputHeader{}))
+
+	require.Equal(t, "outlier", findCELStub(t, b, nil, map[string]any{"amount": json.Number("150")}))
+	require.Equal(t, "outlier", findCELStub(t, b, nil, map[string]any{"amount": json.Number("-5")}))
+	require.Equal(t, "outlier", findCELStub(t, b, nil, map[string]any{}))
+	require.Empty(t, findCELStub(t, b, nil, map[string]any{"amount": json.Number("50")}))
+}
+
+func TestWhereExplain(t *testing.T) {
+	t.Parallel()
+
+	input := stuber.InputData{Where: map[string]any{"amount": map[string]any{"gt": 100}}}
+	data := map[string]any{"amount": json.Number("5")}
+
+	require.False(t, input.Match(data))
+	require.Equal(t, []stuber.Mismatch{{
+		Field: "amount", Operator: "where", Expected: map[string]any{"gt": 100}, Actual: json.Number("5"),
+	}}, input.Explain(data))
+}
@@ -0,0 +1,129 @@
+package stuber
+
+import (
+	"slices"
+	"sync"
+
+	"github.com/google/uuid"
+)
+
+// Persister keeps runtime-created stubs outside the process so they survive a
+// restart. Once attached with EnablePersistence, Budgerigar writes through it
+// on every mutation of a stub whose source is persistent.
+type Persister interface {
+	// Load returns every stub kept by the persister. Stubs that could be read
+	// are returned together with an error describing the ones that could not.
+	Load() ([]*Stub, error)
+	// Save stores the stubs, replacing earlier versions with the same ID.
+	Save(stubs ...*Stub) error
+	// Delete removes the stubs with the given IDs; unknown IDs are ignored.
+	Delete(ids ...uuid.UUID) error
+}
+
+// IsPersistentSource reports whether stubs of the source are written to a
+// Persister. File stubs are not: their files already are the persistent copy.
+func IsPersistentSource(source string) bool {
+	switch source {
+	case SourceRest, SourceMCP, SourceProxy:
+		return true
+	default:
+		return false
+	}
+}
+
+// persistence serializes in-memory mutations with their write-through so the
+// persister always ends in the same order as storage. It remembers the IDs it
+// has written, so stubs that were never persisted, such as file stubs, cost
+// no store deletes. Callers hold mu.
+type persistence struct {
+	mu        sync.Mutex
+	store     Persister
+	onError   func(error)
+	persisted map[uuid.UUID]struct{}
+}
+
+// save writes stubs with a persistent source and removes the persisted copy of
+// the others, e.g. a REST stub later replaced by a file stub with the same ID.
+func (p *persistence) save(stubs []*Stub) {
+	kept := make([]*Stub, 0, len(stubs))
+	dropped := make([]uuid.UUID, 0)
+
+	for _, stub := range stubs {
+		if IsPersistentSource(stub.Source) {
+			kept = append(kept, stub)
+		} else {
+			dropped = append(dropped, stub.ID)
+		}
+	}
+
+	if len(kept) > 0 {
+		// Marked even when Save fails: it may have written part of them.
+		for _, stub := range kept {
+			p.persisted[stub.ID] = struct{}{}
+		}
+
+		p.report(p.store.Save(kept...))
+	}
+
+	p.delete(dropped)
+}
+
+// delete removes the persisted copies among ids.
+func (p *persistence) delete(ids []uuid.UUID) {
+	stored := make([]uuid.UUID, 0, len(ids))
+
+	for _, id := range ids {
+		if _, ok := p.persisted[id]; ok {
+			delete(p.persisted, id)
+			stored = append(stored, id)
+		}
+	}
+
+	if len(stored) > 0 {
+		p.report(p.store.Delete(stored...))
+	}
+}
+
+func (p *persistence) report(err error) {
+	if err != nil && p.onError != nil {
+		p.onError(err)
+	}
+}
+
+// EnablePersistence restores the stubs kept by store and from then on writes
+// every PutMany, UpdateMany and deletion through to it. Write errors are
+// passed to onError, which may be nil. It returns the number of restored
+// stubs; an error from Load is returned after restoring the readable ones.
+func (b *Budgerigar) EnablePersistence(store Persister, onError func(error)) (int, error) {
+	stubs, err := store.Load()
+
+	stubs = slices.DeleteFunc(stubs, func(stub *Stub) bool {
+		return stub == nil || stub.ID == uuid.Nil
+	})
+
+	p := &persistence{store: store, onError: onError, persisted: make(map[uuid.UUID]struct{}, len(stubs))}
+	for _, stub := range stubs {
+		p.persisted[stub.ID] = struct{}{}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	b.searcher.upsert(stubs...)
+	b.persist.Store(p)
+
+	return len(stubs), err
+}
+
+// idsOf returns the IDs of the stubs accepted by keep.
+func idsOf(stubs []*Stub, keep func(*Stub) bool) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(stubs))
+
+	for _, stub := range stubs {
+		if keep(stub) {
+			ids = append(ids, stub.ID)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,190 @@
+package stuber
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/cockroachdb/errors"
+	"github.com/goccy/go-yaml"
+	"github.com/google/uuid"
+
+	"github.com/bavix/gripmock/v3/internal/infra/jsondecoder"
+)
+
+const (
+	persistFileSuffix = ".yaml"
+	persistFilePerm   = 0o640
+)
+
+// ErrStubStoreDirRequired is returned when a DirStore is opened without a directory.
+var ErrStubStoreDirRequired = errors.New("stub store directory is required")
+
+// DirStore is a Persister keeping one YAML file per stub, named after its ID,
+// in a single directory. Each file holds the stub in the same shape the stub
+// loader reads, so the directory can also be mounted as ordinary stubs. Files
+// are replaced atomically, so a crash never leaves a half-written stub.
+type DirStore struct {
+	mu    sync.Mutex
+	dir   string
+	known map[uuid.UUID]struct{}
+}
+
+var _ Persister = (*DirStore)(nil)
+
+// OpenDirStore opens the stubs kept in dir, creating the directory when it
+// does not exist.
+func OpenDirStore(dir string) (*DirStore, error) {
+	if dir == "" {
+		return nil, ErrStubStoreDirRequired
+	}
+
+	if err := os.MkdirAll(dir, dumpDirPerm); err != nil {
+		return nil, errors.Wrap(err, "failed to create stub store directory")
+	}
+
+	return &DirStore{dir: dir, known: make(map[uuid.UUID]struct{})}, nil
+}
+
+// Load implements Persister.
+func (s *DirStore) Load() ([]*Stub, error) {
+	entries, err := os.ReadDir(s.dir)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to read stub store directory")
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		stubs []*Stub
+		errs  []error
+	)
+
+	for _, entry := range entries {
+		id, ok := parsePersistFileName(entry.Name())
+		if !ok || entry.IsDir() {
+			continue
+		}
+
+		stub, err := s.read(id)
+		if err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		s.known[stub.ID] = struct{}{}
+		stubs = append(stubs, stub)
+	}
+
+	return stubs, errors.Join(errs...)
+}
+
+func (s *DirStore) read(id uuid.UUID) (*Stub, error) {
+	path := s.path(id)
+
+	raw, err := os.ReadFile(path) //nolint:gosec
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read stub %s", path)
+	}
+
+	raw, err = yaml.YAMLToJSON(raw)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to decode stub %s", path)
+	}
+
+	var stub Stub
+	if err := jsondecoder.Unmarshal(raw, &stub); err != nil {
+		return nil, errors.Wrapf(err, "failed to decode stub %s", path)
+	}
+
+	if stub.ID == uuid.Nil {
+		stub.ID = id
+	}
+
+	return &stub, nil
+}
+
+// Save implements Persister.
+func (s *DirStore) Save(stubs ...*Stub) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+
+	for _, stub := range stubs {
+		if err := s.write(stub); err != nil {
+			errs = append(errs, err)
+
+			continue
+		}
+
+		s.known[stub.ID] = struct{}{}
+	}
+
+	return errors.Join(errs...)
+}
+
+// write replaces the stub's file atomically.
+func (s *DirStore) write(stub *Stub) error {
+	raw, err := json.Marshal(stub)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode stub %s", stub.ID)
+	}
+
+	raw, err = yaml.JSONToYAML(raw)
+	if err != nil {
+		return errors.Wrapf(err, "failed to encode stub %s", stub.ID)
+	}
+
+	err = WriteFileAtomic(s.path(stub.ID), persistFilePerm, func(w io.Writer) error {
+		_, err := w.Write(raw)
+
+		return err
+	})
+
+	return errors.Wrapf(err, "failed to write stub %s", stub.ID)
+}
+
+// Delete implements Persister.
+func (s *DirStore) Delete(ids ...uuid.UUID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var errs []error
+
+	for _, id := range ids {
+		if _, ok := s.known[id]; !ok {
+			continue
+		}
+
+		if err := os.Remove(s.path(id)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete stub %s", id))
+
+			continue
+		}
+
+		delete(s.known, id)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (s *DirStore) path(id uuid.UUID) string {
+	return filepath.Join(s.dir, id.String()+persistFileSuffix)
+}
+
+func parsePersistFileName(name string) (uuid.UUID, bool) {
+	base, ok := strings.CutSuffix(name, persistFileSuffix)
+	if !ok {
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(base)
+
+	return id, err == nil
+}
@@ -0,0 +1,170 @@
+package stuber_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func openPersistent(t *testing.T, dir string) *stuber.Budgerigar {
+	t.Helper()
+
+	store, err := stuber.OpenDirStore(dir)
+	require.NoError(t, err)
+
+	b := stuber.NewBudgerigar()
+	_, err = b.EnablePersistence(store, func(err error) { t.Errorf("persist: %v", err) })
+	require.NoError(t, err)
+
+	return b
+}
+
+func persistedStub(source, session string) *stuber.Stub {
+	return &stuber.Stub{
+		Service: "shop.Orders",
+		Method:  "GetOrder",
+		Session: session,
+		Source:  source,
+		Input:   stuber.InputData{Equals: map[string]any{"id": "1"}},
+		Output:  stuber.Output{Data: map[string]any{"status": "paid"}},
+	}
+}
+
+func TestDirStoreRestoresRuntimeStubs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	b := openPersistent(t, dir)
+
+	ids := b.PutMany(
+		persistedStub(stuber.SourceRest, ""),
+		persistedStub(stuber.SourceProxy, "s1"),
+		persistedStub(stuber.SourceFile, ""),
+	)
+
+	restored := openPersistent(t, dir)
+	require.Len(t, restored.All(), 2)
+
+	rest := restored.FindByID(ids[0])
+	require.NotNil(t, rest)
+	require.Equal(t, stuber.SourceRest, rest.Source)
+	require.Equal(t, map[string]any{"id": "1"}, rest.Input.Equals)
+	require.Equal(t, map[string]any{"status": "paid"}, rest.Output.Data)
+
+	require.Equal(t, "s1", restored.FindByID(ids[1]).Session)
+	require.Nil(t, restored.FindByID(ids[2]))
+}
+
+func TestDirStoreWritesThroughMutations(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	b := openPersistent(t, dir)
+
+	ids := b.PutMany(
+		persistedStub(stuber.SourceRest, ""),
+		persistedStub(stuber.SourceRest, "s1"),
+		persistedStub(stuber.SourceMCP, ""),
+	)
+
+	updated := persistedStub(stuber.SourceRest, "")
+	updated.ID = ids[0]
+	updated.Priority = 7
+	b.UpdateMany(updated)
+
+	require.Equal(t, 1, b.DeleteSession("s1"))
+	require.Equal(t, 1, b.DeleteByID(ids[2]))
+
+	restored := openPersistent(t, dir)
+	require.Len(t, restored.All(), 1)
+	require.Equal(t, 7, restored.FindByID(ids[0]).Priority)
+
+	restored.Clear()
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Empty(t, entries)
+}
+
+func TestDirStoreFileStubReplacesPersistedCopy(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	b := openPersistent(t, dir)
+
+	ids := b.PutMany(persistedStub(stuber.SourceRest, ""))
+
+	fromFile := persistedStub(stuber.SourceFile, "")
+	fromFile.ID = ids[0]
+	b.PutMany(fromFile)
+
+	require.NoFileExists(t, filepath.Join(dir, ids[0].String()+".yaml"))
+}
+
+func TestDirStoreSkipsUnreadableFiles(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	b := openPersistent(t, dir)
+	b.PutMany(persistedStub(stuber.SourceRest, ""))
+
+	broken := filepath.Join(dir, uuid.NewString()+".yaml")
+	require.NoError(t, os.WriteFile(broken, []byte("service: [\n"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), []byte("ignored"), 0o600))
+
+	store, err := stuber.OpenDirStore(dir)
+	require.NoError(t, err)
+
+	restored, err := stuber.NewBudgerigar().EnablePersistence(store, nil)
+	require.Error(t, err)
+	require.Equal(t, 1, restored)
+}
+
+func TestOpenDirStoreRequiresDir(t *testing.T) {
+	t.Parallel()
+
+	_, err := stuber.OpenDirStore("")
+	require.ErrorIs(t, err, stuber.ErrStubStoreDirRequired)
+}
+
+type countingStore struct {
+	stuber.Persister
+
+	deleted []uuid.UUID
+}
+
+func (s *countingStore) Delete(ids ...uuid.UUID) error {
+	s.deleted = append(s.deleted, ids...)
+
+	return s.Persister.Delete(ids...)
+}
+
+func TestDirStoreDeletesOnlyPersistedStubs(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	openPersistent(t, dir).PutMany(persistedStub(stuber.SourceRest, ""))
+
+	inner, err := stuber.OpenDirStore(dir)
+	require.NoError(t, err)
+
+	store := &countingStore{Persister: inner}
+	b := stuber.NewBudgerigar()
+	_, err = b.EnablePersistence(store, nil)
+	require.NoError(t, err)
+
+	restored := b.All()[0].ID
+	fileIDs := b.PutMany(persistedStub(stuber.SourceFile, ""), persistedStub(stuber.SourceFile, "s1"))
+
+	b.DeleteByID(fileIDs[0])
+	b.DeleteSession("s1")
+	require.Empty(t, store.deleted, "stubs that were never persisted were deleted from the store")
+
+	b.Clear()
+	require.Equal(t, []uuid.UUID{restored}, store.deleted)
+}
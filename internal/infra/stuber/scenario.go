@@ -0,0 +1,153 @@
+package stuber
+
+import (
+	"maps"
+	"slices"
+)
+
+// ScenarioStarted is the state every scenario is in until a stub moves it on.
+const ScenarioStarted = "Started"
+
+// Scenario is the current state of a scenario as seen from one session.
+type Scenario struct {
+	Name           string   `json:"name"`
+	State          string   `json:"state"`
+	PossibleStates []string `json:"possibleStates"`
+}
+
+// scenarioKey identifies a scenario's state per session. Session empty = global state.
+type scenarioKey struct {
+	name    string
+	session string
+}
+
+// scenarioState returns the current state of the scenario. Caller holds s.mu.
+func (s *searcher) scenarioState(name, session string) string {
+	if state, ok := s.scenarioStates[scenarioKey{name: name, session: session}]; ok {
+		return state
+	}
+
+	return ScenarioStarted
+}
+
+// inRequiredState reports whether the stub's scenario allows it to match. Caller holds s.mu.
+func (s *searcher) inRequiredState(stub *Stub, session string) bool {
+	if stub.Scenario == "" || stub.RequiredState == "" {
+		return true
+	}
+
+	return s.scenarioState(stub.Scenario, session) == stub.RequiredState
+}
+
+// advanceScenario moves the stub's scenario to NewState. Caller holds s.mu for writing.
+func (s *searcher) advanceScenario(stub *Stub, session string) {
+	if stub.Scenario == "" || stub.NewState == "" {
+		return
+	}
+
+	s.scenarioStates[scenarioKey{name: stub.Scenario, session: session}] = stub.NewState
+}
+
+// filterByScenarioState removes stubs whose scenario is not in their required state.
+func (s *searcher) filterByScenarioState(stubs []*Stub, session string) []*Stub {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	filtered := make([]*Stub, 0, len(stubs))
+	for _, stub := range stubs {
+		if s.inRequiredState(stub, session) {
+			filtered = append(filtered, stub)
+		}
+	}
+
+	return filtered
+}
+
+// scenarios lists the scenarios declared by stubs visible to the session, sorted by name.
+func (s *searcher) scenarios(session string) []Scenario {
+	declared := make(map[string]map[string]struct{})
+
+	for stub := range s.storage.values() {
+		if stub.Scenario == "" || !isStubVisibleForSession(stub.Session, session) {
+			continue
+		}
+
+		states, ok := declared[stub.Scenario]
+		if !ok {
+			states = map[string]struct{}{ScenarioStarted: {}}
+			declared[stub.Scenario] = states
+		}
+
+		for _, state := range []string{stub.RequiredState, stub.NewState} {
+			if state != "" {
+				states[state] = struct{}{}
+			}
+		}
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	out := make([]Scenario, 0, len(declared))
+
+	for _, name := range slices.Sorted(maps.Keys(declared)) {
+		out = append(out, Scenario{
+			Name:           name,
+			State:          s.scenarioState(name, session),
+			PossibleStates: slices.Sorted(maps.Keys(declared[name])),
+		})
+	}
+
+	return out
+}
+
+// setScenarioState moves the scenario to state within the session; an empty
+// state resets it to ScenarioStarted. It returns false when no stub visible to
+// the session declares the scenario.
+func (s *searcher) setScenarioState(name, session, state string) bool {
+	if !s.declaresScenario(name, session) {
+		return false
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key := scenarioKey{name: name, session: session}
+
+	if state == "" || state == ScenarioStarted {
+		delete(s.scenarioStates, key)
+
+		return true
+	}
+
+	s.scenarioStates[key] = state
+
+	return true
+}
+
+func (s *searcher) declaresScenario(name, session string) bool {
+	for stub := range s.storage.values() {
+		if stub.Scenario == name && isStubVisibleForSession(stub.Session, session) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// resetScenarios returns every scenario of the session to ScenarioStarted.
+func (s *searcher) resetScenarios(session string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.dropScenarioStates(session)
+}
+
+// dropScenarioStates forgets the session's scenario states. Caller holds s.mu for writing.
+func (s *searcher) dropScenarioStates(session string) {
+	for key := range s.scenarioStates {
+		if key.session == session {
+			delete(s.scenarioStates, key)
+		}
+	}
+}
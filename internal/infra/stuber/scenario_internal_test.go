@@ -0,0 +1,139 @@
+package stuber_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func orderFlowStubs(session string) []*stuber.Stub {
+	step := func(requiredState, newState, status string) *stuber.Stub {
+		return &stuber.Stub{
+			Service:       "shop.Orders",
+			Method:        "GetOrder",
+			Session:       session,
+			Scenario:      "order",
+			RequiredState: requiredState,
+			NewState:      newState,
+			Input:         stuber.InputData{Equals: map[string]any{"id": "1"}},
+			Output:        stuber.Output{Data: map[string]any{"status": status}},
+		}
+	}
+
+	return []*stuber.Stub{
+		step(stuber.ScenarioStarted, "Paid", "created"),
+		step("Paid", "Shipped", "paid"),
+		step("Shipped", "", "shipped"),
+	}
+}
+
+func findOrderStatus(t *testing.T, b *stuber.Budgerigar, session string) string {
+	t.Helper()
+
+	result, err := b.FindByQuery(stuber.Query{
+		Service: "shop.Orders",
+		Method:  "GetOrder",
+		Session: session,
+		Input:   []map[string]any{{"id": "1"}},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, result.Found())
+
+	data, ok := result.Found().Output.Data.(map[string]any)
+	require.True(t, ok)
+
+	status, _ := data["status"].(string)
+
+	return status
+}
+
+func TestScenarioTransitions(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(orderFlowStubs("")...)
+
+	require.Equal(t, "created", findOrderStatus(t, b, ""))
+	require.Equal(t, "paid", findOrderStatus(t, b, ""))
+	require.Equal(t, "shipped", findOrderStatus(t, b, ""))
+	require.Equal(t, "shipped", findOrderStatus(t, b, ""))
+
+	require.Equal(t, []stuber.Scenario{{
+		Name:           "order",
+		State:          "Shipped",
+		PossibleStates: []string{"Paid", "Shipped", stuber.ScenarioStarted},
+	}}, b.Scenarios(""))
+
+	b.ResetScenarios("")
+	require.Equal(t, "created", findOrderStatus(t, b, ""))
+}
+
+func TestScenarioStateIsPerSession(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(orderFlowStubs("")...)
+
+	require.Equal(t, "created", findOrderStatus(t, b, "A"))
+	require.Equal(t, "paid", findOrderStatus(t, b, "A"))
+
+	require.Equal(t, "created", findOrderStatus(t, b, "B"))
+	require.Equal(t, "created", findOrderStatus(t, b, ""))
+
+	require.Equal(t, "Shipped", b.Scenarios("A")[0].State)
+	require.Equal(t, "Paid", b.Scenarios("B")[0].State)
+
+	b.ResetScenarios("A")
+	require.Equal(t, stuber.ScenarioStarted, b.Scenarios("A")[0].State)
+	require.Equal(t, "Paid", b.Scenarios("B")[0].State)
+}
+
+func TestSetScenarioState(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(orderFlowStubs("A")...)
+
+	require.False(t, b.SetScenarioState("order", "", "Paid"), "session stubs are invisible globally")
+	require.False(t, b.SetScenarioState("missing", "A", "Paid"))
+
+	require.True(t, b.SetScenarioState("order", "A", "Shipped"))
+	require.Equal(t, "shipped", findOrderStatus(t, b, "A"))
+
+	require.True(t, b.SetScenarioState("order", "A", ""))
+	require.Equal(t, "created", findOrderStatus(t, b, "A"))
+
+	b.DeleteSession("A")
+	require.Empty(t, b.Scenarios("A"))
+}
+
+func TestScenarioRequiredStateMismatchIsNotFound(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+	b.PutMany(&stuber.Stub{
+		Service:       "shop.Orders",
+		Method:        "GetOrder",
+		Scenario:      "order",
+		RequiredState: "Paid",
+		Input:         stuber.InputData{Equals: map[string]any{"id": "1"}},
+		Output:        stuber.Output{Data: map[string]any{"status": "paid"}},
+	})
+
+	_, err := b.FindByQuery(stuber.Query{
+		Service: "shop.Orders",
+		Method:  "GetOrder",
+		Input:   []map[string]any{{"id": "1"}},
+	})
+	require.ErrorIs(t, err, stuber.ErrStubNotFound)
+
+	report := b.InspectQuery(stuber.Query{
+		Service: "shop.Orders",
+		Method:  "GetOrder",
+		Input:   []map[string]any{{"id": "1"}},
+	})
+	require.Len(t, report.Candidates, 1)
+	require.Contains(t, report.Candidates[0].ExcludedBy, "scenario")
+}
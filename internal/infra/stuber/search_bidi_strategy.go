@@ -1,5 +1,7 @@
 package stuber
 
+import "github.com/bavix/gripmock/v3/internal/infra/celmatch"
+
 func matchBidiStubHeaders(stub *Stub, queryHeaders map[string]any) bool {
 	if stub.Headers.Len() > 0 && len(queryHeaders) == 0 {
 		return false
@@ -79,7 +81,9 @@ func matchInputData(inputData InputData, messageData map[string]any) bool {
 
 	if !matchInputEquals(inputData.Equals, messageData) ||
 		!matchInputContains(inputData.Contains, messageData) ||
-		!matchInputRegex(inputData.Matches, messageData) {
+		!matchInputRegex(inputData.Matches, messageData) ||
+		!celMatch(inputData.CEL, celmatch.Request, messageData) ||
+		!whereMatch(inputData.Where, messageData) {
 		return false
 	}
 
@@ -91,7 +95,8 @@ func matchInputData(inputData InputData, messageData map[string]any) bool {
 		alt := &inputData.AnyOf[i]
 		if matchInputEquals(alt.Equals, messageData) &&
 			matchInputContains(alt.Contains, messageData) &&
-			matchInputRegex(alt.Matches, messageData) {
+			matchInputRegex(alt.Matches, messageData) &&
+			whereMatch(alt.Where, messageData) {
 			return true
 		}
 	}
@@ -106,7 +111,8 @@ func rankInputData(inputData InputData, messageData map[string]any) float64 {
 
 	base := rankInputEquals(inputData.Equals, messageData) +
 		rankInputContains(inputData.Contains, messageData) +
-		rankInputRegex(inputData.Matches, messageData)
+		rankInputRegex(inputData.Matches, messageData) +
+		(rankCEL(inputData.CEL, celmatch.Request, messageData)+rankWhere(inputData.Where, messageData))*10 //nolint:mnd
 
 	if len(inputData.AnyOf) == 0 {
 		return base
@@ -119,7 +125,8 @@ func rankInputData(inputData InputData, messageData map[string]any) float64 {
 
 		r := rankInputEquals(alt.Equals, messageData) +
 			rankInputContains(alt.Contains, messageData) +
-			rankInputRegex(alt.Matches, messageData)
+			rankInputRegex(alt.Matches, messageData) +
+			rankWhere(alt.Where, messageData)*10 //nolint:mnd
 
 		if r > bestAlt {
 			bestAlt = r
@@ -240,5 +247,6 @@ func keyStyleFlags(s string) (bool, bool) {
 }
 
 func isInputMatcherEmpty(inputData InputData) bool {
-	return len(inputData.Equals) == 0 && len(inputData.Contains) == 0 && len(inputData.Matches) == 0 && len(inputData.AnyOf) == 0
+	return len(inputData.Equals) == 0 && len(inputData.Contains) == 0 && len(inputData.Matches) == 0 &&
+		len(inputData.AnyOf) == 0 && inputData.CEL == "" && len(inputData.Where) == 0
 }
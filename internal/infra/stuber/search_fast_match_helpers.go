@@ -7,15 +7,28 @@ func inputHasConditions(e InputData) bool {
 		len(e.Contains) > 0 ||
 		len(e.Matches) > 0 ||
 		len(e.Glob) > 0 ||
+		e.CEL != "" ||
+		len(e.Where) > 0 ||
 		slices.ContainsFunc(e.AnyOf, anyOfElementHasFields)
 }
 
 func anyOfElementHasFields(alt AnyOfElement) bool {
-	return len(alt.Equals) > 0 || len(alt.Contains) > 0 || len(alt.Matches) > 0 || len(alt.Glob) > 0
+	return len(alt.Equals) > 0 || len(alt.Contains) > 0 || len(alt.Matches) > 0 || len(alt.Glob) > 0 ||
+		len(alt.Where) > 0
 }
 
 func anyOfElementDeclares(alt AnyOfElement) bool {
-	return alt.Equals != nil || alt.Contains != nil || alt.Matches != nil || alt.Glob != nil
+	return alt.Equals != nil || alt.Contains != nil || alt.Matches != nil || alt.Glob != nil || alt.Where != nil
+}
+
+// anyOfMatchesEmpty reports whether an empty message meets one of the
+// alternatives: one with no field matchers whose conditions hold on absent
+// fields.
+func anyOfMatchesEmpty(anyOf []AnyOfElement) bool {
+	return len(anyOf) == 0 || slices.ContainsFunc(anyOf, func(alt AnyOfElement) bool {
+		return len(alt.Equals) == 0 && len(alt.Contains) == 0 && len(alt.Matches) == 0 && len(alt.Glob) == 0 &&
+			whereMatch(alt.Where, nil)
+	})
 }
 
 func (s *searcher) fastMatchV2(query Query, stub *Stub) bool {
@@ -104,10 +117,15 @@ func (s *searcher) fastRankV2(query Query, stub *Stub) float64 {
 //nolint:cyclop
 func (s *searcher) fastMatchInput(queryData map[string]any, stubInput InputData) bool {
 	if len(queryData) == 0 {
-		return !inputHasConditions(stubInput)
+		// An empty message still meets conditions on absent fields.
+		rest := stubInput
+		rest.Where = nil
+		rest.AnyOf = nil
+
+		return !inputHasConditions(rest) && whereMatch(stubInput.Where, queryData) && anyOfMatchesEmpty(stubInput.AnyOf)
 	}
 
-	if len(stubInput.AnyOf) == 0 && len(stubInput.Glob) == 0 {
+	if len(stubInput.AnyOf) == 0 && len(stubInput.Glob) == 0 && stubInput.CEL == "" && len(stubInput.Where) == 0 {
 		if len(stubInput.Equals) > 0 && len(stubInput.Contains) == 0 && len(stubInput.Matches) == 0 {
 			return equals(stubInput.Equals, queryData, stubInput.IgnoreArrayOrder)
 		}
@@ -127,8 +145,8 @@ func (s *searcher) fastMatchInput(queryData map[string]any, stubInput InputData)
 //nolint:cyclop
 func (s *searcher) fastMatchStream(queryStream []map[string]any, stubStream []InputData) bool {
 	declaresMatcher := func(e InputData) bool {
-		return e.Equals != nil || e.Contains != nil || e.Matches != nil || e.Glob != nil ||
-			slices.ContainsFunc(e.AnyOf, anyOfElementDeclares)
+		return e.Equals != nil || e.Contains != nil || e.Matches != nil || e.Glob != nil || e.CEL != "" ||
+			e.Where != nil || slices.ContainsFunc(e.AnyOf, anyOfElementDeclares)
 	}
 
 	if !slices.ContainsFunc(stubStream, declaresMatcher) {
@@ -166,7 +184,7 @@ func (s *searcher) fastRankInput(queryData map[string]any, stubInput InputData)
 	}
 
 	if len(stubInput.Equals) > 0 && len(stubInput.Contains) == 0 && len(stubInput.Matches) == 0 &&
-		len(stubInput.Glob) == 0 && len(stubInput.AnyOf) == 0 {
+		len(stubInput.Glob) == 0 && len(stubInput.AnyOf) == 0 && stubInput.CEL == "" && len(stubInput.Where) == 0 {
 		if equals(stubInput.Equals, queryData, stubInput.IgnoreArrayOrder) {
 			return 1.0
 		}
@@ -3,6 +3,8 @@ package stuber
 import (
 	"bytes"
 	"slices"
+
+	"github.com/bavix/gripmock/v3/internal/infra/celmatch"
 )
 
 type rankedMatch struct {
@@ -99,10 +101,11 @@ func sortRankedMatches(matches []rankedMatch) {
 func countStubFields(stub *Stub) int {
 	count := len(stub.Input.Equals) + len(stub.Input.Contains) + len(stub.Input.Matches) + len(stub.Input.Glob)
 	count += len(stub.Headers.Equals) + len(stub.Headers.Contains) + len(stub.Headers.Matches) + len(stub.Headers.Glob)
+	count += celFields(stub.Input.CEL) + celFields(stub.Headers.CEL) + whereFields(stub.Input.Where)
 
 	for _, input := range stub.Inputs {
 		count += len(input.Equals) + len(input.Contains) + len(input.Matches) + len(input.Glob)
-		count += countAnyOfFields(input.AnyOf)
+		count += countAnyOfFields(input.AnyOf) + celFields(input.CEL) + whereFields(input.Where)
 	}
 
 	count += countAnyOfFields(stub.Input.AnyOf)
@@ -114,11 +117,19 @@ func countStubFields(stub *Stub) int {
 	return count
 }
 
+func celFields(expression string) int {
+	if expression == "" {
+		return 0
+	}
+
+	return celmatch.Weight(expression)
+}
+
 func countAnyOfFields(anyOf []AnyOfElement) int {
 	var n int
 
 	for _, alt := range anyOf {
-		n += len(alt.Equals) + len(alt.Contains) + len(alt.Matches) + len(alt.Glob)
+		n += len(alt.Equals) + len(alt.Contains) + len(alt.Matches) + len(alt.Glob) + whereFields(alt.Where)
 	}
 
 	return n
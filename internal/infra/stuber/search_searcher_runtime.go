@@ -45,6 +45,8 @@ func (s *searcher) delBySession(session string) int {
 		}
 	}
 
+	s.dropScenarioStates(session)
+
 	return s.storage.delBySession(session)
 }
 
@@ -63,12 +65,13 @@ func (s *searcher) findBy(service, method string) ([]*Stub, error) {
 	return collectStubs(seq), nil
 }
 
-// clear resets call counts, lookup cache and storage.
+// clear resets call counts, scenario states, lookup cache and storage.
 func (s *searcher) clear() {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
 	s.stubCallCount = make(map[callCountKey]int)
+	s.scenarioStates = make(map[scenarioKey]string)
 
 	s.lookupMu.Lock()
 	s.lookupCache = make(map[string]*searcherLookup)
@@ -146,8 +149,9 @@ func (s *searcher) searchByID(query Query) (*Result, error) {
 	return nil, ErrServiceNotFound
 }
 
-// tryReserve atomically checks if the stub can be used (under Times limit) and increments the count.
-// When query.Session is set, the count is per-session (parallel test isolation).
+// tryReserve atomically checks if the stub can be used (under Times limit, scenario in
+// the required state), increments the count and applies the scenario transition.
+// When query.Session is set, the count and scenario state are per-session (parallel test isolation).
 func (s *searcher) tryReserve(query Query, stub *Stub) (int, bool) {
 	if query.RequestInternal() {
 		return 1, true
@@ -163,7 +167,12 @@ func (s *searcher) tryReserve(query Query, stub *Stub) (int, bool) {
 		return 0, false
 	}
 
+	if !s.inRequiredState(stub, query.Session) {
+		return 0, false
+	}
+
 	s.stubCallCount[key]++
+	s.advanceScenario(stub, query.Session)
 
 	return s.stubCallCount[key], true
 }
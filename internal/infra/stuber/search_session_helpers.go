@@ -26,7 +26,7 @@ func (s *searcher) isVisibleAndNotExhausted(stub *Stub, session string) bool {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	return s.notExhausted(stub, session)
+	return s.available(stub, session)
 }
 
 // filterExhaustedStubs removes stubs that have reached their Times limit for the given session.
@@ -50,7 +50,7 @@ func (s *searcher) filterNotExhaustedSeq(seq iter.Seq[*Stub], session string) it
 		defer s.mu.RUnlock()
 
 		for stub := range seq {
-			if s.notExhausted(stub, session) {
+			if s.available(stub, session) {
 				if !yield(stub) {
 					return
 				}
@@ -59,6 +59,12 @@ func (s *searcher) filterNotExhaustedSeq(seq iter.Seq[*Stub], session string) it
 	}
 }
 
+// available reports whether the stub may match: it is under its Times limit
+// and its scenario is in the required state. Caller holds s.mu.
+func (s *searcher) available(stub *Stub, session string) bool {
+	return s.notExhausted(stub, session) && s.inRequiredState(stub, session)
+}
+
 func (s *searcher) notExhausted(stub *Stub, session string) bool {
 	times := stub.EffectiveTimes()
 	if times <= 0 {
@@ -1,6 +1,9 @@
 package stuber
 
-import "github.com/bavix/gripmock/v3/internal/infra/protoconv"
+import (
+	"github.com/bavix/gripmock/v3/internal/infra/celmatch"
+	"github.com/bavix/gripmock/v3/internal/infra/protoconv"
+)
 
 // calcSpecificity calculates the specificity score for a stub against a query.
 // Higher specificity means more fields match between stub and query.
@@ -41,11 +44,23 @@ func (s *searcher) calcSpecificityUnary(stubInput InputData, queryData map[strin
 	specificity += countMatcherKeys(stubInput.Matches, fieldExistsWithNonDefaultValue)
 	specificity += countMatcherKeys(stubInput.Glob, fieldExistsWithNonDefaultValue)
 
+	if stubInput.CEL != "" && celmatch.Match(stubInput.CEL, celmatch.Request, queryData) {
+		specificity += celmatch.Weight(stubInput.CEL)
+	}
+
+	if len(stubInput.Where) > 0 && whereMatch(stubInput.Where, queryData) {
+		specificity += whereFields(stubInput.Where)
+	}
+
 	for _, alt := range stubInput.AnyOf {
 		specificity += countMatcherKeys(alt.Equals, fieldExistsWithNonDefaultValue)
 		specificity += countMatcherKeys(alt.Contains, fieldExistsWithNonDefaultValue)
 		specificity += countMatcherKeys(alt.Matches, fieldExistsWithNonDefaultValue)
 		specificity += countMatcherKeys(alt.Glob, fieldExistsWithNonDefaultValue)
+
+		if len(alt.Where) > 0 && whereMatch(alt.Where, queryData) {
+			specificity += whereFields(alt.Where)
+		}
 	}
 
 	return specificity
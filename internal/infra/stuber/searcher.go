@@ -37,6 +37,7 @@ type searcher struct {
 	mu              sync.RWMutex
 	lookupMu        sync.RWMutex
 	stubCallCount   map[callCountKey]int // count of matches per stub+session (for Times limit)
+	scenarioStates  map[scenarioKey]string
 	storage         stubStorage
 	internalStorage InternalStubStorage
 	lookupProvider  searcherLookupProvider
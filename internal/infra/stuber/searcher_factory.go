@@ -28,6 +28,7 @@ func newSearcherWithOptions(options searcherOptions) *searcher {
 		storage:         storage,
 		internalStorage: storage.Internal(),
 		stubCallCount:   make(map[callCountKey]int),
+		scenarioStates:  make(map[scenarioKey]string),
 		lookupProvider:  lookupProvider,
 		lookupCache:     make(map[string]*searcherLookup),
 	}
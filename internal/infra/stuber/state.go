@@ -0,0 +1,99 @@
+package stuber
+
+import (
+	"cmp"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// State is everything a Budgerigar holds that changes at runtime: the stubs
+// together with how often each matched and where their scenarios are.
+type State struct {
+	Stubs     []*Stub         `json:"stubs"`
+	Counters  []MatchCounter  `json:"counters,omitempty"`
+	Scenarios []ScenarioState `json:"scenarios,omitempty"`
+}
+
+// MatchCounter is how many times a stub matched within a session; it drives
+// the Used flag and the Times limit. Session empty = global count.
+type MatchCounter struct {
+	ID      uuid.UUID `json:"id"`
+	Session string    `json:"session,omitempty"`
+	Count   int       `json:"count"`
+}
+
+// ScenarioState is a scenario's state within a session, kept only once it
+// left ScenarioStarted. Session empty = global state.
+type ScenarioState struct {
+	Name    string `json:"name"`
+	Session string `json:"session,omitempty"`
+	State   string `json:"state"`
+}
+
+// State returns the stubs with their match counters and scenario states,
+// ordered so equal states encode equally. Internal stubs are not included.
+func (b *Budgerigar) State() State {
+	stubs := b.All()
+	slices.SortFunc(stubs, func(a, b *Stub) int {
+		return cmp.Or(
+			cmp.Compare(a.Service, b.Service),
+			cmp.Compare(a.Method, b.Method),
+			cmp.Compare(a.ID.String(), b.ID.String()),
+		)
+	})
+
+	b.searcher.mu.RLock()
+	defer b.searcher.mu.RUnlock()
+
+	state := State{
+		Stubs:     stubs,
+		Counters:  make([]MatchCounter, 0, len(b.searcher.stubCallCount)),
+		Scenarios: make([]ScenarioState, 0, len(b.searcher.scenarioStates)),
+	}
+
+	for key, count := range b.searcher.stubCallCount {
+		state.Counters = append(state.Counters, MatchCounter{ID: key.id, Session: key.session, Count: count})
+	}
+
+	for key, value := range b.searcher.scenarioStates {
+		state.Scenarios = append(state.Scenarios, ScenarioState{Name: key.name, Session: key.session, State: value})
+	}
+
+	slices.SortFunc(state.Counters, func(a, b MatchCounter) int {
+		return cmp.Or(cmp.Compare(a.ID.String(), b.ID.String()), cmp.Compare(a.Session, b.Session))
+	})
+	slices.SortFunc(state.Scenarios, func(a, b ScenarioState) int {
+		return cmp.Or(cmp.Compare(a.Name, b.Name), cmp.Compare(a.Session, b.Session))
+	})
+
+	return state
+}
+
+// RestoreState replaces every stub, match counter and scenario state with the
+// given ones. Counters of stubs missing from the state are dropped. With
+// persistence enabled the persisted stubs are replaced as well.
+func (b *Budgerigar) RestoreState(state State) {
+	b.Clear()
+	b.PutMany(state.Stubs...)
+
+	known := make(map[uuid.UUID]struct{}, len(state.Stubs))
+	for _, stub := range state.Stubs {
+		known[stub.ID] = struct{}{}
+	}
+
+	b.searcher.mu.Lock()
+	defer b.searcher.mu.Unlock()
+
+	for _, counter := range state.Counters {
+		if _, ok := known[counter.ID]; ok && counter.Count > 0 {
+			b.searcher.stubCallCount[callCountKey{id: counter.ID, session: counter.Session}] = counter.Count
+		}
+	}
+
+	for _, scenario := range state.Scenarios {
+		if scenario.State != "" && scenario.State != ScenarioStarted {
+			b.searcher.scenarioStates[scenarioKey{name: scenario.Name, session: scenario.Session}] = scenario.State
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package stuber_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func TestRestoreStateKeepsCountersAndScenarios(t *testing.T) {
+	t.Parallel()
+
+	b := stuber.NewBudgerigar()
+
+	limited := &stuber.Stub{
+		Service: "shop.Orders",
+		Method:  "List",
+		Options: stuber.StubOptions{Times: 2},
+		Output:  stuber.Output{Data: map[string]any{"ok": true}},
+	}
+	b.PutMany(limited)
+	b.PutMany(orderFlowStubs("s1")...)
+
+	_, err := b.FindByQuery(stuber.Query{Service: "shop.Orders", Method: "List", Input: []map[string]any{{}}})
+	require.NoError(t, err)
+	require.Equal(t, "created", findOrderStatus(t, b, "s1"))
+
+	state := b.State()
+	require.Len(t, state.Stubs, 4)
+	require.Len(t, state.Counters, 2)
+	require.Equal(t, []stuber.ScenarioState{{Name: "order", Session: "s1", State: "Paid"}}, state.Scenarios)
+
+	// Drift away from the snapshot, then go back.
+	_, err = b.FindByQuery(stuber.Query{Service: "shop.Orders", Method: "List", Input: []map[string]any{{}}})
+	require.NoError(t, err)
+	b.ResetScenarios("s1")
+	b.PutMany(&stuber.Stub{Service: "extra.Svc", Method: "M"})
+
+	restored := stuber.NewBudgerigar()
+	restored.RestoreState(state)
+
+	require.Len(t, restored.All(), 4)
+	require.Contains(t, restored.UsedIDs(), limited.ID)
+	require.Equal(t, "paid", findOrderStatus(t, restored, "s1"))
+
+	_, err = restored.FindByQuery(stuber.Query{Service: "shop.Orders", Method: "List", Input: []map[string]any{{}}})
+	require.NoError(t, err)
+
+	_, err = restored.FindByQuery(stuber.Query{Service: "shop.Orders", Method: "List", Input: []map[string]any{{}}})
+	require.Error(t, err, "times limit should count matches made before the snapshot")
+}
@@ -91,7 +91,7 @@ func anchoredLiteral(pattern string) (string, bool) {
 // the only shape the index can reason about exactly.
 func indexableFields(stub *Stub) (map[string]any, bool, bool) {
 	in := stub.Input
-	if stub.Inputs != nil || len(in.Glob) > 0 || len(in.AnyOf) > 0 {
+	if stub.Inputs != nil || len(in.Glob) > 0 || len(in.AnyOf) > 0 || in.CEL != "" || len(in.Where) > 0 {
 		return nil, false, false
 	}
 
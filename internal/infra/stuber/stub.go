@@ -7,6 +7,7 @@ import (
 	"github.com/google/uuid"
 	"google.golang.org/grpc/codes"
 
+	"github.com/bavix/gripmock/v3/internal/infra/jsonpatch"
 	"github.com/bavix/gripmock/v3/internal/infra/types"
 )
 
@@ -44,9 +45,18 @@ type Stub struct {
 	Inputs   []InputData   `json:"inputs,omitempty"  validate:"valid_input_config"`
 	Output   Output        `json:"output"            validate:"valid_output_config"`
 	Effects  []Effect      `json:"effects,omitempty" validate:"valid_effects"`
+	Faults   *Faults       `json:"faults,omitempty"`
+	Seed     *uint64       `json:"seed,omitempty"`
 	Source   string        `json:"source,omitempty"`
 	Handler  StreamHandler `json:"-"`
 
+	// Scenario names the state machine the stub belongs to. The stub only
+	// matches while the scenario is in RequiredState (any state when empty)
+	// and moves it to NewState on a match. State is tracked per session.
+	Scenario      string `json:"scenario,omitempty"      validate:"required_with=RequiredState NewState"`
+	RequiredState string `json:"requiredState,omitempty"`
+	NewState      string `json:"newState,omitempty"`
+
 	UnaryHandler        UnaryHandler        `json:"-"`
 	ServerStreamHandler ServerStreamHandler `json:"-"`
 	ClientStreamHandler ClientStreamHandler `json:"-"`
@@ -264,6 +274,12 @@ type InputData struct {
 	Glob             map[string]any `json:"glob,omitempty"`
 	AnyOf            []AnyOfElement `json:"anyOf,omitempty"`
 	IgnoreArrayOrder bool           `json:"ignoreArrayOrder,omitempty"`
+	// CEL is an expression over the request, bound as `request`, that must
+	// evaluate to true.
+	CEL string `json:"cel,omitempty"`
+	// Where maps fields to conditions such as {gt: 100} or {every: {...}};
+	// see deeply.Satisfies for the operators.
+	Where map[string]any `json:"where,omitempty"`
 }
 
 // AnyOfElement is a flat alternative matcher inside InputData.
@@ -273,6 +289,8 @@ type AnyOfElement struct {
 	Contains         map[string]any `json:"contains"`
 	Matches          map[string]any `json:"matches"`
 	Glob             map[string]any `json:"glob,omitempty"`
+	// Where maps fields to conditions, as InputData.Where does.
+	Where map[string]any `json:"where,omitempty"`
 }
 
 // GetEquals returns the data to match exactly.
@@ -302,6 +320,9 @@ type InputHeader struct {
 	Matches  map[string]any       `json:"matches"`
 	Glob     map[string]any       `json:"glob,omitempty"`
 	AnyOf    []AnyOfHeaderElement `json:"anyOf,omitempty"`
+	// CEL is an expression over the headers, bound as `headers`, that must
+	// evaluate to true.
+	CEL string `json:"cel,omitempty"`
 }
 
 // AnyOfHeaderElement is a flat alternative matcher inside InputHeader.
@@ -336,6 +357,10 @@ func (i InputHeader) GetGlob() map[string]any {
 func (i InputHeader) Len() int {
 	n := len(i.Equals) + len(i.Matches) + len(i.Contains) + len(i.Glob)
 
+	if i.CEL != "" {
+		n++
+	}
+
 	for _, alt := range i.AnyOf {
 		n += len(alt.Equals) + len(alt.Matches) + len(alt.Contains) + len(alt.Glob)
 	}
@@ -353,4 +378,21 @@ type Output struct {
 	Code     *codes.Code       `json:"code,omitempty"`
 	Details  []map[string]any  `json:"details,omitempty"`
 	Delay    types.Delay       `json:"delay,omitempty"`
+	// Script is a Starlark program run after templates; the response dict it
+	// assigns overrides data, headers, trailers, code and error.
+	Script string `json:"script,omitempty"`
+	// Upstream, when set, forwards the call to the method's proxy upstream and
+	// answers with its response, patched. Headers, trailers, code, error and
+	// details set on the output override the upstream's.
+	Upstream *UpstreamPatch `json:"upstream,omitempty"`
+}
+
+// UpstreamPatch edits an upstream response before it is returned. Patch runs
+// first, then Merge; both apply only when the final status is OK.
+type UpstreamPatch struct {
+	// Patch is a JSON patch (RFC 6902) applied to the response message.
+	Patch []jsonpatch.Operation `json:"patch,omitempty"`
+	// Merge is a merge patch (RFC 7396) applied after Patch. Its values may
+	// hold templates.
+	Merge map[string]any `json:"merge,omitempty"`
 }
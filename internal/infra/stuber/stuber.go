@@ -1,6 +1,8 @@
 package stuber
 
 import (
+	"sync/atomic"
+
 	"github.com/google/uuid"
 	healthgrpc "google.golang.org/grpc/health/grpc_health_v1"
 )
@@ -11,6 +13,7 @@ type Aliveness interface {
 
 type Budgerigar struct {
 	searcher *searcher
+	persist  atomic.Pointer[persistence]
 }
 
 func NewBudgerigar() *Budgerigar {
@@ -40,7 +43,7 @@ func (b *Budgerigar) PutMany(values ...*Stub) []uuid.UUID {
 		}
 	}
 
-	return b.searcher.upsert(values...)
+	return b.upsert(values)
 }
 
 // UpdateMany updates stubs that have non-nil IDs.
@@ -53,12 +56,38 @@ func (b *Budgerigar) UpdateMany(values ...*Stub) []uuid.UUID {
 		}
 	}
 
-	return b.searcher.upsert(updates...)
+	return b.upsert(updates)
+}
+
+func (b *Budgerigar) upsert(values []*Stub) []uuid.UUID {
+	p := b.persist.Load()
+	if p == nil {
+		return b.searcher.upsert(values...)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := b.searcher.upsert(values...)
+	p.save(values)
+
+	return ids
 }
 
 // DeleteByID deletes the Stub values with the given IDs from the Budgerigar's searcher.
 func (b *Budgerigar) DeleteByID(ids ...uuid.UUID) int {
-	return b.searcher.del(ids...)
+	p := b.persist.Load()
+	if p == nil {
+		return b.searcher.del(ids...)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	deleted := b.searcher.del(ids...)
+	p.delete(ids)
+
+	return deleted
 }
 
 // DeleteSession deletes all stubs that belong to the provided session.
@@ -68,7 +97,19 @@ func (b *Budgerigar) DeleteSession(session string) int {
 		return 0
 	}
 
-	return b.searcher.delBySession(session)
+	p := b.persist.Load()
+	if p == nil {
+		return b.searcher.delBySession(session)
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := idsOf(b.searcher.all(), func(stub *Stub) bool { return stub.Session == session })
+	deleted := b.searcher.delBySession(session)
+	p.delete(ids)
+
+	return deleted
 }
 
 // FindByID retrieves the Stub value associated with the given ID.
@@ -135,7 +176,37 @@ func (b *Budgerigar) Sessions() []string {
 	return b.searcher.sessions()
 }
 
+// Scenarios returns the scenarios declared by stubs visible to the session,
+// with their current state in that session, sorted by name.
+func (b *Budgerigar) Scenarios(session string) []Scenario {
+	return b.searcher.scenarios(session)
+}
+
+// SetScenarioState moves a scenario to the given state within the session.
+// An empty state resets it to ScenarioStarted. It returns false when no stub
+// visible to the session declares the scenario.
+func (b *Budgerigar) SetScenarioState(name, session, state string) bool {
+	return b.searcher.setScenarioState(name, session, state)
+}
+
+// ResetScenarios returns every scenario of the session to ScenarioStarted.
+func (b *Budgerigar) ResetScenarios(session string) {
+	b.searcher.resetScenarios(session)
+}
+
 // Clear removes all Stub values.
 func (b *Budgerigar) Clear() {
+	p := b.persist.Load()
+	if p == nil {
+		b.searcher.clear()
+
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	ids := idsOf(b.searcher.all(), func(*Stub) bool { return true })
 	b.searcher.clear()
+	p.delete(ids)
 }
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"maps"
 	"slices"
 	"strings"
 	"text/template"
@@ -12,6 +13,7 @@ import (
 	"github.com/cockroachdb/errors"
 	lru "github.com/hashicorp/golang-lru/v2"
 
+	"github.com/bavix/gripmock/v3/internal/infra/faker"
 	"github.com/bavix/gripmock/v3/pkg/plugins"
 )
 
@@ -43,24 +45,48 @@ type Data struct {
 	StubID        string `json:"stubId"` // Unique identifier of the stub
 	// RequestID is a backward-compatibility alias mapped to StubID
 	RequestID string `json:"requestId"`
+	// Faker, when set, feeds the faker and uuid functions instead of the
+	// engine's sequences, so a seeded call renders the same values on every run.
+	Faker *faker.Sequence `json:"-"`
+	// Session selects the engine's sequence of a seeded session when Faker is
+	// nil.
+	Session string `json:"-"`
 }
 
 // Engine provides template rendering functionality.
 type Engine struct {
 	funcs template.FuncMap
 	cache *lru.Cache[string, *template.Template]
+	seeds *faker.Seeds
 }
 
-// New creates a new template engine with custom functions.
+// New creates a new template engine with custom functions and a randomly
+// seeded faker.
 func New(ctx context.Context, reg plugins.Registry) *Engine {
+	return NewWithSeeds(ctx, reg, faker.NewSeeds(0))
+}
+
+// NewWithSeeds is New with the faker sequences the engine's faker and uuid
+// functions draw from.
+func NewWithSeeds(ctx context.Context, reg plugins.Registry, seeds *faker.Seeds) *Engine {
 	cache, _ := lru.New[string, *template.Template](templateCacheSize)
 
+	funcs := Functions(ctx, reg)
+	funcs["faker"] = seeds.Next
+	funcs["uuid"] = func() string { return seeds.Next().Identity().UUID() }
+
 	return &Engine{
-		funcs: Functions(ctx, reg),
+		funcs: funcs,
 		cache: cache,
+		seeds: seeds,
 	}
 }
 
+// Seeds returns the faker sequences of the engine.
+func (e *Engine) Seeds() *faker.Seeds {
+	return e.seeds
+}
+
 // Render renders a template string with the given data.
 //
 //nolint:nonamedreturns
@@ -76,6 +102,15 @@ func (e *Engine) Render(tmpl string, data Data) (result string, err error) {
 	}()
 
 	exec := func(parsed *template.Template) error {
+		seq := data.Faker
+		if seq == nil {
+			seq = e.seeds.Sessions().Get(data.Session)
+		}
+
+		if seq != nil {
+			parsed = seeded(parsed, seq)
+		}
+
 		var buf bytes.Buffer
 		if err := parsed.Execute(&buf, data); err != nil {
 			return fmt.Errorf("failed to execute template: %w", err)
@@ -100,6 +135,19 @@ func (e *Engine) Render(tmpl string, data Data) (result string, err error) {
 	return result, exec(parsed)
 }
 
+// seeded is a copy of parsed whose random functions draw from seq.
+func seeded(parsed *template.Template, seq *faker.Sequence) *template.Template {
+	clone, err := parsed.Clone()
+	if err != nil {
+		return parsed
+	}
+
+	return clone.Funcs(template.FuncMap{
+		"faker": seq.Next,
+		"uuid":  func() string { return seq.Next().Identity().UUID() },
+	})
+}
+
 // unescapeTemplateQuotes removes escape sequences from quotes inside template expressions.
 func unescapeTemplateQuotes(tmpl string) string {
 	var result strings.Builder
@@ -253,8 +301,10 @@ func processMapTemplates(data map[string]any, templateData Data, engine *Engine,
 		return ErrMaxRecursionDepthExceeded
 	}
 
-	for key, value := range data {
-		switch v := value.(type) {
+	// Keys go in order so a seeded faker hands the same values to the same
+	// keys on every run.
+	for _, key := range slices.Sorted(maps.Keys(data)) {
+		switch v := data[key].(type) {
 		case string:
 			if IsTemplateString(v) {
 				rendered, err := engine.Render(v, templateData)
@@ -296,8 +346,8 @@ func processHeadersField(headers map[string]string, templateData Data, engine *E
 		return nil
 	}
 
-	for key, value := range headers {
-		if IsTemplateString(value) {
+	for _, key := range slices.Sorted(maps.Keys(headers)) {
+		if value := headers[key]; IsTemplateString(value) {
 			rendered, err := engine.Render(value, templateData)
 			if err != nil {
 				return fmt.Errorf("failed to process header template for %s: %w", key, err)
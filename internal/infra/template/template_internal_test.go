@@ -4,8 +4,30 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/faker"
 )
 
+func TestSeededRenderRepeats(t *testing.T) {
+	t.Parallel()
+
+	render := func(seed uint64) map[string]any {
+		data := map[string]any{
+			"id":    "{{ uuid }}",
+			"name":  "{{ faker.Person.Name }}",
+			"other": "{{ uuid }}",
+		}
+		require.NoError(t, New(t.Context(), nil).ProcessMap(data, Data{Faker: faker.NewSequence(seed)}))
+
+		return data
+	}
+
+	first := render(3)
+	require.Equal(t, first, render(3))
+	require.NotEqual(t, first["id"], first["other"])
+	require.NotEqual(t, first, render(4))
+}
+
 // Regression: unescapeTemplateQuotes ran unconditionally and stripped \" inside
 // every {{...}}, corrupting a legitimate escaped quote in a string literal. It
 // must now only apply as a fallback when the raw template fails to parse.
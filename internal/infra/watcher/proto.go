@@ -0,0 +1,169 @@
+package watcher
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/bavix/gripmock/v3/internal/config"
+)
+
+// ProtoWatcher tracks proto sources on disk: .proto files and descriptor
+// sets, given directly or inside directories.
+type ProtoWatcher struct {
+	enabled     bool
+	interval    time.Duration
+	watcherType string
+}
+
+func NewProtoWatcher(cfg config.Config) *ProtoWatcher {
+	watcherType := string(cfg.ProtoWatcherType)
+
+	if watcherType != string(config.WatcherTimer) {
+		watcherType = string(config.WatcherFSNotify)
+	}
+
+	return &ProtoWatcher{
+		enabled:     cfg.ProtoWatcherEnabled,
+		interval:    cfg.ProtoWatcherInterval,
+		watcherType: watcherType,
+	}
+}
+
+// Watch sends the path of every proto source under paths that is written,
+// created or removed. A file is watched through its directory, so changes to
+// its neighbours, typically the files it imports, are reported too. The
+// channel is closed when ctx is done, or at once when watching is disabled.
+func (p *ProtoWatcher) Watch(ctx context.Context, paths []string) (<-chan string, error) {
+	roots := protoRoots(paths)
+
+	if !p.enabled || len(roots) == 0 {
+		ch := make(chan string)
+		close(ch)
+
+		return ch, nil
+	}
+
+	zerolog.Ctx(ctx).Info().
+		Str("type", p.watcherType).
+		Strs("paths", roots).
+		Msg("Tracking changes in proto sources")
+
+	if p.watcherType == string(config.WatcherFSNotify) {
+		return notify(ctx, roots, isProtoSource)
+	}
+
+	return p.ticker(ctx, roots), nil
+}
+
+// ticker polls roots and reports the files whose modification time changed
+// since the previous scan, including files that appeared or disappeared.
+func (p *ProtoWatcher) ticker(ctx context.Context, roots []string) <-chan string {
+	ch := make(chan string)
+	known := scanProtoSources(roots)
+
+	go func() {
+		ticker := time.NewTicker(p.interval)
+		defer ticker.Stop()
+		defer close(ch)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				current := scanProtoSources(roots)
+
+				for _, path := range changedPaths(known, current) {
+					select {
+					case ch <- path:
+					case <-ctx.Done():
+						return
+					}
+				}
+
+				known = current
+			}
+		}
+	}()
+
+	return ch
+}
+
+// protoRoots returns the directories to watch for paths: directories as they
+// are, files through their parent. Paths that do not exist are skipped.
+func protoRoots(paths []string) []string {
+	roots := make([]string, 0, len(paths))
+
+	for _, path := range paths {
+		info, err := os.Stat(path)
+		if err != nil {
+			continue
+		}
+
+		root := path
+		if !info.IsDir() {
+			root = filepath.Dir(path)
+		}
+
+		if !slices.Contains(roots, root) {
+			roots = append(roots, root)
+		}
+	}
+
+	return roots
+}
+
+func scanProtoSources(roots []string) map[string]time.Time {
+	files := make(map[string]time.Time)
+
+	for _, root := range roots {
+		_ = filepath.Walk(root, func(currentPath string, info fs.FileInfo, err error) error {
+			if err != nil {
+				return nil //nolint:nilerr
+			}
+
+			if !info.IsDir() && isProtoSource(currentPath) {
+				files[currentPath] = info.ModTime()
+			}
+
+			return nil
+		})
+	}
+
+	return files
+}
+
+// changedPaths lists, sorted, the paths whose modification time differs
+// between before and after, or that only one of them holds.
+func changedPaths(before, after map[string]time.Time) []string {
+	changed := make([]string, 0)
+
+	for path, modTime := range after {
+		if previous, ok := before[path]; !ok || !previous.Equal(modTime) {
+			changed = append(changed, path)
+		}
+	}
+
+	for path := range before {
+		if _, ok := after[path]; !ok {
+			changed = append(changed, path)
+		}
+	}
+
+	slices.Sort(changed)
+
+	return changed
+}
+
+func isProtoSource(path string) bool {
+	return strings.HasSuffix(path, ".proto") ||
+		strings.HasSuffix(path, ".pb") ||
+		strings.HasSuffix(path, ".protoset")
+}
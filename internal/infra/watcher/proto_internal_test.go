@@ -0,0 +1,75 @@
+package watcher
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/config"
+)
+
+func TestChangedPaths(t *testing.T) {
+	t.Parallel()
+
+	now := time.Now()
+	before := map[string]time.Time{"a.proto": now, "b.proto": now, "c.proto": now}
+	after := map[string]time.Time{"a.proto": now, "b.proto": now.Add(time.Second), "d.pb": now}
+
+	require.Equal(t, []string{"b.proto", "c.proto", "d.pb"}, changedPaths(before, after))
+}
+
+func TestProtoWatcherDisabled(t *testing.T) {
+	t.Parallel()
+
+	ch, err := NewProtoWatcher(config.Config{}).Watch(t.Context(), []string{t.TempDir()})
+	require.NoError(t, err)
+
+	_, ok := <-ch
+	require.False(t, ok, "a disabled watcher closes its channel at once")
+}
+
+func TestProtoWatcherTimerReportsChanges(t *testing.T) {
+	t.Parallel()
+
+	dir := t.TempDir()
+	service := filepath.Join(dir, "service.proto")
+	require.NoError(t, os.WriteFile(service, []byte(`syntax = "proto3";`), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "stub.yaml"), []byte("service: x"), 0o600))
+
+	watcher := NewProtoWatcher(config.Config{
+		ProtoWatcherEnabled:  true,
+		ProtoWatcherType:     config.WatcherTimer,
+		ProtoWatcherInterval: 10 * time.Millisecond,
+	})
+
+	ch, err := watcher.Watch(t.Context(), []string{service})
+	require.NoError(t, err)
+
+	select {
+	case path := <-ch:
+		t.Fatalf("files present at start are not changes, got %s", path)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	added := filepath.Join(dir, "types.proto")
+	require.NoError(t, os.WriteFile(added, []byte(`syntax = "proto3";`), 0o600))
+
+	select {
+	case path := <-ch:
+		require.Equal(t, added, path, "a file next to the watched one is reported")
+	case <-time.After(time.Second):
+		t.Fatal("no change reported")
+	}
+
+	require.NoError(t, os.Remove(service))
+
+	select {
+	case path := <-ch:
+		require.Equal(t, service, path, "a removed file is reported")
+	case <-time.After(time.Second):
+		t.Fatal("no removal reported")
+	}
+}
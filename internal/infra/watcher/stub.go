@@ -151,8 +151,15 @@ func (s *StubWatcher) Watch(ctx context.Context, folderPath string) (<-chan stri
 	return s.ticker(ctx, folderPath)
 }
 
-//nolint:cyclop
 func (s *StubWatcher) notify(ctx context.Context, folderPath string) (<-chan string, error) {
+	return notify(ctx, []string{folderPath}, isStub)
+}
+
+// notify watches every directory under roots with fsnotify and sends the
+// paths of changed files that match, debounced per path.
+//
+//nolint:cyclop
+func notify(ctx context.Context, roots []string, match func(string) bool) (<-chan string, error) {
 	watcher, err := fsnotify.NewWatcher()
 	if err != nil {
 		return nil, err //nolint:wrapcheck
@@ -190,12 +197,21 @@ func (s *StubWatcher) notify(ctx context.Context, folderPath string) (<-chan str
 					continue
 				}
 
-				s.handleFsnotifyEvent(ctx, watcher, d, event)
+				handleFsnotifyEvent(ctx, watcher, d, event, match)
 			}
 		}
 	}()
 
-	_ = filepath.Walk(folderPath, func(currentPath string, info fs.FileInfo, err error) error {
+	for _, root := range roots {
+		watchTree(ctx, watcher, root)
+	}
+
+	return ch, nil
+}
+
+// watchTree adds root and every directory below it to watcher.
+func watchTree(ctx context.Context, watcher *fsnotify.Watcher, root string) {
+	_ = filepath.Walk(root, func(currentPath string, info fs.FileInfo, err error) error {
 		if err != nil {
 			return err
 		}
@@ -210,8 +226,6 @@ func (s *StubWatcher) notify(ctx context.Context, folderPath string) (<-chan str
 
 		return nil
 	})
-
-	return ch, nil
 }
 
 func (s *StubWatcher) ticker(ctx context.Context, folderPath string) (<-chan string, error) {
@@ -267,7 +281,13 @@ func isStub(path string) bool {
 }
 
 // handleFsnotifyEvent handles a single fsnotify event with panic recovery.
-func (s *StubWatcher) handleFsnotifyEvent(ctx context.Context, watcher *fsnotify.Watcher, d *debouncer, event fsnotify.Event) {
+func handleFsnotifyEvent(
+	ctx context.Context,
+	watcher *fsnotify.Watcher,
+	d *debouncer,
+	event fsnotify.Event,
+	match func(string) bool,
+) {
 	defer func() {
 		if r := recover(); r != nil {
 			zerolog.Ctx(ctx).
@@ -285,7 +305,7 @@ func (s *StubWatcher) handleFsnotifyEvent(ctx context.Context, watcher *fsnotify
 			Msg("Adding directory to watcher")
 	}
 
-	if isStub(event.Name) {
+	if match(event.Name) {
 		d.add(event.Name)
 	}
 }
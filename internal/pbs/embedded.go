@@ -4,8 +4,10 @@ import (
 	_ "embed"
 	"sync"
 
+	"buf.build/gen/go/bufbuild/protovalidate/protocolbuffers/go/buf/validate"
 	"github.com/bufbuild/protocompile"
 	"github.com/cockroachdb/errors"
+	"google.golang.org/protobuf/reflect/protodesc"
 	"google.golang.org/protobuf/reflect/protoregistry"
 	"google.golang.org/protobuf/types/descriptorpb"
 
@@ -92,6 +94,12 @@ func (p *ThirdPartyResolver) FindFileByPath(path string) (protocompile.SearchRes
 		if file, ok := index[path]; ok {
 			return protocompile.SearchResult{Proto: file}, nil
 		}
+
+		// The protovalidate rules ship with the binary, so protos using them
+		// compile without a copy of validate.proto on the import path.
+		if path == validate.File_buf_validate_validate_proto.Path() {
+			return protocompile.SearchResult{Proto: protodesc.ToFileDescriptorProto(validate.File_buf_validate_validate_proto)}, nil
+		}
 	}
 
 	return protocompile.SearchResult{}, protoregistry.NotFound
@@ -384,3 +384,15 @@ func TestThirdPartyResolverWithDuplicateNames(t *testing.T) {
 	require.NotNil(t, result)
 	require.Equal(t, "duplicate.proto", result.Proto.GetName())
 }
+
+func TestNewResolverServesProtovalidate(t *testing.T) {
+	t.Parallel()
+
+	resolver, err := NewResolver()
+	require.NoError(t, err)
+
+	result, err := resolver.FindFileByPath("buf/validate/validate.proto")
+	require.NoError(t, err)
+	require.NotNil(t, result.Proto)
+	require.Equal(t, "buf.validate", result.Proto.GetPackage())
+}
@@ -0,0 +1,113 @@
+package plugins
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Lifecycle hook groups. A function registered with one of these as its Group
+// is called by the server at that point of a mocked call, in plugin load order,
+// with a *HookEvent as its only argument.
+const (
+	// HookBeforeMatch runs before stubs are searched. Changes to Requests and
+	// Headers are what the call is matched and rendered with.
+	HookBeforeMatch = "before-match"
+	// HookAfterMatch runs once a stub matched; Stub holds it.
+	HookAfterMatch = "after-match"
+	// HookBeforeResponse runs with the rendered Response, right before it is
+	// sent. Changes to Response are what the client receives.
+	HookBeforeResponse = "before-response"
+	// HookOnMiss runs when no stub matched.
+	HookOnMiss = "on-miss"
+	// HookOnStubUpsert runs for every stub added through the API. Changes to
+	// Stub are what gets stored.
+	HookOnStubUpsert = "on-stub-upsert"
+)
+
+// ErrHookArgument is returned by HookFunc when it is called with something
+// other than a hook event.
+var ErrHookArgument = errors.New("hook expects a single HookEvent argument")
+
+// HookEvent describes a lifecycle point. A hook rejects the call by returning
+// an error; a gRPC status error keeps its code. Setting Response in a
+// before-match, after-match or on-miss hook answers a unary call without a
+// stub.
+type HookEvent struct {
+	Hook      string           `json:"hook"`
+	Transport string           `json:"transport,omitempty"`
+	Service   string           `json:"service,omitempty"`
+	Method    string           `json:"method,omitempty"`
+	Session   string           `json:"session,omitempty"`
+	Headers   map[string]any   `json:"headers,omitempty"`
+	Requests  []map[string]any `json:"requests,omitempty"`
+	// Stub is the JSON form of the matched or upserted stub.
+	Stub     map[string]any `json:"stub,omitempty"`
+	Response *HookResponse  `json:"response,omitempty"`
+}
+
+// HookResponse is the answer to a call, shaped like a stub output.
+type HookResponse struct {
+	Data     any               `json:"data,omitempty"`
+	Headers  map[string]string `json:"headers,omitempty"`
+	Trailers map[string]string `json:"trailers,omitempty"`
+	Error    string            `json:"error,omitempty"`
+	Code     *uint32           `json:"code,omitempty"`
+}
+
+// HookFunc adapts a typed hook to Func. The event is decoded from its JSON
+// form when the hook runs as a process plugin, and the changed event is
+// returned to the server either way.
+func HookFunc(fn func(ctx context.Context, event *HookEvent) error) Func {
+	return func(ctx context.Context, args ...any) (any, error) {
+		event, err := HookEventArg(args)
+		if err != nil {
+			return nil, err
+		}
+
+		if err := fn(ctx, event); err != nil {
+			return nil, err
+		}
+
+		return event, nil
+	}
+}
+
+// HookEventArg extracts the event from hook arguments.
+func HookEventArg(args []any) (*HookEvent, error) {
+	if len(args) != 1 {
+		return nil, fmt.Errorf("%w: got %d arguments", ErrHookArgument, len(args))
+	}
+
+	return AsHookEvent(args[0])
+}
+
+// AsHookEvent converts a hook argument or result into an event. It accepts
+// *HookEvent, HookEvent and the JSON object form.
+func AsHookEvent(v any) (*HookEvent, error) {
+	switch event := v.(type) {
+	case *HookEvent:
+		if event == nil {
+			return nil, ErrHookArgument
+		}
+
+		return event, nil
+	case HookEvent:
+		return &event, nil
+	case map[string]any:
+		raw, err := json.Marshal(event)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrHookArgument, err)
+		}
+
+		decoded := new(HookEvent)
+		if err := json.Unmarshal(raw, decoded); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrHookArgument, err)
+		}
+
+		return decoded, nil
+	default:
+		return nil, fmt.Errorf("%w: got %T", ErrHookArgument, v)
+	}
+}
@@ -0,0 +1,59 @@
+package plugins
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestHookFuncMutatesEvent(t *testing.T) {
+	t.Parallel()
+
+	fn := HookFunc(func(_ context.Context, event *HookEvent) error {
+		event.Headers["x-seen"] = event.Method
+
+		return nil
+	})
+
+	event := &HookEvent{Hook: HookBeforeMatch, Method: "Get", Headers: map[string]any{}}
+
+	got, err := fn(t.Context(), event)
+	require.NoError(t, err)
+	require.Same(t, event, got)
+	require.Equal(t, "Get", event.Headers["x-seen"])
+}
+
+func TestHookFuncDecodesJSONForm(t *testing.T) {
+	t.Parallel()
+
+	fn := HookFunc(func(_ context.Context, event *HookEvent) error {
+		event.Response = &HookResponse{Data: event.Requests[0]}
+
+		return nil
+	})
+
+	got, err := fn(t.Context(), map[string]any{
+		"hook":     HookOnMiss,
+		"requests": []any{map[string]any{"id": 1.0}},
+	})
+	require.NoError(t, err)
+
+	event, ok := got.(*HookEvent)
+	require.True(t, ok)
+	require.Equal(t, HookOnMiss, event.Hook)
+	require.Equal(t, map[string]any{"id": 1.0}, event.Response.Data)
+}
+
+func TestHookEventArgRejectsOtherValues(t *testing.T) {
+	t.Parallel()
+
+	_, err := HookEventArg(nil)
+	require.ErrorIs(t, err, ErrHookArgument)
+
+	_, err = HookEventArg([]any{"x"})
+	require.ErrorIs(t, err, ErrHookArgument)
+
+	_, err = AsHookEvent((*HookEvent)(nil))
+	require.ErrorIs(t, err, ErrHookArgument)
+}
@@ -0,0 +1,303 @@
+package pluginrpc
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/cockroachdb/errors"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+)
+
+const (
+	// handshakeTimeout bounds how long a plugin may take to announce itself.
+	handshakeTimeout = 10 * time.Second
+	// callTimeout bounds a single function call when the caller set no deadline.
+	callTimeout = 5 * time.Second
+	// stopTimeout is how long a plugin gets to exit after its stdin closes.
+	stopTimeout = 3 * time.Second
+)
+
+// Process is a running plugin seen from the host.
+type Process struct {
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+	conn  *grpc.ClientConn
+
+	info      pkgplugins.PluginInfo
+	functions []pkgplugins.FunctionInfo
+
+	exited  chan struct{}
+	waitErr error
+
+	closeOnce sync.Once
+}
+
+// Start launches cmd as a plugin, waits for its handshake and reads what it
+// provides. ctx bounds the startup only; the process runs until Close. The
+// plugin's own output after the handshake goes to the logger in ctx.
+func Start(ctx context.Context, cmd *exec.Cmd) (*Process, error) {
+	cmd.Env = append(cmd.Environ(), CookieEnv+"="+CookieValue)
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "pluginrpc: stdin")
+	}
+
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, errors.Wrap(err, "pluginrpc: stdout")
+	}
+
+	logger := zerolog.Ctx(ctx).With().Str("plugin", cmd.Path).Logger()
+	cmd.Stderr = logWriter{logger: logger}
+
+	if err := cmd.Start(); err != nil {
+		return nil, errors.Wrapf(err, "pluginrpc: start %s", cmd.Path)
+	}
+
+	p := &Process{cmd: cmd, stdin: stdin, exited: make(chan struct{})}
+
+	go func() {
+		p.waitErr = cmd.Wait()
+		close(p.exited)
+	}()
+
+	if err := p.connect(ctx, stdout, logger); err != nil {
+		p.kill()
+
+		return nil, err
+	}
+
+	return p, nil
+}
+
+func (p *Process) connect(ctx context.Context, stdout io.Reader, logger zerolog.Logger) error {
+	ctx, cancel := context.WithTimeout(ctx, handshakeTimeout)
+	defer cancel()
+
+	lines := bufio.NewScanner(stdout)
+	handshake := make(chan string, 1)
+
+	go func() {
+		if lines.Scan() {
+			handshake <- lines.Text()
+		} else {
+			close(handshake)
+		}
+
+		for lines.Scan() {
+			logger.Info().Msg(lines.Text())
+		}
+	}()
+
+	var line string
+
+	select {
+	case got, ok := <-handshake:
+		if !ok {
+			return errors.Wrap(ErrBadHandshake, "plugin exited before the handshake")
+		}
+
+		line = got
+	case <-ctx.Done():
+		return errors.Wrap(ctx.Err(), "pluginrpc: waiting for handshake")
+	}
+
+	target, err := parseHandshake(line)
+	if err != nil {
+		return err
+	}
+
+	conn, err := grpc.NewClient(target, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return errors.Wrap(err, "pluginrpc: dial")
+	}
+
+	p.conn = conn
+
+	return p.describe(ctx)
+}
+
+// parseHandshake turns "1|tcp|127.0.0.1:50123" into a dial target.
+func parseHandshake(line string) (string, error) {
+	const fields = 3
+
+	parts := strings.Split(strings.TrimSpace(line), "|")
+	if len(parts) != fields {
+		return "", errors.Wrapf(ErrBadHandshake, "%q", line)
+	}
+
+	version, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return "", errors.Wrapf(ErrBadHandshake, "%q", line)
+	}
+
+	if version != ProtocolVersion {
+		return "", errors.Wrapf(ErrProtocolVersion, "got %d, want %d", version, ProtocolVersion)
+	}
+
+	switch parts[1] {
+	case "tcp":
+		return parts[2], nil
+	case "unix":
+		return "unix://" + parts[2], nil
+	default:
+		return "", errors.Wrapf(ErrBadHandshake, "network %q", parts[1])
+	}
+}
+
+func (p *Process) describe(ctx context.Context) error {
+	out := new(structpb.Struct)
+	if err := p.conn.Invoke(ctx, describeMethod, new(emptypb.Empty), out); err != nil {
+		return errors.Wrap(err, "pluginrpc: describe")
+	}
+
+	fields := out.GetFields()
+
+	p.info = pkgplugins.PluginInfo{
+		Name:        fields["name"].GetStringValue(),
+		Version:     fields["version"].GetStringValue(),
+		Description: fields["description"].GetStringValue(),
+	}
+
+	for _, item := range fields["functions"].GetListValue().GetValues() {
+		fn := item.GetStructValue().GetFields()
+		if fn["name"].GetStringValue() == "" {
+			continue
+		}
+
+		p.functions = append(p.functions, pkgplugins.FunctionInfo{
+			Name:        fn["name"].GetStringValue(),
+			Description: fn["description"].GetStringValue(),
+			Group:       fn["group"].GetStringValue(),
+			Replacement: fn["replacement"].GetStringValue(),
+		})
+	}
+
+	return nil
+}
+
+// Info is what the plugin reported about itself.
+func (p *Process) Info() pkgplugins.PluginInfo {
+	return p.info
+}
+
+// Specs returns one spec per plugin function; calling one is a round trip
+// to the process.
+func (p *Process) Specs() []pkgplugins.FuncSpec {
+	specs := make([]pkgplugins.FuncSpec, 0, len(p.functions))
+
+	for _, fn := range p.functions {
+		name := fn.Name
+		specs = append(specs, pkgplugins.FuncSpec{
+			Name:        name,
+			Description: fn.Description,
+			Group:       fn.Group,
+			Replacement: fn.Replacement,
+			Fn: pkgplugins.Func(func(ctx context.Context, args ...any) (any, error) {
+				return p.Call(ctx, name, args...)
+			}),
+		})
+	}
+
+	return specs
+}
+
+// Call runs one plugin function.
+func (p *Process) Call(ctx context.Context, name string, args ...any) (any, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+
+		ctx, cancel = context.WithTimeout(ctx, callTimeout)
+		defer cancel()
+	}
+
+	values := make([]*structpb.Value, len(args))
+	for i, arg := range args {
+		value, err := toValue(arg)
+		if err != nil {
+			return nil, errors.Wrapf(err, "%s: argument %d", name, i)
+		}
+
+		values[i] = value
+	}
+
+	in := &structpb.Struct{Fields: map[string]*structpb.Value{
+		"name": structpb.NewStringValue(name),
+		"args": structpb.NewListValue(&structpb.ListValue{Values: values}),
+	}}
+
+	out := new(structpb.Value)
+	if err := p.conn.Invoke(ctx, callMethod, in, out); err != nil {
+		select {
+		case <-p.exited:
+			return nil, errors.Wrapf(err, "plugin %s: %s: process exited (%v)", p.info.Name, name, p.waitErr)
+		default:
+		}
+
+		// A function that failed with a status of its own, such as a hook
+		// rejecting a call, keeps it intact.
+		if st, ok := status.FromError(err); ok && st.Code() != codes.Unknown && st.Code() != codes.Unavailable {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return nil, errors.Wrapf(err, "plugin %s: %s", p.info.Name, name)
+	}
+
+	return out.AsInterface(), nil
+}
+
+// Close asks the plugin to exit by closing its stdin and kills it if it does
+// not do so in time.
+func (p *Process) Close() error {
+	p.closeOnce.Do(func() {
+		if p.conn != nil {
+			_ = p.conn.Close()
+		}
+
+		_ = p.stdin.Close()
+
+		select {
+		case <-p.exited:
+		case <-time.After(stopTimeout):
+			p.kill()
+		}
+	})
+
+	return nil
+}
+
+func (p *Process) kill() {
+	if p.cmd.Process != nil {
+		_ = p.cmd.Process.Kill()
+	}
+
+	<-p.exited
+}
+
+// logWriter forwards plugin stderr lines to the host log.
+type logWriter struct {
+	logger zerolog.Logger
+}
+
+func (w logWriter) Write(b []byte) (int, error) {
+	for line := range strings.SplitSeq(strings.TrimRight(string(b), "\n"), "\n") {
+		w.logger.Warn().Msg(line)
+	}
+
+	return len(b), nil
+}
@@ -0,0 +1,178 @@
+package pluginrpc
+
+import (
+	"context"
+	"os"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"github.com/cockroachdb/errors"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+)
+
+const helperEnv = "PLUGINRPC_TEST_PLUGIN"
+
+var errBoom = errors.New("boom")
+
+// TestMain turns the test binary into a plugin when the host starts it.
+func TestMain(m *testing.M) {
+	if os.Getenv(helperEnv) == "1" {
+		err := Serve(pkgplugins.PluginInfo{Name: "echo", Version: "v0.1.0"},
+			pkgplugins.FuncSpec{Name: "upper", Fn: strings.ToUpper, Description: "upper case", Group: "text"},
+			pkgplugins.FuncSpec{Name: "sum", Fn: func(a, b float64) float64 { return a + b }},
+			pkgplugins.FuncSpec{Name: "pair", Fn: func(s string) []string { return []string{s, s} }},
+			pkgplugins.FuncSpec{Name: "fail", Fn: func(...any) (any, error) { return nil, errBoom }},
+			pkgplugins.FuncSpec{
+				Name:  "tag",
+				Group: pkgplugins.HookBeforeMatch,
+				Fn: pkgplugins.HookFunc(func(_ context.Context, event *pkgplugins.HookEvent) error {
+					if event.Headers["authorization"] == nil {
+						return status.Error(codes.Unauthenticated, "no token")
+					}
+
+					event.Headers["x-tagged"] = event.Method
+
+					return nil
+				}),
+			},
+		)
+		if err != nil {
+			os.Exit(1)
+		}
+
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+func startHelper(t *testing.T) *Process {
+	t.Helper()
+
+	cmd := exec.Command(os.Args[0]) //nolint:gosec,noctx
+	cmd.Env = append(os.Environ(), helperEnv+"=1")
+
+	p, err := Start(t.Context(), cmd)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = p.Close() })
+
+	return p
+}
+
+func TestProcessDescribeAndCall(t *testing.T) {
+	t.Parallel()
+
+	p := startHelper(t)
+
+	require.Equal(t, "echo", p.Info().Name)
+	require.Equal(t, "v0.1.0", p.Info().Version)
+
+	specs := p.Specs()
+	require.Len(t, specs, 5)
+	require.Equal(t, "upper", specs[0].Name)
+	require.Equal(t, "text", specs[0].Group)
+
+	fn, ok := specs[0].Fn.(pkgplugins.Func)
+	require.True(t, ok)
+
+	got, err := fn(context.Background(), "hello")
+	require.NoError(t, err)
+	require.Equal(t, "HELLO", got)
+
+	got, err = p.Call(t.Context(), "sum", 2, 3.5)
+	require.NoError(t, err)
+	require.InDelta(t, 5.5, got, 0)
+
+	got, err = p.Call(t.Context(), "pair", "x")
+	require.NoError(t, err)
+	require.Equal(t, []any{"x", "x"}, got)
+
+	_, err = p.Call(t.Context(), "fail")
+	require.ErrorContains(t, err, "boom")
+
+	_, err = p.Call(t.Context(), "missing")
+	require.ErrorContains(t, err, "unknown function")
+}
+
+func TestProcessHookRoundTrip(t *testing.T) {
+	t.Parallel()
+
+	p := startHelper(t)
+
+	event := &pkgplugins.HookEvent{
+		Hook:    pkgplugins.HookBeforeMatch,
+		Method:  "SayHello",
+		Headers: map[string]any{"authorization": "Bearer x"},
+	}
+
+	got, err := p.Call(t.Context(), "tag", event)
+	require.NoError(t, err)
+
+	changed, err := pkgplugins.AsHookEvent(got)
+	require.NoError(t, err)
+	require.Equal(t, "SayHello", changed.Headers["x-tagged"])
+
+	_, err = p.Call(t.Context(), "tag", &pkgplugins.HookEvent{Hook: pkgplugins.HookBeforeMatch})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestProcessCloseStopsPlugin(t *testing.T) {
+	t.Parallel()
+
+	p := startHelper(t)
+	require.NoError(t, p.Close())
+
+	select {
+	case <-p.exited:
+	default:
+		t.Fatal("plugin must exit after Close")
+	}
+
+	_, err := p.Call(t.Context(), "upper", "x")
+	require.Error(t, err)
+}
+
+func TestServeRequiresHost(t *testing.T) {
+	t.Parallel()
+
+	if os.Getenv(CookieEnv) != "" {
+		t.Skip("running under a plugin host")
+	}
+
+	require.ErrorIs(t, Serve(pkgplugins.PluginInfo{Name: "x"}), ErrNotLaunchedByHost)
+}
+
+func TestParseHandshake(t *testing.T) {
+	t.Parallel()
+
+	target, err := parseHandshake("1|tcp|127.0.0.1:4000\n")
+	require.NoError(t, err)
+	require.Equal(t, "127.0.0.1:4000", target)
+
+	target, err = parseHandshake("1|unix|/tmp/p.sock")
+	require.NoError(t, err)
+	require.Equal(t, "unix:///tmp/p.sock", target)
+
+	_, err = parseHandshake("2|tcp|127.0.0.1:4000")
+	require.ErrorIs(t, err, ErrProtocolVersion)
+
+	_, err = parseHandshake("hello")
+	require.ErrorIs(t, err, ErrBadHandshake)
+
+	_, err = parseHandshake("1|udp|x")
+	require.ErrorIs(t, err, ErrBadHandshake)
+}
+
+func TestStartFailsWithoutHandshake(t *testing.T) {
+	t.Parallel()
+
+	cmd := exec.Command(os.Args[0], "-test.run=^$") //nolint:gosec,noctx
+
+	_, err := Start(t.Context(), cmd)
+	require.ErrorIs(t, err, ErrBadHandshake)
+}
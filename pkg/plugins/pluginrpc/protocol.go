@@ -0,0 +1,131 @@
+// Package pluginrpc runs template plugins as separate processes.
+//
+// A process plugin is any executable that speaks a small gRPC protocol, so it
+// can be built with any Go toolchain, or in another language, and keeps
+// working across gripmock upgrades. The host starts the executable with
+// CookieEnv set, the plugin listens on a loopback address and announces it as
+// the first line of its stdout:
+//
+//	1|tcp|127.0.0.1:50123
+//
+// (protocol version, network, address). The host then calls the
+// TemplatePlugin service described in template_plugin.proto. Closing the
+// plugin's stdin asks it to exit.
+//
+// Go plugins call Serve from main; the rest of the package is the host side.
+package pluginrpc
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+const (
+	// ProtocolVersion is the handshake version this package speaks.
+	ProtocolVersion = 1
+
+	// CookieEnv and CookieValue mark a process as started by gripmock. Serve
+	// refuses to run without them, so a plugin launched by hand explains
+	// itself instead of hanging.
+	CookieEnv   = "GRIPMOCK_PLUGIN_COOKIE"
+	CookieValue = "b7c1d0e4-template-plugin"
+
+	// ServiceName is the fully qualified gRPC service a plugin implements.
+	ServiceName = "gripmock.plugin.v1.TemplatePlugin"
+
+	describeMethod = "/" + ServiceName + "/Describe"
+	callMethod     = "/" + ServiceName + "/Call"
+)
+
+var (
+	ErrNotLaunchedByHost = errors.New("pluginrpc: this binary is a gripmock plugin and must be started by gripmock")
+	ErrBadHandshake      = errors.New("pluginrpc: malformed plugin handshake")
+	ErrProtocolVersion   = errors.New("pluginrpc: unsupported plugin protocol version")
+	ErrUnknownFunction   = errors.New("pluginrpc: unknown function")
+)
+
+// templatePluginServer is the service a plugin exposes.
+type templatePluginServer interface {
+	Describe(ctx context.Context, in *emptypb.Empty) (*structpb.Struct, error)
+	Call(ctx context.Context, in *structpb.Struct) (*structpb.Value, error)
+}
+
+//nolint:gochecknoglobals
+var serviceDesc = grpc.ServiceDesc{
+	ServiceName: ServiceName,
+	HandlerType: (*templatePluginServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Describe", Handler: describeHandler},
+		{MethodName: "Call", Handler: callHandler},
+	},
+	Metadata: "template_plugin.proto",
+}
+
+func describeHandler(
+	srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	server, _ := srv.(templatePluginServer)
+	if interceptor == nil {
+		return server.Describe(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: describeMethod}
+
+	return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+		empty, _ := req.(*emptypb.Empty)
+
+		return server.Describe(ctx, empty)
+	})
+}
+
+func callHandler(
+	srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor,
+) (any, error) {
+	in := new(structpb.Struct)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	server, _ := srv.(templatePluginServer)
+	if interceptor == nil {
+		return server.Call(ctx, in)
+	}
+
+	info := &grpc.UnaryServerInfo{Server: srv, FullMethod: callMethod}
+
+	return interceptor(ctx, in, info, func(ctx context.Context, req any) (any, error) {
+		call, _ := req.(*structpb.Struct)
+
+		return server.Call(ctx, call)
+	})
+}
+
+// toValue converts a Go value into a protobuf Value. Values structpb does not
+// know, such as typed slices or structs, go through their JSON form.
+func toValue(v any) (*structpb.Value, error) {
+	if value, err := structpb.NewValue(v); err == nil {
+		return value, nil
+	}
+
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, errors.Wrapf(err, "pluginrpc: cannot encode %T", v)
+	}
+
+	var generic any
+	if err := json.Unmarshal(raw, &generic); err != nil {
+		return nil, errors.Wrapf(err, "pluginrpc: cannot encode %T", v)
+	}
+
+	return structpb.NewValue(generic)
+}
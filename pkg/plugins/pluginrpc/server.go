@@ -0,0 +1,143 @@
+package pluginrpc
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+
+	"github.com/cockroachdb/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+
+	"github.com/bavix/gripmock/v3/internal/infra/funcwrap"
+	pkgplugins "github.com/bavix/gripmock/v3/pkg/plugins"
+)
+
+// Serve runs the plugin until gripmock closes its stdin. Function shapes are
+// the ones a .so plugin may register; arguments arrive in their JSON form, so
+// numbers are float64 and objects are map[string]any.
+//
+//	func main() {
+//		err := pluginrpc.Serve(plugins.PluginInfo{Name: "hash"}, plugins.FuncSpec{
+//			Name: "sha256",
+//			Fn:   func(s string) string { ... },
+//		})
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//	}
+func Serve(info pkgplugins.PluginInfo, specs ...pkgplugins.FuncSpec) error {
+	if os.Getenv(CookieEnv) != CookieValue {
+		return ErrNotLaunchedByHost
+	}
+
+	return serve(context.Background(), os.Stdin, os.Stdout, info, specs)
+}
+
+func serve(ctx context.Context, stdin io.Reader, stdout io.Writer, info pkgplugins.PluginInfo, specs []pkgplugins.FuncSpec) error {
+	impl, err := newPluginServer(info, specs)
+	if err != nil {
+		return err
+	}
+
+	lis, err := (&net.ListenConfig{}).Listen(ctx, "tcp", "127.0.0.1:0")
+	if err != nil {
+		return errors.Wrap(err, "pluginrpc: listen")
+	}
+
+	server := grpc.NewServer()
+	server.RegisterService(&serviceDesc, impl)
+
+	if _, err := fmt.Fprintf(stdout, "%d|%s|%s\n", ProtocolVersion, lis.Addr().Network(), lis.Addr().String()); err != nil {
+		_ = lis.Close()
+
+		return errors.Wrap(err, "pluginrpc: handshake")
+	}
+
+	go func() {
+		_, _ = io.Copy(io.Discard, stdin)
+
+		server.GracefulStop()
+	}()
+
+	return server.Serve(lis)
+}
+
+type pluginServer struct {
+	describe *structpb.Struct
+	funcs    map[string]pkgplugins.Func
+}
+
+func newPluginServer(info pkgplugins.PluginInfo, specs []pkgplugins.FuncSpec) (*pluginServer, error) {
+	functions := make([]any, 0, len(specs))
+	funcs := make(map[string]pkgplugins.Func, len(specs))
+
+	for _, spec := range specs {
+		if spec.Name == "" || spec.Fn == nil {
+			continue
+		}
+
+		funcs[spec.Name] = pkgplugins.WrapFunc(spec.Fn, func(fn any) pkgplugins.Func {
+			return funcwrap.WrapReflect(fn)
+		})
+
+		functions = append(functions, map[string]any{
+			"name":        spec.Name,
+			"description": spec.Description,
+			"group":       spec.Group,
+			"replacement": spec.Replacement,
+		})
+	}
+
+	describe, err := structpb.NewStruct(map[string]any{
+		"protocol":    ProtocolVersion,
+		"name":        info.Name,
+		"version":     info.Version,
+		"description": info.Description,
+		"functions":   functions,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "pluginrpc: describe")
+	}
+
+	return &pluginServer{describe: describe, funcs: funcs}, nil
+}
+
+func (s *pluginServer) Describe(context.Context, *emptypb.Empty) (*structpb.Struct, error) {
+	return s.describe, nil
+}
+
+func (s *pluginServer) Call(ctx context.Context, in *structpb.Struct) (*structpb.Value, error) {
+	name := in.GetFields()["name"].GetStringValue()
+
+	fn, ok := s.funcs[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "%v: %s", ErrUnknownFunction, name)
+	}
+
+	var args []any
+	if list := in.GetFields()["args"].GetListValue(); list != nil {
+		args = list.AsSlice()
+	}
+
+	result, err := fn(ctx, args...)
+	if err != nil {
+		if _, ok := status.FromError(err); ok {
+			return nil, err //nolint:wrapcheck
+		}
+
+		return nil, status.Error(codes.Unknown, err.Error())
+	}
+
+	value, err := toValue(result)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return value, nil
+}
@@ -209,6 +209,7 @@ func inputDataToMap(in stuber.InputData) map[string]any {
 	putIfAny(out, "contains", in.Contains)
 	putIfAny(out, "matches", in.Matches)
 	putIfAny(out, "glob", in.Glob)
+	putIfAny(out, "where", in.Where)
 
 	if len(in.AnyOf) > 0 {
 		out["anyOf"] = in.AnyOf
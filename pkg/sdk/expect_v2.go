@@ -44,6 +44,8 @@ type expectationBase struct {
 	sequence []stuber.InputData
 
 	effects []stuber.Effect
+
+	scenario scenarioSpec
 }
 
 // Available after calling a terminal method (Return, SendStream, Run).
@@ -399,6 +401,7 @@ func (e *UnaryExpectation) registerOutput(output stuber.Output, priority int) *s
 
 		UnaryHandler: stuber.UnaryHandler(e.handler),
 	}
+	e.scenario.apply(stub)
 	e.srv.trackExpectation(stub)
 
 	return stub
@@ -483,6 +486,7 @@ func (e *ServerStreamExpectation) SendStream(items ...any) *ServerStreamBuilder
 		pri:          e.priority,
 		times:        e.times,
 		effects:      e.effects,
+		scenario:     e.scenario,
 	}
 }
 
@@ -567,6 +571,7 @@ func (e *ServerStreamExpectation) ReturnErrorWithDetails(
 	}
 	e.stubID = stub.ID
 	e.stub = stub
+	e.scenario.apply(stub)
 	e.srv.trackExpectation(stub)
 
 	return e
@@ -619,6 +624,7 @@ func (e *ServerStreamExpectation) register(output stuber.Output, handler stuber.
 	}
 	e.stubID = id
 	e.stub = stub
+	e.scenario.apply(stub)
 	e.srv.trackExpectation(stub)
 
 	return stub
@@ -640,6 +646,7 @@ type ServerStreamBuilder struct {
 	times        int
 	chainIdx     int
 	effects      []stuber.Effect
+	scenario     scenarioSpec
 }
 
 // Send accepts KV pairs or DelayItem: Send(Delay(100*ms, "msg", "hello")).
@@ -686,6 +693,7 @@ func (b *ServerStreamBuilder) NextWillReturn(kv ...any) *ServerStreamBuilder {
 		Options:  stuber.StubOptions{Times: 1},
 		Effects:  b.effects,
 	}
+	b.scenario.apply(stub)
 	b.srv.trackExpectation(stub)
 
 	return b
@@ -906,6 +914,7 @@ func (e *ClientStreamExpectation) register() {
 		ClientStreamHandler: stuber.ClientStreamHandler(e.handler),
 	}
 	e.stub = stub
+	e.scenario.apply(stub)
 	e.srv.trackExpectation(stub)
 }
 
@@ -1102,6 +1111,8 @@ func mergeInputData(inputs ...stuber.InputData) stuber.InputData {
 			maps.Copy(out.Glob, in.Glob)
 		}
 
+		out.Where = mergeConditions(out.Where, in.Where)
+
 		if len(in.AnyOf) > 0 {
 			out.AnyOf = append(out.AnyOf, in.AnyOf...)
 		}
@@ -1184,6 +1195,7 @@ func (e *BidirectionalExpectation) register(
 		Effects:  e.effects,
 	}
 	e.stub = stub
+	e.scenario.apply(stub)
 	e.srv.trackExpectation(stub)
 
 	return e
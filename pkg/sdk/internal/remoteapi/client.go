@@ -41,6 +41,10 @@ type HistoryCall struct {
 	ElapsedMS       int64
 	StubID          uuid.UUID
 	Timestamp       time.Time
+	RequestHeaders  map[string]string
+	Peer            string
+	Transport       string
+	Deadline        *time.Time
 }
 
 const maxErrorBodyBytes = 4096
@@ -345,6 +349,75 @@ func (c Client) PurgeHistory() error {
 	return nil
 }
 
+// FetchScenarioState returns the state of one scenario in the client's session.
+func (c Client) FetchScenarioState(name string) (string, bool, error) {
+	resp, err := c.sendRequestQuery(http.MethodGet, "api/scenarios", nil, nil, "")
+	if err != nil {
+		return "", false, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", false, describeFailure("fetch scenarios", resp)
+	}
+
+	var list []struct {
+		Name  string `json:"name"`
+		State string `json:"state"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+		return "", false, fmt.Errorf("sdk: failed to decode scenarios: %w", err)
+	}
+
+	for _, scenario := range list {
+		if scenario.Name == name {
+			return scenario.State, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// SetScenarioState moves a scenario of the client's session to state; empty resets it.
+func (c Client) SetScenarioState(name, state string) error {
+	body, err := json.Marshal(map[string]string{"state": state})
+	if err != nil {
+		return fmt.Errorf("sdk: failed to marshal scenario state: %w", err)
+	}
+
+	resp, err := c.sendRequest(
+		http.MethodPut,
+		"api/scenarios/"+url.PathEscape(name)+"/state",
+		body,
+		"application/json",
+	)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return describeFailure("set scenario state", resp)
+	}
+
+	return nil
+}
+
+// ResetScenarios returns every scenario of the client's session to Started.
+func (c Client) ResetScenarios() error {
+	resp, err := c.sendRequest(http.MethodPost, "api/scenarios/reset", nil, "")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return describeFailure("reset scenarios", resp)
+	}
+
+	return nil
+}
+
 func decodeHistory(body io.Reader) ([]HistoryCall, error) {
 	var list []struct {
 		Service         *string             `json:"service"`
@@ -358,6 +431,10 @@ func decodeHistory(body io.Reader) ([]HistoryCall, error) {
 		ElapsedMS       *int64              `json:"elapsedMs"`
 		StubID          *openapi_types.UUID `json:"stubId"`
 		Timestamp       *time.Time          `json:"timestamp"`
+		RequestHeaders  *map[string]string  `json:"requestHeaders"`
+		Peer            *string             `json:"peer"`
+		Transport       *string             `json:"transport"`
+		Deadline        *time.Time          `json:"deadline"`
 	}
 	if err := json.NewDecoder(body).Decode(&list); err != nil {
 		return nil, fmt.Errorf("sdk: failed to decode history: %w", err)
@@ -377,6 +454,10 @@ func decodeHistory(body io.Reader) ([]HistoryCall, error) {
 			ElapsedMS:       ptrOrZero(call.ElapsedMS),
 			StubID:          ptrOrZero(call.StubID),
 			Timestamp:       ptrOrZero(call.Timestamp),
+			RequestHeaders:  ptrOrZero(call.RequestHeaders),
+			Peer:            ptrOrZero(call.Peer),
+			Transport:       ptrOrZero(call.Transport),
+			Deadline:        call.Deadline,
 		}
 	}
 
@@ -2,7 +2,10 @@ package sdk
 
 import (
 	"maps"
+	"strings"
+	"time"
 
+	"github.com/bavix/gripmock/v3/internal/infra/deeply"
 	"github.com/bavix/gripmock/v3/internal/infra/stuber"
 )
 
@@ -12,11 +15,15 @@ import (
 //
 // Use Match(sdk.Contains(...)) for payload matching and
 // WithHeader(sdk.Contains(...)) for gRPC metadata matching.
+//
+// Field conditions (Gt, Between, Exists, Len, Every, ...) apply to payloads
+// only; WithHeader ignores them.
 type Matcher struct {
 	equals   map[string]any
 	contains map[string]any
 	matches  map[string]any
 	glob     map[string]any
+	where    map[string]any
 	anyOf    []Matcher
 	noOrder  bool
 }
@@ -37,8 +44,124 @@ func Glob(key, pattern string) Matcher {
 	return Matcher{glob: map[string]any{key: pattern}}
 }
 
+// Gt matches when the field at key is greater than value: a number, a
+// time.Time for Timestamp fields or a time.Duration for Duration fields. Key
+// may be a dotted path into nested messages; an empty key targets the element
+// itself inside Every and Some.
+func Gt(key string, value any) Matcher {
+	return condition(key, map[string]any{deeply.OpGt: operand(value)})
+}
+
+// Gte matches when the field at key is greater than or equal to value.
+func Gte(key string, value any) Matcher {
+	return condition(key, map[string]any{deeply.OpGte: operand(value)})
+}
+
+// Lt matches when the field at key is less than value.
+func Lt(key string, value any) Matcher {
+	return condition(key, map[string]any{deeply.OpLt: operand(value)})
+}
+
+// Lte matches when the field at key is less than or equal to value.
+func Lte(key string, value any) Matcher {
+	return condition(key, map[string]any{deeply.OpLte: operand(value)})
+}
+
+// Between matches when the field at key lies within [low, high].
+func Between(key string, low, high any) Matcher {
+	return condition(key, map[string]any{deeply.OpBetween: []any{operand(low), operand(high)}})
+}
+
+// Near matches when the number at key is within tolerance of value.
+func Near(key string, value, tolerance float64) Matcher {
+	return condition(key, map[string]any{deeply.OpEq: value, deeply.OpTolerance: tolerance})
+}
+
+// Exists matches when the request sets the field at key.
+func Exists(key string) Matcher {
+	return condition(key, map[string]any{deeply.OpExists: true})
+}
+
+// Absent matches when the request leaves the field at key unset.
+func Absent(key string) Matcher {
+	return condition(key, map[string]any{deeply.OpExists: false})
+}
+
+// Len matches when the list, map or string at key has exactly n elements.
+func Len(key string, n int) Matcher {
+	return condition(key, map[string]any{deeply.OpLen: n})
+}
+
+// LenBetween matches when the list, map or string at key has between low and
+// high elements.
+func LenBetween(key string, low, high int) Matcher {
+	return condition(key, map[string]any{deeply.OpLen: map[string]any{deeply.OpBetween: []any{low, high}}})
+}
+
+// Every matches when each element of the list at key meets the conditions of
+// element, e.g. Every("items", Gt("qty", 0)). Element is built from field
+// conditions only, combined with And; it panics on Equals, Contains, Matches,
+// Glob or AnyOf.
+func Every(key string, element Matcher) Matcher {
+	return condition(key, map[string]any{deeply.OpEvery: element.conditions("Every")})
+}
+
+// Some matches when at least one element of the list at key meets the
+// conditions of element. Element takes the same matchers as in Every.
+func Some(key string, element Matcher) Matcher {
+	return condition(key, map[string]any{deeply.OpSome: element.conditions("Some")})
+}
+
+// conditions returns the field conditions of an Every or Some element.
+func (m Matcher) conditions(caller string) map[string]any {
+	if len(m.equals) > 0 || len(m.contains) > 0 || len(m.matches) > 0 || len(m.glob) > 0 || len(m.anyOf) > 0 {
+		panic("gripmock: " + caller + " takes field conditions (Gt, Between, Exists, Len, ...) only")
+	}
+
+	if len(m.where) == 0 {
+		panic("gripmock: " + caller + " requires a field condition")
+	}
+
+	return m.where
+}
+
+func condition(key string, cond map[string]any) Matcher {
+	if key == "" {
+		return Matcher{where: cond}
+	}
+
+	parts := strings.Split(key, ".")
+	for i := len(parts) - 1; i > 0; i-- {
+		cond = map[string]any{parts[i]: cond}
+	}
+
+	return Matcher{where: map[string]any{parts[0]: cond}}
+}
+
+// operand encodes times and durations the way protojson encodes Timestamp
+// and Duration fields.
+func operand(value any) any {
+	switch v := value.(type) {
+	case time.Time:
+		return v.UTC().Format(time.RFC3339Nano)
+	case time.Duration:
+		return v.String()
+	default:
+		return value
+	}
+}
+
 // AnyOf returns a Matcher that passes when at least one alternative matches (OR logic).
+// An alternative may combine any matchers with And, but cannot hold an AnyOf
+// of its own; that panics. Field conditions in an alternative are ignored by
+// WithHeader, as they are elsewhere.
 func AnyOf(matchers ...Matcher) Matcher {
+	for _, m := range matchers {
+		if len(m.anyOf) > 0 {
+			panic("gripmock: AnyOf cannot nest")
+		}
+	}
+
 	return Matcher{anyOf: matchers}
 }
 
@@ -51,6 +174,8 @@ func And(matchers ...Matcher) Matcher {
 		out.matches = mergeStrAny(out.matches, m.matches)
 
 		out.glob = mergeStrAny(out.glob, m.glob)
+		out.where = mergeConditions(out.where, m.where)
+
 		if m.noOrder {
 			out.noOrder = true
 		}
@@ -74,6 +199,7 @@ func (m Matcher) compilePayload() stuber.InputData {
 		Contains:         m.contains,
 		Matches:          m.matches,
 		Glob:             m.glob,
+		Where:            m.where,
 		IgnoreArrayOrder: m.noOrder,
 		AnyOf:            compileAnyOf(m.anyOf),
 	}
@@ -99,6 +225,7 @@ func compileAnyOf(matchers []Matcher) []stuber.AnyOfElement {
 			Contains:         m.contains,
 			Matches:          m.matches,
 			Glob:             m.glob,
+			Where:            m.where,
 		}
 	}
 
@@ -132,3 +259,27 @@ func mergeStrAny(a, b map[string]any) map[string]any {
 
 	return a
 }
+
+// mergeConditions merges b into a field by field, so conditions on the same
+// field combine instead of replacing each other.
+func mergeConditions(a, b map[string]any) map[string]any {
+	if len(b) == 0 {
+		return a
+	}
+
+	out := make(map[string]any, len(a)+len(b))
+	maps.Copy(out, a)
+
+	for key, value := range b {
+		left, leftOK := out[key].(map[string]any)
+		right, rightOK := value.(map[string]any)
+
+		if leftOK && rightOK {
+			out[key] = mergeConditions(left, right)
+		} else {
+			out[key] = value
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,65 @@
+package sdk
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+func TestConditionMatchersCompile(t *testing.T) {
+	t.Parallel()
+
+	at := time.Date(2024, 3, 1, 12, 0, 0, 0, time.FixedZone("CET", 3600))
+
+	m := And(
+		Gt("amount", 100),
+		Lte("amount", 500),
+		Between("customer.created_at", at, at.Add(time.Hour)),
+		Lt("ttl", 90*time.Second),
+		Every("items", Gt("qty", 0)),
+		Some("scores", Gte("", 9)),
+		Absent("coupon"),
+	)
+
+	require.Equal(t, map[string]any{
+		"amount": map[string]any{"gt": 100, "lte": 500},
+		"customer": map[string]any{"created_at": map[string]any{
+			"between": []any{"2024-03-01T11:00:00Z", "2024-03-01T12:00:00Z"},
+		}},
+		"ttl":    map[string]any{"lt": "1m30s"},
+		"items":  map[string]any{"every": map[string]any{"qty": map[string]any{"gt": 0}}},
+		"scores": map[string]any{"some": map[string]any{"gte": 9}},
+		"coupon": map[string]any{"exists": false},
+	}, m.compilePayload().Where)
+	require.Nil(t, m.compileHeader().Equals)
+}
+
+func TestAnyOfKeepsConditions(t *testing.T) {
+	t.Parallel()
+
+	m := AnyOf(Gt("amount", 100), And(Equals("currency", "EUR"), Lt("amount", 0)))
+
+	require.Equal(t, []stuber.AnyOfElement{
+		{Where: map[string]any{"amount": map[string]any{"gt": 100}}},
+		{
+			Equals: map[string]any{"currency": "EUR"},
+			Where:  map[string]any{"amount": map[string]any{"lt": 0}},
+		},
+	}, m.compilePayload().AnyOf)
+
+	require.PanicsWithValue(t, "gripmock: AnyOf cannot nest", func() {
+		AnyOf(Equals("a", 1), AnyOf(Equals("b", 1), Equals("c", 1)))
+	})
+}
+
+func TestEveryTakesConditionsOnly(t *testing.T) {
+	t.Parallel()
+
+	require.Panics(t, func() { Every("items", Equals("sku", "a")) })
+	require.Panics(t, func() { Some("items", And(Gt("qty", 0), Matches("sku", "^a"))) })
+	require.Panics(t, func() { Every("items", Matcher{}) })
+	require.NotPanics(t, func() { Some("items", And(Gt("qty", 0), Exists("sku"))) })
+}
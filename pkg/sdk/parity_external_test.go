@@ -1384,6 +1384,9 @@ func TestConfigAfterTerminalPanicsOnEveryShape(t *testing.T) {
 		"bidi Times":            func() { bidi().Times(2) },
 		"bidi MatchSequence":    func() { bidi().MatchSequence(sdk.Equals("name", "y")) },
 		"bidi ReturnHeaders":    func() { bidi().ReturnHeaders(headers) },
+		"unary InScenario":      func() { unary().InScenario("flow") },
+		"stream WillSetStateTo": func() { stream().WillSetStateTo("Done") },
+		"client WhenState":      func() { client().WhenScenarioStateIs("Done") },
 	}
 
 	for name, mutate := range cases {
@@ -45,6 +45,25 @@ func TestMatchersRejectNonMatching(t *testing.T) {
 		require.Error(t, sayHelloErr(t, srv, fds, "Zoe"),
 			"AnyOf(Alex, Bob) must not match Zoe")
 	})
+
+	t.Run("anyof of field conditions", func(t *testing.T) {
+		t.Parallel()
+
+		srv, fds := newServer(t)
+		defer func() { _ = srv.Close() }()
+
+		srv.ExpectUnary("/test.Greeter/SayHello").
+			Match(sdk.AnyOf(
+				sdk.Len("name", 3),
+				sdk.LenBetween("name", 6, 10),
+			)).
+			Return("message", "conditions")
+
+		require.Equal(t, "conditions", getMsg(t, sayHello(t, srv, fds, "Bob")))
+		require.Equal(t, "conditions", getMsg(t, sayHello(t, srv, fds, "Joanna")))
+		require.Error(t, sayHelloErr(t, srv, fds, "Alex"),
+			"AnyOf(Len 3, Len 6..10) must not match Alex")
+	})
 }
 
 func TestResetClearsHistoryInPlace(t *testing.T) {
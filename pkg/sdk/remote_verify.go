@@ -64,6 +64,10 @@ func convertHistory(history []remoteapi.HistoryCall) []CallRecord {
 			ElapsedMS:       c.ElapsedMS,
 			StubID:          c.StubID,
 			Timestamp:       c.Timestamp,
+			RequestHeaders:  c.RequestHeaders,
+			Peer:            c.Peer,
+			Transport:       c.Transport,
+			Deadline:        c.Deadline,
 		}
 	}
 
@@ -0,0 +1,167 @@
+package sdk
+
+import (
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// ScenarioStarted is the state every scenario is in until a stub moves it on.
+const ScenarioStarted = stuber.ScenarioStarted
+
+type scenarioSpec struct {
+	name          string
+	requiredState string
+	newState      string
+}
+
+func (s scenarioSpec) apply(stub *stuber.Stub) {
+	stub.Scenario = s.name
+	stub.RequiredState = s.requiredState
+	stub.NewState = s.newState
+}
+
+// InScenario ties the expectation to a scenario (state machine). State is
+// tracked per session and starts at ScenarioStarted.
+func (e *UnaryExpectation) InScenario(name string) *UnaryExpectation {
+	e.mustNotBeCommitted("InScenario")
+	e.scenario.name = name
+
+	return e
+}
+
+// WhenScenarioStateIs makes the expectation match only while its scenario is in state.
+func (e *UnaryExpectation) WhenScenarioStateIs(state string) *UnaryExpectation {
+	e.mustNotBeCommitted("WhenScenarioStateIs")
+	e.scenario.requiredState = state
+
+	return e
+}
+
+// WillSetStateTo moves the scenario to state when the expectation matches.
+func (e *UnaryExpectation) WillSetStateTo(state string) *UnaryExpectation {
+	e.mustNotBeCommitted("WillSetStateTo")
+	e.scenario.newState = state
+
+	return e
+}
+
+// InScenario ties the expectation to a scenario (state machine).
+func (e *ServerStreamExpectation) InScenario(name string) *ServerStreamExpectation {
+	e.mustNotBeCommitted("InScenario")
+	e.scenario.name = name
+
+	return e
+}
+
+// WhenScenarioStateIs makes the expectation match only while its scenario is in state.
+func (e *ServerStreamExpectation) WhenScenarioStateIs(state string) *ServerStreamExpectation {
+	e.mustNotBeCommitted("WhenScenarioStateIs")
+	e.scenario.requiredState = state
+
+	return e
+}
+
+// WillSetStateTo moves the scenario to state when the expectation matches.
+func (e *ServerStreamExpectation) WillSetStateTo(state string) *ServerStreamExpectation {
+	e.mustNotBeCommitted("WillSetStateTo")
+	e.scenario.newState = state
+
+	return e
+}
+
+// InScenario ties the expectation to a scenario (state machine).
+func (e *ClientStreamExpectation) InScenario(name string) *ClientStreamExpectation {
+	e.mustNotBeCommitted("InScenario")
+	e.scenario.name = name
+
+	return e
+}
+
+// WhenScenarioStateIs makes the expectation match only while its scenario is in state.
+func (e *ClientStreamExpectation) WhenScenarioStateIs(state string) *ClientStreamExpectation {
+	e.mustNotBeCommitted("WhenScenarioStateIs")
+	e.scenario.requiredState = state
+
+	return e
+}
+
+// WillSetStateTo moves the scenario to state when the expectation matches.
+func (e *ClientStreamExpectation) WillSetStateTo(state string) *ClientStreamExpectation {
+	e.mustNotBeCommitted("WillSetStateTo")
+	e.scenario.newState = state
+
+	return e
+}
+
+// InScenario ties the expectation to a scenario (state machine).
+func (e *BidirectionalExpectation) InScenario(name string) *BidirectionalExpectation {
+	e.mustNotBeCommitted("InScenario")
+	e.scenario.name = name
+
+	return e
+}
+
+// WhenScenarioStateIs makes the expectation match only while its scenario is in state.
+func (e *BidirectionalExpectation) WhenScenarioStateIs(state string) *BidirectionalExpectation {
+	e.mustNotBeCommitted("WhenScenarioStateIs")
+	e.scenario.requiredState = state
+
+	return e
+}
+
+// WillSetStateTo moves the scenario to state when the expectation matches.
+func (e *BidirectionalExpectation) WillSetStateTo(state string) *BidirectionalExpectation {
+	e.mustNotBeCommitted("WillSetStateTo")
+	e.scenario.newState = state
+
+	return e
+}
+
+// ScenarioState returns the current state of a scenario in the server's
+// session, or an empty string when no stub declares it.
+func (s *Server) ScenarioState(name string) string {
+	if s.remote != nil {
+		state, _, err := s.remote.apiWithContext(s.readCtx()).FetchScenarioState(name)
+		if err != nil {
+			s.t.Error("gripmock: ScenarioState failed: ", err)
+		}
+
+		return state
+	}
+
+	for _, scenario := range s.budgerigar.Scenarios(s.session) {
+		if scenario.Name == name {
+			return scenario.State
+		}
+	}
+
+	return ""
+}
+
+// SetScenarioState moves a scenario of the server's session to state; an
+// empty state resets it to ScenarioStarted.
+func (s *Server) SetScenarioState(name, state string) {
+	if s.remote != nil {
+		if err := s.remote.apiWithContext(s.readCtx()).SetScenarioState(name, state); err != nil {
+			s.t.Error("gripmock: SetScenarioState failed: ", err)
+		}
+
+		return
+	}
+
+	if !s.budgerigar.SetScenarioState(name, s.session, state) {
+		s.t.Error("gripmock: SetScenarioState: scenario " + name + " not found")
+	}
+}
+
+// ResetScenarios returns every scenario of the server's session to ScenarioStarted.
+func (s *Server) ResetScenarios() {
+	if s.remote != nil {
+		if err := s.remote.apiWithContext(s.readCtx()).ResetScenarios(); err != nil {
+			s.t.Error("gripmock: ResetScenarios failed: ", err)
+		}
+
+		return
+	}
+
+	s.budgerigar.ResetScenarios(s.session)
+}
@@ -0,0 +1,38 @@
+package sdk_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/bavix/gripmock/v3/pkg/sdk"
+)
+
+func TestScenarioDrivesUnaryResponses(t *testing.T) {
+	t.Parallel()
+
+	srv, fds := newServer(t)
+
+	srv.ExpectUnary("/test.Greeter/SayHello").
+		InScenario("greeting").
+		WhenScenarioStateIs(sdk.ScenarioStarted).
+		WillSetStateTo("Greeted").
+		Return("message", "first")
+
+	srv.ExpectUnary("/test.Greeter/SayHello").
+		InScenario("greeting").
+		WhenScenarioStateIs("Greeted").
+		Return("message", "again")
+
+	require.Equal(t, sdk.ScenarioStarted, srv.ScenarioState("greeting"))
+	require.Equal(t, "first", getMsg(t, sayHello(t, srv, fds, "Alex")))
+	require.Equal(t, "Greeted", srv.ScenarioState("greeting"))
+	require.Equal(t, "again", getMsg(t, sayHello(t, srv, fds, "Alex")))
+	require.Equal(t, "again", getMsg(t, sayHello(t, srv, fds, "Alex")))
+
+	srv.ResetScenarios()
+	require.Equal(t, "first", getMsg(t, sayHello(t, srv, fds, "Alex")))
+
+	srv.SetScenarioState("greeting", sdk.ScenarioStarted)
+	require.Equal(t, "first", getMsg(t, sayHello(t, srv, fds, "Alex")))
+}
@@ -971,6 +971,20 @@ func TestMatchers(t *testing.T) {
 		require.Equal(t, "anyof", getMsg(t, sayHello(t, srv, fds, "Alex")))
 		require.Equal(t, "anyof", getMsg(t, sayHello(t, srv, fds, "Bob")))
 	})
+	t.Run("conditions", func(t *testing.T) {
+		t.Parallel()
+
+		srv, fds := newServer(t)
+		defer func() { _ = srv.Close() }()
+
+		srv.ExpectUnary("/test.Greeter/SayHello").
+			Match(sdk.And(sdk.LenBetween("name", 3, 4), sdk.Absent("nickname"))).
+			Return("message", "short")
+
+		require.Equal(t, "short", getMsg(t, sayHello(t, srv, fds, "Alex")))
+
+		require.Error(t, sayHelloErr(t, srv, fds, "Alexander"))
+	})
 }
 
 func TestReturnFormats(t *testing.T) {
@@ -0,0 +1,164 @@
+package sdk
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+	"github.com/bavix/gripmock/v3/internal/infra/stuber"
+)
+
+// CallVerification asserts on recorded calls to one method. Narrow it with
+// Match and WithHeader, then finish with a count: Called, Times, AtLeast,
+// AtMost, Between or Never. A failed count is reported through the test with
+// the closest non-matching calls and what kept each from matching.
+//
+//	srv.Verify(pb.Greeter_SayHello_FullMethodName).
+//		Match("name", "Alex").
+//		WithHeader(sdk.Equals("x-tenant", "acme")).
+//		AtLeast(1)
+type CallVerification struct {
+	srv    *Server
+	filter callFilter
+}
+
+// callFilter selects the recorded calls a verification counts.
+type callFilter struct {
+	fullMethod string
+	matchers   []stuber.InputData
+	headers    stuber.InputHeader
+}
+
+func (f *callFilter) match(matches []any) {
+	f.matchers = append(f.matchers, compileMatchArgs(matches...)...)
+}
+
+func (f *callFilter) withHeader(headers []Matcher) {
+	for _, h := range headers {
+		f.headers = mergeInputHeader(f.headers, h.compileHeader())
+	}
+}
+
+// expectation fills the call selection of exp, leaving its count bounds alone.
+func (f *callFilter) expectation(exp history.Expectation, session string) history.Expectation {
+	exp.Service, exp.Method = splitMethodName(f.fullMethod)
+	exp.Session = session
+	exp.Headers = f.headers
+
+	if len(f.matchers) > 0 {
+		exp.Input = mergeInputData(f.matchers...)
+	}
+
+	return exp
+}
+
+// Verify starts an assertion on the calls recorded for fullMethod, scoped to
+// the server's session.
+func (s *Server) Verify(fullMethod string) *CallVerification {
+	return &CallVerification{srv: s, filter: callFilter{fullMethod: fullMethod}}
+}
+
+// Match counts only calls whose request satisfies the matchers. It accepts the
+// same arguments as UnaryExpectation.Match.
+func (v *CallVerification) Match(matches ...any) *CallVerification {
+	v.filter.match(matches)
+
+	return v
+}
+
+// WithHeader counts only calls whose request headers satisfy the matchers.
+func (v *CallVerification) WithHeader(headers ...Matcher) *CallVerification {
+	v.filter.withHeader(headers)
+
+	return v
+}
+
+// Called asserts at least one matching call.
+func (v *CallVerification) Called() bool {
+	return v.check(history.Expectation{})
+}
+
+// Times asserts exactly n matching calls.
+func (v *CallVerification) Times(n int) bool {
+	return v.check(history.Expectation{Times: &n})
+}
+
+// AtLeast asserts n or more matching calls.
+func (v *CallVerification) AtLeast(n int) bool {
+	return v.check(history.Expectation{AtLeast: &n})
+}
+
+// AtMost asserts n or fewer matching calls.
+func (v *CallVerification) AtMost(n int) bool {
+	return v.check(history.Expectation{AtMost: &n})
+}
+
+// Between asserts a matching call count within [minCalls, maxCalls].
+func (v *CallVerification) Between(minCalls, maxCalls int) bool {
+	return v.check(history.Expectation{AtLeast: &minCalls, AtMost: &maxCalls})
+}
+
+// Never asserts that no call matched.
+func (v *CallVerification) Never() bool {
+	return v.AtMost(0)
+}
+
+func (v *CallVerification) check(exp history.Expectation) bool {
+	exp = v.filter.expectation(exp, v.srv.session)
+
+	result, ok := v.srv.verifyExpectation(exp)
+	if !ok {
+		return false
+	}
+
+	if exp.Met(result.Actual) {
+		return true
+	}
+
+	v.srv.t.Error(formatVerifyFailure(exp, result))
+
+	return false
+}
+
+func (s *Server) verifyExpectation(exp history.Expectation) (history.VerifyResult, bool) {
+	if s.remote == nil {
+		return history.Verify(s.recorder, exp), true
+	}
+
+	calls, err := s.remote.history().FilterByMethodContext(s.readCtx(), exp.Service, exp.Method)
+	if err != nil {
+		s.reportRemoteErr("Verify", err)
+
+		return history.VerifyResult{}, false
+	}
+
+	return history.VerifySeq(slices.Values(calls), exp), true
+}
+
+func formatVerifyFailure(exp history.Expectation, result history.VerifyResult) string {
+	var b strings.Builder
+
+	fmt.Fprintf(&b, "gripmock: expected %s/%s to be called %s, got %d",
+		exp.Service, exp.Method, exp.Describe(), result.Actual)
+
+	for i, diff := range result.Closest {
+		fmt.Fprintf(&b, "\n  closest call #%d at %s:", i+1, diff.Call.Timestamp.Format("15:04:05.000"))
+		writeMismatches(&b, "input", diff.Input)
+		writeMismatches(&b, "header", diff.Headers)
+	}
+
+	return b.String()
+}
+
+func writeMismatches(b *strings.Builder, kind string, mismatches []stuber.Mismatch) {
+	for _, m := range mismatches {
+		if m.Field == "" {
+			fmt.Fprintf(b, "\n    %s %s: no alternative matched %v", kind, m.Operator, m.Expected)
+
+			continue
+		}
+
+		fmt.Fprintf(b, "\n    %s %s %q: want %v, got %v", kind, m.Operator, m.Field, m.Expected, m.Actual)
+	}
+}
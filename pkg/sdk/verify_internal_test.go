@@ -0,0 +1,101 @@
+package sdk
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordingT struct {
+	TestingT
+
+	errors []string
+}
+
+func (r *recordingT) Error(args ...any) { r.errors = append(r.errors, fmt.Sprint(args...)) }
+
+func (r *recordingT) Fail() {}
+
+func TestVerifyCountsMatchingCalls(t *testing.T) {
+	t.Parallel()
+
+	srv, fds := newProjectSrv(t, "greeter")
+
+	srv.ExpectUnary("/helloworld.Greeter/SayHello").Return("message", "Hi")
+
+	reg := mustBuildReg(t, fds)
+	_ = invokeGreeter(t, srv.Conn(), reg, "Alex")
+	_ = invokeGreeter(t, srv.Conn(), reg, "Alex")
+	_ = invokeGreeter(t, srv.Conn(), reg, "Bob")
+
+	const method = "/helloworld.Greeter/SayHello"
+
+	require.True(t, srv.Verify(method).Called())
+	require.True(t, srv.Verify(method).Match("name", "Alex").Times(2))
+	require.True(t, srv.Verify(method).Match(Glob("name", "B*")).AtLeast(1))
+	require.True(t, srv.Verify(method).Match("name", "Alex").Between(1, 2))
+	require.True(t, srv.Verify(method).Match("name", "Eve").Never())
+	require.True(t, srv.Verify(method).AtMost(3))
+}
+
+func TestVerifyReportsClosestCalls(t *testing.T) {
+	t.Parallel()
+
+	srv, fds := newProjectSrv(t, "greeter")
+
+	srv.ExpectUnary("/helloworld.Greeter/SayHello").Return("message", "Hi")
+
+	reg := mustBuildReg(t, fds)
+	_ = invokeGreeter(t, srv.Conn(), reg, "Alex")
+
+	rec := &recordingT{TestingT: t}
+	srv.t = rec
+
+	ok := srv.Verify("/helloworld.Greeter/SayHello").
+		Match("name", "Bob").
+		WithHeader(Equals("x-tenant", "acme")).
+		AtLeast(1)
+	require.False(t, ok)
+	require.Len(t, rec.errors, 1)
+	require.Contains(t, rec.errors[0], "expected helloworld.Greeter/SayHello to be called at least 1 times, got 0")
+	require.Contains(t, rec.errors[0], `input equals "name": want Bob, got Alex`)
+	require.Contains(t, rec.errors[0], `header equals "x-tenant": want acme, got <nil>`)
+
+	rec.errors = nil
+	require.False(t, srv.Verify("/helloworld.Greeter/SayHello").Never())
+	require.Len(t, rec.errors, 1)
+	require.NotContains(t, rec.errors[0], "closest call", "overshooting a bound has no near misses")
+}
+
+func TestVerifyInOrder(t *testing.T) {
+	t.Parallel()
+
+	srv, fds := newProjectSrv(t, "greeter")
+
+	srv.ExpectUnary("/helloworld.Greeter/SayHello").Return("message", "Hi")
+
+	reg := mustBuildReg(t, fds)
+	_ = invokeGreeter(t, srv.Conn(), reg, "login")
+	_ = invokeGreeter(t, srv.Conn(), reg, "charge")
+	_ = invokeGreeter(t, srv.Conn(), reg, "create")
+
+	const method = "/helloworld.Greeter/SayHello"
+
+	require.True(t, srv.VerifyInOrder(
+		Call(method).Match("name", "login"),
+		Call(method).Match("name", "charge").Times(1),
+		Call(method).Match("name", "logout").Never(),
+		Call(method).Match("name", "create"),
+	))
+
+	rec := &recordingT{TestingT: t}
+	srv.t = rec
+
+	require.False(t, srv.VerifyInOrder(
+		Call(method).Match("name", "create"),
+		Call(method).Match("name", "login"),
+	))
+	require.Len(t, rec.errors, 1)
+	require.Contains(t, rec.errors[0], "step 2: expected helloworld.Greeter/SayHello to be called at least once after helloworld.Greeter/SayHello, got 0")
+}
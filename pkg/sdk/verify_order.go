@@ -0,0 +1,113 @@
+package sdk
+
+import (
+	"slices"
+
+	"github.com/bavix/gripmock/v3/internal/domain/history"
+)
+
+// CallStep is one step of Server.VerifyInOrder. Without a count bound the step
+// must match at least once.
+type CallStep struct {
+	filter callFilter
+	bounds history.Expectation
+}
+
+// Call starts a step for fullMethod.
+func Call(fullMethod string) *CallStep {
+	return &CallStep{filter: callFilter{fullMethod: fullMethod}}
+}
+
+// Match narrows the step to calls whose request satisfies the matchers.
+func (c *CallStep) Match(matches ...any) *CallStep {
+	c.filter.match(matches)
+
+	return c
+}
+
+// WithHeader narrows the step to calls whose request headers satisfy the matchers.
+func (c *CallStep) WithHeader(headers ...Matcher) *CallStep {
+	c.filter.withHeader(headers)
+
+	return c
+}
+
+// Times requires exactly n matching calls at this point of the sequence.
+func (c *CallStep) Times(n int) *CallStep {
+	c.bounds.Times = &n
+
+	return c
+}
+
+// AtLeast requires n or more matching calls at this point of the sequence.
+func (c *CallStep) AtLeast(n int) *CallStep {
+	c.bounds.AtLeast = &n
+
+	return c
+}
+
+// AtMost allows n or fewer matching calls at this point of the sequence.
+func (c *CallStep) AtMost(n int) *CallStep {
+	c.bounds.AtMost = &n
+
+	return c
+}
+
+// Never asserts the call did not happen between the neighbouring steps.
+func (c *CallStep) Never() *CallStep {
+	return c.AtMost(0)
+}
+
+// VerifyInOrder asserts that the recorded calls, ordered by start time,
+// follow steps. Each step takes the calls it matches from where the previous
+// step stopped up to the first call a later step matches, and its count
+// bounds apply to that stretch. Calls that do not fit the sequence are
+// skipped. A failure is reported through the test.
+//
+//	srv.VerifyInOrder(
+//		sdk.Call(authpb.Auth_Login_FullMethodName),
+//		sdk.Call(paypb.Payments_Charge_FullMethodName).Times(1),
+//		sdk.Call(orderpb.Orders_Create_FullMethodName),
+//	)
+func (s *Server) VerifyInOrder(steps ...*CallStep) bool {
+	if len(steps) == 0 {
+		return true
+	}
+
+	expectations := make([]history.Expectation, len(steps))
+	for i, step := range steps {
+		expectations[i] = step.filter.expectation(step.bounds, s.session)
+	}
+
+	result, ok := s.verifyOrder(expectations)
+	if !ok {
+		return false
+	}
+
+	if result.OK() {
+		return true
+	}
+
+	s.t.Error("gripmock: calls out of order: ", result.Message(expectations))
+
+	return false
+}
+
+func (s *Server) verifyOrder(steps []history.Expectation) (history.OrderResult, bool) {
+	if s.remote == nil {
+		return history.VerifyOrder(s.recorder, s.session, steps), true
+	}
+
+	calls, err := s.remote.history().AllContext(s.readCtx())
+	if err != nil {
+		s.reportRemoteErr("VerifyInOrder", err)
+
+		return history.OrderResult{}, false
+	}
+
+	slices.SortStableFunc(calls, func(a, b CallRecord) int {
+		return a.Timestamp.Compare(b.Timestamp)
+	})
+
+	return history.VerifyOrderCalls(calls, steps), true
+}